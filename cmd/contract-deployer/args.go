@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	wasm_runtime "vsc-node/modules/wasm/runtime"
 )
 
 type args struct {
@@ -16,9 +17,12 @@ type args struct {
 	gqlUrl        string
 	dataDir       string
 	sysconfigPath string
+	upgradePolicy string
+	runtime       wasm_runtime.Runtime
 
 	// update contract args
 	contractId string
+	migrate    bool
 }
 
 func ParseArgs() (args, error) {
@@ -37,8 +41,16 @@ func ParseArgs() (args, error) {
 	dataDir := flag.String("data-dir", "data", "Data directory for config")
 	contractId := flag.String("contractId", "", "Existing contract ID to update contract. Omit to deploy a new contract.")
 	sysconfigPath := flag.String("sysconfig", "", "Path to JSON file with system config overrides")
+	upgradePolicy := flag.String("upgradePolicy", "", "Upgrade policy of a new contract: immutable, immediate (default) or timelock(<blocks>)")
+	runtime := flag.String("runtime", wasm_runtime.Go.String(), "Runtime the WASM bytecode was compiled for: go, rust or assembly-script")
+	migrate := flag.Bool("migrate", false, "Run the migrate entrypoint of the updated code once it is active")
 	flag.Parse()
 
+	rt := wasm_runtime.NewFromString(*runtime)
+	if rt.IsErr() {
+		return args{}, rt.UnwrapErr()
+	}
+
 	return args{
 		*network,
 		*wasmPath,
@@ -49,6 +61,9 @@ func ParseArgs() (args, error) {
 		*gqlUrl,
 		*dataDir,
 		*sysconfigPath,
+		*upgradePolicy,
+		rt.Unwrap(),
 		*contractId,
+		*migrate,
 	}, nil
 }
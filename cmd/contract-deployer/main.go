@@ -13,7 +13,6 @@ import (
 	"vsc-node/modules/hive/streamer"
 	p2pInterface "vsc-node/modules/p2p"
 	stateEngine "vsc-node/modules/state-processing"
-	wasm_runtime "vsc-node/modules/wasm/runtime"
 
 	"github.com/vsc-eco/hivego"
 )
@@ -117,8 +116,10 @@ func deployNewContract(
 			Description:  args.description,
 			Owner:        args.owner,
 			Code:         proof.Hash,
-			Runtime:      wasm_runtime.Go,
+			Runtime:      args.runtime,
 			StorageProof: *proof,
+
+			UpgradePolicy: args.upgradePolicy,
 		}
 
 		j, err := json.Marshal(tx)
@@ -182,9 +183,10 @@ func updateContract(
 			Description: args.description,
 		}
 		if proof != nil {
-			tx.Runtime = &wasm_runtime.Go
+			tx.Runtime = &args.runtime
 			tx.Code = proof.Hash
 			tx.StorageProof = proof
+			tx.Migrate = args.migrate
 		}
 		if args.owner != "" {
 			tx.Owner = args.owner
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 
+	"vsc-node/lib/keystore"
+	"vsc-node/lib/signer"
 	"vsc-node/lib/vsclog"
 )
 
@@ -16,6 +18,13 @@ type args struct {
 	disableTss    bool
 	logLevel      string
 	sysconfigPath string
+
+	keystoreKeyfile string
+	migrateKeystore bool
+
+	signerEndpoint string
+
+	snapshotCid string
 }
 
 func ParseArgs() (args, error) {
@@ -30,6 +39,11 @@ func ParseArgs() (args, error) {
 	disableTss := flag.Bool("disable-tss", false, "Disable TSS plugin (testnet only)")
 	logLevel := flag.String("log-level", "info", "Log level spec: error|warn|info|debug|verbose|trace or comma-separated with module overrides (e.g. error,tss=verbose,bp=info)")
 	sysconfigPath := flag.String("sysconfig", "", "Path to JSON file with system config overrides")
+	keystoreKeyfile := flag.String("keystore-keyfile", "", "Path to the keyfile unlocking the encrypted keystore (alternatively set "+keystore.KeyfileEnv+" or "+keystore.PassphraseEnv+")")
+	migrateKeystore := flag.Bool("migrate-keystore", false, "Encrypt the identity secrets and TSS key shares of an existing data dir, then exit")
+	signerEndpoint := flag.String("signer", "", "Endpoint of a remote signing daemon holding the BLS and Hive active keys, e.g. unix:///run/vsc-signer.sock or http://127.0.0.1:7080 (token in "+signer.TokenEnv+")")
+
+	snapshotCid := flag.String("snapshot", "", "CID of an attested state snapshot to fast sync a new node from")
 
 	flag.Parse()
 
@@ -40,6 +54,10 @@ func ParseArgs() (args, error) {
 		*disableTss,
 		*logLevel,
 		*sysconfigPath,
+		*keystoreKeyfile,
+		*migrateKeystore,
+		*signerEndpoint,
+		*snapshotCid,
 	}, nil
 }
 
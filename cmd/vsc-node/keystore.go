@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"vsc-node/lib/keystore"
+	"vsc-node/modules/common"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	flatfs "github.com/ipfs/go-ds-flatfs"
+)
+
+func keystoreHeaderPath(dataDir string) string {
+	return path.Join(dataDir, "config", "keystore.json")
+}
+
+// Unlocks the keystore of the data dir. Returns nil if the data dir has not
+// been encrypted, unless initializing a new node with an unlock secret set.
+func openKeystore(args args) (*keystore.Keystore, error) {
+	headerPath := keystoreHeaderPath(args.dataDir)
+
+	secret, err := keystore.LoadSecret(args.keystoreKeyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !keystore.Exists(headerPath) {
+		if args.isInit && secret != nil {
+			return keystore.Create(headerPath, secret)
+		}
+		return nil, nil
+	}
+
+	if secret == nil {
+		return nil, keystore.ErrLocked
+	}
+
+	return keystore.Open(headerPath, secret)
+}
+
+// Encrypts the identity secrets and TSS key shares of an existing data dir.
+// Already encrypted values are left untouched, so an interrupted migration
+// can be resumed by running it again.
+func migrateKeystore(args args) error {
+	headerPath := keystoreHeaderPath(args.dataDir)
+
+	secret, err := keystore.LoadSecret(args.keystoreKeyfile)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return keystore.ErrLocked
+	}
+
+	var ks *keystore.Keystore
+	if keystore.Exists(headerPath) {
+		ks, err = keystore.Open(headerPath, secret)
+	} else {
+		ks, err = keystore.Create(headerPath, secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	identityConfig := common.NewIdentityConfig(args.dataDir)
+	identityConfig.UseKeystore(ks)
+	if err := identityConfig.Init(); err != nil {
+		return fmt.Errorf("failed to load identity config: %w", err)
+	}
+	if err := identityConfig.Save(); err != nil {
+		return fmt.Errorf("failed to write identity config: %w", err)
+	}
+	fmt.Println("encrypted identity config")
+
+	flatDb, err := flatfs.CreateOrOpen(path.Join(args.dataDir, "tss-keys"), flatfs.Prefix(1), false)
+	if err != nil {
+		return err
+	}
+	defer flatDb.Close()
+
+	ctx := context.Background()
+	results, err := flatDb.Query(ctx, query.Query{})
+	if err != nil {
+		return err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return err
+	}
+
+	encrypted := keystore.WrapDatastore(flatDb, ks)
+	migrated := 0
+	for _, e := range entries {
+		if keystore.IsEncrypted(e.Value) {
+			continue
+		}
+		if err := encrypted.Put(ctx, datastore.NewKey(e.Key), e.Value); err != nil {
+			return fmt.Errorf("failed to encrypt key share %s: %w", e.Key, err)
+		}
+		migrated++
+	}
+	fmt.Printf("encrypted %d of %d TSS key shares\n", migrated, len(entries))
+
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	cbortypes "vsc-node/lib/cbor-types"
 	"vsc-node/lib/datalayer"
 	"vsc-node/lib/hive"
+	"vsc-node/lib/keystore"
+	"vsc-node/lib/signer"
 	"vsc-node/modules/aggregate"
 	"vsc-node/modules/announcements"
 	blockproducer "vsc-node/modules/block-producer"
@@ -17,12 +19,15 @@ import (
 	systemconfig "vsc-node/modules/common/system-config"
 	"vsc-node/modules/db"
 	"vsc-node/modules/db/vsc"
+	"vsc-node/modules/db/vsc/authorities"
+	"vsc-node/modules/db/vsc/bridge"
 	"vsc-node/modules/db/vsc/contracts"
 	"vsc-node/modules/db/vsc/elections"
 	"vsc-node/modules/db/vsc/hive_blocks"
 	ledgerDb "vsc-node/modules/db/vsc/ledger"
 	"vsc-node/modules/db/vsc/nonces"
 	rcDb "vsc-node/modules/db/vsc/rcs"
+	"vsc-node/modules/db/vsc/snapshots"
 	"vsc-node/modules/db/vsc/transactions"
 	tss_db "vsc-node/modules/db/vsc/tss"
 	vscBlocks "vsc-node/modules/db/vsc/vsc_blocks"
@@ -35,6 +40,7 @@ import (
 	"vsc-node/modules/hive/streamer"
 	"vsc-node/modules/oracle"
 	p2pInterface "vsc-node/modules/p2p"
+	"vsc-node/modules/snapshot"
 	stateEngine "vsc-node/modules/state-processing"
 	transactionpool "vsc-node/modules/transaction-pool"
 	"vsc-node/modules/tss"
@@ -43,6 +49,7 @@ import (
 	wasm_runtime "vsc-node/modules/wasm/runtime_ipc"
 	wasm_sdk "vsc-node/modules/wasm/sdk"
 
+	"github.com/ipfs/go-datastore"
 	flatfs "github.com/ipfs/go-ds-flatfs"
 	"github.com/vsc-eco/hivego"
 )
@@ -56,12 +63,23 @@ func main() {
 	}
 	initLogLevel(args.logLevel)
 
+	if args.migrateKeystore {
+		if err := migrateKeystore(args); err != nil {
+			fmt.Println("Error migrating keystore:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Keystore migration complete")
+		return
+	}
+
 	dbConf := db.NewDbConfig(args.dataDir)
 	p2pConf := p2pInterface.NewConfig(args.dataDir)
 	gqlConf := gql.NewGqlConfig(args.dataDir)
 	oracleConf := oracle.NewOracleConfig(args.dataDir)
 	hiveApiUrl := streamer.NewHiveConfig(args.dataDir)
 	hiveApiUrlErr := hiveApiUrl.Init()
+	//The backend picks the collection implementations, so load it up front
+	dbConfErr := dbConf.Init()
 
 	hiveURIs := hiveApiUrl.Get().HiveURIs
 
@@ -84,11 +102,16 @@ func main() {
 	actionsDb := ledgerDb.NewActionsDb(vscDb)
 	interestClaims := ledgerDb.NewInterestClaimDb(vscDb)
 	contractState := contracts.NewContractState(vscDb)
+	contractUpgrades := contracts.NewContractUpgrades(vscDb)
+	contractSchedules := contracts.NewContractSchedules(vscDb)
 	nonceDb := nonces.New(vscDb)
+	authorityDb := authorities.New(vscDb)
 	rcDb := rcDb.New(vscDb)
 	tssKeys := tss_db.NewKeys(vscDb)
 	tssCommitments := tss_db.NewCommitments(vscDb)
 	tssRequests := tss_db.NewRequests(vscDb)
+	bridgeDb := bridge.New(vscDb)
+	snapshotDb := snapshots.New(vscDb)
 	sysConfig := systemconfig.FromNetwork(args.network)
 	wasm_sdk.Init(sysConfig.OnMainnet())
 	if args.sysconfigPath != "" {
@@ -108,6 +131,9 @@ func main() {
 	} else if hiveApiUrlErr != nil {
 		fmt.Println("Failed to parse Hive API config", hiveApiUrlErr)
 		os.Exit(1)
+	} else if dbConfErr != nil {
+		fmt.Println("Failed to parse db config", dbConfErr)
+		os.Exit(1)
 	}
 
 	if sysConfig.OnMainnet() && args.disableTss {
@@ -115,6 +141,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	//Snapshots are read from and written to the mongo collections
+	if args.snapshotCid != "" && dbConf.GetBackend() == db.BackendBadger {
+		fmt.Println("--snapshot is not supported on the badger db backend, sync with the mongo backend instead")
+		os.Exit(1)
+	}
+
 	// choose the source
 	hiveRpcClient := hivego.NewHiveRpc(hiveURIs)
 	hiveRpcClient.ChainID = sysConfig.HiveChainId()
@@ -131,12 +163,28 @@ func main() {
 	streamerPlugin := streamer.NewStreamer(hiveRpcClient, hiveBlocks, filters, vFilters, &stBlock) // optional starting block #
 
 	identityConfig := common.NewIdentityConfig(args.dataDir)
+	ks, err := openKeystore(args)
+	if err != nil {
+		fmt.Println("Error unlocking keystore:", err)
+		os.Exit(1)
+	}
+	if ks != nil {
+		identityConfig.UseKeystore(ks)
+	}
+	if args.signerEndpoint != "" {
+		remoteSigner, err := signer.NewRemote(args.signerEndpoint, os.Getenv(signer.TokenEnv))
+		if err != nil {
+			fmt.Println("Error connecting to signer:", err)
+			os.Exit(1)
+		}
+		identityConfig.UseSigner(remoteSigner)
+	}
 
 	hiveCreator := hive.LiveTransactionCreator{
 		TransactionCrafter: hive.TransactionCrafter{},
 		TransactionBroadcaster: hive.TransactionBroadcaster{
-			Client:  hiveRpcClient,
-			KeyPair: identityConfig.HiveActiveKeyPair,
+			Client: hiveRpcClient,
+			Signer: identityConfig.Signer(),
 		},
 	}
 
@@ -171,6 +219,8 @@ func main() {
 		electionDb,
 		contractDb,
 		contractState,
+		contractUpgrades,
+		contractSchedules,
 		txDb,
 		ledgerDbImpl,
 		balanceDb,
@@ -180,9 +230,11 @@ func main() {
 		actionsDb,
 		rcDb,
 		nonceDb,
+		authorityDb,
 		tssKeys,
 		tssCommitments,
 		tssRequests,
+		bridgeDb,
 		wasm,
 	)
 
@@ -205,11 +257,27 @@ func main() {
 		blockConsumer,
 	)
 
+	snapshotMgr := snapshot.New(
+		sysConfig,
+		identityConfig,
+		p2p,
+		vscDb,
+		da,
+		electionDb,
+		hiveBlocks,
+		snapshotDb,
+		blockConsumer,
+		se,
+		hiveRpcClient,
+		args.snapshotCid,
+		&stBlock,
+	)
+
 	bp := blockproducer.New(p2p, blockConsumer, se, identityConfig, sysConfig, &hiveCreator, da, electionDb, vscBlocks, txDb, rcSystem, nonceDb)
 
-	txpool := transactionpool.New(p2p, txDb, nonceDb, electionDb, hiveBlocks, da, identityConfig, rcSystem)
+	txpool := transactionpool.New(p2p, se.Events.PoolTransactions(txDb), nonceDb, authorityDb, electionDb, hiveBlocks, da, identityConfig, sysConfig, rcSystem)
 
-	oracle := oracle.New(p2p, identityConfig, sysConfig, electionDb, witnessDb, blockConsumer, se, contractState, da, txpool, oracleConf, nonceDb)
+	oracle := oracle.New(p2p, identityConfig, sysConfig, electionDb, witnessDb, blockConsumer, se, contractState, da, txpool, oracleConf, nonceDb, &hiveCreator)
 
 	multisig := gateway.New(
 		sysConfig,
@@ -231,6 +299,10 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	var tssKeyStore datastore.Batching = flatDb
+	if ks != nil {
+		tssKeyStore = keystore.WrapDatastore(flatDb, ks)
+	}
 
 	tssMgr := tss.New(
 		p2p,
@@ -243,31 +315,37 @@ func main() {
 		se,
 		identityConfig,
 		sysConfig,
-		flatDb,
+		tssKeyStore,
 		&hiveCreator,
 	)
 
 	gqlManager := gql.New(gqlgen.NewExecutableSchema(gqlgen.Config{Complexity: gql.NewComplexityRoot(), Resolvers: &gqlgen.Resolver{
-		Witnesses:      witnessDb,
-		TxPool:         txpool,
-		Balances:       balanceDb,
-		Ledger:         ledgerDbImpl,
-		Actions:        actionsDb,
-		Elections:      electionDb,
-		Transactions:   txDb,
-		Nonces:         nonceDb,
-		Rc:             rcDb,
-		HiveBlocks:     hiveBlocks,
-		StateEngine:    se,
-		Da:             da,
-		Contracts:      contractDb,
-		ContractsState: contractState,
-		TssKeys:        tssKeys,
-		TssCommitments: tssCommitments,
-		TssRequests:    tssRequests,
-		InterestClaims: interestClaims,
-		ChainOracle:    oracle.ChainOracle(),
+		Witnesses:         witnessDb,
+		TxPool:            txpool,
+		Balances:          balanceDb,
+		Ledger:            ledgerDbImpl,
+		Actions:           actionsDb,
+		Elections:         electionDb,
+		Transactions:      txDb,
+		Nonces:            nonceDb,
+		Authorities:       authorityDb,
+		Rc:                rcDb,
+		HiveBlocks:        hiveBlocks,
+		StateEngine:       se,
+		Da:                da,
+		Contracts:         contractDb,
+		ContractsState:    contractState,
+		ContractUpgrades:  contractUpgrades,
+		ContractSchedules: contractSchedules,
+		TssKeys:           tssKeys,
+		TssCommitments:    tssCommitments,
+		TssRequests:       tssRequests,
+		Bridge:            bridgeDb,
+		InterestClaims:    interestClaims,
+		VscBlocks:         vscBlocks,
+		ChainOracle:       oracle.ChainOracle(),
 	}}), gqlConf)
+	gqlManager.Handle("GET "+gql.MempoolPath, gql.MempoolHandler(txpool, sysConfig))
 
 	plugins := make([]aggregate.Plugin, 0)
 
@@ -296,16 +374,22 @@ func main() {
 		balanceDb,
 		rcDb,
 		nonceDb,
+		authorityDb,
 		interestClaims,
 		contractState,
+		contractUpgrades,
+		contractSchedules,
 		tssKeys,
 		tssCommitments,
 		tssRequests,
+		bridgeDb,
+		snapshotDb,
 
 		p2p,
 		da,                   //Deps: [p2p]
 		dataAvailability,     //Deps: [p2p]
 		announcementsManager, // Deps: [p2p]
+		snapshotMgr,          // Deps: [p2p, da]; imports --snapshot before the streamer starts
 
 		blockConsumer,
 		//Startup main state processing pipeline
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"vsc-node/lib/keystore"
+	"vsc-node/lib/signer"
+)
+
+type args struct {
+	dataDir         string
+	listen          string
+	keystoreKeyfile string
+}
+
+func ParseArgs() (args, error) {
+	flag.Usage = func() {
+		fmt.Printf("Signing daemon holding the BLS and Hive active keys of a VSC node.\n\n")
+		fmt.Printf("Usage: %s [options]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	dataDir := flag.String("data-dir", "data", "Data directory holding the identity config")
+	listen := flag.String("listen", "unix://vsc-signer.sock", "Endpoint to serve on, a unix socket (unix:///run/vsc-signer.sock) or a local HTTP address (http://127.0.0.1:7080). Requests must carry the token in "+signer.TokenEnv+" if set")
+	keystoreKeyfile := flag.String("keystore-keyfile", "", "Path to the keyfile unlocking the encrypted keystore (alternatively set "+keystore.KeyfileEnv+" or "+keystore.PassphraseEnv+")")
+
+	flag.Parse()
+
+	return args{
+		*dataDir,
+		*listen,
+		*keystoreKeyfile,
+	}, nil
+}
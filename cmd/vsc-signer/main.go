@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+
+	"vsc-node/lib/keystore"
+	"vsc-node/lib/signer"
+	"vsc-node/modules/common"
+)
+
+func main() {
+	args, err := ParseArgs()
+	if err != nil {
+		fmt.Println("Error parsing arguments:", err)
+		os.Exit(1)
+	}
+
+	identityConfig := common.NewIdentityConfig(args.dataDir)
+	if _, err := os.Stat(identityConfig.FilePath()); err != nil {
+		fmt.Println("Error loading identity config:", err)
+		os.Exit(1)
+	}
+
+	headerPath := path.Join(args.dataDir, "config", "keystore.json")
+	if keystore.Exists(headerPath) {
+		secret, err := keystore.LoadSecret(args.keystoreKeyfile)
+		if err != nil {
+			fmt.Println("Error unlocking keystore:", err)
+			os.Exit(1)
+		}
+		ks, err := keystore.Open(headerPath, secret)
+		if err != nil {
+			fmt.Println("Error unlocking keystore:", err)
+			os.Exit(1)
+		}
+		identityConfig.UseKeystore(ks)
+	}
+
+	if err := identityConfig.Init(); err != nil {
+		fmt.Println("Error loading identity config:", err)
+		os.Exit(1)
+	}
+
+	s := identityConfig.Signer()
+	blsDid, err := s.BlsDID()
+	if err != nil {
+		fmt.Println("Error loading BLS key:", err)
+		os.Exit(1)
+	}
+
+	token := os.Getenv(signer.TokenEnv)
+	listener, err := signer.Listen(args.listen)
+	if err != nil {
+		fmt.Println("Error listening:", err)
+		os.Exit(1)
+	}
+
+	server := &http.Server{Handler: signer.NewHandler(s, token)}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	fmt.Println("Signing for", identityConfig.Get().HiveUsername, blsDid.String(), "on", args.listen)
+	if token == "" {
+		fmt.Println("Warning:", signer.TokenEnv, "is not set, requests are not authenticated")
+	}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Println("Error serving:", err)
+		os.Exit(1)
+	}
+}
@@ -299,6 +299,72 @@ func enumerateLeaf(lf *LeafDir, prefix string, result map[string]cid.Cid) error
 	return nil
 }
 
+// Walk calls fn with the keys within [start, end) in lexicographic order until it returns false.
+// An empty end is unbounded. Directories entirely outside of the range are not read.
+// Returns the number of links read, which the caller can charge for.
+func (db *DataBin) Walk(start string, end string, fn func(key string) bool) (int, error) {
+	visited := 0
+	_, err := walkLeaf(&db.Leaf, "", start, end, fn, &visited)
+	return visited, err
+}
+
+func walkLeaf(lf *LeafDir, prefix string, start string, end string, fn func(key string) bool, visited *int) (bool, error) {
+	links, err := lf.Dir.Links(context.Background())
+	if err != nil {
+		return false, err
+	}
+	*visited += len(links)
+
+	//Directories are ordered by their name followed by "/", the same as the keys within them.
+	//Leaves are used rather than links so uncompacted directories are included.
+	names := make([]string, 0, len(links))
+	for _, link := range links {
+		if link.Cid.Prefix().Codec != uint64(multicodec.Protobuf) && lf.leaves[link.Name] == nil {
+			names = append(names, link.Name)
+		}
+	}
+	for name := range lf.leaves {
+		names = append(names, name+"/")
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := prefix + name
+		if end != "" && key >= end {
+			return false, nil
+		}
+		if dirName, isDir := strings.CutSuffix(name, "/"); isDir {
+			if after := PrefixEnd(key); after != "" && after <= start {
+				continue
+			}
+			cont, err := walkLeaf(lf.leaves[dirName], key, start, end, fn, visited)
+			if err != nil || !cont {
+				return cont, err
+			}
+			continue
+		}
+		if key < start {
+			continue
+		}
+		if !fn(key) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Smallest key greater than every key starting with prefix, or "" when there is none
+func PrefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
 // Must compact to be safe. If single level
 func (db *DataBin) Cid() cid.Cid {
 	db.Leaf.Compact(true)
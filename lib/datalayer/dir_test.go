@@ -253,3 +253,38 @@ func TestDeterministicCidManyKeysReloadAndModify(t *testing.T) {
 	assert.Equal(t, cidA, cidB,
 		"Two nodes loading same HAMT base and applying same diffs should produce identical CIDs")
 }
+
+// TestDataBinWalk verifies that keys are walked in lexicographic order across
+// subdirectories and that walking stops at the end of the range.
+func TestDataBinWalk(t *testing.T) {
+	da := getDirTestDA(t)
+	value := merkledag.NewRawNode([]byte("walk-value"))
+	require.Nil(t, da.DagServ.Add(context.Background(), value))
+
+	db := DataLayer.NewDataBin(da)
+	for _, key := range []string{"b/c/d", "a0", "a/y", "a-b", "a/x", "c"} {
+		require.Nil(t, db.Set(key, value.Cid()))
+	}
+
+	walk := func(start string, end string, limit int) []string {
+		keys := make([]string, 0)
+		_, err := db.Walk(start, end, func(key string) bool {
+			keys = append(keys, key)
+			return len(keys) < limit
+		})
+		require.Nil(t, err)
+		return keys
+	}
+
+	assert.Equal(t, []string{"a-b", "a/x", "a/y", "a0", "b/c/d", "c"}, walk("", "", 100))
+	assert.Equal(t, []string{"a/y", "a0", "b/c/d"}, walk("a/y", "c", 100))
+	assert.Equal(t, []string{"a/x", "a/y"}, walk("a/", DataLayer.PrefixEnd("a/"), 100))
+	assert.Equal(t, []string{"a-b", "a/x"}, walk("", "", 2))
+
+	//Directories before the start are skipped without being read
+	visitedAll, err := db.Walk("", "", func(string) bool { return true })
+	require.Nil(t, err)
+	visitedTail, err := db.Walk("c", "", func(string) bool { return true })
+	require.Nil(t, err)
+	assert.Less(t, visitedTail, visitedAll)
+}
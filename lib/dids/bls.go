@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"maps"
 	"math/big"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -683,15 +684,27 @@ func (b *BlsCircuit) Verify() (bool, []BlsDID, error) {
 		return false, nil, fmt.Errorf("no public keys to verify")
 	}
 
-	// aggregate the pub keys
-	// agg all the pub keys at once
-	pubKey, _ := bls.AggregatePubkeys(includedPubKeys)
-	// aggWorks := aggPub.Aggregate(includedPubKeys, true)
-	// aggPubKey := aggPub.ToAffine()
-
-	// verify the aggregated sig
-	verified := bls.Verify(pubKey, b.msg.Bytes(), b.aggSigs)
+	// verify the aggregated sig against the aggregated pub keys
+	verified := VerifyAggregate(includedPubKeys, b.msg.Bytes(), b.aggSigs)
 	// verified := b.aggSigs.FastAggregateVerify(true, includedPubKeys, b.msg.Bytes(), nil)
 
 	return verified, includedDIDs, nil
 }
+
+// verifies an aggregate sig over the same msg by every pub key
+//
+// the pub keys are expected to be proven (like election members are), since
+// aggregating unproven keys is open to rogue key attacks
+func VerifyAggregate(pubKeys []*BlsPubKey, msg []byte, sig *BlsSig) bool {
+	if len(pubKeys) == 0 || sig == nil || slices.Contains(pubKeys, nil) {
+		return false
+	}
+
+	// agg all the pub keys at once
+	pubKey, err := bls.AggregatePubkeys(pubKeys)
+	if err != nil {
+		return false
+	}
+
+	return bls.Verify(pubKey, msg, sig)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"math/rand"
 	"time"
+	"vsc-node/lib/signer"
 
 	"github.com/vsc-eco/hivego"
 )
@@ -35,6 +36,8 @@ type HiveTransactionCreator interface {
 type TransactionBroadcaster struct {
 	Client  *hivego.HiveRpcNode
 	KeyPair func() (*hivego.KeyPair, error)
+	//Signs with the active key of the signer instead of KeyPair when set
+	Signer signer.Signer
 }
 
 func (t *TransactionBroadcaster) Broadcast(tx hivego.HiveTransaction) (string, error) {
@@ -173,6 +176,9 @@ func (t *TransactionCrafter) CancelTransferFromSavings(from string, requestId in
 }
 
 func (t *TransactionBroadcaster) Sign(tx hivego.HiveTransaction) (string, error) {
+	if t.Signer != nil {
+		return signer.SignHiveTx(t.Signer, signer.ActiveKey, tx, t.Client.ChainID)
+	}
 	kp, err := t.KeyPair()
 	if err != nil {
 		return "", err
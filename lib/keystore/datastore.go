@@ -0,0 +1,75 @@
+package keystore
+
+import (
+	"context"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+)
+
+// Datastore encrypting every value written to the wrapped datastore.
+// Values are bound to their key, so they cannot be moved to another key.
+type Datastore struct {
+	datastore.Batching
+	ks *Keystore
+}
+
+var _ datastore.Batching = &Datastore{}
+
+func WrapDatastore(ds datastore.Batching, ks *Keystore) *Datastore {
+	return &Datastore{ds, ks}
+}
+
+func (d *Datastore) Get(ctx context.Context, key datastore.Key) ([]byte, error) {
+	sealed, err := d.Batching.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return d.ks.Decrypt(sealed, key.Bytes())
+}
+
+func (d *Datastore) GetSize(ctx context.Context, key datastore.Key) (int, error) {
+	value, err := d.Get(ctx, key)
+	if err != nil {
+		return -1, err
+	}
+	return len(value), nil
+}
+
+func (d *Datastore) Put(ctx context.Context, key datastore.Key, value []byte) error {
+	return d.Batching.Put(ctx, key, d.ks.Encrypt(value, key.Bytes()))
+}
+
+// Filters and orders may depend on values, so they are applied after decryption
+func (d *Datastore) Query(ctx context.Context, q query.Query) (query.Results, error) {
+	results, err := d.Batching.Query(ctx, query.Query{
+		Prefix:   q.Prefix,
+		KeysOnly: q.KeysOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := results.Rest()
+	if err != nil {
+		return nil, err
+	}
+
+	if !q.KeysOnly {
+		for i, e := range entries {
+			value, err := d.ks.Decrypt(e.Value, []byte(e.Key))
+			if err != nil {
+				return nil, err
+			}
+			entries[i].Value = value
+			entries[i].Size = len(value)
+		}
+	}
+
+	return query.NaiveQueryApply(q, query.ResultsWithEntries(q, entries)), nil
+}
+
+// Batches go through Put so their values are encrypted as well
+func (d *Datastore) Batch(ctx context.Context) (datastore.Batch, error) {
+	return datastore.NewBasicBatch(d), nil
+}
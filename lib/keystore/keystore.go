@@ -0,0 +1,253 @@
+// Encryption at rest for node secrets.
+//
+// The encryption key is derived with argon2id from a passphrase or keyfile
+// and used with AES-256-GCM. The KDF salt and parameters are stored in a
+// header file next to the node config, along with a sealed check value so a
+// wrong secret fails at unlock rather than on the first decrypt.
+//
+// Sealed values are prefixed with a magic so encrypted and legacy plaintext
+// data can be told apart during migration.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Environment variables holding the unlock secret
+const PassphraseEnv = "VSC_KEYSTORE_PASSPHRASE"
+const KeyfileEnv = "VSC_KEYSTORE_KEYFILE"
+
+const headerVersion = 1
+const checkValue = "vsc-keystore-check"
+
+// Prefix of sealed byte values and encoded string values
+var magic = []byte("vscks1")
+
+const stringPrefix = "enc:v1:"
+
+var (
+	ErrLocked          = fmt.Errorf("keystore is locked: set %s or %s, or pass a keyfile", PassphraseEnv, KeyfileEnv)
+	ErrWrongSecret     = errors.New("failed to unlock keystore: wrong passphrase or keyfile")
+	ErrNotEncrypted    = errors.New("value is not encrypted")
+	ErrNotInitialized  = errors.New("keystore not initialized")
+	ErrAlreadyExists   = errors.New("keystore already initialized")
+	ErrMalformedHeader = errors.New("malformed keystore header")
+)
+
+// Persisted KDF parameters. Never contains the key itself.
+type header struct {
+	Version int    `json:"version"`
+	Kdf     string `json:"kdf"`
+	Salt    string `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	Check   string `json:"check"`
+}
+
+type Keystore struct {
+	aead cipher.AEAD
+}
+
+// Reads the unlock secret. An explicit keyfile takes precedence over the
+// environment. Returns nil if no secret is configured.
+func LoadSecret(keyfile string) ([]byte, error) {
+	if keyfile == "" {
+		keyfile = os.Getenv(KeyfileEnv)
+	}
+	if keyfile != "" {
+		b, err := os.ReadFile(keyfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyfile: %w", err)
+		}
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			return nil, fmt.Errorf("keyfile is empty")
+		}
+		return b, nil
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	return nil, nil
+}
+
+// Returns true if a keystore header exists at the path
+func Exists(headerPath string) bool {
+	_, err := os.Stat(headerPath)
+	return err == nil
+}
+
+// Creates a new keystore header at the path and returns the unlocked keystore
+func Create(headerPath string, secret []byte) (*Keystore, error) {
+	if len(secret) == 0 {
+		return nil, ErrLocked
+	}
+	if Exists(headerPath) {
+		return nil, ErrAlreadyExists
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	h := header{
+		Version: headerVersion,
+		Kdf:     "argon2id",
+		Salt:    hex.EncodeToString(salt),
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	ks, err := unlock(h, secret)
+	if err != nil {
+		return nil, err
+	}
+	h.Check = ks.EncryptString(checkValue, "check")
+
+	b, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(headerPath), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(headerPath, b, 0600); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Unlocks an existing keystore
+func Open(headerPath string, secret []byte) (*Keystore, error) {
+	b, err := os.ReadFile(headerPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotInitialized
+		}
+		return nil, err
+	}
+	if len(secret) == 0 {
+		return nil, ErrLocked
+	}
+
+	h := header{}
+	if err := json.Unmarshal(b, &h); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedHeader, err)
+	}
+	if h.Version != headerVersion || h.Kdf != "argon2id" {
+		return nil, fmt.Errorf("%w: unsupported version %d (%s)", ErrMalformedHeader, h.Version, h.Kdf)
+	}
+
+	ks, err := unlock(h, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := ks.DecryptString(h.Check, "check")
+	if err != nil || check != checkValue {
+		return nil, ErrWrongSecret
+	}
+
+	return ks, nil
+}
+
+func unlock(h header, secret []byte) (*Keystore, error) {
+	salt, err := hex.DecodeString(h.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, ErrMalformedHeader
+	}
+
+	key := argon2.IDKey(secret, salt, h.Time, h.Memory, h.Threads, 32)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keystore{aead}, nil
+}
+
+// Returns true if the value was sealed by a keystore
+func IsEncrypted(value []byte) bool {
+	return bytes.HasPrefix(value, magic)
+}
+
+// Returns true if the string was encoded by EncryptString
+func IsEncryptedString(value string) bool {
+	return strings.HasPrefix(value, stringPrefix)
+}
+
+// Seals the value. The associated data (e.g. the storage key) must be
+// supplied again to decrypt, which prevents swapping values between keys.
+func (ks *Keystore) Encrypt(plaintext []byte, ad []byte) []byte {
+	nonce := make([]byte, ks.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(fmt.Errorf("failed to generate nonce: %w", err))
+	}
+
+	out := make([]byte, 0, len(magic)+len(nonce)+len(plaintext)+ks.aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, nonce...)
+	return ks.aead.Seal(out, nonce, plaintext, ad)
+}
+
+func (ks *Keystore) Decrypt(sealed []byte, ad []byte) ([]byte, error) {
+	if !IsEncrypted(sealed) {
+		return nil, ErrNotEncrypted
+	}
+
+	sealed = sealed[len(magic):]
+	nonceSize := ks.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf("sealed value too short")
+	}
+
+	plaintext, err := ks.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], ad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Seals a string for storage in text formats such as JSON configs
+func (ks *Keystore) EncryptString(plaintext string, ad string) string {
+	return stringPrefix + base64.StdEncoding.EncodeToString(ks.Encrypt([]byte(plaintext), []byte(ad)))
+}
+
+func (ks *Keystore) DecryptString(value string, ad string) (string, error) {
+	if !IsEncryptedString(value) {
+		return "", ErrNotEncrypted
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, stringPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	plaintext, err := ks.Decrypt(sealed, []byte(ad))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
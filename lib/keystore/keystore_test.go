@@ -0,0 +1,117 @@
+package keystore_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"vsc-node/lib/keystore"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndOpen(t *testing.T) {
+	headerPath := filepath.Join(t.TempDir(), "config", "keystore.json")
+
+	_, err := keystore.Open(headerPath, []byte("secret"))
+	require.ErrorIs(t, err, keystore.ErrNotInitialized)
+
+	ks, err := keystore.Create(headerPath, []byte("secret"))
+	require.NoError(t, err)
+	require.True(t, keystore.Exists(headerPath))
+
+	_, err = keystore.Create(headerPath, []byte("secret"))
+	require.ErrorIs(t, err, keystore.ErrAlreadyExists)
+
+	_, err = keystore.Open(headerPath, nil)
+	require.ErrorIs(t, err, keystore.ErrLocked)
+
+	_, err = keystore.Open(headerPath, []byte("wrong"))
+	require.ErrorIs(t, err, keystore.ErrWrongSecret)
+
+	sealed := ks.EncryptString("5Jsecretwif", "HiveActiveKey")
+	assert.True(t, keystore.IsEncryptedString(sealed))
+	assert.NotContains(t, sealed, "5Jsecretwif")
+
+	reopened, err := keystore.Open(headerPath, []byte("secret"))
+	require.NoError(t, err)
+
+	plaintext, err := reopened.DecryptString(sealed, "HiveActiveKey")
+	require.NoError(t, err)
+	assert.Equal(t, "5Jsecretwif", plaintext)
+
+	// values are bound to their associated data
+	_, err = reopened.DecryptString(sealed, "BlsPrivKeySeed")
+	assert.Error(t, err)
+
+	_, err = reopened.DecryptString("5Jsecretwif", "HiveActiveKey")
+	assert.ErrorIs(t, err, keystore.ErrNotEncrypted)
+}
+
+func TestLoadSecret(t *testing.T) {
+	t.Setenv(keystore.KeyfileEnv, "")
+	t.Setenv(keystore.PassphraseEnv, "")
+
+	secret, err := keystore.LoadSecret("")
+	require.NoError(t, err)
+	assert.Nil(t, secret)
+
+	t.Setenv(keystore.PassphraseEnv, "passphrase")
+	secret, err = keystore.LoadSecret("")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("passphrase"), secret)
+
+	_, err = keystore.LoadSecret(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestEncryptedDatastore(t *testing.T) {
+	ks, err := keystore.Create(filepath.Join(t.TempDir(), "keystore.json"), []byte("secret"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	inner := dssync.MutexWrap(datastore.NewMapDatastore())
+	ds := keystore.WrapDatastore(inner, ks)
+
+	key := datastore.NewKey("/key-1-0")
+	require.NoError(t, ds.Put(ctx, key, []byte("share")))
+
+	raw, err := inner.Get(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, keystore.IsEncrypted(raw))
+	assert.NotContains(t, string(raw), "share")
+
+	value, err := ds.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("share"), value)
+
+	size, err := ds.GetSize(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, len("share"), size)
+
+	batch, err := ds.Batch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, batch.Put(ctx, datastore.NewKey("/key-2-0"), []byte("batched")))
+	require.NoError(t, batch.Commit(ctx))
+
+	raw, err = inner.Get(ctx, datastore.NewKey("/key-2-0"))
+	require.NoError(t, err)
+	assert.True(t, keystore.IsEncrypted(raw))
+
+	results, err := ds.Query(ctx, query.Query{Orders: []query.Order{query.OrderByKey{}}})
+	require.NoError(t, err)
+	entries, err := results.Rest()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []byte("share"), entries[0].Value)
+	assert.Equal(t, []byte("batched"), entries[1].Value)
+
+	// a value moved to another key fails to decrypt
+	sealed, _ := inner.Get(ctx, key)
+	require.NoError(t, inner.Put(ctx, datastore.NewKey("/key-3-0"), sealed))
+	_, err = ds.Get(ctx, datastore.NewKey("/key-3-0"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"vsc-node/lib/dids"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
+	"github.com/ipfs/go-cid"
+	blsu "github.com/protolambda/bls12-381-util"
+	"github.com/vsc-eco/hivego"
+)
+
+// Private keys of a local signer, as stored in the identity config
+type Keys struct {
+	BlsPrivKeySeed string
+	HiveActiveKey  string
+}
+
+// In-process signer. Keys are read on every call so updates to the
+// underlying config apply immediately.
+type local struct {
+	keys func() Keys
+}
+
+var _ Signer = &local{}
+
+func NewLocal(keys func() Keys) Signer {
+	return &local{keys}
+}
+
+func (l *local) blsSeed() ([]byte, error) {
+	blsPrivSeed, err := hex.DecodeString(l.keys().BlsPrivKeySeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bls priv seed: %w", err)
+	}
+	if len(blsPrivSeed) != 32 {
+		return nil, fmt.Errorf("bls priv seed must be 32 bytes")
+	}
+	return blsPrivSeed, nil
+}
+
+func (l *local) blsKey() (*dids.BlsPrivKey, error) {
+	blsPrivSeed, err := l.blsSeed()
+	if err != nil {
+		return nil, err
+	}
+
+	blsPrivKey := &dids.BlsPrivKey{}
+	var arr [32]byte
+	copy(arr[:], blsPrivSeed)
+	if err = blsPrivKey.Deserialize(&arr); err != nil {
+		return nil, fmt.Errorf("failed to deserialize bls priv key: %w", err)
+	}
+	return blsPrivKey, nil
+}
+
+func (l *local) hiveKey(key HiveKey) (*hivego.KeyPair, error) {
+	switch key {
+	case ActiveKey:
+		return hivego.KeyPairFromWif(l.keys().HiveActiveKey)
+	case GatewayKey:
+		blsPrivSeed, err := l.blsSeed()
+		if err != nil {
+			return nil, err
+		}
+		gatewayKey := sha256.Sum256(append(blsPrivSeed, []byte("gateway_key")...))
+		return hivego.KeyPairFromBytes(gatewayKey[:]), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+	}
+}
+
+func (l *local) BlsDID() (dids.BlsDID, error) {
+	blsPrivKey, err := l.blsKey()
+	if err != nil {
+		return "", err
+	}
+
+	pubKey, err := blsu.SkToPk(blsPrivKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to get bls pub key: %w", err)
+	}
+
+	blsDid, err := dids.NewBlsDID(pubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bls did: %w", err)
+	}
+	return blsDid, nil
+}
+
+func (l *local) SignCid(c cid.Cid) ([96]byte, error) {
+	blsPrivKey, err := l.blsKey()
+	if err != nil {
+		return [96]byte{}, err
+	}
+
+	sig := blsu.Sign(blsPrivKey, c.Bytes())
+	if sig == nil {
+		return [96]byte{}, fmt.Errorf("signing failed")
+	}
+	return sig.Serialize(), nil
+}
+
+func (l *local) HivePublicKey(key HiveKey) (string, error) {
+	kp, err := l.hiveKey(key)
+	if err != nil {
+		return "", err
+	}
+	return *kp.GetPublicKeyString(), nil
+}
+
+func (l *local) SignHiveDigest(key HiveKey, digest []byte) ([]byte, error) {
+	if err := checkDigest(digest); err != nil {
+		return nil, err
+	}
+	kp, err := l.hiveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1.SignCompact(kp.PrivateKey, digest, true)
+}
@@ -0,0 +1,177 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"vsc-node/lib/dids"
+
+	"github.com/ipfs/go-cid"
+)
+
+// Environment variable holding the bearer token shared with the signing daemon
+const TokenEnv = "VSC_SIGNER_TOKEN"
+
+const remoteTimeout = 10 * time.Second
+
+// Signer delegating to a signing daemon. The node only ever sees public keys
+// and signatures.
+type remote struct {
+	client  *http.Client
+	baseUrl string
+	token   string
+}
+
+var _ Signer = &remote{}
+
+// Connects to a signing daemon at the endpoint, either a unix socket
+// (unix:///run/vsc-signer.sock) or an HTTP URL (http://127.0.0.1:7080).
+func NewRemote(endpoint string, token string) (Signer, error) {
+	network, address, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &remote{
+		client: &http.Client{Timeout: remoteTimeout},
+		token:  token,
+	}
+
+	if network == "unix" {
+		r.client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", address)
+			},
+		}
+		// host is ignored when dialing the socket
+		r.baseUrl = "http://signer"
+	} else {
+		r.baseUrl = strings.TrimSuffix(endpoint, "/")
+	}
+
+	return r, nil
+}
+
+// Splits an endpoint into the network to listen on or dial and its address
+func parseEndpoint(endpoint string) (network string, address string, err error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid signer endpoint: %w", err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		// unix://relative.sock parses the file name as the host
+		address = u.Host + u.Path
+		if address == "" {
+			address = u.Opaque
+		}
+		if address == "" {
+			return "", "", fmt.Errorf("invalid signer endpoint: missing socket path")
+		}
+		return "unix", address, nil
+	case "http", "https":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("invalid signer endpoint: missing host")
+		}
+		return "tcp", u.Host, nil
+	default:
+		return "", "", fmt.Errorf("invalid signer endpoint: unsupported scheme %q", u.Scheme)
+	}
+}
+
+func (r *remote) call(path string, req any, res any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, r.baseUrl+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	httpRes, err := r.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("signer unreachable: %w", err)
+	}
+	defer httpRes.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(httpRes.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+
+	if httpRes.StatusCode != http.StatusOK {
+		errRes := errorResponse{}
+		if json.Unmarshal(resBody, &errRes) == nil && errRes.Error != "" {
+			return fmt.Errorf("signer: %s", errRes.Error)
+		}
+		return fmt.Errorf("signer: unexpected status %s", httpRes.Status)
+	}
+
+	return json.Unmarshal(resBody, res)
+}
+
+func (r *remote) BlsDID() (dids.BlsDID, error) {
+	res := blsDidResponse{}
+	if err := r.call(pathBlsDid, struct{}{}, &res); err != nil {
+		return "", err
+	}
+	return dids.BlsDID(res.Did), nil
+}
+
+func (r *remote) SignCid(c cid.Cid) ([96]byte, error) {
+	res := signatureResponse{}
+	if err := r.call(pathBlsSign, signCidRequest{Cid: c.String()}, &res); err != nil {
+		return [96]byte{}, err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(res.Sig)
+	if err != nil || len(sig) != 96 {
+		return [96]byte{}, fmt.Errorf("signer returned malformed bls signature")
+	}
+
+	var out [96]byte
+	copy(out[:], sig)
+	return out, nil
+}
+
+func (r *remote) HivePublicKey(key HiveKey) (string, error) {
+	res := publicKeyResponse{}
+	if err := r.call(pathHivePublicKey, publicKeyRequest{Key: key}, &res); err != nil {
+		return "", err
+	}
+	return res.PublicKey, nil
+}
+
+func (r *remote) SignHiveDigest(key HiveKey, digest []byte) ([]byte, error) {
+	if err := checkDigest(digest); err != nil {
+		return nil, err
+	}
+
+	res := signatureResponse{}
+	req := signDigestRequest{Key: key, Digest: hex.EncodeToString(digest)}
+	if err := r.call(pathHiveSign, req, &res); err != nil {
+		return nil, err
+	}
+
+	sig, err := hex.DecodeString(res.Sig)
+	if err != nil || len(sig) != 65 {
+		return nil, fmt.Errorf("signer returned malformed hive signature")
+	}
+	return sig, nil
+}
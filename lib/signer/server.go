@@ -0,0 +1,188 @@
+package signer
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/ipfs/go-cid"
+)
+
+const (
+	pathBlsDid        = "/v1/bls/did"
+	pathBlsSign       = "/v1/bls/sign"
+	pathHivePublicKey = "/v1/hive/public_key"
+	pathHiveSign      = "/v1/hive/sign"
+)
+
+const maxBodySize = 64 * 1024
+
+type signCidRequest struct {
+	Cid string `json:"cid"`
+}
+
+type publicKeyRequest struct {
+	Key HiveKey `json:"key"`
+}
+
+type signDigestRequest struct {
+	Key    HiveKey `json:"key"`
+	Digest string  `json:"digest"`
+}
+
+type blsDidResponse struct {
+	Did string `json:"did"`
+}
+
+type publicKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+type signatureResponse struct {
+	Sig string `json:"sig"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Serves the signer to remote signers. Requests must carry the token as a
+// bearer token unless it is empty.
+func NewHandler(s Signer, token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+pathBlsDid, func(w http.ResponseWriter, r *http.Request) {
+		did, err := s.BlsDID()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJson(w, blsDidResponse{Did: did.String()})
+	})
+
+	mux.HandleFunc("POST "+pathBlsSign, func(w http.ResponseWriter, r *http.Request) {
+		req := signCidRequest{}
+		if !readJson(w, r, &req) {
+			return
+		}
+		c, err := cid.Parse(req.Cid)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid cid: %w", err))
+			return
+		}
+		sig, err := s.SignCid(c)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJson(w, signatureResponse{Sig: base64.StdEncoding.EncodeToString(sig[:])})
+	})
+
+	mux.HandleFunc("POST "+pathHivePublicKey, func(w http.ResponseWriter, r *http.Request) {
+		req := publicKeyRequest{}
+		if !readJson(w, r, &req) {
+			return
+		}
+		if !req.Key.valid() {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %s", ErrUnknownKey, req.Key))
+			return
+		}
+		pubKey, err := s.HivePublicKey(req.Key)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJson(w, publicKeyResponse{PublicKey: pubKey})
+	})
+
+	mux.HandleFunc("POST "+pathHiveSign, func(w http.ResponseWriter, r *http.Request) {
+		req := signDigestRequest{}
+		if !readJson(w, r, &req) {
+			return
+		}
+		if !req.Key.valid() {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %s", ErrUnknownKey, req.Key))
+			return
+		}
+		digest, err := hex.DecodeString(req.Digest)
+		if err == nil {
+			err = checkDigest(digest)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid digest: %w", err))
+			return
+		}
+		sig, err := s.SignHiveDigest(req.Key, digest)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJson(w, signatureResponse{Sig: hex.EncodeToString(sig)})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			expected := []byte("Bearer " + token)
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+				writeError(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Listens on a signer endpoint. Unix sockets are only accessible by the
+// owner, and a stale socket left by a previous run is replaced.
+func Listen(endpoint string) (net.Listener, error) {
+	network, address, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+
+	if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func readJson(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err == nil {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
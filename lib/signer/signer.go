@@ -0,0 +1,66 @@
+// Signing abstraction for the node's BLS and Hive keys.
+//
+// Modules never read private keys directly. They go through a Signer, which
+// is either backed by the local identity config or by a separate signing
+// daemon reached over a unix socket or HTTP, so operators can keep keys in
+// an isolated process or HSM backed service.
+package signer
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"vsc-node/lib/dids"
+
+	"github.com/ipfs/go-cid"
+	"github.com/vsc-eco/hivego"
+)
+
+// Hive key held by the signer
+type HiveKey string
+
+const (
+	// The node account's active key
+	ActiveKey HiveKey = "active"
+	// Key of the gateway multisig, derived from the BLS seed
+	GatewayKey HiveKey = "gateway"
+)
+
+var ErrUnknownKey = errors.New("unknown hive key")
+
+type Signer interface {
+	// DID of the BLS key
+	BlsDID() (dids.BlsDID, error)
+	// BLS signs the bytes of the CID and returns the compressed signature
+	SignCid(c cid.Cid) ([96]byte, error)
+	// Public key in Hive's STM format
+	HivePublicKey(key HiveKey) (string, error)
+	// secp256k1 signs a 32 byte transaction digest and returns the compact signature
+	SignHiveDigest(key HiveKey, digest []byte) ([]byte, error)
+}
+
+func (k HiveKey) valid() bool {
+	return k == ActiveKey || k == GatewayKey
+}
+
+// Signs a Hive transaction with the key. Returns the hex encoded signature,
+// as produced by hivego.HiveTransaction.Sign.
+func SignHiveTx(s Signer, key HiveKey, tx hivego.HiveTransaction, chainId string) (string, error) {
+	message, err := hivego.SerializeTx(tx)
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := s.SignHiveDigest(key, hivego.HashTxForSig(message, chainId))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sig), nil
+}
+
+func checkDigest(digest []byte) error {
+	if len(digest) != 32 {
+		return fmt.Errorf("digest must be 32 bytes, got %d", len(digest))
+	}
+	return nil
+}
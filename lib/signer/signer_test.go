@@ -0,0 +1,130 @@
+package signer_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"vsc-node/lib/signer"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	blsu "github.com/protolambda/bls12-381-util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vsc-eco/hivego"
+)
+
+const testWif = "5JpboCuFbypjdBzSgWwWm3ZjGpQoPbSc7bcoXYg26TWQZ7uwFRM"
+const testBlsSeed = "9a1f0ff2f2a38e42c0a5b2d1bd3e5b8c1f6a3d9e2b7c4a1f0e3d2c1b0a998877"
+
+func testKeys() signer.Keys {
+	return signer.Keys{
+		BlsPrivKeySeed: testBlsSeed,
+		HiveActiveKey:  testWif,
+	}
+}
+
+func testCid(t *testing.T) cid.Cid {
+	mh, err := multihash.Sum([]byte("block"), multihash.SHA2_256, -1)
+	require.NoError(t, err)
+	return cid.NewCidV1(cid.DagCBOR, mh)
+}
+
+func testTx() hivego.HiveTransaction {
+	return hivego.HiveTransaction{
+		RefBlockNum:    1234,
+		RefBlockPrefix: 5678,
+		Expiration:     "2030-01-01T00:00:01",
+		Operations: []hivego.HiveOperation{hivego.CustomJsonOperation{
+			RequiredAuths:        []string{"vsc.node1"},
+			RequiredPostingAuths: []string{},
+			Id:                   "vsc.test",
+			Json:                 "{}",
+		}},
+		Signatures: []string{},
+	}
+}
+
+func assertSigner(t *testing.T, s signer.Signer) {
+	c := testCid(t)
+
+	did, err := s.BlsDID()
+	require.NoError(t, err)
+
+	sigBytes, err := s.SignCid(c)
+	require.NoError(t, err)
+	sig := new(blsu.Signature)
+	require.NoError(t, sig.Deserialize(&sigBytes))
+	assert.True(t, blsu.Verify(did.Identifier(), c.Bytes(), sig))
+
+	kp, err := hivego.KeyPairFromWif(testWif)
+	require.NoError(t, err)
+
+	pubKey, err := s.HivePublicKey(signer.ActiveKey)
+	require.NoError(t, err)
+	assert.Equal(t, *kp.GetPublicKeyString(), pubKey)
+
+	tx := testTx()
+	expected, err := tx.Sign(*kp)
+	require.NoError(t, err)
+	actual, err := signer.SignHiveTx(s, signer.ActiveKey, tx, "")
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	seed, _ := hex.DecodeString(testBlsSeed)
+	gatewayKey := sha256.Sum256(append(seed, []byte("gateway_key")...))
+	gatewayPubKey, err := s.HivePublicKey(signer.GatewayKey)
+	require.NoError(t, err)
+	assert.Equal(t, *hivego.KeyPairFromBytes(gatewayKey[:]).GetPublicKeyString(), gatewayPubKey)
+
+	_, err = s.HivePublicKey("owner")
+	assert.Error(t, err)
+
+	_, err = s.SignHiveDigest(signer.ActiveKey, []byte("short"))
+	assert.Error(t, err)
+}
+
+func TestLocal(t *testing.T) {
+	assertSigner(t, signer.NewLocal(testKeys))
+
+	_, err := signer.NewLocal(func() signer.Keys { return signer.Keys{BlsPrivKeySeed: "00"} }).BlsDID()
+	assert.Error(t, err)
+}
+
+func TestRemoteHttp(t *testing.T) {
+	server := httptest.NewServer(signer.NewHandler(signer.NewLocal(testKeys), "token"))
+	defer server.Close()
+
+	s, err := signer.NewRemote(server.URL, "token")
+	require.NoError(t, err)
+	assertSigner(t, s)
+
+	unauthorized, err := signer.NewRemote(server.URL, "wrong")
+	require.NoError(t, err)
+	_, err = unauthorized.BlsDID()
+	assert.ErrorContains(t, err, "unauthorized")
+}
+
+func TestRemoteUnixSocket(t *testing.T) {
+	endpoint := "unix://" + filepath.Join(t.TempDir(), "signer.sock")
+
+	listener, err := signer.Listen(endpoint)
+	require.NoError(t, err)
+	server := &http.Server{Handler: signer.NewHandler(signer.NewLocal(testKeys), "")}
+	go server.Serve(listener)
+	defer server.Close()
+
+	s, err := signer.NewRemote(endpoint, "")
+	require.NoError(t, err)
+	assertSigner(t, s)
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "unix://", "tcp://127.0.0.1:80", "http://"} {
+		_, err := signer.NewRemote(endpoint, "")
+		assert.Error(t, err, endpoint)
+	}
+}
@@ -0,0 +1,326 @@
+// Inclusion proofs for contract state.
+//
+// A proof links a contract state value to a VSC block header signed by the
+// election through the following chain of content addressed blocks:
+//
+//	signed header -> block content -> contract output -> state tree nodes -> value
+//
+// Verify only needs the proof itself and the BLS keys of the election that
+// signed the header, so it can be used without trusting the node serving it.
+//
+// Ledger balances are out of scope: they are kept in the ledger database
+// rather than a content addressed tree, and block headers do not commit to
+// them, so there is nothing a balance could be proven against.
+package stateproof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"vsc-node/lib/dids"
+	"vsc-node/modules/common"
+
+	"github.com/ipfs/boxo/blockservice"
+	"github.com/ipfs/boxo/blockstore"
+	"github.com/ipfs/boxo/exchange/offline"
+	"github.com/ipfs/boxo/ipld/merkledag"
+	uio "github.com/ipfs/boxo/ipld/unixfs/io"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/multiformats/go-multicodec"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Proof that a contract state key held a value as of a signed VSC block
+type Proof struct {
+	ContractId string `json:"contract_id"`
+	Key        string `json:"key"`
+	OutputId   string `json:"output_id"`
+	StateRoot  string `json:"state_root"`
+	//Election epoch whose members signed the header
+	Epoch  uint64 `json:"epoch"`
+	Header Header `json:"header"`
+	//Block content, contract output, state tree nodes and the value itself
+	Blocks []Block `json:"blocks"`
+}
+
+// A content addressed block included in a proof
+type Block struct {
+	Cid  string `json:"cid"`
+	Data []byte `json:"data"`
+}
+
+// VSC block header along with the BLS signature of the election
+type Header struct {
+	Type       string                 `json:"type"`
+	Version    string                 `json:"version"`
+	Prevb      *string                `json:"prevb"`
+	Br         [2]int                 `json:"br"`
+	MerkleRoot *string                `json:"merkle_root"`
+	Block      string                 `json:"block"`
+	Signature  dids.SerializedCircuit `json:"signature"`
+}
+
+// Members of the election that signed a header.
+// Keys must be in election order, as the signature bit vector refers to them by index.
+// When Weights is nil, every member counts equally.
+type Election struct {
+	Keys    []string
+	Weights []uint64
+}
+
+// Same encoding as vscBlocks.VscHeader, which is what block producers sign
+type unsignedHeader struct {
+	Version    string        `refmt:"__v"`
+	Type       string        `refmt:"__t"`
+	Headers    headerHeaders `refmt:"headers"`
+	MerkleRoot *string       `refmt:"merkle_root"`
+	Block      cid.Cid       `refmt:"block"`
+}
+
+type headerHeaders struct {
+	Br    [2]int  `refmt:"br"`
+	Prevb *string `refmt:"prevb"`
+}
+
+// Same encoding as vscBlocks.VscBlock
+type blockContent struct {
+	Transactions []blockTx    `refmt:"txs"`
+	Headers      blockHeaders `refmt:"headers"`
+	MerkleRoot   *string      `refmt:"merkle_root"`
+	SigRoot      *string      `refmt:"sig_root"`
+}
+
+type blockHeaders struct {
+	Prevb *string `refmt:"prevb"`
+}
+
+type blockTx struct {
+	Id   string  `refmt:"id"`
+	Op   *string `refmt:"op"`
+	Type int     `refmt:"type"`
+}
+
+func init() {
+	cbornode.RegisterCborType(unsignedHeader{})
+	cbornode.RegisterCborType(headerHeaders{})
+	cbornode.RegisterCborType(blockContent{})
+	cbornode.RegisterCborType(blockHeaders{})
+	cbornode.RegisterCborType(blockTx{})
+}
+
+// CID of the header, which is the message signed by the election
+func (h Header) Cid() (cid.Cid, error) {
+	blockCid, err := cid.Parse(h.Block)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("invalid block cid: %w", err)
+	}
+	header := unsignedHeader{
+		Version:    h.Version,
+		Type:       h.Type,
+		Headers:    headerHeaders{Br: h.Br, Prevb: h.Prevb},
+		MerkleRoot: h.MerkleRoot,
+		Block:      blockCid,
+	}
+
+	cborBytes, err := cbornode.Encode(header)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return cid.Prefix{
+		Version:  1,
+		Codec:    uint64(multicodec.DagCbor),
+		MhType:   mh.SHA2_256,
+		MhLength: -1,
+	}.Sum(cborBytes)
+}
+
+// Resolves a "/" separated key within a state tree.
+// Like DataBin.Get, directories are not values and resolve to os.ErrNotExist.
+func Resolve(ctx context.Context, dag ipld.DAGService, root cid.Cid, key string) (ipld.Node, error) {
+	node, err := dag.Get(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range strings.Split(key, "/") {
+		dir, err := uio.NewDirectoryFromNode(dag, node)
+		if err != nil {
+			return nil, os.ErrNotExist
+		}
+		node, err = dir.Find(ctx, segment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if node.Cid().Prefix().Codec == uint64(multicodec.Protobuf) {
+		return nil, os.ErrNotExist
+	}
+	return node, nil
+}
+
+// Collects the state tree nodes needed to resolve key, ordered from the root down to the value
+func CollectStatePath(ctx context.Context, dag ipld.DAGService, root cid.Cid, key string) ([]Block, error) {
+	rec := &recordingDag{DAGService: dag}
+	if _, err := Resolve(ctx, rec, root, key); err != nil {
+		return nil, err
+	}
+	return rec.blocks, nil
+}
+
+// Verifies a proof and returns the raw value of the proven key
+func Verify(proof Proof, election Election) ([]byte, error) {
+	ctx := context.Background()
+
+	dag, err := newProofDag(ctx, proof.Blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	headerCid, err := proof.Header.Cid()
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(headerCid, proof.Header.Signature, election); err != nil {
+		return nil, err
+	}
+
+	blockCid, _ := cid.Parse(proof.Header.Block)
+	content := blockContent{}
+	if err := getObject(ctx, dag, blockCid, &content); err != nil {
+		return nil, fmt.Errorf("block content: %w", err)
+	}
+	included := slices.ContainsFunc(content.Transactions, func(tx blockTx) bool {
+		return tx.Id == proof.OutputId && tx.Type == int(common.BlockTypeOutput)
+	})
+	if !included {
+		return nil, fmt.Errorf("output %s is not included in block %s", proof.OutputId, blockCid)
+	}
+
+	outputCid, err := cid.Parse(proof.OutputId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output id: %w", err)
+	}
+	output := map[string]interface{}{}
+	if err := getObject(ctx, dag, outputCid, &output); err != nil {
+		return nil, fmt.Errorf("contract output: %w", err)
+	}
+	if output["contract_id"] != proof.ContractId {
+		return nil, fmt.Errorf("output %s does not belong to contract %s", proof.OutputId, proof.ContractId)
+	}
+	if output["state_merkle"] != proof.StateRoot {
+		return nil, fmt.Errorf("output %s does not commit to state root %s", proof.OutputId, proof.StateRoot)
+	}
+
+	root, err := cid.Parse(proof.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state root: %w", err)
+	}
+	node, err := Resolve(ctx, dag, root, proof.Key)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", proof.Key, err)
+	}
+	return node.RawData(), nil
+}
+
+// Mirrors TxProposeBlock.Validate: more than 2/3 of the election weight must have signed
+func verifySignature(msg cid.Cid, sig dids.SerializedCircuit, election Election) error {
+	keyset := make([]dids.BlsDID, 0, len(election.Keys))
+	for _, key := range election.Keys {
+		keyset = append(keyset, dids.BlsDID(key))
+	}
+	circuit, err := dids.DeserializeBlsCircuit(sig, keyset, msg)
+	if err != nil {
+		return err
+	}
+	verified, _, err := circuit.Verify()
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return errors.New("invalid header signature")
+	}
+
+	bv := circuit.RawBitVector()
+	var signed, total uint64
+	if election.Weights == nil {
+		signed = uint64(len(circuit.IncludedDIDs()))
+		total = uint64(len(election.Keys))
+	} else {
+		for idx, weight := range election.Weights {
+			if bv.Bit(idx) == 1 {
+				signed += weight
+			}
+			total += weight
+		}
+	}
+	if signed <= (total*2)/3 {
+		return fmt.Errorf("header signed by %d of %d election weight", signed, total)
+	}
+	return nil
+}
+
+// In memory DAG holding only the blocks of a proof.
+// Every block is checked against its CID, so anything resolved from it is authentic.
+func newProofDag(ctx context.Context, proofBlocks []Block) (ipld.DAGService, error) {
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	for _, b := range proofBlocks {
+		c, err := cid.Parse(b.Cid)
+		if err != nil {
+			return nil, err
+		}
+		sum, err := c.Prefix().Sum(b.Data)
+		if err != nil {
+			return nil, err
+		}
+		if !sum.Equals(c) {
+			return nil, fmt.Errorf("block %s does not match its data", c)
+		}
+		blk, err := blocks.NewBlockWithCid(b.Data, c)
+		if err != nil {
+			return nil, err
+		}
+		if err := bs.Put(ctx, blk); err != nil {
+			return nil, err
+		}
+	}
+	return merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs))), nil
+}
+
+func getObject(ctx context.Context, dag ipld.DAGService, c cid.Cid, v interface{}) error {
+	node, err := dag.Get(ctx, c)
+	if err != nil {
+		return err
+	}
+	return cbornode.DecodeInto(node.RawData(), v)
+}
+
+// Records every node fetched through the wrapped DAG service
+type recordingDag struct {
+	ipld.DAGService
+	blocks []Block
+}
+
+func (r *recordingDag) Get(ctx context.Context, c cid.Cid) (ipld.Node, error) {
+	node, err := r.DAGService.Get(ctx, c)
+	if err == nil {
+		r.blocks = append(r.blocks, Block{Cid: c.String(), Data: node.RawData()})
+	}
+	return node, err
+}
+
+func (r *recordingDag) GetMany(ctx context.Context, cids []cid.Cid) <-chan *ipld.NodeOption {
+	out := make(chan *ipld.NodeOption, len(cids))
+	for _, c := range cids {
+		node, err := r.Get(ctx, c)
+		out <- &ipld.NodeOption{Node: node, Err: err}
+	}
+	close(out)
+	return out
+}
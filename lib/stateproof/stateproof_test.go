@@ -0,0 +1,214 @@
+package stateproof_test
+
+import (
+	"context"
+	"testing"
+	cbortypes "vsc-node/lib/cbor-types"
+	"vsc-node/lib/dids"
+	"vsc-node/lib/stateproof"
+	vscBlocks "vsc-node/modules/db/vsc/vsc_blocks"
+
+	"github.com/ipfs/boxo/ipld/merkledag"
+	mdtest "github.com/ipfs/boxo/ipld/merkledag/test"
+	uio "github.com/ipfs/boxo/ipld/unixfs/io"
+	"github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	ipld "github.com/ipfs/go-ipld-format"
+	mh "github.com/multiformats/go-multihash"
+	ethBls "github.com/protolambda/bls12-381-util"
+	"github.com/stretchr/testify/require"
+)
+
+type signer struct {
+	did      dids.BlsDID
+	provider dids.BlsProvider
+}
+
+func newSigner(t *testing.T, seed string) signer {
+	var seedBytes [32]byte
+	copy(seedBytes[:], seed)
+	privKey := dids.BlsPrivKey{}
+	privKey.Deserialize(&seedBytes)
+	pubKey, err := ethBls.SkToPk(&privKey)
+	require.NoError(t, err)
+	did, err := dids.NewBlsDID(pubKey)
+	require.NoError(t, err)
+	provider, err := dids.NewBlsProvider(&privKey)
+	require.NoError(t, err)
+	return signer{did, provider}
+}
+
+func putDir(t *testing.T, dag ipld.DAGService, entries map[string]ipld.Node) ipld.Node {
+	ctx := context.Background()
+	dir := uio.NewDirectory(dag)
+	for name, node := range entries {
+		require.NoError(t, dag.Add(ctx, node))
+		require.NoError(t, dir.AddChild(ctx, name, node))
+	}
+	node, err := dir.GetNode()
+	require.NoError(t, err)
+	require.NoError(t, dag.Add(ctx, node))
+	return node
+}
+
+func putObject(t *testing.T, dag ipld.DAGService, obj interface{}) ipld.Node {
+	node, err := cbornode.WrapObject(obj, mh.SHA2_256, -1)
+	require.NoError(t, err)
+	require.NoError(t, dag.Add(context.Background(), node))
+	return node
+}
+
+func rawBlock(t *testing.T, dag ipld.DAGService, c cid.Cid) stateproof.Block {
+	node, err := dag.Get(context.Background(), c)
+	require.NoError(t, err)
+	return stateproof.Block{Cid: c.String(), Data: node.RawData()}
+}
+
+// Builds a proof for users/alice signed by the given subset of members
+func buildProof(t *testing.T, members []signer, signers []signer) stateproof.Proof {
+	ctx := context.Background()
+	dag := mdtest.Mock()
+
+	users := putDir(t, dag, map[string]ipld.Node{
+		"alice": merkledag.NewRawNode([]byte("7")),
+		"bob":   merkledag.NewRawNode([]byte("9")),
+	})
+	root := putDir(t, dag, map[string]ipld.Node{
+		"supply": merkledag.NewRawNode([]byte("16")),
+		"users":  users,
+	})
+	output := putObject(t, dag, map[string]interface{}{
+		"__t":          "vsc-output",
+		"__v":          "0.1",
+		"contract_id":  "vsc1contract",
+		"state_merkle": root.Cid().String(),
+		"inputs":       []string{"tx1"},
+	})
+	content := putObject(t, dag, map[string]interface{}{
+		"txs": []map[string]interface{}{
+			{"id": "bafyreigz4cbcwqytnafmm5tzrhbmowbhoiuvcuc7oxgxnkr3wvc4zb3k4a", "type": 1},
+			{"id": output.Cid().String(), "type": 2},
+		},
+		"headers":     map[string]interface{}{"prevb": nil},
+		"merkle_root": nil,
+		"sig_root":    nil,
+	})
+
+	header := stateproof.Header{
+		Type:    "vsc-bh",
+		Version: "0.1",
+		Br:      [2]int{100, 110},
+		Block:   content.Cid().String(),
+	}
+	msg, err := header.Cid()
+	require.NoError(t, err)
+
+	keyset := make([]dids.Member, 0, len(members))
+	for _, m := range members {
+		keyset = append(keyset, m.did)
+	}
+	partial, err := dids.NewBlsCircuitGenerator(keyset).Generate(msg)
+	require.NoError(t, err)
+	for _, s := range signers {
+		sig, err := s.provider.Sign(msg)
+		require.NoError(t, err)
+		added, err := partial.AddAndVerify(s.did, sig)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	circuit, err := partial.Finalize()
+	require.NoError(t, err)
+	serialized, err := circuit.Serialize()
+	require.NoError(t, err)
+	header.Signature = *serialized
+
+	path, err := stateproof.CollectStatePath(ctx, dag, root.Cid(), "users/alice")
+	require.NoError(t, err)
+
+	return stateproof.Proof{
+		ContractId: "vsc1contract",
+		Key:        "users/alice",
+		OutputId:   output.Cid().String(),
+		StateRoot:  root.Cid().String(),
+		Header:     header,
+		Blocks:     append([]stateproof.Block{rawBlock(t, dag, content.Cid()), rawBlock(t, dag, output.Cid())}, path...),
+	}
+}
+
+func electionOf(members []signer) stateproof.Election {
+	keys := make([]string, 0, len(members))
+	for _, m := range members {
+		keys = append(keys, m.did.String())
+	}
+	return stateproof.Election{Keys: keys}
+}
+
+func TestVerifyStateProof(t *testing.T) {
+	members := []signer{newSigner(t, "stateproof_seed_1"), newSigner(t, "stateproof_seed_2"), newSigner(t, "stateproof_seed_3")}
+	proof := buildProof(t, members, members)
+
+	//Root dir, users dir and the value
+	require.Len(t, proof.Blocks, 5)
+
+	value, err := stateproof.Verify(proof, electionOf(members))
+	require.NoError(t, err)
+	require.Equal(t, "7", string(value))
+
+	wrongContract := proof
+	wrongContract.ContractId = "vsc1other"
+	_, err = stateproof.Verify(wrongContract, electionOf(members))
+	require.Error(t, err)
+
+	wrongKey := proof
+	wrongKey.Key = "users/bob"
+	_, err = stateproof.Verify(wrongKey, electionOf(members))
+	require.Error(t, err, "bob is not part of the proof")
+
+	tampered := proof
+	tampered.Blocks = append([]stateproof.Block{}, proof.Blocks...)
+	last := len(tampered.Blocks) - 1
+	tampered.Blocks[last] = stateproof.Block{Cid: proof.Blocks[last].Cid, Data: []byte("8")}
+	_, err = stateproof.Verify(tampered, electionOf(members))
+	require.Error(t, err)
+
+	_, err = stateproof.Verify(proof, electionOf(members[:2]))
+	require.Error(t, err, "signature must not verify against a different election")
+}
+
+func TestVerifyStateProofRequiresSupermajority(t *testing.T) {
+	members := []signer{newSigner(t, "stateproof_seed_1"), newSigner(t, "stateproof_seed_2"), newSigner(t, "stateproof_seed_3")}
+	proof := buildProof(t, members, members[:2])
+
+	_, err := stateproof.Verify(proof, electionOf(members))
+	require.Error(t, err)
+
+	election := electionOf(members)
+	election.Weights = []uint64{5, 5, 1}
+	value, err := stateproof.Verify(proof, election)
+	require.NoError(t, err)
+	require.Equal(t, "7", string(value))
+}
+
+func TestHeaderCidMatchesBlockHeader(t *testing.T) {
+	cbortypes.RegisterTypes()
+	prevb := "bafyreigz4cbcwqytnafmm5tzrhbmowbhoiuvcuc7oxgxnkr3wvc4zb3k4a"
+	root := "root"
+	header := stateproof.Header{
+		Type:       "vsc-bh",
+		Version:    "0.1",
+		Prevb:      &prevb,
+		Br:         [2]int{100, 110},
+		MerkleRoot: &root,
+		Block:      prevb,
+	}
+	expected := vscBlocks.VscHeader{Type: header.Type, Version: header.Version, MerkleRoot: &root}
+	expected.Headers.Br = header.Br
+	expected.Headers.Prevb = &prevb
+	expected.Block, _ = cid.Parse(prevb)
+
+	expectedNode, err := cbornode.WrapObject(expected, mh.SHA2_256, -1)
+	require.NoError(t, err)
+	actual, err := header.Cid()
+	require.NoError(t, err)
+	require.Equal(t, expectedNode.Cid(), actual)
+}
@@ -26,6 +26,7 @@ import (
 	wasm_context "vsc-node/modules/wasm/context"
 	wasm_runtime "vsc-node/modules/wasm/runtime"
 	wasm_runtime_ipc "vsc-node/modules/wasm/runtime_ipc"
+	wasm_types "vsc-node/modules/wasm/types"
 
 	"github.com/ipfs/go-cid"
 )
@@ -95,6 +96,8 @@ func NewContractTest() ContractTest {
 		&elections,
 		&contractDb,
 		&contractState,
+		NewMockContractUpgradesDb(),
+		NewMockContractSchedulesDb(),
 		nil,
 		&ledgers,
 		&balances,
@@ -104,9 +107,11 @@ func NewContractTest() ContractTest {
 		&actions,
 		&rc,
 		nil,
+		NewMockAuthoritiesDb(),
 		&tssKeys,
 		&tssCommitments,
 		&tssRequests,
+		NewMockBridgeDb(),
 		nil,
 	)
 	var blockStatus common_types.BlockStatusGetter
@@ -168,6 +173,11 @@ func (ct *ContractTest) IncrementBlocks(count uint64) {
 
 // Register a contract from bytecode.
 func (ct *ContractTest) RegisterContract(contractId string, owner string, bytecode []byte) {
+	ct.RegisterContractWithRuntime(contractId, owner, bytecode, wasm_runtime.Go)
+}
+
+// Register a contract from bytecode compiled for the given runtime.
+func (ct *ContractTest) RegisterContractWithRuntime(contractId string, owner string, bytecode []byte, runtime wasm_runtime.Runtime) {
 	cid, err := ct.DataLayer.PutRaw(bytecode, common_types.PutRawOptions{Pin: true})
 	if err != nil {
 		panic(fmt.Errorf("failed to create cid for contract %s", contractId))
@@ -177,7 +187,7 @@ func (ct *ContractTest) RegisterContract(contractId string, owner string, byteco
 		Owner:          owner,
 		Code:           cid.String(),
 		CreationHeight: ct.BlockHeight,
-		Runtime:        wasm_runtime.Go,
+		Runtime:        runtime,
 	})
 }
 
@@ -271,7 +281,7 @@ func (ct *ContractTest) Call(tx stateEngine.TxVscCallContract) ContractTestCallR
 		wasm_context.WasmExecCodeCtxKey,
 		hex.EncodeToString(code),
 	)
-	res := w.Execute(ctx, gas*params.CYCLE_GAS_PER_RC, tx.Action, string(tx.Payload), wasm_runtime.Go)
+	res := w.Execute(ctx, gas*params.CYCLE_GAS_PER_RC, tx.Action, string(tx.Payload), info.Runtime)
 	rcUsed := int64(math.Max(math.Ceil(float64(res.Gas)/params.CYCLE_GAS_PER_RC), 100))
 	ct.RcSession.Consume(rcPayer, ct.BlockHeight, rcUsed)
 	ct.StateEngine.RcMap[rcPayer] = ct.StateEngine.RcMap[rcPayer] + rcUsed
@@ -363,6 +373,25 @@ func (ct *ContractTest) StateDelete(contractId string, key string) {
 	ct.CallSession.Commit()
 }
 
+// List the keys starting with prefix in the contract state storage, as db.list_keys does.
+// Returns the cursor of the next page, or "" if there is none.
+func (ct *ContractTest) StateListKeys(contractId string, prefix string, cursor string, limit int) ([]string, string) {
+	keys, next, _ := ct.CallSession.GetStateStore(contractId).ListKeys(prefix, cursor, limit)
+	return keys, next
+}
+
+// Retrieve the entries with start <= key < end from the contract state storage, as db.range does.
+// Returns the start of the next page, or "" if there is none.
+func (ct *ContractTest) StateRange(contractId string, start string, end string, limit int) ([]wasm_types.StateEntry, string) {
+	store := ct.CallSession.GetStateStore(contractId)
+	keys, next, _ := store.Keys(start, end, limit)
+	entries := make([]wasm_types.StateEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, wasm_types.StateEntry{Key: key, Value: string(store.Get(key))})
+	}
+	return entries, next
+}
+
 // Set the value of a key in the ephemeral contract state
 func (ct *ContractTest) EphemStateSet(contractId string, key string, value string) {
 	ct.CallSession.GetStateStore(contractId).SetEphem(key, []byte(value))
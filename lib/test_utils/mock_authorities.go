@@ -0,0 +1,35 @@
+package test_utils
+
+import (
+	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/authorities"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MockAuthoritiesDb struct {
+	aggregate.Plugin
+	Records map[string][]authorities.AuthorityRecord
+}
+
+func NewMockAuthoritiesDb() *MockAuthoritiesDb {
+	return &MockAuthoritiesDb{Records: make(map[string][]authorities.AuthorityRecord)}
+}
+
+func (m *MockAuthoritiesDb) GetAuthority(account string, blockHeight uint64) (authorities.AuthorityRecord, error) {
+	var best *authorities.AuthorityRecord
+	for i, r := range m.Records[account] {
+		if r.BlockHeight <= blockHeight && (best == nil || r.BlockHeight >= best.BlockHeight) {
+			best = &m.Records[account][i]
+		}
+	}
+	if best == nil {
+		return authorities.AuthorityRecord{}, mongo.ErrNoDocuments
+	}
+	return *best, nil
+}
+
+func (m *MockAuthoritiesDb) SetAuthority(record authorities.AuthorityRecord) error {
+	m.Records[record.Account] = append(m.Records[record.Account], record)
+	return nil
+}
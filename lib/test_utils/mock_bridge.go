@@ -0,0 +1,92 @@
+package test_utils
+
+import (
+	"slices"
+	"strings"
+	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/bridge"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MockBridgeDb struct {
+	aggregate.Plugin
+	Withdrawals map[string]bridge.Withdrawal
+}
+
+func NewMockBridgeDb() *MockBridgeDb {
+	return &MockBridgeDb{Withdrawals: make(map[string]bridge.Withdrawal)}
+}
+
+func (m *MockBridgeDb) InsertWithdrawal(w bridge.Withdrawal) error {
+	if _, ok := m.Withdrawals[w.Id]; !ok {
+		m.Withdrawals[w.Id] = w
+	}
+	return nil
+}
+
+func (m *MockBridgeDb) GetWithdrawal(id string) (bridge.Withdrawal, error) {
+	w, ok := m.Withdrawals[id]
+	if !ok {
+		return bridge.Withdrawal{}, mongo.ErrNoDocuments
+	}
+	return w, nil
+}
+
+func (m *MockBridgeDb) UpdateWithdrawal(w bridge.Withdrawal) error {
+	if _, ok := m.Withdrawals[w.Id]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	m.Withdrawals[w.Id] = w
+	return nil
+}
+
+func (m *MockBridgeDb) FindBySignRequest(keyId string, msg string) ([]bridge.Withdrawal, error) {
+	results := make([]bridge.Withdrawal, 0)
+	for _, w := range m.Withdrawals {
+		if w.KeyId == keyId && w.Msg == msg {
+			results = append(results, w)
+		}
+	}
+	return results, nil
+}
+
+func (m *MockBridgeDb) FindUnkeyed(chain string, limit int) ([]bridge.Withdrawal, error) {
+	results := make([]bridge.Withdrawal, 0)
+	for _, w := range m.Withdrawals {
+		if w.Chain == chain && w.KeyId == "" && w.Status == bridge.WithdrawalPending {
+			results = append(results, w)
+		}
+	}
+	slices.SortFunc(results, func(a, b bridge.Withdrawal) int {
+		if a.BlockHeight != b.BlockHeight {
+			return int(a.BlockHeight) - int(b.BlockHeight)
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return results[:min(limit, len(results))], nil
+}
+
+func (m *MockBridgeDb) FindWithdrawals(account *string, chain *string, status *string, offset int, limit int) ([]bridge.Withdrawal, error) {
+	results := make([]bridge.Withdrawal, 0)
+	for _, w := range m.Withdrawals {
+		if account != nil && w.From != *account && w.To != *account {
+			continue
+		}
+		if chain != nil && w.Chain != *chain {
+			continue
+		}
+		if status != nil && w.Status != *status {
+			continue
+		}
+		results = append(results, w)
+	}
+	slices.SortFunc(results, func(a, b bridge.Withdrawal) int {
+		return int(b.BlockHeight) - int(a.BlockHeight)
+	})
+	if offset >= len(results) {
+		return make([]bridge.Withdrawal, 0), nil
+	}
+	results = results[offset:]
+	return results[:min(limit, len(results))], nil
+}
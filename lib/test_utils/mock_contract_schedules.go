@@ -0,0 +1,92 @@
+package test_utils
+
+import (
+	"cmp"
+	"slices"
+	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/contracts"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MockContractSchedulesDb struct {
+	aggregate.Plugin
+	Schedules map[string]contracts.ContractSchedule
+}
+
+func NewMockContractSchedulesDb() *MockContractSchedulesDb {
+	return &MockContractSchedulesDb{Schedules: make(map[string]contracts.ContractSchedule)}
+}
+
+func (m *MockContractSchedulesDb) InsertSchedule(schedule contracts.ContractSchedule) error {
+	if _, ok := m.Schedules[schedule.Id]; !ok {
+		m.Schedules[schedule.Id] = schedule
+	}
+	return nil
+}
+
+func (m *MockContractSchedulesDb) GetSchedule(id string) (contracts.ContractSchedule, error) {
+	s, ok := m.Schedules[id]
+	if !ok {
+		return contracts.ContractSchedule{}, mongo.ErrNoDocuments
+	}
+	return s, nil
+}
+
+func (m *MockContractSchedulesDb) SetSchedule(schedule contracts.ContractSchedule) error {
+	if _, ok := m.Schedules[schedule.Id]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	m.Schedules[schedule.Id] = schedule
+	return nil
+}
+
+func (m *MockContractSchedulesDb) FindDueSchedules(height uint64, limit int) ([]contracts.ContractSchedule, error) {
+	results := make([]contracts.ContractSchedule, 0)
+	for _, s := range m.Schedules {
+		if s.Status == contracts.ScheduleActive && s.NextHeight <= height {
+			results = append(results, s)
+		}
+	}
+	slices.SortFunc(results, func(a, b contracts.ContractSchedule) int {
+		if c := cmp.Compare(a.NextHeight, b.NextHeight); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return results[:min(limit, len(results))], nil
+}
+
+func (m *MockContractSchedulesDb) CountActiveSchedules(contractId string) (int, error) {
+	count := 0
+	for _, s := range m.Schedules {
+		if s.ContractId == contractId && s.Status == contracts.ScheduleActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockContractSchedulesDb) FindSchedules(contractId *string, status *string, offset int, limit int) ([]contracts.ContractSchedule, error) {
+	results := make([]contracts.ContractSchedule, 0)
+	for _, s := range m.Schedules {
+		if contractId != nil && s.ContractId != *contractId {
+			continue
+		}
+		if status != nil && s.Status != *status {
+			continue
+		}
+		results = append(results, s)
+	}
+	slices.SortFunc(results, func(a, b contracts.ContractSchedule) int {
+		if c := cmp.Compare(b.CreatedHeight, a.CreatedHeight); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Id, a.Id)
+	})
+	if offset >= len(results) {
+		return make([]contracts.ContractSchedule, 0), nil
+	}
+	results = results[offset:]
+	return results[:min(limit, len(results))], nil
+}
@@ -0,0 +1,79 @@
+package test_utils
+
+import (
+	"slices"
+	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/contracts"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MockContractUpgradesDb struct {
+	aggregate.Plugin
+	Upgrades map[string]contracts.ContractUpgrade
+}
+
+func NewMockContractUpgradesDb() *MockContractUpgradesDb {
+	return &MockContractUpgradesDb{Upgrades: make(map[string]contracts.ContractUpgrade)}
+}
+
+func (m *MockContractUpgradesDb) InsertUpgrade(upgrade contracts.ContractUpgrade) error {
+	if _, ok := m.Upgrades[upgrade.TxId]; !ok {
+		m.Upgrades[upgrade.TxId] = upgrade
+	}
+	return nil
+}
+
+func (m *MockContractUpgradesDb) GetPendingUpgrade(contractId string) (contracts.ContractUpgrade, error) {
+	for _, u := range m.Upgrades {
+		if u.ContractId == contractId && u.Status == contracts.UpgradePending {
+			return u, nil
+		}
+	}
+	return contracts.ContractUpgrade{}, mongo.ErrNoDocuments
+}
+
+func (m *MockContractUpgradesDb) FindDueUpgrades(height uint64) ([]contracts.ContractUpgrade, error) {
+	results := make([]contracts.ContractUpgrade, 0)
+	for _, u := range m.Upgrades {
+		if u.Status == contracts.UpgradePending && u.ActivationHeight <= height {
+			results = append(results, u)
+		}
+	}
+	slices.SortFunc(results, func(a, b contracts.ContractUpgrade) int {
+		return int(a.ActivationHeight) - int(b.ActivationHeight)
+	})
+	return results, nil
+}
+
+func (m *MockContractUpgradesDb) SetUpgradeStatus(txId string, status string, height uint64) error {
+	u, ok := m.Upgrades[txId]
+	if !ok {
+		return mongo.ErrNoDocuments
+	}
+	u.Status = status
+	u.UpdatedHeight = height
+	m.Upgrades[txId] = u
+	return nil
+}
+
+func (m *MockContractUpgradesDb) FindUpgrades(contractId *string, status *string, offset int, limit int) ([]contracts.ContractUpgrade, error) {
+	results := make([]contracts.ContractUpgrade, 0)
+	for _, u := range m.Upgrades {
+		if contractId != nil && u.ContractId != *contractId {
+			continue
+		}
+		if status != nil && u.Status != *status {
+			continue
+		}
+		results = append(results, u)
+	}
+	slices.SortFunc(results, func(a, b contracts.ContractUpgrade) int {
+		return int(b.ProposedHeight) - int(a.ProposedHeight)
+	})
+	if offset >= len(results) {
+		return make([]contracts.ContractUpgrade, 0), nil
+	}
+	results = results[offset:]
+	return results[:min(limit, len(results))], nil
+}
@@ -3,6 +3,7 @@ package test_utils
 import (
 	"vsc-node/modules/aggregate"
 	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
 
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -44,6 +45,7 @@ func (m *MockContractStateDb) IngestOutput(inputArgs contracts.IngestOutputArgs)
 		Metadata:    inputArgs.Metadata,
 		Inputs:      inputArgs.Inputs,
 		Results:     inputArgs.Results,
+		AnchoredId:  inputArgs.AnchoredId,
 	}
 
 	m.Outputs[output.Id] = output
@@ -63,14 +65,17 @@ func (m *MockContractStateDb) GetLastOutput(contractId string, height uint64) (c
 	}
 
 	if !found {
-		return contracts.ContractOutput{}, nil // Return empty output and nil error to match original implementation
+		return contracts.ContractOutput{}, mongo.ErrNoDocuments
 	}
 
 	return lastOutput, nil
 }
 
 func (m *MockContractStateDb) GetOutput(outputId string) *contracts.ContractOutput {
-	result := m.Outputs[outputId]
+	result, ok := m.Outputs[outputId]
+	if !ok {
+		return nil
+	}
 	return &result
 }
 
@@ -78,3 +83,13 @@ func (m *MockContractStateDb) GetOutput(outputId string) *contracts.ContractOutp
 func (m *MockContractStateDb) FindOutputs(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]contracts.ContractOutput, error) {
 	return []contracts.ContractOutput{}, nil
 }
+
+// GraphQL use only, not implemented in mocks
+func (m *MockContractStateDb) FindOutputsPage(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[contracts.ContractOutput], error) {
+	return hive_blocks.Page[contracts.ContractOutput]{}, nil
+}
+
+// GraphQL use only, not implemented in mocks
+func (m *MockContractStateDb) FindEvents(contract *string, name *string, topic *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[contracts.ContractEventRecord], error) {
+	return hive_blocks.Page[contracts.ContractEventRecord]{}, nil
+}
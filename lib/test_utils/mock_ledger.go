@@ -4,6 +4,7 @@ import (
 	"slices"
 	"strings"
 	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	ledgerDb "vsc-node/modules/db/vsc/ledger"
 )
 
@@ -100,6 +101,11 @@ func (m *MockLedgerDb) GetLedgersTsRange(account *string, txId *string, txTypes
 	return make([]ledgerDb.LedgerRecord, 0), nil
 }
 
+// GraphQL use only, not implemented in mocks
+func (m *MockLedgerDb) GetLedgersTsPage(account *string, txId *string, txTypes []string, asset *ledgerDb.Asset, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ledgerDb.LedgerRecord], error) {
+	return hive_blocks.Page[ledgerDb.LedgerRecord]{}, nil
+}
+
 // GraphQL use only, not implemented in mocks
 func (m *MockLedgerDb) GetRawLedgerRange(account *string, txId *string, txTypes []string, asset *ledgerDb.Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ledgerDb.LedgerRecord, error) {
 	return make([]ledgerDb.LedgerRecord, 0), nil
@@ -202,6 +208,11 @@ func (m *MockActionsDb) GetActionsRange(txId *string, actionId *string, account
 	return make([]ledgerDb.ActionRecord, 0), nil
 }
 
+// GraphQL use only, not implemented in mocks
+func (m *MockActionsDb) GetActionsPage(txId *string, actionId *string, account *string, byTypes []string, asset *ledgerDb.Asset, status *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ledgerDb.ActionRecord], error) {
+	return hive_blocks.Page[ledgerDb.ActionRecord]{}, nil
+}
+
 func (m *MockActionsDb) GetAccountPendingConsensusUnstake(account string) (int64, error) {
 	result := int64(0)
 	for _, action := range m.Actions {
@@ -221,3 +232,21 @@ func (m *MockActionsDb) GetActionsByTxId(txId string) ([]ledgerDb.ActionRecord,
 	}
 	return result, nil
 }
+
+func (m *MockActionsDb) GetDueScheduledTransfers(bh uint64) ([]ledgerDb.ActionRecord, error) {
+	result := make([]ledgerDb.ActionRecord, 0)
+	for _, action := range m.Actions {
+		if action.Type != "scheduled_transfer" || action.Status != "pending" {
+			continue
+		}
+		if h, ok := action.Params["release_height"].(uint64); ok && h <= bh {
+			result = append(result, action)
+		}
+	}
+	return result, nil
+}
+
+// GraphQL use only, not implemented in mocks
+func (m *MockActionsDb) GetScheduledTransfers(account *string, status *string, offset int, limit int) ([]ledgerDb.ActionRecord, error) {
+	return make([]ledgerDb.ActionRecord, 0), nil
+}
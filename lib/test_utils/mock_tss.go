@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"slices"
 	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	tss "vsc-node/modules/db/vsc/tss"
 )
 
@@ -189,6 +190,20 @@ func (m *MockTssCommitmentsDb) FindCommitments(keyId *string, byTypes []string,
 	return results, nil
 }
 
+// Cursors are not implemented in mocks, only the first page is returned
+func (m *MockTssCommitmentsDb) FindCommitmentsPage(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[tss.TssCommitment], error) {
+	results := m.filterAndSort(keyId, byTypes, epoch, fromBlock, toBlock)
+	hasMore := len(results) > page.Limit
+	if hasMore {
+		results = results[:page.Limit]
+	}
+	cursors := make([]hive_blocks.PageCursor, 0, len(results))
+	for _, r := range results {
+		cursors = append(cursors, hive_blocks.PageCursor{Height: r.BlockHeight, Id: []string{r.TxId, r.KeyId}})
+	}
+	return hive_blocks.Page[tss.TssCommitment]{Items: results, Cursors: cursors, HasMore: hasMore}, nil
+}
+
 func (m *MockTssCommitmentsDb) FindCommitmentsSimple(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, limit int) ([]tss.TssCommitment, error) {
 	results := m.filterAndSort(keyId, byTypes, epoch, fromBlock, toBlock)
 	if limit > 0 && limit < len(results) {
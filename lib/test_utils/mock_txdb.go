@@ -1,7 +1,9 @@
 package test_utils
 
 import (
+	"slices"
 	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	"vsc-node/modules/db/vsc/transactions"
 )
 
@@ -22,7 +24,11 @@ func (m *MockTxDb) Ingest(offTx transactions.IngestTransactionUpdate) error {
 		Ops:                  offTx.Ops,
 		OpTypes:              offTx.OpTypes,
 		RcLimit:              offTx.RcLimit,
+		Tip:                  offTx.Tip,
+		TipAsset:             offTx.TipAsset,
+		Payer:                offTx.Payer,
 		Ledger:               &offTx.Ledger,
+		ExpireBlock:          offTx.ExpireBlock,
 	}
 	return nil
 }
@@ -56,6 +62,10 @@ func (m *MockTxDb) FindTransactions(ids []string, id *string, account *string, c
 	return make([]transactions.TransactionRecord, 0), nil
 }
 
+func (m *MockTxDb) FindTransactionsPage(ids []string, id *string, account *string, contract *string, status *transactions.TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[transactions.TransactionRecord], error) {
+	return hive_blocks.Page[transactions.TransactionRecord]{}, nil
+}
+
 func (m *MockTxDb) InvalidateCompetingTransactions(requiredAuths []string, nonces []uint64) (int64, error) {
 	nonceSet := make(map[uint64]bool, len(nonces))
 	for _, n := range nonces {
@@ -131,3 +141,52 @@ func (m *MockTxDb) FindUnconfirmedTransactions(height uint64) ([]transactions.Tr
 	}
 	return results, nil
 }
+
+func (m *MockTxDb) FindUnconfirmedWithNonce(requiredAuths []string, nonce uint64) ([]transactions.TransactionRecord, error) {
+	results := make([]transactions.TransactionRecord, 0)
+	for _, rec := range m.Records {
+		if rec.Status != transactions.TransactionStatusUnconfirmed || int64(nonce) != rec.Nonce {
+			continue
+		}
+		if len(rec.RequiredAuths) != len(requiredAuths) {
+			continue
+		}
+		match := true
+		for _, a := range rec.RequiredAuths {
+			if !slices.Contains(requiredAuths, a) {
+				match = false
+				break
+			}
+		}
+		if match {
+			results = append(results, rec)
+		}
+	}
+	return results, nil
+}
+
+func (m *MockTxDb) MarkReplaced(ids []string, replacedBy string) error {
+	for _, id := range ids {
+		rec, exists := m.Records[id]
+		if !exists || rec.Status != transactions.TransactionStatusUnconfirmed {
+			continue
+		}
+		rec.Status = transactions.TransactionStatusReplaced
+		rec.ReplacedBy = &replacedBy
+		m.Records[id] = rec
+	}
+	return nil
+}
+
+func (m *MockTxDb) ExpireUnconfirmed(height uint64) (int64, error) {
+	var count int64
+	for id, rec := range m.Records {
+		if rec.Status != transactions.TransactionStatusUnconfirmed || rec.ExpireBlock == nil || *rec.ExpireBlock > height {
+			continue
+		}
+		rec.Status = transactions.TransactionStatusExpired
+		m.Records[id] = rec
+		count++
+	}
+	return count, nil
+}
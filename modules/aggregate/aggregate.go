@@ -2,6 +2,7 @@ package aggregate
 
 import (
 	"context"
+	"vsc-node/lib/utils"
 	start_status "vsc-node/modules/start-status"
 
 	"github.com/chebyrash/promise"
@@ -70,7 +71,12 @@ func (a *Aggregate) Start() *promise.Promise[any] {
 		promises[i] = p.Start()
 		starter, ok := p.(start_status.Starter)
 		if ok {
-			starter.Started().Await(a.ctx)
+			// Later plugins may depend on this one, so don't start them
+			if _, err := starter.Started().Await(a.ctx); err != nil {
+				a.lastPlugin = utils.PromiseReject[any](err)
+				a.startStatus.TriggerStartFailure(err)
+				return a.lastPlugin
+			}
 		}
 	}
 	a.lastPlugin = promises[len(promises)-1]
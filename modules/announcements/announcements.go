@@ -2,13 +2,13 @@ package announcements
 
 import (
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 	"vsc-node/lib/dids"
 	"vsc-node/lib/hive"
+	"vsc-node/lib/signer"
 	agg "vsc-node/modules/aggregate"
 	"vsc-node/modules/common"
 	"vsc-node/modules/common/common_types"
@@ -16,12 +16,10 @@ import (
 	p2p "vsc-node/modules/p2p"
 
 	"github.com/libp2p/go-libp2p/core/network"
-	"github.com/minio/sha256-simd"
 	"github.com/robfig/cron/v3"
 	"github.com/vsc-eco/hivego"
 
 	"github.com/chebyrash/promise"
-	ethBls "github.com/protolambda/bls12-381-util"
 )
 
 // ===== types =====
@@ -196,35 +194,15 @@ func (a *AnnouncementsManager) announce(ctx context.Context) error {
 		return fmt.Errorf("account has no memo key")
 	}
 
-	blsPrivKey := dids.BlsPrivKey{}
-	var arr [32]byte
-	blsPrivSeedHex := a.conf.Get().BlsPrivKeySeed
-	blsPrivSeed, err := hex.DecodeString(blsPrivSeedHex)
+	blsDid, err := a.conf.BlsDID()
 	if err != nil {
-		return fmt.Errorf("failed to decode bls priv seed: %w", err)
-	}
-	if len(blsPrivSeed) != 32 {
-		return fmt.Errorf("bls priv seed must be 32 bytes")
-	}
-
-	copy(arr[:], blsPrivSeed)
-	if err = blsPrivKey.Deserialize(&arr); err != nil {
-		return fmt.Errorf("failed to deserialize bls priv key: %w", err)
-	}
-	pubKey, err := ethBls.SkToPk(&blsPrivKey)
-	if err != nil {
-		return fmt.Errorf("failed to get bls pub key: %w", err)
+		return fmt.Errorf("failed to get bls did: %w", err)
 	}
 
-	// gens the BlsDID from the pub key
-	blsDid, err := dids.NewBlsDID(pubKey)
+	gatewayKey, err := a.conf.Signer().HivePublicKey(signer.GatewayKey)
 	if err != nil {
-		return fmt.Errorf("failed to create bls did: %w", err)
+		return fmt.Errorf("failed to get gateway key: %w", err)
 	}
-	salt := []byte("gateway_key")
-	gatewayKey := sha256.Sum256(append(blsPrivSeed, salt...))
-
-	gatewayKP := hivego.KeyPairFromBytes(gatewayKey[:])
 
 	peerAddrs := make([]string, 0)
 
@@ -256,7 +234,7 @@ func (a *AnnouncementsManager) announce(ctx context.Context) error {
 			GitCommit:       GitCommit,
 			VersionId:       VersionId, //Use standard versioning
 			ProtocolVersion: 0,         //Protocol 0 until protocol 1 is finalized.
-			GatewayKey:      *gatewayKP.GetPublicKeyString(),
+			GatewayKey:      gatewayKey,
 			Witness: struct {
 				Enabled bool `json:"enabled"`
 			}{
@@ -3,13 +3,10 @@ package blockproducer
 import (
 	"context"
 	"encoding/base64"
-	"encoding/binary"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
-	"math/rand"
 	"reflect"
 	"slices"
 	"sort"
@@ -40,7 +37,6 @@ import (
 	"github.com/chebyrash/promise"
 	"github.com/ipfs/go-cid"
 	"github.com/multiformats/go-multicodec"
-	blsu "github.com/protolambda/bls12-381-util"
 	"github.com/vsc-eco/hivego"
 )
 
@@ -244,25 +240,10 @@ func (bp *BlockProducer) generateTransactions(slotHeight uint64) []vscBlocks.Vsc
 	txs := make([]vscBlocks.VscBlockTx, 0)
 	//Get transactions here!
 
-	prefilteredTxs, _ := bp.TxDb.FindUnconfirmedTransactions(slotHeight)
+	consensusParams := bp.sconf.ConsensusParams()
+	txRecords, nonceMap := transactionpool.PendingTransactions(bp.TxDb, bp.nonceDb, slotHeight, consensusParams.MinRcLimit)
 
-	vlog.Debug("generateTransactions", "prefilteredCount", len(prefilteredTxs), "slotHeight", slotHeight)
-	txRecords := make([]transactions.TransactionRecord, 0)
-
-	nonceMap := make(map[string]int64, len(prefilteredTxs))
-
-	for _, txRecord := range prefilteredTxs {
-		keyId := transactionpool.HashKeyAuths(txRecord.RequiredAuths)
-		if nonceMap[keyId] == 0 {
-			nonceRecord, _ := bp.nonceDb.GetNonce(keyId)
-			nonceMap[keyId] = int64(nonceRecord.Nonce)
-		}
-		if txRecord.Nonce >= nonceMap[keyId] && txRecord.RcLimit >= bp.sconf.ConsensusParams().MinRcLimit {
-			txRecords = append(txRecords, txRecord)
-		} else {
-			vlog.Debug("tx filtered out", "id", txRecord.Id, "txNonce", txRecord.Nonce, "dbNonce", nonceMap[keyId], "rcLimit", txRecord.RcLimit, "minRc", bp.sconf.ConsensusParams().MinRcLimit)
-		}
-	}
+	vlog.Debug("generateTransactions", "filteredCount", len(txRecords), "slotHeight", slotHeight)
 
 	if len(txRecords) == 0 {
 		vlog.Debug("no transactions passed nonce/rc filter")
@@ -271,42 +252,7 @@ func (bp *BlockProducer) generateTransactions(slotHeight uint64) []vscBlocks.Vsc
 
 	//Sequence transactions
 
-	preOrder := make([]transactions.TransactionRecord, len(txRecords))
-	copy(preOrder, txRecords)
-
-	ids := make([]string, 0)
-
-	for _, txRecord := range txRecords {
-		ids = append(ids, txRecord.Id)
-	}
-
 	vlog.Verbose("txRecords", "records", txRecords)
-	vlog.Verbose("transaction ids", "ids", ids)
-	seedStr := []byte(transactionpool.HashKeyAuths(ids))
-
-	data := binary.BigEndian.Uint64(seedStr[:])
-	rando := rand.New(rand.NewSource(int64(data)))
-	rando.Shuffle(len(preOrder), func(i, j int) {
-		preOrder[i], preOrder[j] = preOrder[j], preOrder[i]
-	})
-
-	// nonceMap := make(map[string]uint64)
-
-	txMap := make(map[string][]transactions.TransactionRecord)
-
-	for _, txRecord := range txRecords {
-		keyId := transactionpool.HashKeyAuths(txRecord.RequiredAuths)
-		if txMap[keyId] == nil {
-			txMap[keyId] = []transactions.TransactionRecord{}
-		}
-		txMap[keyId] = append(txMap[keyId], txRecord)
-	}
-
-	for k, _ := range txMap {
-		slices.SortFunc(txMap[k], func(i, j transactions.TransactionRecord) int {
-			return int(i.Nonce) - int(j.Nonce)
-		})
-	}
 
 	ledgerSession := ledgerSystem.NewSession(&ledgerSystem.LedgerState{
 		Oplog:           make([]ledgerSystem.OpLogEvent, 0),
@@ -321,47 +267,38 @@ func (bp *BlockProducer) generateTransactions(slotHeight uint64) []vscBlocks.Vsc
 	// ledgerSession := bp.StateEngine.LedgerSystem.NewSession(slotHeight)
 	rcSession := bp.rcSystem.NewSession(ledgerSession)
 
-	sequencedTxs := make([]transactions.TransactionRecord, 0)
-	for _, preRecord := range preOrder {
-		keyId := transactionpool.HashKeyAuths(preRecord.RequiredAuths)
-		payer := preRecord.RequiredAuths[0]
-
-		tx := txMap[keyId][0]
-
-		rcLimit := uint64(0)
-		if tx.RcLimit == 0 {
-			//Minimum of 0.05 hbd or 50 integer units
-			for _, op := range tx.Ops {
-				if op.Type == "transfer" {
-					rcLimit += 100
-				} else if op.Type == "stake_hbd" {
-					rcLimit += 200
-				} else if op.Type == "unstake_hbd" {
-					rcLimit += 200
-				} else if op.Type == "withdraw" {
-					rcLimit += 200
-				} else if op.Type == "call" {
-					rcLimit += 100
-				} else {
-					rcLimit += 50
-				}
+	//HBD tips already promised by each payer in this block
+	hbdTips := make(map[string]int64)
+
+	blockRcBudget := consensusParams.BlockRcBudget(slotHeight)
+	byTip := slotHeight >= consensusParams.TipHeight
+	seq := transactionpool.SequenceTransactions(txRecords, nonceMap, blockRcBudget, byTip, func(tx transactions.TransactionRecord) bool {
+		payer := transactionpool.RcPayer(tx.RequiredAuths, tx.Payer)
+
+		if tx.TipAsset == transactionpool.TipAssetHbd && tx.Tip > 0 {
+			hbdBal := ledgerSession.GetBalance(payer, slotHeight, "hbd")
+			if hbdBal-hbdTips[payer] < int64(tx.Tip) {
+				vlog.Debug("tx tip unpaid", "id", tx.Id, "payer", payer, "tip", tx.Tip)
+				return false
 			}
 		}
 
-		didConsume, _ := rcSession.Consume(payer, slotHeight, int64(tx.RcLimit))
+		rcs := transactionpool.RequiredRcs(tx.RcLimit, tx.Tip, tx.TipAsset)
+		didConsume, _ := rcSession.Consume(payer, slotHeight, int64(rcs))
+		if !didConsume {
+			vlog.Debug("tx RC consume failed", "id", tx.Id, "payer", payer, "rcLimit", tx.RcLimit, "tip", tx.Tip)
+			return false
+		}
 
-		if didConsume {
-			if nonceMap[keyId] == tx.Nonce {
-				txMap[keyId] = txMap[keyId][1:]
-				nonceMap[keyId]++
-				sequencedTxs = append(sequencedTxs, tx)
-				vlog.Debug("tx sequenced", "id", tx.Id, "nonce", tx.Nonce)
-			} else {
-				vlog.Debug("tx nonce mismatch", "id", tx.Id, "txNonce", tx.Nonce, "expected", nonceMap[keyId])
-			}
-		} else {
-			vlog.Debug("tx RC consume failed", "id", tx.Id, "payer", payer, "rcLimit", tx.RcLimit)
+		if tx.TipAsset == transactionpool.TipAssetHbd {
+			hbdTips[payer] += int64(tx.Tip)
 		}
+		vlog.Debug("tx sequenced", "id", tx.Id, "nonce", tx.Nonce, "tip", tx.Tip)
+		return true
+	})
+	sequencedTxs := seq.Txs
+	if seq.Full {
+		vlog.Debug("block RC budget reached", "rcs", seq.Rcs, "budget", blockRcBudget)
 	}
 
 	vlog.Debug("generateTransactions result", "sequenced", len(sequencedTxs), "filtered", len(txRecords))
@@ -545,26 +482,12 @@ func (bp *BlockProducer) HandleBlockMsg(msg p2pMessage) (string, error) {
 		return "", errors.New("invalid producer")
 	}
 
-	blsPrivKey := blsu.SecretKey{}
-	var arr [32]byte
-	blsPrivSeedHex := bp.config.Get().BlsPrivKeySeed
-	blsPrivSeed, err := hex.DecodeString(blsPrivSeedHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to decode bls priv seed: %w", err)
-	}
-	if len(blsPrivSeed) != 32 {
-		return "", fmt.Errorf("bls priv seed must be 32 bytes")
-	}
-
-	copy(arr[:], blsPrivSeed)
-	if err = blsPrivKey.Deserialize(&arr); err != nil {
-		return "", fmt.Errorf("failed to deserialize bls priv key: %w", err)
-	}
-
 	// Producer receiving its own message: use cached CID, skip GenerateBlock
 	if producer == bp.config.Get().HiveUsername && bp.blockSigning != nil {
-		sig := blsu.Sign(&blsPrivKey, bp.blockSigning.cid.Bytes())
-		sigBytes := sig.Serialize()
+		sigBytes, err := bp.config.Signer().SignCid(bp.blockSigning.cid)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign block: %w", err)
+		}
 		return base64.RawURLEncoding.EncodeToString(sigBytes[:]), nil
 	}
 
@@ -623,8 +546,10 @@ func (bp *BlockProducer) HandleBlockMsg(msg p2pMessage) (string, error) {
 	}
 
 	// CIDs match — sign
-	sig := blsu.Sign(&blsPrivKey, localCid.Bytes())
-	sigBytes := sig.Serialize()
+	sigBytes, err := bp.config.Signer().SignCid(*localCid)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign block: %w", err)
+	}
 	sigStr := base64.RawURLEncoding.EncodeToString(sigBytes[:])
 
 	return sigStr, nil
@@ -908,12 +833,14 @@ func (bp *BlockProducer) MakeOutputs(session *datalayer.Session) []vscBlocks.Vsc
 			}
 
 			results = append(results, contracts.ContractOutputResult{
-				Ret:    ret,
-				Ok:     v.Success,
-				Err:    v.Err,
-				ErrMsg: v.ErrMsg,
-				Logs:   v.Logs,
-				TssOps: v.TssOps,
+				Ret:       ret,
+				Ok:        v.Success,
+				Err:       v.Err,
+				ErrMsg:    v.ErrMsg,
+				Logs:      v.Logs,
+				TssOps:    v.TssOps,
+				Events:    v.Events,
+				Schedules: v.Schedules,
 			})
 			inputIds = append(inputIds, v.TxId)
 		}
@@ -2,8 +2,13 @@ package common_test
 
 import (
 	"testing"
+	"vsc-node/lib/dids"
 	"vsc-node/modules/common"
 
+	"github.com/ethereum/go-ethereum/crypto"
+	blocks "github.com/ipfs/go-block-format"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	"github.com/multiformats/go-multihash"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,3 +24,79 @@ func TestParseFloat(t *testing.T) {
 	}
 	t.Log(result)
 }
+
+func TestVerifyAuthorities(t *testing.T) {
+	data := map[string]interface{}{
+		"tx": map[string]interface{}{
+			"op": "transfer",
+			"payload": map[string]interface{}{
+				"from":   "did:pkh:eip155:1:0x0000000000000000000000000000000000000001",
+				"to":     "hive:vaultec",
+				"amount": 500,
+				"tk":     "HIVE",
+			},
+		},
+		"__t": "vsc-tx",
+		"__v": "0.2",
+	}
+	node, err := cbornode.WrapObject(data, multihash.SHA2_256, -1)
+	assert.Nil(t, err)
+	blk, err := blocks.NewBlockWithCid(node.RawData(), node.Cid())
+	assert.Nil(t, err)
+
+	keys := make([]string, 3)
+	sigs := make([]common.Sig, 3)
+	for i := range keys {
+		priv, err := crypto.GenerateKey()
+		assert.Nil(t, err)
+		keys[i] = dids.NewEthDID(crypto.PubkeyToAddress(priv.PublicKey).Hex()).String()
+		sig, err := dids.NewEthProvider(priv).Sign(blk)
+		assert.Nil(t, err)
+		sigs[i] = common.Sig{Sig: sig, Kid: keys[i]}
+	}
+
+	account := "did:pkh:eip155:1:0x0000000000000000000000000000000000000001"
+	authority := &common.Authority{
+		Keys: []common.AuthorityKey{
+			{Key: keys[0], Weight: 1},
+			{Key: keys[1], Weight: 1},
+			{Key: keys[2], Weight: 2},
+		},
+		Threshold: 2,
+	}
+	resolve := func(a string) (*common.Authority, error) {
+		if a == account {
+			return authority, nil
+		}
+		return nil, nil
+	}
+
+	//Two weight 1 keys meet the threshold
+	ok, err := common.VerifyAuthorities([]string{account}, resolve, blk, sigs[:2])
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	//A single weight 2 key meets it on its own
+	ok, err = common.VerifyAuthorities([]string{account}, resolve, blk, sigs[2:])
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	//A single weight 1 key does not
+	ok, err = common.VerifyAuthorities([]string{account}, resolve, blk, sigs[:1])
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	//A signature made by another key does not count
+	forged := common.Sig{Sig: sigs[0].Sig, Kid: keys[2]}
+	ok, err = common.VerifyAuthorities([]string{account}, resolve, blk, []common.Sig{forged})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	//Accounts without an authority sign with their own key
+	ok, err = common.VerifyAuthorities([]string{account, keys[0]}, resolve, blk, sigs[:2])
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = common.VerifyAuthorities([]string{account, keys[0]}, resolve, blk, sigs[1:])
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
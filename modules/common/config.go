@@ -5,10 +5,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"vsc-node/lib/dids"
+	"vsc-node/lib/keystore"
+	"vsc-node/lib/signer"
 	"vsc-node/modules/config"
 
 	"github.com/libp2p/go-libp2p/core/crypto"
-	blsu "github.com/protolambda/bls12-381-util"
 	"github.com/vsc-eco/hivego"
 )
 
@@ -51,62 +52,86 @@ func (ac *identityConfigStruct) Libp2pPrivateKey() (crypto.PrivKey, error) {
 	return crypto.UnmarshalEd25519PrivateKey(b)
 }
 
-func (ac *identityConfigStruct) blsPrivateKey() (*dids.BlsPrivKey, error) {
-	blsPrivKey := &dids.BlsPrivKey{}
-	var arr [32]byte
-	blsPrivSeedHex := ac.Get().BlsPrivKeySeed
-	blsPrivSeed, err := hex.DecodeString(blsPrivSeedHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode bls priv seed: %w", err)
-	}
-	if len(blsPrivSeed) != 32 {
-		return nil, fmt.Errorf("bls priv seed must be 32 bytes")
+// Signer for the identity's keys. Defaults to signing in-process with the
+// keys of this config unless a remote signer is set.
+func (ac *identityConfigStruct) Signer() signer.Signer {
+	if ac.signer != nil {
+		return ac.signer
 	}
+	return signer.NewLocal(func() signer.Keys {
+		conf := ac.Get()
+		return signer.Keys{
+			BlsPrivKeySeed: conf.BlsPrivKeySeed,
+			HiveActiveKey:  conf.HiveActiveKey,
+		}
+	})
+}
 
-	copy(arr[:], blsPrivSeed)
-	if err = blsPrivKey.Deserialize(&arr); err != nil {
-		return nil, fmt.Errorf("failed to deserialize bls priv key: %w", err)
-	}
+// Delegates all signing to the signer, e.g. a remote signing daemon
+func (ac *identityConfigStruct) UseSigner(s signer.Signer) {
+	ac.signer = s
+}
 
-	return blsPrivKey, nil
+func (ac *identityConfigStruct) BlsDID() (dids.BlsDID, error) {
+	return ac.Signer().BlsDID()
 }
 
-func (ac *identityConfigStruct) BlsProvider() (dids.BlsProvider, error) {
-	blsPrivKey, err := ac.blsPrivateKey()
-	if err != nil {
-		return nil, err
-	}
+type identityConfigStruct struct {
+	*config.Config[identityConfig]
 
-	provider, err := dids.NewBlsProvider(blsPrivKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create bls provider: %w", err)
-	}
+	//Encrypts secrets at rest when set
+	keystore *keystore.Keystore
 
-	return provider, nil
+	//Holds the keys when signing outside the node
+	signer signer.Signer
 }
 
-func (ac *identityConfigStruct) BlsDID() (dids.BlsDID, error) {
-	blsPrivKey, err := ac.blsPrivateKey()
-	if err != nil {
-		return "", err
+// Secret fields of the identity config, keyed by field name
+func (ic *identityConfig) secrets() map[string]*string {
+	return map[string]*string{
+		"BlsPrivKeySeed": &ic.BlsPrivKeySeed,
+		"HiveActiveKey":  &ic.HiveActiveKey,
+		"Libp2pPrivKey":  &ic.Libp2pPrivKey,
 	}
+}
 
-	pubKey, err := blsu.SkToPk(blsPrivKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to get bls pub key: %w", err)
-	}
+// Encrypts secrets written to disk from now on. Must be called before Init.
+func (ac *identityConfigStruct) UseKeystore(ks *keystore.Keystore) {
+	ac.keystore = ks
+}
 
-	// gens the BlsDID from the pub key
-	blsDid, err := dids.NewBlsDID(pubKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to create bls did: %w", err)
-	}
+// Writes the current value back to disk, e.g. to encrypt legacy plaintext secrets
+func (ac *identityConfigStruct) Save() error {
+	return ac.Update(func(*identityConfig) {})
+}
 
-	return blsDid, nil
+func (ac *identityConfigStruct) encodeSecrets(ic identityConfig) (identityConfig, error) {
+	if ac.keystore == nil {
+		return ic, nil
+	}
+	for name, value := range ic.secrets() {
+		if !keystore.IsEncryptedString(*value) {
+			*value = ac.keystore.EncryptString(*value, name)
+		}
+	}
+	return ic, nil
 }
 
-type identityConfigStruct struct {
-	*config.Config[identityConfig]
+func (ac *identityConfigStruct) decodeSecrets(ic identityConfig) (identityConfig, error) {
+	for name, value := range ic.secrets() {
+		if !keystore.IsEncryptedString(*value) {
+			continue
+		}
+		if ac.keystore == nil {
+			return ic, fmt.Errorf("identity config secrets are encrypted: %w", keystore.ErrLocked)
+		}
+		plaintext, err := ac.keystore.DecryptString(*value, name)
+		if err != nil {
+			return ic, fmt.Errorf("failed to decrypt %s: %w", name, err)
+		}
+		*value = plaintext
+	}
+	return ic, nil
 }
 
 type IdentityConfig = *identityConfigStruct
@@ -137,7 +162,7 @@ func NewIdentityConfig(dataDir ...string) IdentityConfig {
 
 	// defaults now for our config if not already provided
 
-	return &identityConfigStruct{config.New(
+	ac := &identityConfigStruct{Config: config.New(
 		identityConfig{
 			BlsPrivKeySeed: hex.EncodeToString(seed[:]),
 			HiveActiveKey:  "ADD_YOUR_PRIVATE_WIF",
@@ -146,4 +171,7 @@ func NewIdentityConfig(dataDir ...string) IdentityConfig {
 		},
 		dataDirPtr,
 	)}
+	ac.SetCodec(ac.encodeSecrets, ac.decodeSecrets)
+
+	return ac
 }
@@ -3,6 +3,7 @@ package params
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 	"time"
@@ -20,6 +21,20 @@ const READ_IO_GAS_RC_COST = 1
 
 const EPHEM_IO_GAS = 100
 
+// Maximum number of keys returned by a single db.list_keys or db.range call
+const DB_SCAN_MAX_LIMIT = 100
+
+// Read IO gas charged for every state tree link visited by db.list_keys or db.range
+const DB_SCAN_NODE_IO_GAS = 64
+
+// Limits of events emitted through system.emit_event
+const CONTRACT_EVENT_MAX_NAME_LENGTH = 64
+const CONTRACT_EVENT_MAX_TOPICS = 4
+const CONTRACT_EVENT_MAX_TOPIC_LENGTH = 128
+
+// Maximum number of keys in an account authority
+const MAX_AUTHORITY_KEYS = 10
+
 // 2,000 HIVE
 var CONSENSUS_MINIMUM = int64(2_000_000)
 
@@ -35,25 +50,93 @@ var RC_RETURN_PERIOD uint64 = 120 * 60 * 20 // 5 day cool down period for RCs
 var RC_HIVE_FREE_AMOUNT int64 = 10_000      // 5 HBD worth of RCs for Hive accounts
 var MINIMUM_RC_LIMIT uint64 = 50
 
+// Sum of the RC limits of the transactions in a single block
+var MAX_BLOCK_RCS uint64 = 1_000_000
+
+// Mainnet block RC budget, not activated yet
+var MAX_BLOCK_RCS_HEIGHT uint64 = math.MaxUint64
+
 var CONTRACT_DEPLOYMENT_FEE int64 = 10_000 // 10 HBD per contract
 var CONTRACT_DEPLOYMENT_FEE_START_HEIGHT uint64 = 99410000
 var CONTRACT_UPDATE_HEIGHT uint64 = 102100000
 var CONTRACT_CALL_MAX_RECURSION_DEPTH = 20
 
+// Longest timelock a contract upgrade policy may declare, about 30 days
+var CONTRACT_UPGRADE_MAX_DELAY uint64 = 864_000
+
+// RC limit of the migrate call run when an upgrade activates, paid by the contract owner
+var CONTRACT_MIGRATE_RC_LIMIT uint = 10_000
+
+// Most RCs a contract may reserve for the callback of a TSS signing request
+var TSS_SIGN_CALLBACK_MAX_RC_LIMIT uint = 10_000
+
+// Most RCs a single scheduled contract call may use, paid by the contract account
+var CONTRACT_SCHEDULE_MAX_RC_LIMIT uint = 10_000
+
+// Furthest ahead a contract call may be scheduled, about 30 days
+var CONTRACT_SCHEDULE_MAX_DELAY uint64 = 864_000
+
+// Scheduled contract calls queued per block, the rest run in the following blocks
+var CONTRACT_SCHEDULE_MAX_PER_BLOCK = 50
+
+// Withdrawals per chain keyed per block once a chain gets its bridge key,
+// the rest are keyed in the following blocks
+var BRIDGE_KEY_MAX_PER_BLOCK = 50
+
+// Active schedules a contract may have, further schedules are dropped
+var CONTRACT_SCHEDULE_MAX_ACTIVE = 100
+
+// RCs charged to the caller for each schedule a contract creates
+var CONTRACT_SCHEDULE_CREATE_RCS uint = 100
+
 // Mainnet TSS key indexing
 var TSS_INDEX_HEIGHT uint64 = 102_083_000
 
+// Mainnet scheduled transfers, not activated yet
+var SCHEDULED_TRANSFER_HEIGHT uint64 = math.MaxUint64
+
+// Mainnet transaction tips, not activated yet
+var TIP_HEIGHT uint64 = math.MaxUint64
+
+// Mainnet sponsored transactions, not activated yet
+var PAYER_HEIGHT uint64 = math.MaxUint64
+
+// Mainnet account authorities, not activated yet
+var AUTHORITY_HEIGHT uint64 = math.MaxUint64
+
+// Mainnet bridge withdrawal queue, not activated yet
+var BRIDGE_HEIGHT uint64 = math.MaxUint64
+
+// Mainnet Rust contract runtime, not activated yet
+var RUST_RUNTIME_HEIGHT uint64 = math.MaxUint64
+
 // Election once every 6 hours on mainnet
 var ELECTION_INTERVAL = uint64(6 * 60 * 20)
 
 type ConsensusParams struct {
-	MinStake             int64  `json:"minStake,omitempty"`
-	MinMembers           int    `json:"minMembers,omitempty"`
-	MinSpSigners         int    `json:"minSpSigners,omitempty"`
-	MinRcLimit           uint64 `json:"minRcLimit,omitempty"`
-	TssIndexHeight       uint64 `json:"tssIndexHeight,omitempty"`
-	ElectionInterval     uint64 `json:"electionInterval,omitempty"`
-	ElectionDupeFixEpoch uint64 `json:"electionDupeFixEpoch,omitempty"`
+	MinStake                int64  `json:"minStake,omitempty"`
+	MinMembers              int    `json:"minMembers,omitempty"`
+	MinSpSigners            int    `json:"minSpSigners,omitempty"`
+	MinRcLimit              uint64 `json:"minRcLimit,omitempty"`
+	MaxBlockRcs             uint64 `json:"maxBlockRcs,omitempty"`       // Block capacity in RCs, 0 for no limit
+	MaxBlockRcsHeight       uint64 `json:"maxBlockRcsHeight,omitempty"` // Blocks below this height have no RC budget
+	TssIndexHeight          uint64 `json:"tssIndexHeight,omitempty"`
+	ScheduledTransferHeight uint64 `json:"scheduledTransferHeight,omitempty"` // Scheduled transfer ops are ignored below this height
+	TipHeight               uint64 `json:"tipHeight,omitempty"`               // Tips are charged and order blocks from this height
+	PayerHeight             uint64 `json:"payerHeight,omitempty"`             // Transaction payers are ignored below this height
+	AuthorityHeight         uint64 `json:"authorityHeight,omitempty"`         // Account authority updates fail below this height
+	BridgeHeight            uint64 `json:"bridgeHeight,omitempty"`            // Bridged asset withdrawals are queued for the chain key from this height
+	RustRuntimeHeight       uint64 `json:"rustRuntimeHeight,omitempty"`       // Contracts can't be deployed with the Rust runtime below this height
+	ElectionInterval        uint64 `json:"electionInterval,omitempty"`
+	ElectionDupeFixEpoch    uint64 `json:"electionDupeFixEpoch,omitempty"`
+}
+
+// Block capacity in RCs at the given height, 0 for no limit
+func (p ConsensusParams) BlockRcBudget(height uint64) uint64 {
+	if height < p.MaxBlockRcsHeight {
+		return 0
+	}
+	return p.MaxBlockRcs
 }
 
 type TssParams struct {
@@ -173,6 +256,10 @@ type OracleParams struct {
 	// is in this map, the oracle skips BLS relay for it.
 	ZKVerifierChains map[string]string `json:"zkVerifierChains,omitempty"`
 
+	// BridgeKeys maps chain symbols (e.g. "ETH") to the TSS key that
+	// signs withdrawals of assets bridged from that chain.
+	BridgeKeys map[string]string `json:"bridgeKeys,omitempty"`
+
 	// Deprecated: use ChainContracts["BTC"] instead.
 	BtcContractId string `json:"btcContractId,omitempty"`
 }
@@ -187,6 +274,12 @@ func (o OracleParams) HasZKVerifier(symbol string) bool {
 	return ok
 }
 
+// BridgeKeyId returns the TSS key signing withdrawals to the given chain,
+// or an empty string when the chain has no key yet.
+func (o OracleParams) BridgeKeyId(symbol string) string {
+	return o.BridgeKeys[symbol]
+}
+
 // ContractId returns the relay contract ID for the given chain symbol.
 // Falls back to the legacy BtcContractId field for BTC.
 func (o OracleParams) ContractId(symbol string) string {
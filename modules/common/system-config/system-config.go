@@ -159,13 +159,21 @@ func MainnetConfig() SystemConfig {
 		gatewayWallet:  "vsc.gateway",
 		startHeight:    94601000,
 		consensusParams: params.ConsensusParams{
-			MinStake:             params.CONSENSUS_MINIMUM,
-			MinMembers:           7,
-			MinSpSigners:         6,
-			MinRcLimit:           params.MINIMUM_RC_LIMIT,
-			TssIndexHeight:       params.TSS_INDEX_HEIGHT,
-			ElectionInterval:     params.ELECTION_INTERVAL,
-			ElectionDupeFixEpoch: 1406,
+			MinStake:                params.CONSENSUS_MINIMUM,
+			MinMembers:              7,
+			MinSpSigners:            6,
+			MinRcLimit:              params.MINIMUM_RC_LIMIT,
+			MaxBlockRcs:             params.MAX_BLOCK_RCS,
+			MaxBlockRcsHeight:       params.MAX_BLOCK_RCS_HEIGHT,
+			TssIndexHeight:          params.TSS_INDEX_HEIGHT,
+			ScheduledTransferHeight: params.SCHEDULED_TRANSFER_HEIGHT,
+			TipHeight:               params.TIP_HEIGHT,
+			PayerHeight:             params.PAYER_HEIGHT,
+			AuthorityHeight:         params.AUTHORITY_HEIGHT,
+			BridgeHeight:            params.BRIDGE_HEIGHT,
+			RustRuntimeHeight:       params.RUST_RUNTIME_HEIGHT,
+			ElectionInterval:        params.ELECTION_INTERVAL,
+			ElectionDupeFixEpoch:    1406,
 		},
 		oracleParams: params.OracleParams{
 			ChainContracts: map[string]string{
@@ -188,13 +196,21 @@ func TestnetConfig() SystemConfig {
 		gatewayWallet:  "vsc.gateway",
 		startHeight:    2,
 		consensusParams: params.ConsensusParams{
-			MinStake:             params.CONSENSUS_MINIMUM,
-			MinMembers:           3,
-			MinSpSigners:         3,
-			MinRcLimit:           params.MINIMUM_RC_LIMIT,
-			TssIndexHeight:       1409500,
-			ElectionInterval:     3600,
-			ElectionDupeFixEpoch: 268,
+			MinStake:                params.CONSENSUS_MINIMUM,
+			MinMembers:              3,
+			MinSpSigners:            3,
+			MinRcLimit:              params.MINIMUM_RC_LIMIT,
+			MaxBlockRcs:             params.MAX_BLOCK_RCS,
+			MaxBlockRcsHeight:       params.MAX_BLOCK_RCS_HEIGHT,
+			TssIndexHeight:          1409500,
+			ScheduledTransferHeight: params.SCHEDULED_TRANSFER_HEIGHT,
+			TipHeight:               params.TIP_HEIGHT,
+			PayerHeight:             params.PAYER_HEIGHT,
+			AuthorityHeight:         params.AUTHORITY_HEIGHT,
+			BridgeHeight:            params.BRIDGE_HEIGHT,
+			RustRuntimeHeight:       params.RUST_RUNTIME_HEIGHT,
+			ElectionInterval:        3600,
+			ElectionDupeFixEpoch:    268,
 		},
 		oracleParams: params.OracleParams{
 			ChainContracts: map[string]string{
@@ -219,13 +235,21 @@ func DevnetConfig() SystemConfig {
 		gatewayWallet: "vsc.gateway",
 		startHeight:   2,
 		consensusParams: params.ConsensusParams{
-			MinStake:             1000,
-			MinMembers:           3,
-			MinSpSigners:         3,
-			MinRcLimit:           params.MINIMUM_RC_LIMIT,
-			TssIndexHeight:       0,
-			ElectionInterval:     40,
-			ElectionDupeFixEpoch: 0,
+			MinStake:                1000,
+			MinMembers:              3,
+			MinSpSigners:            3,
+			MinRcLimit:              params.MINIMUM_RC_LIMIT,
+			MaxBlockRcs:             params.MAX_BLOCK_RCS,
+			MaxBlockRcsHeight:       0,
+			TssIndexHeight:          0,
+			ScheduledTransferHeight: 0,
+			TipHeight:               0,
+			PayerHeight:             0,
+			AuthorityHeight:         0,
+			BridgeHeight:            0,
+			RustRuntimeHeight:       0,
+			ElectionInterval:        40,
+			ElectionDupeFixEpoch:    0,
 		},
 		tssParams: params.DefaultTssParams,
 	}
@@ -240,12 +264,13 @@ func MocknetConfig() SystemConfig {
 		gatewayWallet: "vsc.mocknet",
 		startHeight:   0,
 		consensusParams: params.ConsensusParams{
-			MinStake:             1,
-			MinMembers:           3,
-			MinRcLimit:           1,
-			TssIndexHeight:       0,
-			ElectionInterval:     1000,
-			ElectionDupeFixEpoch: 0,
+			MinStake:                1,
+			MinMembers:              3,
+			MinRcLimit:              1,
+			TssIndexHeight:          0,
+			ScheduledTransferHeight: 0,
+			ElectionInterval:        1000,
+			ElectionDupeFixEpoch:    0,
 		},
 		tssParams: params.MocknetTssParams,
 	}
@@ -99,6 +99,70 @@ func VerifySignatures(auths []dids.DID, blk blocks.Block, sigs []Sig) (bool, err
 	return verified, err
 }
 
+// Key of an account authority and the weight its signature carries
+type AuthorityKey struct {
+	Key    string `refmt:"key" json:"key" bson:"key"`
+	Weight uint64 `refmt:"weight" json:"weight" bson:"weight"`
+}
+
+// Weighted key set authorizing an account. An account without an
+// authority is authorized by its own key alone.
+type Authority struct {
+	Keys      []AuthorityKey
+	Threshold uint64
+}
+
+// Resolves an account to its current authority, nil if it has none
+type AuthorityResolver func(account string) (*Authority, error)
+
+// Verifies that every account is authorized by the signatures, each
+// signature naming the key it was made with in Kid. An account is
+// authorized once the weights of its keys with a valid signature reach
+// the threshold of its authority.
+func VerifyAuthorities(accounts []string, resolve AuthorityResolver, blk blocks.Block, sigs []Sig) (bool, error) {
+	sigsByKey := make(map[string]string, len(sigs))
+	for _, sig := range sigs {
+		sigsByKey[sig.Kid] = sig.Sig
+	}
+
+	verifiedKeys := make(map[string]bool)
+	for _, account := range accounts {
+		authority, err := resolve(account)
+		if err != nil {
+			return false, err
+		}
+		if authority == nil {
+			authority = &Authority{Keys: []AuthorityKey{{Key: account, Weight: 1}}, Threshold: 1}
+		}
+
+		weight := uint64(0)
+		for _, key := range authority.Keys {
+			verified, checked := verifiedKeys[key.Key]
+			if !checked {
+				sig, ok := sigsByKey[key.Key]
+				if ok {
+					did, err := dids.Parse(key.Key)
+					if err != nil {
+						return false, err
+					}
+					verified, err = did.Verify(blk, sig)
+					if err != nil {
+						return false, err
+					}
+				}
+				verifiedKeys[key.Key] = verified
+			}
+			if verified {
+				weight += key.Weight
+			}
+		}
+		if weight < authority.Threshold {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func SafeParseHiveFloat(amount string) (int64, error) {
 	parts := strings.Split(amount, ".")
 	if len(parts) != 2 {
@@ -23,6 +23,10 @@ type Config[T any] struct {
 	value  T
 
 	dataDir string
+
+	//Optional transforms between the in memory and on disk value
+	encode func(T) (T, error)
+	decode func(T) (T, error)
 }
 
 var UseMainConfigDuringTests = false
@@ -105,6 +109,12 @@ func (c *Config[T]) Init() error {
 		if err != nil {
 			return err
 		}
+		if c.decode != nil {
+			c.value, err = c.decode(c.value)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	c.loaded = true
 	return nil
@@ -125,7 +135,15 @@ func (c *Config[T]) Get() T {
 func (c *Config[T]) Update(updater func(*T)) error {
 	temp := c.value
 	updater(&temp)
-	b, err := json.MarshalIndent(temp, "", "  ")
+	disk := temp
+	if c.encode != nil {
+		var err error
+		disk, err = c.encode(temp)
+		if err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(disk, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -141,6 +159,13 @@ func (c *Config[T]) Update(updater func(*T)) error {
 	return nil
 }
 
+// Sets transforms applied when writing the value to disk and after reading it
+// back, e.g. to keep secrets encrypted at rest. Must be set before Init.
+func (c *Config[T]) SetCodec(encode func(T) (T, error), decode func(T) (T, error)) {
+	c.encode = encode
+	c.decode = decode
+}
+
 func (c *Config[T]) DefaultValue() T {
 	return c.defaultValue
 }
@@ -1,6 +1,7 @@
 package contract_execution_context
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -8,7 +9,6 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
-	"strings"
 	"unicode/utf8"
 	"vsc-node/lib/vsclog"
 	"vsc-node/modules/common"
@@ -73,6 +73,7 @@ type Environment struct {
 	RequiredPostingAuths []string
 	Caller               string
 	Sender               string
+	Payer                string // Account paying RCs, the first required auth if empty
 	Intents              []contracts.Intent
 }
 
@@ -194,6 +195,82 @@ func (ctx *contractExecutionContext) Log(msg string) {
 	ctx.callSession.AppendLogs(ctx.env.ContractId, msg)
 }
 
+// Emits a structured event. topicsJson is a JSON array of strings indexers can filter by,
+// dataJson is an arbitrary JSON payload.
+func (ctx *contractExecutionContext) EmitEvent(name string, topicsJson string, dataJson string) result.Result[struct{}] {
+	ctx.doIO(len(name) + len(topicsJson) + len(dataJson))
+	if name == "" || len(name) > params.CONTRACT_EVENT_MAX_NAME_LENGTH {
+		return result.Err[struct{}](
+			errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("event name must be 1 to %d bytes", params.CONTRACT_EVENT_MAX_NAME_LENGTH)),
+		)
+	}
+	topics := make([]string, 0)
+	if topicsJson != "" {
+		if err := json.Unmarshal([]byte(topicsJson), &topics); err != nil {
+			return result.Err[struct{}](
+				errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("topics must be a JSON array of strings: %w", err)),
+			)
+		}
+	}
+	if len(topics) > params.CONTRACT_EVENT_MAX_TOPICS {
+		return result.Err[struct{}](
+			errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("at most %d topics are allowed", params.CONTRACT_EVENT_MAX_TOPICS)),
+		)
+	}
+	for _, topic := range topics {
+		if len(topic) > params.CONTRACT_EVENT_MAX_TOPIC_LENGTH {
+			return result.Err[struct{}](
+				errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("topics must be at most %d bytes", params.CONTRACT_EVENT_MAX_TOPIC_LENGTH)),
+			)
+		}
+	}
+	if dataJson == "" {
+		dataJson = "null"
+	}
+	data := bytes.Buffer{}
+	if err := json.Compact(&data, []byte(dataJson)); err != nil {
+		return result.Err[struct{}](
+			errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("event data must be valid JSON: %w", err)),
+		)
+	}
+	ctx.callSession.AppendEvent(ctx.env.ContractId, contracts.ContractEvent{
+		Name:   name,
+		Topics: topics,
+		Data:   data.String(),
+	})
+	return result.Ok(struct{}{})
+}
+
+// Schedules a call to an entrypoint of the contract, once at a height ("at:<height>")
+// or every few blocks ("every:<blocks>"). Returns the ID of the schedule.
+func (ctx *contractExecutionContext) Schedule(action string, payload string, when string, rcLimit uint) result.Result[string] {
+	ctx.doIO(0, len(action)+len(payload))
+	timing, err := contracts.ParseScheduleTiming(when, ctx.env.BlockHeight)
+	if err != nil {
+		return result.Err[string](errors.Join(fmt.Errorf(contracts.SDK_ERROR), err))
+	}
+	id := fmt.Sprintf("%s:%d", ctx.env.TxId, ctx.callSession.NextScheduleSeq())
+	ctx.callSession.AppendSchedule(ctx.env.ContractId, contracts.ScheduleOp{
+		Type:     contracts.ScheduleOpCreate,
+		Id:       id,
+		Action:   action,
+		Payload:  payload,
+		Height:   timing.Height,
+		Interval: timing.Interval,
+		RcLimit:  rcLimit,
+	})
+	return result.Ok(id)
+}
+
+// Cancels a schedule of the contract once the output is ingested
+func (ctx *contractExecutionContext) CancelSchedule(id string) result.Result[struct{}] {
+	ctx.callSession.AppendSchedule(ctx.env.ContractId, contracts.ScheduleOp{
+		Type: contracts.ScheduleOpCancel,
+		Id:   id,
+	})
+	return result.Ok(struct{}{})
+}
+
 func (ctx *contractExecutionContext) EnvVar(key string) result.Result[string] {
 	switch key {
 	case "contract.id":
@@ -231,11 +308,8 @@ func (ctx *contractExecutionContext) EnvVar(key string) result.Result[string] {
 			},
 		)
 	case "msg.payer":
-		//TODO: implement payer logic
-		if len(ctx.env.RequiredAuths) > 0 {
-			return result.Ok(ctx.env.RequiredAuths[0])
-		} else if len(ctx.env.RequiredPostingAuths) > 0 {
-			return result.Ok(ctx.env.RequiredPostingAuths[0])
+		if payer := ctx.payer(); payer != "" {
+			return result.Ok(payer)
 		}
 	case "msg.caller":
 		return result.Ok(ctx.env.Caller)
@@ -261,14 +335,21 @@ func (ctx *contractExecutionContext) EnvVar(key string) result.Result[string] {
 	)
 }
 
-// GetEnv returns the environment variables in a standard contract format
-func (ctx *contractExecutionContext) GetEnv() result.Result[string] {
-	var payer string
-	if len(ctx.env.RequiredAuths) > 0 {
-		payer = ctx.env.RequiredAuths[0]
+// Account paying RCs for the transaction
+func (ctx *contractExecutionContext) payer() string {
+	if ctx.env.Payer != "" {
+		return ctx.env.Payer
+	} else if len(ctx.env.RequiredAuths) > 0 {
+		return ctx.env.RequiredAuths[0]
 	} else if len(ctx.env.RequiredPostingAuths) > 0 {
-		payer = ctx.env.RequiredPostingAuths[0]
+		return ctx.env.RequiredPostingAuths[0]
 	}
+	return ""
+}
+
+// GetEnv returns the environment variables in a standard contract format
+func (ctx *contractExecutionContext) GetEnv() result.Result[string] {
+	payer := ctx.payer()
 
 	if ctx.env.RequiredAuths == nil {
 		ctx.env.RequiredAuths = []string{}
@@ -359,6 +440,41 @@ func (ctx *contractExecutionContext) DeleteState(key string) result.Result[struc
 	return result.Ok(struct{}{})
 }
 
+// Lists the keys starting with prefix in lexicographic order, resuming after cursor
+func (ctx *contractExecutionContext) ListStateKeys(prefix string, cursor string, limit int) result.Result[string] {
+	ctx.doIO(len(prefix) + len(cursor))
+	keys, next, visited := ctx.callSession.GetStateStore(ctx.env.ContractId).ListKeys(prefix, cursor, limit)
+	ctx.doIO(visited * params.DB_SCAN_NODE_IO_GAS)
+	for _, key := range keys {
+		ctx.doIO(len(key))
+	}
+	return marshalStatePage(wasm_types.StateKeysPage{Keys: keys, Next: next})
+}
+
+// Reads the entries with keys within [start, end) in lexicographic order. An empty end is unbounded.
+func (ctx *contractExecutionContext) RangeState(start string, end string, limit int) result.Result[string] {
+	ctx.doIO(len(start) + len(end))
+	page := wasm_types.StateRangePage{Entries: make([]wasm_types.StateEntry, 0)}
+	keys, next, visited := ctx.callSession.GetStateStore(ctx.env.ContractId).Keys(start, end, limit)
+	ctx.doIO(visited * params.DB_SCAN_NODE_IO_GAS)
+	for _, key := range keys {
+		page.Entries = append(page.Entries, wasm_types.StateEntry{
+			Key:   key,
+			Value: ctx.GetState(key).Unwrap(),
+		})
+	}
+	page.Next = next
+	return marshalStatePage(page)
+}
+
+func marshalStatePage(page any) result.Result[string] {
+	pageBytes, err := json.Marshal(page)
+	if err != nil {
+		return result.Err[string](errors.Join(fmt.Errorf(contracts.SDK_ERROR), err))
+	}
+	return result.Ok(string(pageBytes))
+}
+
 func (ctx *contractExecutionContext) GetEphemState(contractId string, key string) result.Result[string] {
 	c := contractId
 	if c == "" {
@@ -416,11 +532,12 @@ func (ctx *contractExecutionContext) PullBalance(from string, amount int64, asse
 
 	// Reserve HBD for RC consumption only when the source is the RC payer.
 	// Inter-contract pulls (from = "contract:...") originate from a contract
-	// that isn't paying RCs, so no exclusion applies. The RC payer's free
-	// tier (RC_HIVE_FREE_AMOUNT minus what's already frozen) covers part of
-	// rcLimit without needing HBD backing — only the remainder is reserved.
+	// that isn't paying RCs, and a sponsored caller isn't paying RCs either,
+	// so no exclusion applies. The RC payer's free tier (RC_HIVE_FREE_AMOUNT
+	// minus what's already frozen) covers part of rcLimit without needing
+	// HBD backing — only the remainder is reserved.
 	var transferOptions []ledgerSystem.TransferOptions
-	if asset == "hbd" && !strings.HasPrefix(from, "contract:") {
+	if asset == "hbd" && from == ctx.payer() {
 		exclusion := ctx.rcLimit - ctx.rcFreeRemaining
 		if exclusion > 0 {
 			transferOptions = []ledgerSystem.TransferOptions{
@@ -486,6 +603,38 @@ func (ctx *contractExecutionContext) WithdrawBalance(to string, amount int64, as
 	return result.Ok(struct{}{})
 }
 
+// Mints the contract's own token symbol to an account
+func (ctx *contractExecutionContext) MintToken(to string, amount int64, symbol string) result.Result[struct{}] {
+	res := ctx.ledger.Mint(ledgerSystem.TokenParams{
+		Id:          ctx.env.TxId,
+		ContractId:  ctx.env.ContractId,
+		Account:     to,
+		Asset:       ledgerSystem.TokenAsset(ctx.env.ContractId, symbol),
+		Amount:      amount,
+		BlockHeight: ctx.env.BlockHeight,
+	})
+	if !res.Ok {
+		return result.Err[struct{}](errors.Join(fmt.Errorf(contracts.LEDGER_ERROR), fmt.Errorf("%s", res.Msg)))
+	}
+	return result.Ok(struct{}{})
+}
+
+// Burns the contract's own token symbol out of the contract balance
+func (ctx *contractExecutionContext) BurnToken(amount int64, symbol string) result.Result[struct{}] {
+	res := ctx.ledger.Burn(ledgerSystem.TokenParams{
+		Id:          ctx.env.TxId,
+		ContractId:  ctx.env.ContractId,
+		Account:     "contract:" + ctx.env.ContractId,
+		Asset:       ledgerSystem.TokenAsset(ctx.env.ContractId, symbol),
+		Amount:      amount,
+		BlockHeight: ctx.env.BlockHeight,
+	})
+	if !res.Ok {
+		return result.Err[struct{}](errors.Join(fmt.Errorf(contracts.LEDGER_ERROR), fmt.Errorf("%s", res.Msg)))
+	}
+	return result.Ok(struct{}{})
+}
+
 func (ctx *contractExecutionContext) ContractStateGet(contractId string, key string) result.Result[string] {
 	ctx.doIO(len(key))
 	res := ctx.callSession.GetStateStore(contractId).Get(key)
@@ -540,6 +689,7 @@ func (ctx *contractExecutionContext) ContractCall(
 				RequiredPostingAuths: ctx.env.RequiredPostingAuths,
 				Caller:               "contract:" + ctx.env.ContractId,
 				Sender:               ctx.env.Sender,
+				Payer:                ctx.env.Payer,
 				Intents:              opts.Intents,
 			}, ctx.rcLimit, 0, gasRemaining, ctx.ledger, ctx.callSession, nextRecursion)
 
@@ -636,6 +786,12 @@ func (ctx *contractExecutionContext) TssGetKey(keyId string) result.Result[strin
 }
 
 func (ctx *contractExecutionContext) TssKeySign(keyId string, msg string) result.Result[string] {
+	return ctx.TssKeySignCallback(keyId, msg, "", 0)
+}
+
+// Requests a signature and registers callback to be called with it once the
+// signature is ingested. rcLimit is reserved up front by the SDK.
+func (ctx *contractExecutionContext) TssKeySignCallback(keyId string, msg string, callback string, rcLimit uint) result.Result[string] {
 	fullKey := ctx.env.ContractId + "-" + keyId
 
 	keyInfo, err := ctx.callSession.TssKeys.FindKey(fullKey)
@@ -651,9 +807,11 @@ func (ctx *contractExecutionContext) TssKeySign(keyId string, msg string) result
 	}
 
 	ctx.callSession.AppendTssLog(ctx.env.ContractId, tss_db.TssOp{
-		Type:  "sign",
-		KeyId: fullKey,
-		Args:  msg,
+		Type:     "sign",
+		KeyId:    fullKey,
+		Args:     msg,
+		Callback: callback,
+		RcLimit:  rcLimit,
 	})
 	return result.Ok("ok")
 }
@@ -0,0 +1,51 @@
+package contract_execution_context_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"vsc-node/lib/test_utils"
+	"vsc-node/modules/common/params"
+	contract_execution_context "vsc-node/modules/contract/execution-context"
+	"vsc-node/modules/db/vsc/contracts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitEventLimits(t *testing.T) {
+	ct := test_utils.NewContractTest()
+	ctx := contract_execution_context.New(contract_execution_context.Environment{ContractId: "contract"}, 1000, 0, 1_000_000_000, nil, ct.CallSession, 0)
+
+	topics := func(n int, length int) string {
+		list := make([]string, n)
+		for i := range list {
+			list[i] = `"` + strings.Repeat("t", length) + `"`
+		}
+		return "[" + strings.Join(list, ",") + "]"
+	}
+	maxName := strings.Repeat("n", params.CONTRACT_EVENT_MAX_NAME_LENGTH)
+	maxTopic := strings.Repeat("t", params.CONTRACT_EVENT_MAX_TOPIC_LENGTH)
+
+	assert.True(t, ctx.EmitEvent(maxName, topics(params.CONTRACT_EVENT_MAX_TOPICS, params.CONTRACT_EVENT_MAX_TOPIC_LENGTH), `{"a": 1}`).IsOk())
+	assert.True(t, ctx.EmitEvent("empty", "", "").IsOk())
+
+	failures := map[string][3]string{
+		"empty name":        {"", "[]", "{}"},
+		"long name":         {maxName + "n", "[]", "{}"},
+		"too many topics":   {"event", topics(params.CONTRACT_EVENT_MAX_TOPICS+1, 1), "{}"},
+		"long topic":        {"event", topics(1, params.CONTRACT_EVENT_MAX_TOPIC_LENGTH+1), "{}"},
+		"topics not a list": {"event", `{"a":"b"}`, "{}"},
+		"invalid data":      {"event", "[]", "{"},
+	}
+	for name, args := range failures {
+		assert.True(t, ctx.EmitEvent(args[0], args[1], args[2]).IsErr(), name)
+	}
+
+	//Only the valid events are recorded, with compacted data
+	events := ct.CallSession.PopLogs()["contract"].Events
+	assert.Equal(t, []contracts.ContractEvent{
+		{Name: maxName, Topics: slices.Repeat([]string{maxTopic}, params.CONTRACT_EVENT_MAX_TOPICS), Data: `{"a":1}`},
+		{Name: "empty", Topics: []string{}, Data: "null"},
+	}, events)
+}
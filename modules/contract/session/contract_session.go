@@ -21,8 +21,10 @@ type ContractWithCode struct {
 }
 
 type LogOutput struct {
-	Logs   []string
-	TssOps []tss_db.TssOp
+	Logs      []string
+	TssOps    []tss_db.TssOp
+	Events    []contracts.ContractEvent
+	Schedules []contracts.ScheduleOp
 }
 
 // Session for transaction with contract calls
@@ -37,6 +39,9 @@ type CallSession struct {
 
 	// pending carries the temp contract outputs already produced earlier in the slot.
 	pending map[string]*TempOutput
+
+	// number of schedules created in the session, keeps their IDs unique
+	scheduleSeq int
 }
 
 // Create a new contract call session for a transaction.
@@ -144,8 +149,10 @@ func (cs *CallSession) PopLogs() map[string]LogOutput {
 	result := make(map[string]LogOutput)
 	for id, session := range cs.sessions {
 		result[id] = LogOutput{
-			Logs:   session.PopLogs(),
-			TssOps: session.PopTssLogs(),
+			Logs:      session.PopLogs(),
+			TssOps:    session.PopTssLogs(),
+			Events:    session.PopEvents(),
+			Schedules: session.PopSchedules(),
 		}
 	}
 	return result
@@ -156,6 +163,24 @@ func (cs *CallSession) AppendTssLog(contractId string, op tss_db.TssOp) {
 	session.tssOps = append(session.tssOps, op)
 }
 
+// Append a structured event for a contract
+func (cs *CallSession) AppendEvent(contractId string, event contracts.ContractEvent) {
+	session := cs.GetContractSession(contractId)
+	session.events = append(session.events, event)
+}
+
+// Append a schedule change for a contract
+func (cs *CallSession) AppendSchedule(contractId string, op contracts.ScheduleOp) {
+	session := cs.GetContractSession(contractId)
+	session.schedules = append(session.schedules, op)
+}
+
+// Next sequence number of a schedule created in the session
+func (cs *CallSession) NextScheduleSeq() int {
+	cs.scheduleSeq++
+	return cs.scheduleSeq
+}
+
 // Clear ephemeral contract state. Used in contract tests only.
 func (cs *CallSession) ClearEphemState(contractId ...string) {
 	if len(contractId) > 0 {
@@ -199,6 +224,7 @@ func (cs *CallSession) Rollback() {
 	for _, session := range cs.sessions {
 		session.state.Rollback()
 		session.PopLogs()
+		session.PopEvents()
 	}
 }
 
@@ -242,8 +268,10 @@ type ContractSession struct {
 	stateMerkle string
 	state       *StateStore
 
-	logs   []string
-	tssOps []tss_db.TssOp
+	logs      []string
+	tssOps    []tss_db.TssOp
+	events    []contracts.ContractEvent
+	schedules []contracts.ScheduleOp
 }
 
 func NewContractSession(dl *datalayer.DataLayer, output TempOutput) *ContractSession {
@@ -314,6 +342,18 @@ func (cs *ContractSession) PopTssLogs() []tss_db.TssOp {
 	return popped
 }
 
+func (cs *ContractSession) PopEvents() []contracts.ContractEvent {
+	popped := cs.events
+	cs.events = make([]contracts.ContractEvent, 0)
+	return popped
+}
+
+func (cs *ContractSession) PopSchedules() []contracts.ScheduleOp {
+	popped := cs.schedules
+	cs.schedules = make([]contracts.ScheduleOp, 0)
+	return popped
+}
+
 type StateStore struct {
 	cache     map[string][]byte
 	deletions map[string]bool
@@ -323,6 +363,9 @@ type StateStore struct {
 
 	// ephemeral state that only exists throughout the transaction
 	ephem map[string][]byte
+
+	// walks the stored keys, databin.Walk if nil
+	walk func(start string, end string, fn func(key string) bool) (int, error)
 }
 
 type stateKeyDiff struct {
@@ -371,6 +414,59 @@ func (ss *StateStore) Delete(key string) {
 	ss.deletions[key] = true
 }
 
+// Sorted keys within [start, end) including uncommitted writes. An empty end is unbounded.
+// Returns at most limit keys, along with the first key past the page or "" if there is none.
+// The stored state is walked lazily, the number of nodes visited is returned so it can be charged for.
+func (ss *StateStore) Keys(start string, end string, limit int) ([]string, string, int) {
+	inRange := func(key string) bool {
+		return key >= start && (end == "" || key < end)
+	}
+
+	keys := make([]string, 0, limit+1)
+	for key, val := range ss.cache {
+		if val != nil && inRange(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	//The first limit + 1 stored keys are enough to fill the page and find the next key
+	stored := 0
+	walk := ss.walk
+	if walk == nil {
+		walk = ss.databin.Walk
+	}
+	visited, _ := walk(start, end, func(key string) bool {
+		if ss.deletions[key] {
+			return true
+		}
+		keys = append(keys, key)
+		stored++
+		return stored <= limit
+	})
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+	if len(keys) > limit {
+		return keys[:limit], keys[limit], visited
+	}
+	return keys, "", visited
+}
+
+// Keys starting with prefix, resuming after cursor ("" for the first page).
+// Returns at most limit keys, along with the cursor of the next page or "" if there is none,
+// and the number of nodes visited.
+func (ss *StateStore) ListKeys(prefix string, cursor string, limit int) ([]string, string, int) {
+	start := prefix
+	if after := cursor + "\x00"; cursor != "" && after > start {
+		start = after
+	}
+	keys, next, visited := ss.Keys(start, datalayer.PrefixEnd(prefix), limit)
+	if next == "" || len(keys) == 0 {
+		return keys, "", visited
+	}
+	return keys, keys[len(keys)-1], visited
+}
+
 func (ss *StateStore) GetEphem(key string) []byte {
 	return ss.ephem[key]
 }
@@ -1,6 +1,8 @@
 package contract_session
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"vsc-node/modules/db/vsc/contracts"
@@ -76,3 +78,107 @@ func TestCloneTempOutputsDeepCopy(t *testing.T) {
 		t.Fatalf("deletions map should have been deep cloned")
 	}
 }
+
+func TestStateStoreKeysMergesPendingWrites(t *testing.T) {
+	ss := &StateStore{
+		cache: map[string][]byte{
+			"users/carol": {1},
+			"users/dave":  {2},
+			"supply":      {3},
+			"users/eve":   nil,
+		},
+		deletions: map[string]bool{"users/bob": true},
+		walk:      walkSorted([]string{"config", "users/alice", "users/bob", "users/dave"}),
+	}
+
+	keys, next, _ := ss.Keys("", "", 10)
+	expected := []string{"config", "supply", "users/alice", "users/carol", "users/dave"}
+	if !slices.Equal(keys, expected) || next != "" {
+		t.Fatalf("expected %v, got %v next %q", expected, keys, next)
+	}
+
+	keys, next, _ = ss.Keys("supply", "users/d", 2)
+	if !slices.Equal(keys, []string{"supply", "users/alice"}) || next != "users/carol" {
+		t.Fatalf("unexpected range page %v next %q", keys, next)
+	}
+}
+
+func TestStateStoreKeysStopsWalking(t *testing.T) {
+	stored := make([]string, 0)
+	for i := 0; i < 1000; i++ {
+		stored = append(stored, fmt.Sprintf("key%04d", i))
+	}
+	ss := &StateStore{
+		cache:     map[string][]byte{},
+		deletions: map[string]bool{"key0001": true},
+		walk:      walkSorted(stored),
+	}
+
+	keys, next, visited := ss.Keys("key0000", "", 2)
+	if !slices.Equal(keys, []string{"key0000", "key0002"}) || next != "key0003" {
+		t.Fatalf("unexpected page %v next %q", keys, next)
+	}
+	if visited != 4 {
+		t.Fatalf("expected the walk to stop after 4 keys, visited %d", visited)
+	}
+}
+
+func TestStateStoreListKeysPaginates(t *testing.T) {
+	ss := &StateStore{
+		cache:     map[string][]byte{"users/carol": {1}},
+		deletions: map[string]bool{},
+		walk:      walkSorted([]string{"supply", "users/alice", "users/bob", "usersx"}),
+	}
+
+	keys, cursor, _ := ss.ListKeys("users/", "", 2)
+	if !slices.Equal(keys, []string{"users/alice", "users/bob"}) || cursor != "users/bob" {
+		t.Fatalf("unexpected first page %v cursor %q", keys, cursor)
+	}
+	keys, cursor, _ = ss.ListKeys("users/", cursor, 2)
+	if !slices.Equal(keys, []string{"users/carol"}) || cursor != "" {
+		t.Fatalf("unexpected last page %v cursor %q", keys, cursor)
+	}
+}
+
+// Walks sorted keys, counting every key read
+func walkSorted(stored []string) func(string, string, func(string) bool) (int, error) {
+	return func(start string, end string, fn func(string) bool) (int, error) {
+		visited := 0
+		for _, key := range stored {
+			if key < start {
+				continue
+			}
+			if end != "" && key >= end {
+				break
+			}
+			visited++
+			if !fn(key) {
+				break
+			}
+		}
+		return visited, nil
+	}
+}
+
+func TestCallSessionEvents(t *testing.T) {
+	sess := &ContractSession{cache: map[string][]byte{}, deletions: map[string]bool{}}
+	sess.state = &StateStore{cs: sess}
+	cs := &CallSession{sessions: map[string]*ContractSession{"contract": sess}}
+
+	cs.AppendEvent("contract", contracts.ContractEvent{Name: "transfer", Topics: []string{"alice"}, Data: "{}"})
+	cs.AppendEvent("contract", contracts.ContractEvent{Name: "mint", Topics: []string{}, Data: "1"})
+	events := cs.PopLogs()["contract"].Events
+	if len(events) != 2 || events[0].Name != "transfer" || events[1].Name != "mint" {
+		t.Fatalf("unexpected events %#v", events)
+	}
+	if len(cs.PopLogs()["contract"].Events) != 0 {
+		t.Fatalf("expected events to be popped")
+	}
+
+	//Events of a reverted call are discarded
+	cs.AppendEvent("contract", contracts.ContractEvent{Name: "transfer"})
+	cs.Rollback()
+	if len(cs.PopLogs()["contract"].Events) != 0 {
+		t.Fatalf("expected events to be discarded on rollback")
+	}
+}
@@ -35,17 +35,12 @@ func (d *DataAvailability) stopP2P() error {
 func (s p2pSpec) HandleMessage(ctx context.Context, from peer.ID, msg p2pMessage, send libp2p.SendFunc[p2pMessage]) error {
 	switch msg.Type() {
 	case data_availability_spec.P2pMessageData:
-		provider, err := s.Conf().BlsProvider()
-		if err != nil {
-			return err
-		}
-
 		c, err := s.Datalayer().PutRaw(msg.Data(), common_types.PutRawOptions{Pin: true})
 		if err != nil {
 			return fmt.Errorf("failed to create cid: %w", err)
 		}
 
-		sig, err := provider.SignRaw(*c)
+		sig, err := s.Conf().Signer().SignCid(*c)
 		if err != nil {
 			return fmt.Errorf("failed to sign data: %w", err)
 		}
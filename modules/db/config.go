@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"vsc-node/modules/config"
 )
 
@@ -11,13 +12,26 @@ var ErrEmptyURI = errors.New("empty MongoDB URI")
 
 const DefaultDbName = "go-vsc"
 
+// Storage backends
+const (
+	BackendMongo = "mongo"
+	//Embedded badger store, no MongoDB server needed. GraphQL list queries
+	//scan in memory, so GraphQL heavy deployments should stay on Mongo.
+	BackendBadger = "badger"
+)
+
 type dbConfig struct {
 	DbURI  string
 	DbName string
+	//Either mongo (default) or badger
+	Backend string
+	//Directory of the badger backend, defaults to <data dir>/db
+	KvPath string
 }
 
 type dbConfigStruct struct {
 	*config.Config[dbConfig]
+	dataDir string
 }
 
 type DbConfig = *dbConfigStruct
@@ -28,10 +42,15 @@ func NewDbConfig(dataDir ...string) DbConfig {
 		dataDirPtr = &dataDir[0]
 	}
 
-	return &dbConfigStruct{config.New(dbConfig{
-		DbURI:  "mongodb://localhost:27017",
-		DbName: DefaultDbName,
+	conf := &dbConfigStruct{Config: config.New(dbConfig{
+		DbURI:   "mongodb://localhost:27017",
+		DbName:  DefaultDbName,
+		Backend: BackendMongo,
 	}, dataDirPtr)}
+	if dataDirPtr != nil {
+		conf.dataDir = *dataDirPtr
+	}
+	return conf
 }
 
 func (dc *dbConfigStruct) Init() error {
@@ -53,7 +72,17 @@ func (dc *dbConfigStruct) Init() error {
 		return err
 	}
 
-	return nil
+	backend := os.Getenv("DB_BACKEND")
+	if backend != "" {
+		return dc.SetBackend(backend)
+	}
+
+	switch dc.GetBackend() {
+	case BackendMongo, BackendBadger:
+		return nil
+	default:
+		return fmt.Errorf("unknown db backend %s", dc.Get().Backend)
+	}
 }
 
 func (dc *dbConfigStruct) SetDbURI(uri string) error {
@@ -77,3 +106,30 @@ func (dc *dbConfigStruct) SetDbName(name string) error {
 func (dc *dbConfigStruct) GetDbName() string {
 	return dc.Get().DbName
 }
+
+func (dc *dbConfigStruct) SetBackend(backend string) error {
+	if backend != BackendMongo && backend != BackendBadger {
+		return fmt.Errorf("unknown db backend %s", backend)
+	}
+	return dc.Update(func(dc *dbConfig) {
+		dc.Backend = backend
+	})
+}
+
+// Configs written before the backend option existed use Mongo
+func (dc *dbConfigStruct) GetBackend() string {
+	if dc.Get().Backend == "" {
+		return BackendMongo
+	}
+	return dc.Get().Backend
+}
+
+func (dc *dbConfigStruct) KvDir() string {
+	if dc.Get().KvPath != "" {
+		return dc.Get().KvPath
+	}
+	if dc.dataDir != "" {
+		return path.Join(dc.dataDir, "db")
+	}
+	return "data/db"
+}
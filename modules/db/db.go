@@ -12,10 +12,13 @@ import (
 
 type Db interface {
 	Database(name string, opts ...*options.DatabaseOptions) *mongo.Database
+	//Store of the badger backend, nil when using Mongo
+	Kv() *KvStore
 }
 type db struct {
 	conf   DbConfig
 	cancel context.CancelFunc
+	kv     *KvStore
 	*mongo.Client
 }
 
@@ -23,13 +26,17 @@ var _ a.Plugin = &db{}
 var _ Db = &db{}
 
 func New(conf DbConfig) *db {
-	return &db{conf: conf}
+	return &db{conf: conf, kv: NewKvStore()}
 }
 
 func (db *db) Init() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	db.cancel = cancel
 
+	if db.conf.GetBackend() == BackendBadger {
+		return db.kv.Open(db.conf.KvDir())
+	}
+
 	c, err := mongo.Connect(ctx, options.Client().ApplyURI(db.conf.Get().DbURI))
 	if err != nil {
 		return err
@@ -49,5 +56,12 @@ func (db *db) Start() *promise.Promise[any] {
 
 func (db *db) Stop() error {
 	db.cancel()
-	return nil
+	return db.kv.Close()
+}
+
+func (db *db) Kv() *KvStore {
+	if db.conf.GetBackend() != BackendBadger {
+		return nil
+	}
+	return db.kv
 }
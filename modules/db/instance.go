@@ -31,6 +31,9 @@ func NewDbInstance(db Db, conf DbConfig, opts ...*options.DatabaseOptions) *DbIn
 
 // Init implements aggregate.Plugin.
 func (d *DbInstance) Init() error {
+	if d.Kv() != nil {
+		return nil
+	}
 	d.Database = d.db.Database(d.conf.GetDbName(), d.opts...)
 	return nil
 }
@@ -46,5 +49,21 @@ func (d *DbInstance) Stop() error {
 }
 
 func (d *DbInstance) Clear() error {
+	if kv := d.Kv(); kv != nil {
+		names, err := kv.CollectionNames()
+		if err != nil {
+			return err
+		}
+		return kv.DropCollections(names...)
+	}
 	return d.Drop(context.TODO())
 }
+
+// Store of the badger backend, nil when using Mongo.
+// Collections pick their implementation based on it.
+func (d *DbInstance) Kv() *KvStore {
+	if d.db == nil {
+		return nil
+	}
+	return d.db.Kv()
+}
@@ -0,0 +1,467 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// How often the value log of the kv store is garbage collected
+var KvGcInterval = 10 * time.Minute
+
+// Embedded key value store used by the badger backend in place of MongoDB.
+//
+// Records of a collection live under "<collection>/<key>" and secondary index
+// entries under "<collection>@<index>/<index key>/<key>". Keys are built with
+// KvKey so numeric parts sort numerically.
+type KvStore struct {
+	db *badger.DB
+	//Serializes writes, so read-modify-write updates don't race
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+func NewKvStore() *KvStore {
+	return &KvStore{}
+}
+
+// Opens the badger database in dir
+func (s *KvStore) Open(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return s.open(badger.DefaultOptions(dir))
+}
+
+// Opens a store kept entirely in memory, for tests and throwaway nodes
+func (s *KvStore) OpenInMemory() error {
+	return s.open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+}
+
+func (s *KvStore) open(opts badger.Options) error {
+	d, err := badger.Open(opts)
+	if err != nil {
+		return fmt.Errorf("failed to open kv store: %w", err)
+	}
+	s.db = d
+	s.stop = make(chan struct{})
+	if !opts.InMemory {
+		go s.gc()
+	}
+	return nil
+}
+
+func (s *KvStore) gc() {
+	ticker := time.NewTicker(KvGcInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			for s.db.RunValueLogGC(0.5) == nil {
+			}
+		}
+	}
+}
+
+func (s *KvStore) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	close(s.stop)
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+// Names of the collections holding at least one record
+func (s *KvStore) CollectionNames() ([]string, error) {
+	names := make([]string, 0)
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := string(it.Item().Key())
+			name, _, found := strings.Cut(key, "/")
+			if !found || strings.Contains(name, "@") {
+				continue
+			}
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		return nil
+	})
+	return names, err
+}
+
+// Deletes every record and index entry of the collections
+func (s *KvStore) DropCollections(names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefixes := make([][]byte, 0, len(names)*2)
+	for _, name := range names {
+		prefixes = append(prefixes, []byte(name+"/"), []byte(name+"@"))
+	}
+	return s.db.DropPrefix(prefixes...)
+}
+
+func (s *KvStore) getRaw(key string) ([]byte, error) {
+	var val []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		val, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, mongo.ErrNoDocuments
+	}
+	return val, err
+}
+
+func (s *KvStore) setRaw(key string, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), val)
+	})
+}
+
+var kvEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// Encodes the parts of a key. Strings are escaped and unsigned integers zero
+// padded, so keys sort by their parts in order. Signed integers must not be
+// negative.
+func KvKey(parts ...any) string {
+	encoded := make([]string, 0, len(parts))
+	for _, part := range parts {
+		switch v := part.(type) {
+		case string:
+			encoded = append(encoded, kvEscaper.Replace(v))
+		case uint64:
+			encoded = append(encoded, fmt.Sprintf("%020d", v))
+		case int64:
+			encoded = append(encoded, fmt.Sprintf("%020d", uint64(max(v, 0))))
+		case int:
+			encoded = append(encoded, fmt.Sprintf("%020d", uint64(max(v, 0))))
+		default:
+			encoded = append(encoded, kvEscaper.Replace(fmt.Sprint(v)))
+		}
+	}
+	return strings.Join(encoded, "/")
+}
+
+// A record of a kv collection. The ID is assigned on first insert and
+// orders records like the ObjectIDs of Mongo documents.
+type KvEntry[T any] struct {
+	Key string
+	Id  primitive.ObjectID
+	Doc T
+}
+
+type kvRecord[T any] struct {
+	Id  primitive.ObjectID `bson:"_id" json:"_id"`
+	Doc T                  `bson:"doc" json:"doc"`
+}
+
+// Range of keys scanned in a collection or one of its indexes.
+// From and To are inclusive and follow the Prefix parts.
+type KvRange struct {
+	Index   string
+	Prefix  []any
+	From    []any
+	To      []any
+	Reverse bool
+}
+
+// Typed collection of a kv store. Documents are BSON encoded by default,
+// like they would be in Mongo.
+type KvCollection[T any] struct {
+	store   *KvStore
+	name    string
+	indexes map[string]func(doc T) []any
+
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+}
+
+func NewKvCollection[T any](store *KvStore, name string) *KvCollection[T] {
+	return &KvCollection[T]{
+		store:     store,
+		name:      name,
+		indexes:   map[string]func(doc T) []any{},
+		marshal:   bson.Marshal,
+		unmarshal: bson.Unmarshal,
+	}
+}
+
+// Maintains a secondary index over the parts returned by key.
+// Documents for which key returns nil are left out of the index.
+func (c *KvCollection[T]) WithIndex(name string, key func(doc T) []any) *KvCollection[T] {
+	c.indexes[name] = key
+	return c
+}
+
+// Encodes documents as JSON instead of BSON
+func (c *KvCollection[T]) WithJson() *KvCollection[T] {
+	c.marshal = json.Marshal
+	c.unmarshal = json.Unmarshal
+	return c
+}
+
+func (c *KvCollection[T]) recordKey(key string) []byte {
+	return []byte(c.name + "/" + key)
+}
+
+func (c *KvCollection[T]) indexKey(index string, parts []any, key string) []byte {
+	return []byte(c.name + "@" + index + "/" + KvKey(parts...) + "/" + key)
+}
+
+func (c *KvCollection[T]) decode(val []byte) (kvRecord[T], error) {
+	rec := kvRecord[T]{}
+	err := c.unmarshal(val, &rec)
+	return rec, err
+}
+
+func (c *KvCollection[T]) get(txn *badger.Txn, key string) (*kvRecord[T], error) {
+	item, err := txn.Get(c.recordKey(key))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	val, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := c.decode(val)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to decode %s: %w", c.name, key, err)
+	}
+	return &rec, nil
+}
+
+func (c *KvCollection[T]) write(txn *badger.Txn, key string, old *kvRecord[T], rec kvRecord[T]) error {
+	for name, index := range c.indexes {
+		if old != nil {
+			if parts := index(old.Doc); parts != nil {
+				if err := txn.Delete(c.indexKey(name, parts, key)); err != nil {
+					return err
+				}
+			}
+		}
+		if parts := index(rec.Doc); parts != nil {
+			if err := txn.Set(c.indexKey(name, parts, key), []byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	val, err := c.marshal(rec)
+	if err != nil {
+		return err
+	}
+	return txn.Set(c.recordKey(key), val)
+}
+
+// Returns mongo.ErrNoDocuments when there is no record under key
+func (c *KvCollection[T]) Get(key string) (KvEntry[T], error) {
+	var entry KvEntry[T]
+	err := c.store.db.View(func(txn *badger.Txn) error {
+		rec, err := c.get(txn, key)
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			return mongo.ErrNoDocuments
+		}
+		entry = KvEntry[T]{key, rec.Id, rec.Doc}
+		return nil
+	})
+	return entry, err
+}
+
+// Inserts or replaces the record under key
+func (c *KvCollection[T]) Put(key string, doc T) error {
+	return c.Update(key, func(d *T, found bool) bool {
+		*d = doc
+		return true
+	})
+}
+
+// Atomically reads, modifies and writes back the record under key.
+// update receives the zero value when the record doesn't exist, and
+// returns false to leave the record untouched.
+func (c *KvCollection[T]) Update(key string, update func(doc *T, found bool) bool) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	return c.store.db.Update(func(txn *badger.Txn) error {
+		old, err := c.get(txn, key)
+		if err != nil {
+			return err
+		}
+		rec := kvRecord[T]{Id: primitive.NewObjectID()}
+		if old != nil {
+			rec = *old
+		}
+		if !update(&rec.Doc, old != nil) {
+			return nil
+		}
+		return c.write(txn, key, old, rec)
+	})
+}
+
+func (c *KvCollection[T]) Delete(key string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	return c.store.db.Update(func(txn *badger.Txn) error {
+		old, err := c.get(txn, key)
+		if err != nil || old == nil {
+			return err
+		}
+		for name, index := range c.indexes {
+			if parts := index(old.Doc); parts != nil {
+				if err := txn.Delete(c.indexKey(name, parts, key)); err != nil {
+					return err
+				}
+			}
+		}
+		return txn.Delete(c.recordKey(key))
+	})
+}
+
+// Calls fn with the records in r, in key order, until it returns false
+func (c *KvCollection[T]) Scan(r KvRange, fn func(entry KvEntry[T]) bool) error {
+	space := c.name + "/"
+	if r.Index != "" {
+		if _, ok := c.indexes[r.Index]; !ok {
+			return fmt.Errorf("%s: unknown index %s", c.name, r.Index)
+		}
+		space = c.name + "@" + r.Index + "/"
+	}
+	prefix := space
+	if len(r.Prefix) > 0 {
+		prefix += KvKey(r.Prefix...) + "/"
+	}
+	lower := []byte(prefix)
+	if len(r.From) > 0 {
+		lower = []byte(prefix + KvKey(r.From...))
+	}
+	upper := append([]byte(prefix), 0xff)
+	if len(r.To) > 0 {
+		upper = append([]byte(prefix+KvKey(r.To...)+"/"), 0xff)
+	}
+
+	return c.store.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(prefix)
+		opts.Reverse = r.Reverse
+		opts.PrefetchValues = r.Index == ""
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		//Reverse iteration seeks to the last key at or below the upper bound
+		if r.Reverse {
+			it.Seek(upper)
+		} else {
+			it.Seek(lower)
+		}
+		for ; it.ValidForPrefix([]byte(prefix)); it.Next() {
+			item := it.Item()
+			if !r.Reverse && bytes.Compare(item.Key(), upper) > 0 {
+				break
+			}
+			if r.Reverse && bytes.Compare(item.Key(), lower) < 0 {
+				break
+			}
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			var entry KvEntry[T]
+			if r.Index == "" {
+				rec, err := c.decode(val)
+				if err != nil {
+					return fmt.Errorf("%s: failed to decode %s: %w", c.name, item.Key(), err)
+				}
+				entry = KvEntry[T]{strings.TrimPrefix(string(item.Key()), space), rec.Id, rec.Doc}
+			} else {
+				rec, err := c.get(txn, string(val))
+				if err != nil {
+					return err
+				}
+				if rec == nil {
+					continue
+				}
+				entry = KvEntry[T]{string(val), rec.Id, rec.Doc}
+			}
+			if !fn(entry) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// Records in r for which filter returns true. A nil filter matches everything.
+func (c *KvCollection[T]) Find(r KvRange, filter func(doc T) bool) ([]KvEntry[T], error) {
+	entries := make([]KvEntry[T], 0)
+	err := c.Scan(r, func(entry KvEntry[T]) bool {
+		if filter == nil || filter(entry.Doc) {
+			entries = append(entries, entry)
+		}
+		return true
+	})
+	return entries, err
+}
+
+// First record in r for which filter returns true, or mongo.ErrNoDocuments
+func (c *KvCollection[T]) First(r KvRange, filter func(doc T) bool) (KvEntry[T], error) {
+	var entry KvEntry[T]
+	found := false
+	err := c.Scan(r, func(e KvEntry[T]) bool {
+		if filter == nil || filter(e.Doc) {
+			entry = e
+			found = true
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return KvEntry[T]{}, err
+	}
+	if !found {
+		return KvEntry[T]{}, mongo.ErrNoDocuments
+	}
+	return entry, nil
+}
+
+// Documents of the entries, in order
+func KvDocs[T any](entries []KvEntry[T]) []T {
+	docs := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		docs = append(docs, entry.Doc)
+	}
+	return docs
+}
@@ -0,0 +1,130 @@
+package db_test
+
+import (
+	"testing"
+	"vsc-node/modules/db"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type kvDoc struct {
+	Account string `bson:"account"`
+	Height  uint64 `bson:"height"`
+	Status  string `bson:"status"`
+}
+
+func openKvCollection(t *testing.T) *db.KvCollection[kvDoc] {
+	store := db.NewKvStore()
+	if err := store.OpenInMemory(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return db.NewKvCollection[kvDoc](store, "docs").
+		WithIndex("status", func(d kvDoc) []any { return []any{d.Status, d.Height} })
+}
+
+func putDocs(t *testing.T, docs *db.KvCollection[kvDoc], records ...kvDoc) {
+	for _, record := range records {
+		if err := docs.Put(db.KvKey(record.Account, record.Height), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func heights(entries []db.KvEntry[kvDoc]) []uint64 {
+	res := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, entry.Doc.Height)
+	}
+	return res
+}
+
+func TestKvKeyOrder(t *testing.T) {
+	assert.Less(t, db.KvKey("a", uint64(9)), db.KvKey("a", uint64(10)))
+	assert.Less(t, db.KvKey("a/b"), db.KvKey("a", "b"))
+	assert.Equal(t, db.KvKey(uint64(5)), db.KvKey(5))
+}
+
+func TestKvGetUpdateDelete(t *testing.T) {
+	docs := openKvCollection(t)
+
+	_, err := docs.Get(db.KvKey("alice", uint64(1)))
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	putDocs(t, docs, kvDoc{"alice", 1, "pending"})
+	entry, err := docs.Get(db.KvKey("alice", uint64(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", entry.Doc.Status)
+	assert.False(t, entry.Id.IsZero())
+
+	//Returning false leaves the record untouched
+	err = docs.Update(db.KvKey("bob", uint64(1)), func(doc *kvDoc, found bool) bool {
+		doc.Status = "done"
+		return found
+	})
+	assert.NoError(t, err)
+	_, err = docs.Get(db.KvKey("bob", uint64(1)))
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	err = docs.Update(db.KvKey("alice", uint64(1)), func(doc *kvDoc, found bool) bool {
+		doc.Status = "done"
+		return found
+	})
+	assert.NoError(t, err)
+	updated, _ := docs.Get(db.KvKey("alice", uint64(1)))
+	assert.Equal(t, "done", updated.Doc.Status)
+	assert.Equal(t, entry.Id, updated.Id)
+
+	//The index follows the update
+	pending, err := docs.Find(db.KvRange{Index: "status", Prefix: []any{"pending"}}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+	done, err := docs.Find(db.KvRange{Index: "status", Prefix: []any{"done"}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, heights(done))
+	assert.Equal(t, db.KvKey("alice", uint64(1)), done[0].Key)
+
+	assert.NoError(t, docs.Delete(db.KvKey("alice", uint64(1))))
+	_, err = docs.Get(db.KvKey("alice", uint64(1)))
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	done, _ = docs.Find(db.KvRange{Index: "status", Prefix: []any{"done"}}, nil)
+	assert.Empty(t, done)
+}
+
+func TestKvScanRange(t *testing.T) {
+	docs := openKvCollection(t)
+	putDocs(t, docs,
+		kvDoc{"alice", 5, "pending"},
+		kvDoc{"alice", 10, "done"},
+		kvDoc{"alice", 20, "pending"},
+		kvDoc{"alice", 100, "done"},
+		kvDoc{"alicia", 1, "pending"},
+	)
+
+	all, err := docs.Find(db.KvRange{Prefix: []any{"alice"}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5, 10, 20, 100}, heights(all))
+
+	bounded, err := docs.Find(db.KvRange{Prefix: []any{"alice"}, From: []any{uint64(10)}, To: []any{uint64(20)}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{10, 20}, heights(bounded))
+
+	reverse, err := docs.Find(db.KvRange{Prefix: []any{"alice"}, To: []any{uint64(50)}, Reverse: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{20, 10, 5}, heights(reverse))
+
+	latest, err := docs.First(db.KvRange{Prefix: []any{"alice"}, To: []any{uint64(19)}, Reverse: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), latest.Doc.Height)
+
+	_, err = docs.First(db.KvRange{Prefix: []any{"alice"}, To: []any{uint64(4)}, Reverse: true}, nil)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	pending, err := docs.Find(db.KvRange{Index: "status", Prefix: []any{"pending"}, Reverse: true}, func(d kvDoc) bool {
+		return d.Account == "alice"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{20, 5}, heights(pending))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -20,6 +21,10 @@ type DbReindex struct {
 }
 
 func (dbr *DbReindex) Init() error {
+	if kv := dbr.Kv(); kv != nil {
+		return dbr.reindexKv(kv)
+	}
+
 	ctx := context.Background()
 	col := dbr.Collection("hive_blocks")
 	findResult := col.FindOne(ctx, bson.M{
@@ -59,6 +64,44 @@ func (dbr *DbReindex) Init() error {
 	return nil
 }
 
+const kvReindexKey = "meta/reindex_id"
+
+func (dbr *DbReindex) reindexKv(kv *KvStore) error {
+	var indexId uint64
+	if raw, err := kv.getRaw(kvReindexKey); err == nil {
+		indexId, _ = strconv.ParseUint(string(raw), 10, 64)
+	}
+	if indexId == uint64(REINDEX_ID) {
+		return nil
+	}
+
+	fmt.Println("Reindexing database...")
+	cols, err := kv.CollectionNames()
+	if err != nil {
+		return err
+	}
+	cols = slices.DeleteFunc(cols, func(name string) bool {
+		return slices.Contains(IMMUTABLE_COLLECTIONS, name)
+	})
+	if err := kv.DropCollections(cols...); err != nil {
+		return err
+	}
+
+	//Same document the hive_blocks kv implementation keeps its metadata in
+	metadata := NewKvCollection[map[string]interface{}](kv, "hive_blocks").WithJson()
+	err = metadata.Update("metadata", func(doc *map[string]interface{}, found bool) bool {
+		if !found {
+			*doc = map[string]interface{}{"type": "metadata"}
+		}
+		(*doc)["last_processed_block"] = 0
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return kv.setRaw(kvReindexKey, []byte(strconv.Itoa(REINDEX_ID)))
+}
+
 func NewReindex(db *DbInstance) *DbReindex {
 	return &DbReindex{db}
 }
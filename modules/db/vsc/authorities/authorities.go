@@ -0,0 +1,57 @@
+package authorities
+
+import (
+	"context"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type authorities struct {
+	*db.Collection
+}
+
+func New(d *vsc.VscDb) Authorities {
+	if kv := d.Kv(); kv != nil {
+		return newKvAuthorities(kv)
+	}
+	return &authorities{db.NewCollection(d.DbInstance, "account_authorities")}
+}
+
+func (e *authorities) Init() error {
+	err := e.Collection.Init()
+	if err != nil {
+		return err
+	}
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "account", Value: 1}, {Key: "block_height", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err = e.Collection.Indexes().CreateOne(context.Background(), indexModel)
+	return err
+}
+
+func (e *authorities) GetAuthority(account string, blockHeight uint64) (AuthorityRecord, error) {
+	query := bson.M{
+		"account":      account,
+		"block_height": bson.M{"$lte": blockHeight},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "block_height", Value: -1}})
+
+	record := AuthorityRecord{}
+	err := e.FindOne(context.Background(), query, opts).Decode(&record)
+	return record, err
+}
+
+// Replaces the authority an account had set earlier in the same block
+func (e *authorities) SetAuthority(record AuthorityRecord) error {
+	_, err := e.ReplaceOne(context.Background(), bson.M{
+		"account":      record.Account,
+		"block_height": record.BlockHeight,
+	}, record, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,45 @@
+package authorities
+
+import (
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+)
+
+// Authority records on the embedded kv store, keyed by account and block height
+type kvAuthorities struct {
+	docs *db.KvCollection[AuthorityRecord]
+}
+
+func newKvAuthorities(store *db.KvStore) *kvAuthorities {
+	return &kvAuthorities{db.NewKvCollection[AuthorityRecord](store, "account_authorities")}
+}
+
+func (e *kvAuthorities) Init() error {
+	return nil
+}
+
+func (e *kvAuthorities) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (e *kvAuthorities) Stop() error {
+	return nil
+}
+
+func (e *kvAuthorities) GetAuthority(account string, blockHeight uint64) (AuthorityRecord, error) {
+	entry, err := e.docs.First(db.KvRange{
+		Prefix:  []any{account},
+		To:      []any{blockHeight},
+		Reverse: true,
+	}, nil)
+	if err != nil {
+		return AuthorityRecord{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (e *kvAuthorities) SetAuthority(record AuthorityRecord) error {
+	return e.docs.Put(db.KvKey(record.Account, record.BlockHeight), record)
+}
@@ -0,0 +1,31 @@
+package authorities
+
+import (
+	a "vsc-node/modules/aggregate"
+	"vsc-node/modules/common"
+)
+
+type Authorities interface {
+	a.Plugin
+	//Latest authority of an account at or before blockHeight
+	GetAuthority(account string, blockHeight uint64) (AuthorityRecord, error)
+	SetAuthority(record AuthorityRecord) error
+}
+
+// Keys allowed to sign for an account as of BlockHeight.
+// A record without keys returns the account to its own key.
+type AuthorityRecord struct {
+	Account     string                `json:"account" bson:"account"`
+	Keys        []common.AuthorityKey `json:"keys" bson:"keys"`
+	Threshold   uint64                `json:"threshold" bson:"threshold"`
+	BlockHeight uint64                `json:"block_height" bson:"block_height"`
+	TxId        string                `json:"tx_id" bson:"tx_id"`
+}
+
+// Authority the record sets, nil when the account is back to its own key
+func (r AuthorityRecord) Authority() *common.Authority {
+	if len(r.Keys) == 0 {
+		return nil
+	}
+	return &common.Authority{Keys: r.Keys, Threshold: r.Threshold}
+}
@@ -0,0 +1,117 @@
+package bridge
+
+import (
+	"context"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type withdrawals struct {
+	*db.Collection
+}
+
+func New(d *vsc.VscDb) Withdrawals {
+	if kv := d.Kv(); kv != nil {
+		return newKvWithdrawals(kv)
+	}
+	return &withdrawals{db.NewCollection(d.DbInstance, "bridge_withdrawals")}
+}
+
+func (e *withdrawals) Init() error {
+	err := e.Collection.Init()
+	if err != nil {
+		return err
+	}
+
+	_, err = e.Collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "key_id", Value: 1}, {Key: "msg", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "block_height", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "chain", Value: 1}, {Key: "key_id", Value: 1}, {Key: "status", Value: 1}, {Key: "block_height", Value: 1}},
+		},
+	})
+	return err
+}
+
+func (e *withdrawals) InsertWithdrawal(w Withdrawal) error {
+	_, err := e.UpdateOne(context.Background(), bson.M{
+		"id": w.Id,
+	}, bson.M{
+		"$setOnInsert": w,
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (e *withdrawals) GetWithdrawal(id string) (Withdrawal, error) {
+	record := Withdrawal{}
+	err := e.FindOne(context.Background(), bson.M{"id": id}).Decode(&record)
+	return record, err
+}
+
+func (e *withdrawals) UpdateWithdrawal(w Withdrawal) error {
+	_, err := e.ReplaceOne(context.Background(), bson.M{"id": w.Id}, w)
+	return err
+}
+
+func (e *withdrawals) FindBySignRequest(keyId string, msg string) ([]Withdrawal, error) {
+	return e.find(bson.M{"key_id": keyId, "msg": msg}, options.Find())
+}
+
+func (e *withdrawals) FindUnkeyed(chain string, limit int) ([]Withdrawal, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "block_height", Value: 1}, {Key: "id", Value: 1}}).
+		SetLimit(int64(limit))
+	return e.find(bson.M{"chain": chain, "key_id": "", "status": WithdrawalPending}, opts)
+}
+
+func (e *withdrawals) FindWithdrawals(account *string, chain *string, status *string, offset int, limit int) ([]Withdrawal, error) {
+	filter := bson.M{}
+	if account != nil {
+		filter["$or"] = bson.A{
+			bson.M{"from": *account},
+			bson.M{"to": *account},
+		}
+	}
+	if chain != nil {
+		filter["chain"] = *chain
+	}
+	if status != nil {
+		filter["status"] = *status
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "block_height", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	return e.find(filter, opts)
+}
+
+func (e *withdrawals) find(filter bson.M, opts *options.FindOptions) ([]Withdrawal, error) {
+	ctx := context.Background()
+	cursor, err := e.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]Withdrawal, 0)
+	for cursor.Next(ctx) {
+		var record Withdrawal
+		if err := cursor.Decode(&record); err != nil {
+			return nil, err
+		}
+		results = append(results, record)
+	}
+	return results, nil
+}
@@ -0,0 +1,128 @@
+package bridge
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Bridge withdrawals on the embedded kv store, keyed by withdrawal ID
+type kvWithdrawals struct {
+	docs *db.KvCollection[Withdrawal]
+}
+
+func newKvWithdrawals(store *db.KvStore) *kvWithdrawals {
+	return &kvWithdrawals{
+		docs: db.NewKvCollection[Withdrawal](store, "bridge_withdrawals").
+			WithIndex("sign_request", func(w Withdrawal) []any {
+				if w.KeyId == "" {
+					return nil
+				}
+				return []any{w.KeyId, w.Msg}
+			}).
+			WithIndex("unkeyed", func(w Withdrawal) []any {
+				if w.KeyId != "" || w.Status != WithdrawalPending {
+					return nil
+				}
+				return []any{w.Chain, w.BlockHeight}
+			}),
+	}
+}
+
+func (e *kvWithdrawals) Init() error {
+	return nil
+}
+
+func (e *kvWithdrawals) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (e *kvWithdrawals) Stop() error {
+	return nil
+}
+
+func (e *kvWithdrawals) InsertWithdrawal(w Withdrawal) error {
+	return e.docs.Update(db.KvKey(w.Id), func(doc *Withdrawal, found bool) bool {
+		if found {
+			return false
+		}
+		*doc = w
+		return true
+	})
+}
+
+func (e *kvWithdrawals) GetWithdrawal(id string) (Withdrawal, error) {
+	entry, err := e.docs.Get(db.KvKey(id))
+	if err != nil {
+		return Withdrawal{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (e *kvWithdrawals) UpdateWithdrawal(w Withdrawal) error {
+	exists := false
+	err := e.docs.Update(db.KvKey(w.Id), func(doc *Withdrawal, found bool) bool {
+		exists = found
+		*doc = w
+		return found
+	})
+	if err == nil && !exists {
+		err = mongo.ErrNoDocuments
+	}
+	return err
+}
+
+func (e *kvWithdrawals) FindBySignRequest(keyId string, msg string) ([]Withdrawal, error) {
+	entries, err := e.docs.Find(db.KvRange{Index: "sign_request", Prefix: []any{keyId, msg}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return db.KvDocs(entries), nil
+}
+
+func (e *kvWithdrawals) FindUnkeyed(chain string, limit int) ([]Withdrawal, error) {
+	results := make([]Withdrawal, 0)
+	err := e.docs.Scan(db.KvRange{Index: "unkeyed", Prefix: []any{chain}}, func(entry db.KvEntry[Withdrawal]) bool {
+		if len(results) >= limit {
+			return false
+		}
+		results = append(results, entry.Doc)
+		return true
+	})
+	return results, err
+}
+
+func (e *kvWithdrawals) FindWithdrawals(account *string, chain *string, status *string, offset int, limit int) ([]Withdrawal, error) {
+	entries, err := e.docs.Find(db.KvRange{}, func(w Withdrawal) bool {
+		if account != nil && w.From != *account && w.To != *account {
+			return false
+		}
+		if chain != nil && w.Chain != *chain {
+			return false
+		}
+		return status == nil || w.Status == *status
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entries, func(a, b db.KvEntry[Withdrawal]) int {
+		if c := cmp.Compare(b.Doc.BlockHeight, a.Doc.BlockHeight); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.Id[:], a.Id[:])
+	})
+
+	if offset >= len(entries) {
+		return make([]Withdrawal, 0), nil
+	}
+	entries = entries[offset:]
+	if limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return db.KvDocs(entries), nil
+}
@@ -0,0 +1,101 @@
+package bridge
+
+import (
+	"testing"
+	"vsc-node/modules/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func openKvWithdrawals(t *testing.T) *kvWithdrawals {
+	store := db.NewKvStore()
+	if err := store.OpenInMemory(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return newKvWithdrawals(store)
+}
+
+func TestKvWithdrawals(t *testing.T) {
+	withdrawals := openKvWithdrawals(t)
+
+	first := Withdrawal{Id: "tx1-0", From: "hive:alice", To: "eth:0x01", Chain: "ETH", KeyId: "key", Msg: "aa", Status: WithdrawalPending, BlockHeight: 10}
+	second := Withdrawal{Id: "tx2-0", From: "hive:bob", To: "eth:0x02", Chain: "ETH", KeyId: "key", Msg: "bb", Status: WithdrawalPending, BlockHeight: 20}
+	require.NoError(t, withdrawals.InsertWithdrawal(first))
+	require.NoError(t, withdrawals.InsertWithdrawal(second))
+
+	//Replayed inserts keep the progress of the withdrawal
+	signed := first
+	signed.Status = WithdrawalSigned
+	require.NoError(t, withdrawals.UpdateWithdrawal(signed))
+	require.NoError(t, withdrawals.InsertWithdrawal(first))
+	record, err := withdrawals.GetWithdrawal("tx1-0")
+	require.NoError(t, err)
+	assert.Equal(t, WithdrawalSigned, record.Status)
+
+	assert.ErrorIs(t, withdrawals.UpdateWithdrawal(Withdrawal{Id: "missing"}), mongo.ErrNoDocuments)
+
+	found, err := withdrawals.FindBySignRequest("key", "bb")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "tx2-0", found[0].Id)
+
+	all, err := withdrawals.FindWithdrawals(nil, nil, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "tx2-0", all[0].Id)
+
+	alice := "hive:alice"
+	byAccount, err := withdrawals.FindWithdrawals(&alice, nil, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, byAccount, 1)
+	assert.Equal(t, "tx1-0", byAccount[0].Id)
+
+	pending := WithdrawalPending
+	byStatus, err := withdrawals.FindWithdrawals(nil, nil, &pending, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, byStatus, 1)
+	assert.Equal(t, "tx2-0", byStatus[0].Id)
+
+	page, err := withdrawals.FindWithdrawals(nil, nil, nil, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "tx1-0", page[0].Id)
+}
+
+func TestKvUnkeyedWithdrawals(t *testing.T) {
+	withdrawals := openKvWithdrawals(t)
+
+	require.NoError(t, withdrawals.InsertWithdrawal(Withdrawal{Id: "tx2-0", Chain: "ETH", Status: WithdrawalPending, BlockHeight: 20}))
+	require.NoError(t, withdrawals.InsertWithdrawal(Withdrawal{Id: "tx1-0", Chain: "ETH", Status: WithdrawalPending, BlockHeight: 10}))
+	require.NoError(t, withdrawals.InsertWithdrawal(Withdrawal{Id: "tx3-0", Chain: "ETH", KeyId: "key", Msg: "cc", Status: WithdrawalPending, BlockHeight: 5}))
+	require.NoError(t, withdrawals.InsertWithdrawal(Withdrawal{Id: "tx4-0", Chain: "BTC", Status: WithdrawalPending, BlockHeight: 5}))
+
+	unkeyed, err := withdrawals.FindUnkeyed("ETH", 10)
+	require.NoError(t, err)
+	require.Len(t, unkeyed, 2)
+	assert.Equal(t, "tx1-0", unkeyed[0].Id)
+	assert.Equal(t, "tx2-0", unkeyed[1].Id)
+
+	//Keyed withdrawals leave the index
+	keyed := unkeyed[0]
+	keyed.KeyId = "key"
+	keyed.Msg = "aa"
+	require.NoError(t, withdrawals.UpdateWithdrawal(keyed))
+	unkeyed, err = withdrawals.FindUnkeyed("ETH", 1)
+	require.NoError(t, err)
+	require.Len(t, unkeyed, 1)
+	assert.Equal(t, "tx2-0", unkeyed[0].Id)
+}
+
+func TestCanAdvance(t *testing.T) {
+	assert.True(t, CanAdvance(WithdrawalSigned, WithdrawalBroadcast))
+	assert.True(t, CanAdvance(WithdrawalSigned, WithdrawalConfirmed))
+	assert.True(t, CanAdvance(WithdrawalBroadcast, WithdrawalConfirmed))
+	assert.False(t, CanAdvance(WithdrawalPending, WithdrawalBroadcast))
+	assert.False(t, CanAdvance(WithdrawalConfirmed, WithdrawalBroadcast))
+	assert.False(t, CanAdvance(WithdrawalBroadcast, WithdrawalBroadcast))
+	assert.False(t, CanAdvance(WithdrawalSigned, "failed"))
+}
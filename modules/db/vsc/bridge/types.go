@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	a "vsc-node/modules/aggregate"
+)
+
+type Withdrawals interface {
+	a.Plugin
+	//Records a new withdrawal. Withdrawals already recorded are left untouched.
+	InsertWithdrawal(w Withdrawal) error
+	GetWithdrawal(id string) (Withdrawal, error)
+	UpdateWithdrawal(w Withdrawal) error
+	//Withdrawals waiting on a signature of keyId over msg
+	FindBySignRequest(keyId string, msg string) ([]Withdrawal, error)
+	//Pending withdrawals to chain queued before the chain had a key, oldest first
+	FindUnkeyed(chain string, limit int) ([]Withdrawal, error)
+	//Newest withdrawals first
+	FindWithdrawals(account *string, chain *string, status *string, offset int, limit int) ([]Withdrawal, error)
+}
+
+// Withdrawal of a bridged asset to an external chain.
+// Progresses from pending to signed once the chain key signs Msg,
+// then to broadcast and confirmed as reported by the gateway.
+type Withdrawal struct {
+	//Ledger op ID of the withdrawal
+	Id     string `json:"id" bson:"id"`
+	From   string `json:"from" bson:"from"`
+	To     string `json:"to" bson:"to"`
+	Chain  string `json:"chain" bson:"chain"`
+	Asset  string `json:"asset" bson:"asset"`
+	Amount int64  `json:"amount" bson:"amount"`
+	Memo   string `json:"memo" bson:"memo"`
+
+	//TSS key of the chain and the hex digest it signs
+	KeyId string `json:"key_id" bson:"key_id"`
+	Msg   string `json:"msg" bson:"msg"`
+	Sig   string `json:"sig" bson:"sig"`
+
+	Status string `json:"status" bson:"status"`
+	//Transaction ID on the external chain
+	ChainTxId string `json:"chain_tx_id" bson:"chain_tx_id"`
+
+	BlockHeight   uint64 `json:"block_height" bson:"block_height"`
+	UpdatedHeight uint64 `json:"updated_height" bson:"updated_height"`
+}
+
+const (
+	WithdrawalPending   string = "pending"
+	WithdrawalSigned    string = "signed"
+	WithdrawalBroadcast string = "broadcast"
+	WithdrawalConfirmed string = "confirmed"
+)
+
+var statusOrder = []string{WithdrawalPending, WithdrawalSigned, WithdrawalBroadcast, WithdrawalConfirmed}
+
+// Whether a withdrawal in status from may move to status to.
+// Statuses only move forward, and only signed withdrawals can be broadcast or confirmed.
+func CanAdvance(from string, to string) bool {
+	fromIdx, toIdx := -1, -1
+	for i, s := range statusOrder {
+		if s == from {
+			fromIdx = i
+		}
+		if s == to {
+			toIdx = i
+		}
+	}
+	return fromIdx >= 1 && toIdx > fromIdx
+}
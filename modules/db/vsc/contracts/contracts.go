@@ -17,6 +17,9 @@ type contracts struct {
 }
 
 func New(d *vsc.VscDb) Contracts {
+	if kv := d.Kv(); kv != nil {
+		return newKvContracts(kv)
+	}
 	return &contracts{db.NewCollection(d.DbInstance, "contracts")}
 }
 
@@ -111,6 +114,8 @@ func (c *contracts) RegisterContract(contractId string, args Contract) {
 			"tx_id":       args.TxId,
 			"runtime":     args.Runtime,
 			"latest":      true,
+
+			"upgrade_policy": args.UpgradePolicy,
 		},
 	}
 	opts := options.FindOneAndUpdate().SetUpsert(true)
@@ -131,6 +136,26 @@ type contractState struct {
 	*db.Collection
 }
 
+func (ch *contractState) Init() error {
+	err := ch.Collection.Init()
+	if err != nil {
+		return err
+	}
+
+	// Indexes for looking up events of a contract by name or topic
+	for _, field := range []string{"results.events.name", "results.events.topics"} {
+		indexModel := mongo.IndexModel{
+			Keys: bson.D{{Key: "contract_id", Value: 1}, {Key: field, Value: 1}, {Key: "block_height", Value: -1}},
+		}
+		err = ch.CreateIndexIfNotExist(indexModel)
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (ch *contractState) IngestOutput(output IngestOutputArgs) {
 	options := options.FindOneAndUpdate().SetUpsert(true)
 	ch.FindOneAndUpdate(context.Background(), bson.M{"id": output.Id}, bson.M{
@@ -139,6 +164,7 @@ func (ch *contractState) IngestOutput(output IngestOutputArgs) {
 			"contract_id":  output.ContractId,
 			"state_merkle": output.StateMerkle,
 			"block_height": output.AnchoredHeight,
+			"anchr_id":     output.AnchoredId,
 
 			"metadata": output.Metadata,
 			"inputs":   output.Inputs,
@@ -149,12 +175,13 @@ func (ch *contractState) IngestOutput(output IngestOutputArgs) {
 }
 
 func (ch *contractState) GetLastOutput(contractId string, height uint64) (ContractOutput, error) {
-	options := options.FindOne().SetSort(bson.M{"block_height": -1})
+	//Outputs of the same block are ordered by insertion
+	options := options.FindOne().SetSort(bson.D{{Key: "block_height", Value: -1}, {Key: "_id", Value: -1}})
 	findResult := ch.FindOne(context.Background(), bson.M{"contract_id": contractId, "block_height": bson.M{
 		"$lte": height,
 	}}, options)
 	if findResult.Err() != nil {
-		return ContractOutput{}, nil
+		return ContractOutput{}, findResult.Err()
 	}
 	contractOutput := ContractOutput{
 		Metadata: ContractMetadata{},
@@ -174,7 +201,7 @@ func (ch *contractState) GetOutput(outputId string) *ContractOutput {
 	return &contractOutput
 }
 
-func (ch *contractState) FindOutputs(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ContractOutput, error) {
+func outputFilters(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64) bson.D {
 	filters := bson.D{}
 	if id != nil {
 		filters = append(filters, bson.E{Key: "id", Value: *id})
@@ -191,6 +218,11 @@ func (ch *contractState) FindOutputs(id *string, input *string, contract *string
 	if toBlock != nil {
 		filters = append(filters, bson.E{Key: "block_height", Value: bson.D{{Key: "$lte", Value: *toBlock}}})
 	}
+	return filters
+}
+
+func (ch *contractState) FindOutputs(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ContractOutput, error) {
+	filters := outputFilters(id, input, contract, fromBlock, toBlock)
 	pipe := hive_blocks.GetAggTimestampPipeline(filters, "block_height", "timestamp", offset, limit)
 	cursor, err := ch.Aggregate(context.TODO(), pipe)
 	if err != nil {
@@ -208,6 +240,138 @@ func (ch *contractState) FindOutputs(id *string, input *string, contract *string
 	return results, nil
 }
 
+// Outputs are paged by block height, then by ID
+var outputPageKey = hive_blocks.PageKey{Height: "block_height", Id: []string{"id"}}
+
+// Events are paged like their outputs, then by their page index
+var eventPageKey = hive_blocks.PageKey{Height: "block_height", Id: []string{"output_id"}, Index: "page_index"}
+
+func (ch *contractState) FindOutputsPage(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ContractOutput], error) {
+	filters := outputFilters(id, input, contract, fromBlock, toBlock)
+	pipe := hive_blocks.GetAggTimestampPagePipeline(filters, outputPageKey, "timestamp", page)
+	cursor, err := ch.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		return hive_blocks.Page[ContractOutput]{}, err
+	}
+	return hive_blocks.DecodePage[ContractOutput](context.TODO(), cursor, outputPageKey, page)
+}
+
+// Flattens the events of every call in the output, in the order they were emitted
+func (o ContractOutput) EventRecords() []ContractEventRecord {
+	records := make([]ContractEventRecord, 0)
+	for i, res := range o.Results {
+		txId := ""
+		if i < len(o.Inputs) {
+			txId = o.Inputs[i]
+		}
+		for idx, event := range res.Events {
+			records = append(records, ContractEventRecord{
+				OutputId:      o.Id,
+				ContractId:    o.ContractId,
+				TxId:          txId,
+				BlockHeight:   o.BlockHeight,
+				Timestamp:     o.Timestamp,
+				Index:         idx,
+				ContractEvent: event,
+			})
+		}
+	}
+	return records
+}
+
+func eventFilters(name *string, topic *string) bson.D {
+	filters := bson.D{}
+	if name != nil {
+		filters = append(filters, bson.E{Key: "name", Value: *name})
+	}
+	if topic != nil {
+		filters = append(filters, bson.E{Key: "topics", Value: *topic})
+	}
+	return filters
+}
+
+// Events are stored within the results of each output.
+// Outputs holding a matching event are found through the index first, then
+// unwound into one record per event, newest first. Within an output, events
+// are ordered by their page index, see EventPageIndex.
+func (ch *contractState) FindEvents(contract *string, name *string, topic *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ContractEventRecord], error) {
+	filters := outputFilters(nil, nil, contract, fromBlock, toBlock)
+	events := eventFilters(name, topic)
+	if len(events) > 0 {
+		filters = append(filters, bson.E{Key: "results.events", Value: bson.D{{Key: "$elemMatch", Value: events}}})
+	} else {
+		filters = append(filters, bson.E{Key: "results.events.0", Value: bson.D{{Key: "$exists", Value: true}}})
+	}
+
+	unwoundFilters := bson.D{}
+	for _, f := range events {
+		unwoundFilters = append(unwoundFilters, bson.E{Key: "results.events." + f.Key, Value: f.Value})
+	}
+
+	if page.Cursor != nil {
+		//Outputs past the cursor output are skipped before unwinding
+		outputCmp := "$lte"
+		if page.Reverse {
+			outputCmp = "$gte"
+		}
+		filters = append(filters, bson.E{Key: "block_height", Value: bson.D{{Key: outputCmp, Value: page.Cursor.Height}}})
+		unwoundFilters = append(unwoundFilters, hive_blocks.PageCursorMatch(eventPageKey, page))
+	}
+
+	pipe := mongo.Pipeline{
+		{{Key: "$match", Value: filters}},
+		{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$results"}, {Key: "includeArrayIndex", Value: "result_idx"}}}},
+		{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$results.events"}, {Key: "includeArrayIndex", Value: "event_idx"}}}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "output_id", Value: "$id"},
+			{Key: "page_index", Value: bson.D{{Key: "$add", Value: bson.A{
+				bson.D{{Key: "$multiply", Value: bson.A{"$result_idx", int64(1) << 32}}},
+				"$event_idx",
+			}}}},
+		}}},
+		{{Key: "$match", Value: unwoundFilters}},
+		{{Key: "$sort", Value: hive_blocks.PageSort(eventPageKey, page)}},
+		{{Key: "$limit", Value: page.Limit + 1}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "page_index", Value: 1},
+			{Key: "output_id", Value: 1},
+			{Key: "contract_id", Value: 1},
+			{Key: "tx_id", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$inputs", "$result_idx"}}}},
+			{Key: "block_height", Value: 1},
+			{Key: "index", Value: "$event_idx"},
+			{Key: "name", Value: "$results.events.name"},
+			{Key: "topics", Value: "$results.events.topics"},
+			{Key: "data", Value: "$results.events.data"},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "hive_blocks"},
+			{Key: "localField", Value: "block_height"},
+			{Key: "foreignField", Value: "block.block_number"},
+			{Key: "as", Value: "block_info"},
+		}}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "timestamp", Value: hive_blocks.PageTimestamp()},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "block_info", Value: 0},
+		}}},
+	}
+	cursor, err := ch.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		return hive_blocks.Page[ContractEventRecord]{}, err
+	}
+	return hive_blocks.DecodePage[ContractEventRecord](context.TODO(), cursor, eventPageKey, page)
+}
+
+// Position of an event within its output, used as the index of page cursors
+func EventPageIndex(resultIdx int, eventIdx int) uint64 {
+	return uint64(resultIdx)<<32 | uint64(eventIdx)
+}
+
 func NewContractState(d *vsc.VscDb) ContractState {
+	if kv := d.Kv(); kv != nil {
+		return newKvContractState(kv)
+	}
 	return &contractState{db.NewCollection(d.DbInstance, "contract_state")}
 }
@@ -2,6 +2,7 @@ package contracts
 
 import (
 	a "vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	tss_db "vsc-node/modules/db/vsc/tss"
 )
 
@@ -12,11 +13,42 @@ type Contracts interface {
 	FindContracts(contractId *string, code *string, historical *bool, offset int, limit int) ([]Contract, error)
 }
 
+// Code updates of timelocked contracts
+type ContractUpgrades interface {
+	a.Plugin
+	//Records a proposed upgrade. Upgrades already recorded are left untouched.
+	InsertUpgrade(upgrade ContractUpgrade) error
+	GetPendingUpgrade(contractId string) (ContractUpgrade, error)
+	//Pending upgrades activating at or before height, in activation order
+	FindDueUpgrades(height uint64) ([]ContractUpgrade, error)
+	SetUpgradeStatus(txId string, status string, height uint64) error
+	//Most recently proposed first
+	FindUpgrades(contractId *string, status *string, offset int, limit int) ([]ContractUpgrade, error)
+}
+
+// Contract calls run by state processing at set heights
+type ContractSchedules interface {
+	a.Plugin
+	//Records a new schedule. Schedules already recorded are left untouched.
+	InsertSchedule(schedule ContractSchedule) error
+	GetSchedule(id string) (ContractSchedule, error)
+	SetSchedule(schedule ContractSchedule) error
+	//Up to limit active schedules due at or before height, by next height then ID
+	FindDueSchedules(height uint64, limit int) ([]ContractSchedule, error)
+	//Most recently created first
+	FindSchedules(contractId *string, status *string, offset int, limit int) ([]ContractSchedule, error)
+	CountActiveSchedules(contractId string) (int, error)
+}
+
 type ContractState interface {
 	a.Plugin
 	IngestOutput(inputArgs IngestOutputArgs)
+	// Last output at or below height, mongo.ErrNoDocuments if there is none
 	GetLastOutput(contractId string, height uint64) (ContractOutput, error)
+	GetOutput(outputId string) *ContractOutput
 	FindOutputs(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ContractOutput, error)
+	FindOutputsPage(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ContractOutput], error)
+	FindEvents(contract *string, name *string, topic *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ContractEventRecord], error)
 }
 
 type IngestOutputArgs struct {
@@ -36,12 +68,35 @@ type IngestOutputArgs struct {
 }
 
 type ContractOutputResult struct {
-	Ret    string               `json:"ret" bson:"ret"`
-	Ok     bool                 `json:"ok" bson:"ok"`
-	Err    *ContractOutputError `json:"err,omitempty" bson:"err,omitempty"`
-	ErrMsg string               `json:"errMsg,omitempty" bson:"errMsg,omitempty"`
-	Logs   []string             `json:"logs,omitempty" bson:"logs,omitempty"`
-	TssOps []tss_db.TssOp       `json:"tss_ops,omitempty" bson:"tss_ops,omitempty"`
+	Ret       string               `json:"ret" bson:"ret"`
+	Ok        bool                 `json:"ok" bson:"ok"`
+	Err       *ContractOutputError `json:"err,omitempty" bson:"err,omitempty"`
+	ErrMsg    string               `json:"errMsg,omitempty" bson:"errMsg,omitempty"`
+	Logs      []string             `json:"logs,omitempty" bson:"logs,omitempty"`
+	TssOps    []tss_db.TssOp       `json:"tss_ops,omitempty" bson:"tss_ops,omitempty"`
+	Events    []ContractEvent      `json:"events,omitempty" bson:"events,omitempty"`
+	Schedules []ScheduleOp         `json:"schedules,omitempty" bson:"schedules,omitempty"`
+}
+
+// Structured event emitted by a contract through system.emit_event
+type ContractEvent struct {
+	Name   string   `json:"name" bson:"name"`
+	Topics []string `json:"topics" bson:"topics"`
+	//Compact JSON encoded payload
+	Data string `json:"data" bson:"data"`
+}
+
+// An event along with the contract output and call that emitted it
+type ContractEventRecord struct {
+	OutputId    string  `json:"output_id" bson:"output_id"`
+	ContractId  string  `json:"contract_id" bson:"contract_id"`
+	TxId        string  `json:"tx_id" bson:"tx_id"`
+	BlockHeight int64   `json:"block_height" bson:"block_height"`
+	Timestamp   *string `json:"timestamp,omitempty" bson:"timestamp,omitempty"`
+	//Position of the event among those emitted by the call
+	Index int `json:"index" bson:"index"`
+
+	ContractEvent `bson:",inline"`
 }
 
 type ContractOutput struct {
@@ -55,6 +110,8 @@ type ContractOutput struct {
 
 	Results     []ContractOutputResult `json:"results" bson:"results"`
 	StateMerkle string                 `json:"state_merkle" bson:"state_merkle"`
+	//ID of the block proposal that anchored this output
+	AnchoredId string `json:"anchr_id,omitempty" bson:"anchr_id,omitempty"`
 }
 
 type ContractUpdate struct {
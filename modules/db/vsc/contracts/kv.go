@@ -0,0 +1,479 @@
+package contracts
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc/hive_blocks"
+
+	"github.com/chebyrash/promise"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Contracts on the embedded kv store, keyed by contract ID and creation height
+type kvContracts struct {
+	docs *db.KvCollection[Contract]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvContracts(store *db.KvStore) *kvContracts {
+	return &kvContracts{db.NewKvCollection[Contract](store, "contracts"), hive_blocks.NewKvTimestamps(store)}
+}
+
+func (c *kvContracts) Init() error {
+	return nil
+}
+
+func (c *kvContracts) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (c *kvContracts) Stop() error {
+	return nil
+}
+
+// ContractById implements Contracts.
+func (c *kvContracts) ContractById(contractId string, height uint64) (Contract, error) {
+	entry, err := c.docs.First(db.KvRange{
+		Prefix:  []any{contractId},
+		To:      []any{height},
+		Reverse: true,
+	}, nil)
+	if err != nil {
+		return Contract{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (c *kvContracts) FindContracts(contractId *string, code *string, historical *bool, offset int, limit int) ([]Contract, error) {
+	r := db.KvRange{}
+	if contractId != nil {
+		r.Prefix = []any{*contractId}
+	}
+	entries, err := c.docs.Find(r, func(doc Contract) bool {
+		if code != nil && doc.Code != *code {
+			return false
+		}
+		return (historical != nil && *historical) || doc.Latest
+	})
+	if err != nil {
+		return []Contract{}, err
+	}
+	return hive_blocks.KvTimestampRange(c.ts, entries, func(doc Contract) uint64 {
+		return doc.CreationHeight
+	}, func(doc *Contract, ts string) {
+		doc.CreationTs = &ts
+	}, offset, limit), nil
+}
+
+func (c *kvContracts) RegisterContract(contractId string, args Contract) {
+	// TODO: config to prune old versions
+	versions, err := c.docs.Find(db.KvRange{Prefix: []any{contractId}}, func(doc Contract) bool {
+		return doc.Latest
+	})
+	if err != nil {
+		return
+	}
+	for _, version := range versions {
+		c.docs.Update(version.Key, func(doc *Contract, found bool) bool {
+			doc.Latest = false
+			return found
+		})
+	}
+	c.docs.Update(db.KvKey(contractId, args.CreationHeight), func(doc *Contract, found bool) bool {
+		doc.Id = contractId
+		doc.CreationHeight = args.CreationHeight
+		doc.Code = args.Code
+		doc.Name = args.Name
+		doc.Description = args.Description
+		doc.Creator = args.Creator
+		doc.Owner = args.Owner
+		doc.TxId = args.TxId
+		doc.Runtime = args.Runtime
+		doc.UpgradePolicy = args.UpgradePolicy
+		doc.Latest = true
+		return true
+	})
+}
+
+// Contract outputs on the embedded kv store, keyed by output ID
+type kvContractState struct {
+	docs *db.KvCollection[ContractOutput]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvContractState(store *db.KvStore) *kvContractState {
+	return &kvContractState{
+		db.NewKvCollection[ContractOutput](store, "contract_state").
+			WithIndex("contract", func(o ContractOutput) []any { return []any{o.ContractId, o.BlockHeight} }),
+		hive_blocks.NewKvTimestamps(store),
+	}
+}
+
+func (ch *kvContractState) Init() error {
+	return nil
+}
+
+func (ch *kvContractState) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (ch *kvContractState) Stop() error {
+	return nil
+}
+
+func (ch *kvContractState) IngestOutput(output IngestOutputArgs) {
+	ch.docs.Update(db.KvKey(output.Id), func(doc *ContractOutput, found bool) bool {
+		doc.Id = output.Id
+		doc.ContractId = output.ContractId
+		doc.StateMerkle = output.StateMerkle
+		doc.BlockHeight = output.AnchoredHeight
+		doc.AnchoredId = output.AnchoredId
+		doc.Metadata = output.Metadata
+		doc.Inputs = output.Inputs
+		doc.Results = output.Results
+		return true
+	})
+}
+
+func (ch *kvContractState) GetLastOutput(contractId string, height uint64) (ContractOutput, error) {
+	//Outputs of the same block are ordered by insertion
+	var last *db.KvEntry[ContractOutput]
+	err := ch.docs.Scan(db.KvRange{
+		Index:   "contract",
+		Prefix:  []any{contractId},
+		To:      []any{height},
+		Reverse: true,
+	}, func(entry db.KvEntry[ContractOutput]) bool {
+		if last != nil && entry.Doc.BlockHeight != last.Doc.BlockHeight {
+			return false
+		}
+		if last == nil || bytes.Compare(entry.Id[:], last.Id[:]) > 0 {
+			last = &entry
+		}
+		return true
+	})
+	if err != nil {
+		return ContractOutput{}, err
+	}
+	if last == nil {
+		return ContractOutput{}, mongo.ErrNoDocuments
+	}
+	return last.Doc, nil
+}
+
+func (ch *kvContractState) GetOutput(outputId string) *ContractOutput {
+	entry, err := ch.docs.Get(db.KvKey(outputId))
+	if err != nil {
+		return nil
+	}
+	return &entry.Doc
+}
+
+// Outputs matching the filters of outputFilters
+func (ch *kvContractState) findOutputs(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64) ([]db.KvEntry[ContractOutput], error) {
+	r := db.KvRange{}
+	if contract != nil {
+		r = db.KvRange{Index: "contract", Prefix: []any{*contract}}
+	}
+	return ch.docs.Find(r, func(doc ContractOutput) bool {
+		if id != nil && doc.Id != *id {
+			return false
+		}
+		if input != nil && !slices.Contains(doc.Inputs, *input) {
+			return false
+		}
+		if contract != nil && doc.ContractId != *contract {
+			return false
+		}
+		if fromBlock != nil && doc.BlockHeight < int64(*fromBlock) {
+			return false
+		}
+		if toBlock != nil && doc.BlockHeight > int64(*toBlock) {
+			return false
+		}
+		return true
+	})
+}
+
+func outputHeight(doc ContractOutput) uint64 {
+	return uint64(doc.BlockHeight)
+}
+
+func outputPosition(doc ContractOutput) hive_blocks.PageCursor {
+	return hive_blocks.PageCursor{Height: uint64(doc.BlockHeight), Id: []string{doc.Id}}
+}
+
+func setOutputTs(doc *ContractOutput, ts string) {
+	doc.Timestamp = &ts
+}
+
+func (ch *kvContractState) FindOutputs(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ContractOutput, error) {
+	entries, err := ch.findOutputs(id, input, contract, fromBlock, toBlock)
+	if err != nil {
+		return []ContractOutput{}, err
+	}
+	return hive_blocks.KvTimestampRange(ch.ts, entries, outputHeight, setOutputTs, offset, limit), nil
+}
+
+func (ch *kvContractState) FindOutputsPage(id *string, input *string, contract *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ContractOutput], error) {
+	entries, err := ch.findOutputs(id, input, contract, fromBlock, toBlock)
+	if err != nil {
+		return hive_blocks.Page[ContractOutput]{}, err
+	}
+	return hive_blocks.KvTimestampPage(ch.ts, entries, outputPosition, setOutputTs, page), nil
+}
+
+func (ch *kvContractState) FindEvents(contract *string, name *string, topic *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ContractEventRecord], error) {
+	entries, err := ch.findOutputs(nil, nil, contract, fromBlock, toBlock)
+	if err != nil {
+		return hive_blocks.Page[ContractEventRecord]{}, err
+	}
+	outputs := db.KvDocs(entries)
+	slices.SortStableFunc(outputs, func(a, b ContractOutput) int {
+		if page.Reverse {
+			return hive_blocks.ComparePageCursors(outputPosition(a), outputPosition(b))
+		}
+		return hive_blocks.ComparePageCursors(outputPosition(b), outputPosition(a))
+	})
+
+	result := hive_blocks.Page[ContractEventRecord]{
+		Items:   make([]ContractEventRecord, 0, page.Limit),
+		Cursors: make([]hive_blocks.PageCursor, 0, page.Limit),
+	}
+	for _, output := range outputs {
+		ts, hasTs := ch.ts.Get(outputHeight(output))
+		records := output.EventRecords()
+		positions := make([]hive_blocks.PageCursor, 0, len(records))
+		for resultIdx, res := range output.Results {
+			for eventIdx := range res.Events {
+				pos := outputPosition(output)
+				pos.Index = EventPageIndex(resultIdx, eventIdx)
+				positions = append(positions, pos)
+			}
+		}
+		if !page.Reverse {
+			slices.Reverse(records)
+			slices.Reverse(positions)
+		}
+		for i, record := range records {
+			if name != nil && record.Name != *name {
+				continue
+			}
+			if topic != nil && !slices.Contains(record.Topics, *topic) {
+				continue
+			}
+			if cur := page.Cursor; cur != nil {
+				c := hive_blocks.ComparePageCursors(positions[i], *cur)
+				if (page.Reverse && c <= 0) || (!page.Reverse && c >= 0) {
+					continue
+				}
+			}
+			if len(result.Items) == page.Limit {
+				result.HasMore = true
+				break
+			}
+			if hasTs {
+				record.Timestamp = &ts
+			}
+			result.Items = append(result.Items, record)
+			result.Cursors = append(result.Cursors, positions[i])
+		}
+		if result.HasMore {
+			break
+		}
+	}
+	if page.Reverse {
+		slices.Reverse(result.Items)
+		slices.Reverse(result.Cursors)
+	}
+	return result, nil
+}
+
+// Contract upgrades on the embedded kv store, keyed by proposing transaction
+type kvContractUpgrades struct {
+	docs *db.KvCollection[ContractUpgrade]
+}
+
+func newKvContractUpgrades(store *db.KvStore) *kvContractUpgrades {
+	return &kvContractUpgrades{
+		db.NewKvCollection[ContractUpgrade](store, "contract_upgrades").
+			WithIndex("status", func(u ContractUpgrade) []any { return []any{u.Status, u.ActivationHeight} }).
+			WithIndex("contract", func(u ContractUpgrade) []any { return []any{u.ContractId, u.ProposedHeight} }),
+	}
+}
+
+func (cu *kvContractUpgrades) Init() error {
+	return nil
+}
+
+func (cu *kvContractUpgrades) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (cu *kvContractUpgrades) Stop() error {
+	return nil
+}
+
+func (cu *kvContractUpgrades) InsertUpgrade(upgrade ContractUpgrade) error {
+	return cu.docs.Update(db.KvKey(upgrade.TxId), func(doc *ContractUpgrade, found bool) bool {
+		if found {
+			return false
+		}
+		*doc = upgrade
+		return true
+	})
+}
+
+func (cu *kvContractUpgrades) GetPendingUpgrade(contractId string) (ContractUpgrade, error) {
+	entry, err := cu.docs.First(db.KvRange{Index: "contract", Prefix: []any{contractId}, Reverse: true}, func(u ContractUpgrade) bool {
+		return u.Status == UpgradePending
+	})
+	if err != nil {
+		return ContractUpgrade{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (cu *kvContractUpgrades) FindDueUpgrades(height uint64) ([]ContractUpgrade, error) {
+	entries, err := cu.docs.Find(db.KvRange{
+		Index:  "status",
+		Prefix: []any{UpgradePending},
+		To:     []any{height},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return db.KvDocs(entries), nil
+}
+
+func (cu *kvContractUpgrades) SetUpgradeStatus(txId string, status string, height uint64) error {
+	return cu.docs.Update(db.KvKey(txId), func(doc *ContractUpgrade, found bool) bool {
+		doc.Status = status
+		doc.UpdatedHeight = height
+		return found
+	})
+}
+
+func (cu *kvContractUpgrades) FindUpgrades(contractId *string, status *string, offset int, limit int) ([]ContractUpgrade, error) {
+	r := db.KvRange{Index: "contract", Reverse: true}
+	if contractId != nil {
+		r.Prefix = []any{*contractId}
+	}
+	entries, err := cu.docs.Find(r, func(u ContractUpgrade) bool {
+		return status == nil || u.Status == *status
+	})
+	if err != nil {
+		return nil, err
+	}
+	if contractId == nil {
+		slices.SortStableFunc(entries, func(a, b db.KvEntry[ContractUpgrade]) int {
+			if c := cmp.Compare(b.Doc.ProposedHeight, a.Doc.ProposedHeight); c != 0 {
+				return c
+			}
+			return bytes.Compare(b.Id[:], a.Id[:])
+		})
+	}
+	entries = entries[min(max(offset, 0), len(entries)):]
+	entries = entries[:min(max(limit, 0), len(entries))]
+	return db.KvDocs(entries), nil
+}
+
+type kvContractSchedules struct {
+	docs *db.KvCollection[ContractSchedule]
+}
+
+func newKvContractSchedules(store *db.KvStore) *kvContractSchedules {
+	return &kvContractSchedules{
+		db.NewKvCollection[ContractSchedule](store, "contract_schedules").
+			WithIndex("status", func(s ContractSchedule) []any { return []any{s.Status, s.NextHeight, s.Id} }).
+			WithIndex("contract", func(s ContractSchedule) []any { return []any{s.ContractId, s.CreatedHeight, s.Id} }),
+	}
+}
+
+func (cs *kvContractSchedules) Init() error {
+	return nil
+}
+
+func (cs *kvContractSchedules) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (cs *kvContractSchedules) Stop() error {
+	return nil
+}
+
+func (cs *kvContractSchedules) InsertSchedule(schedule ContractSchedule) error {
+	return cs.docs.Update(db.KvKey(schedule.Id), func(doc *ContractSchedule, found bool) bool {
+		if found {
+			return false
+		}
+		*doc = schedule
+		return true
+	})
+}
+
+func (cs *kvContractSchedules) GetSchedule(id string) (ContractSchedule, error) {
+	entry, err := cs.docs.Get(db.KvKey(id))
+	if err != nil {
+		return ContractSchedule{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (cs *kvContractSchedules) SetSchedule(schedule ContractSchedule) error {
+	return cs.docs.Update(db.KvKey(schedule.Id), func(doc *ContractSchedule, found bool) bool {
+		*doc = schedule
+		return found
+	})
+}
+
+func (cs *kvContractSchedules) FindDueSchedules(height uint64, limit int) ([]ContractSchedule, error) {
+	entries, err := cs.docs.Find(db.KvRange{
+		Index:  "status",
+		Prefix: []any{ScheduleActive},
+		To:     []any{height},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	entries = entries[:min(max(limit, 0), len(entries))]
+	return db.KvDocs(entries), nil
+}
+
+func (cs *kvContractSchedules) FindSchedules(contractId *string, status *string, offset int, limit int) ([]ContractSchedule, error) {
+	r := db.KvRange{Index: "contract", Reverse: true}
+	if contractId != nil {
+		r.Prefix = []any{*contractId}
+	}
+	entries, err := cs.docs.Find(r, func(s ContractSchedule) bool {
+		return status == nil || s.Status == *status
+	})
+	if err != nil {
+		return nil, err
+	}
+	if contractId == nil {
+		slices.SortStableFunc(entries, func(a, b db.KvEntry[ContractSchedule]) int {
+			if c := cmp.Compare(b.Doc.CreatedHeight, a.Doc.CreatedHeight); c != 0 {
+				return c
+			}
+			return cmp.Compare(b.Doc.Id, a.Doc.Id)
+		})
+	}
+	entries = entries[min(max(offset, 0), len(entries)):]
+	entries = entries[:min(max(limit, 0), len(entries))]
+	return db.KvDocs(entries), nil
+}
+
+func (cs *kvContractSchedules) CountActiveSchedules(contractId string) (int, error) {
+	count := 0
+	err := cs.docs.Scan(db.KvRange{Index: "contract", Prefix: []any{contractId}}, func(entry db.KvEntry[ContractSchedule]) bool {
+		if entry.Doc.Status == ScheduleActive {
+			count++
+		}
+		return true
+	})
+	return count, err
+}
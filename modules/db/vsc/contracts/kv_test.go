@@ -0,0 +1,110 @@
+package contracts
+
+import (
+	"testing"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc/hive_blocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestKvContractStateLastOutput(t *testing.T) {
+	store := db.NewKvStore()
+	require.NoError(t, store.OpenInMemory())
+	t.Cleanup(func() { store.Close() })
+	state := newKvContractState(store)
+
+	_, err := state.GetLastOutput("c1", 100)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	state.IngestOutput(IngestOutputArgs{Id: "out-10", ContractId: "c1", StateMerkle: "state-10", AnchoredHeight: 10})
+	state.IngestOutput(IngestOutputArgs{Id: "out-20", ContractId: "c1", StateMerkle: "state-20", AnchoredHeight: 20})
+	state.IngestOutput(IngestOutputArgs{Id: "out-30", ContractId: "c1", StateMerkle: "state-30", AnchoredHeight: 30})
+	state.IngestOutput(IngestOutputArgs{Id: "other", ContractId: "c2", StateMerkle: "state-other", AnchoredHeight: 15})
+
+	_, err = state.GetLastOutput("c1", 9)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	//Height is inclusive
+	out, err := state.GetLastOutput("c1", 20)
+	require.NoError(t, err)
+	assert.Equal(t, "state-20", out.StateMerkle)
+
+	out, err = state.GetLastOutput("c1", 29)
+	require.NoError(t, err)
+	assert.Equal(t, "state-20", out.StateMerkle)
+
+	out, err = state.GetLastOutput("c1", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, "state-30", out.StateMerkle)
+
+	//An output holds the state after it was applied
+	assert.Equal(t, "state-10", state.GetOutput("out-10").StateMerkle)
+	assert.Nil(t, state.GetOutput("missing"))
+}
+
+func TestKvFindEvents(t *testing.T) {
+	store := db.NewKvStore()
+	require.NoError(t, store.OpenInMemory())
+	t.Cleanup(func() { store.Close() })
+	state := newKvContractState(store)
+
+	blocks := db.NewKvCollection[hive_blocks.Document](store, "hive_blocks").WithJson()
+	for _, height := range []uint64{10, 20} {
+		block := hive_blocks.HiveBlock{BlockNumber: height, Timestamp: "2025-01-01T00:00:00"}
+		require.NoError(t, blocks.Put(db.KvKey(string(hive_blocks.DocumentTypeHiveBlock), height), hive_blocks.Document{Type: hive_blocks.DocumentTypeHiveBlock, Block: &block}))
+	}
+
+	transfer := func(to string) ContractEvent {
+		return ContractEvent{Name: "transfer", Topics: []string{to}, Data: "{}"}
+	}
+	state.IngestOutput(IngestOutputArgs{Id: "out-10", ContractId: "c1", AnchoredHeight: 10, Inputs: []string{"tx1", "tx2"}, Results: []ContractOutputResult{
+		{Events: []ContractEvent{transfer("alice"), {Name: "mint", Topics: []string{}, Data: "1"}}},
+		{Events: []ContractEvent{transfer("bob")}},
+	}})
+	state.IngestOutput(IngestOutputArgs{Id: "out-20", ContractId: "c1", AnchoredHeight: 20, Inputs: []string{"tx3"}, Results: []ContractOutputResult{
+		{Events: []ContractEvent{transfer("alice")}},
+	}})
+	state.IngestOutput(IngestOutputArgs{Id: "other", ContractId: "c2", AnchoredHeight: 20, Inputs: []string{"tx4"}, Results: []ContractOutputResult{
+		{Events: []ContractEvent{transfer("alice")}},
+	}})
+
+	find := func(contract *string, name *string, topic *string, fromBlock *uint64, page hive_blocks.PageArgs) hive_blocks.Page[ContractEventRecord] {
+		result, err := state.FindEvents(contract, name, topic, fromBlock, nil, page)
+		require.NoError(t, err)
+		return result
+	}
+	txIds := func(page hive_blocks.Page[ContractEventRecord]) []string {
+		ids := make([]string, 0)
+		for _, record := range page.Items {
+			ids = append(ids, record.TxId+"/"+record.Name)
+		}
+		return ids
+	}
+	c1, transferName, alice, from := "c1", "transfer", "alice", uint64(15)
+
+	//Newest first, the last emitted event of an output first
+	all := find(&c1, nil, nil, nil, hive_blocks.PageArgs{Limit: 10})
+	assert.Equal(t, []string{"tx3/transfer", "tx2/transfer", "tx1/mint", "tx1/transfer"}, txIds(all))
+	assert.False(t, all.HasMore)
+	require.NotNil(t, all.Items[0].Timestamp)
+
+	assert.Equal(t, []string{"tx3/transfer", "tx2/transfer", "tx1/transfer"}, txIds(find(&c1, &transferName, nil, nil, hive_blocks.PageArgs{Limit: 10})))
+	assert.Equal(t, []string{"tx3/transfer", "tx1/transfer"}, txIds(find(&c1, nil, &alice, nil, hive_blocks.PageArgs{Limit: 10})))
+	assert.Equal(t, []string{"tx3/transfer"}, txIds(find(&c1, nil, nil, &from, hive_blocks.PageArgs{Limit: 10})))
+	assert.Len(t, find(nil, nil, &alice, nil, hive_blocks.PageArgs{Limit: 10}).Items, 3)
+
+	//Pages continue within an output
+	first := find(&c1, nil, nil, nil, hive_blocks.PageArgs{Limit: 2})
+	assert.Equal(t, []string{"tx3/transfer", "tx2/transfer"}, txIds(first))
+	assert.True(t, first.HasMore)
+	second := find(&c1, nil, nil, nil, hive_blocks.PageArgs{Limit: 2, Cursor: &first.Cursors[1]})
+	assert.Equal(t, []string{"tx1/mint", "tx1/transfer"}, txIds(second))
+	assert.False(t, second.HasMore)
+
+	back := find(&c1, nil, nil, nil, hive_blocks.PageArgs{Limit: 2, Cursor: &second.Cursors[0], Reverse: true})
+	assert.Equal(t, []string{"tx3/transfer", "tx2/transfer"}, txIds(back))
+	assert.Equal(t, first.Cursors, back.Cursors)
+}
@@ -0,0 +1,201 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ScheduleActive    = "active"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+	//The contract account could not pay for a run
+	ScheduleFailed = "failed"
+)
+
+const (
+	//Registers a new scheduled call
+	ScheduleOpCreate = "create"
+	//Cancels a scheduled call of the same contract
+	ScheduleOpCancel = "cancel"
+)
+
+// Schedule change requested by a contract through the SDK,
+// applied when the contract output is ingested
+type ScheduleOp struct {
+	Type    string `json:"type" bson:"type"`
+	Id      string `json:"id" bson:"id"`
+	Action  string `json:"action,omitempty" bson:"action,omitempty"`
+	Payload string `json:"payload,omitempty" bson:"payload,omitempty"`
+	//First height the call runs at
+	Height uint64 `json:"height,omitempty" bson:"height,omitempty"`
+	//Blocks between runs of recurring calls, 0 runs once
+	Interval uint64 `json:"interval,omitempty" bson:"interval,omitempty"`
+	RcLimit  uint   `json:"rc_limit,omitempty" bson:"rc_limit,omitempty"`
+}
+
+// When a scheduled call runs
+type ScheduleTiming struct {
+	Height   uint64
+	Interval uint64
+}
+
+// Parses "at:<height>" or "every:<blocks>" relative to the current block height
+func ParseScheduleTiming(when string, blockHeight uint64) (ScheduleTiming, error) {
+	kind, arg, ok := strings.Cut(when, ":")
+	if !ok {
+		return ScheduleTiming{}, fmt.Errorf("unknown schedule %q", when)
+	}
+	n, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return ScheduleTiming{}, fmt.Errorf("invalid schedule %q", when)
+	}
+
+	switch kind {
+	case "at":
+		if n <= blockHeight {
+			return ScheduleTiming{}, fmt.Errorf("height %d is not in the future", n)
+		}
+		if n-blockHeight > params.CONTRACT_SCHEDULE_MAX_DELAY {
+			return ScheduleTiming{}, fmt.Errorf("height is more than %d blocks ahead", params.CONTRACT_SCHEDULE_MAX_DELAY)
+		}
+		return ScheduleTiming{Height: n}, nil
+	case "every":
+		if n == 0 || n > params.CONTRACT_SCHEDULE_MAX_DELAY {
+			return ScheduleTiming{}, fmt.Errorf("interval must be 1 to %d blocks", params.CONTRACT_SCHEDULE_MAX_DELAY)
+		}
+		return ScheduleTiming{Height: blockHeight + n, Interval: n}, nil
+	}
+	return ScheduleTiming{}, fmt.Errorf("unknown schedule %q", when)
+}
+
+// Contract call run by state processing at a set height, once or recurring
+type ContractSchedule struct {
+	Id         string `json:"id" bson:"id"`
+	ContractId string `json:"contract_id" bson:"contract_id"`
+	Action     string `json:"action" bson:"action"`
+	Payload    string `json:"payload" bson:"payload"`
+	Interval   uint64 `json:"interval" bson:"interval"`
+	//RCs each run may use, paid by the contract account
+	RcLimit uint   `json:"rc_limit" bson:"rc_limit"`
+	Status  string `json:"status" bson:"status"`
+
+	NextHeight    uint64 `json:"next_height" bson:"next_height"`
+	CreatedHeight uint64 `json:"created_height" bson:"created_height"`
+	LastRunHeight uint64 `json:"last_run_height" bson:"last_run_height"`
+	Runs          uint64 `json:"runs" bson:"runs"`
+	UpdatedHeight uint64 `json:"updated_height" bson:"updated_height"`
+}
+
+type contractSchedules struct {
+	*db.Collection
+}
+
+func NewContractSchedules(d *vsc.VscDb) ContractSchedules {
+	if kv := d.Kv(); kv != nil {
+		return newKvContractSchedules(kv)
+	}
+	return &contractSchedules{db.NewCollection(d.DbInstance, "contract_schedules")}
+}
+
+func (cs *contractSchedules) Init() error {
+	err := cs.Collection.Init()
+	if err != nil {
+		return err
+	}
+
+	_, err = cs.Collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "contract_id", Value: 1}, {Key: "created_height", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_height", Value: 1}, {Key: "id", Value: 1}},
+		},
+	})
+	return err
+}
+
+func (cs *contractSchedules) InsertSchedule(schedule ContractSchedule) error {
+	_, err := cs.UpdateOne(context.Background(), bson.M{
+		"id": schedule.Id,
+	}, bson.M{
+		"$setOnInsert": schedule,
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (cs *contractSchedules) GetSchedule(id string) (ContractSchedule, error) {
+	record := ContractSchedule{}
+	err := cs.FindOne(context.Background(), bson.M{"id": id}).Decode(&record)
+	return record, err
+}
+
+func (cs *contractSchedules) SetSchedule(schedule ContractSchedule) error {
+	_, err := cs.ReplaceOne(context.Background(), bson.M{"id": schedule.Id}, schedule)
+	return err
+}
+
+func (cs *contractSchedules) FindDueSchedules(height uint64, limit int) ([]ContractSchedule, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_height", Value: 1}, {Key: "id", Value: 1}}).
+		SetLimit(int64(limit))
+	return cs.find(bson.M{
+		"status":      ScheduleActive,
+		"next_height": bson.M{"$lte": height},
+	}, opts)
+}
+
+func (cs *contractSchedules) FindSchedules(contractId *string, status *string, offset int, limit int) ([]ContractSchedule, error) {
+	filter := bson.M{}
+	if contractId != nil {
+		filter["contract_id"] = *contractId
+	}
+	if status != nil {
+		filter["status"] = *status
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_height", Value: -1}, {Key: "id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	return cs.find(filter, opts)
+}
+
+func (cs *contractSchedules) CountActiveSchedules(contractId string) (int, error) {
+	count, err := cs.CountDocuments(context.Background(), bson.M{
+		"contract_id": contractId,
+		"status":      ScheduleActive,
+	})
+	return int(count), err
+}
+
+func (cs *contractSchedules) find(filter bson.M, opts *options.FindOptions) ([]ContractSchedule, error) {
+	ctx := context.Background()
+	cursor, err := cs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]ContractSchedule, 0)
+	for cursor.Next(ctx) {
+		var record ContractSchedule
+		if err := cursor.Decode(&record); err != nil {
+			return nil, err
+		}
+		results = append(results, record)
+	}
+	return results, nil
+}
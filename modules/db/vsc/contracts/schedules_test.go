@@ -0,0 +1,83 @@
+package contracts
+
+import (
+	"testing"
+	"vsc-node/modules/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseScheduleTiming(t *testing.T) {
+	timing, err := ParseScheduleTiming("at:150", 100)
+	require.NoError(t, err)
+	assert.Equal(t, ScheduleTiming{Height: 150}, timing)
+
+	timing, err = ParseScheduleTiming("every:20", 100)
+	require.NoError(t, err)
+	assert.Equal(t, ScheduleTiming{Height: 120, Interval: 20}, timing)
+
+	for _, input := range []string{"", "at", "at:", "at:100", "at:50", "at:99999999", "every:0", "every:-1", "every:99999999", "later:10"} {
+		_, err := ParseScheduleTiming(input, 100)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestKvContractSchedules(t *testing.T) {
+	store := db.NewKvStore()
+	require.NoError(t, store.OpenInMemory())
+	t.Cleanup(func() { store.Close() })
+	schedules := newKvContractSchedules(store)
+
+	first := ContractSchedule{Id: "tx1:1", ContractId: "c1", Action: "tick", Status: ScheduleActive, NextHeight: 20, CreatedHeight: 10}
+	second := ContractSchedule{Id: "tx2:1", ContractId: "c2", Action: "settle", Status: ScheduleActive, NextHeight: 15, CreatedHeight: 12}
+	third := ContractSchedule{Id: "tx2:2", ContractId: "c2", Action: "settle", Status: ScheduleActive, NextHeight: 15, CreatedHeight: 12}
+	require.NoError(t, schedules.InsertSchedule(first))
+	require.NoError(t, schedules.InsertSchedule(third))
+	require.NoError(t, schedules.InsertSchedule(second))
+
+	got, err := schedules.GetSchedule("tx1:1")
+	require.NoError(t, err)
+	assert.Equal(t, first, got)
+	_, err = schedules.GetSchedule("missing")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	due, err := schedules.FindDueSchedules(14, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = schedules.FindDueSchedules(20, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+	assert.Equal(t, []string{"tx2:1", "tx2:2", "tx1:1"}, []string{due[0].Id, due[1].Id, due[2].Id})
+	due, err = schedules.FindDueSchedules(20, 2)
+	require.NoError(t, err)
+	assert.Len(t, due, 2)
+
+	//Replayed inserts keep the state of the schedule
+	first.Status = ScheduleCancelled
+	first.UpdatedHeight = 13
+	require.NoError(t, schedules.SetSchedule(first))
+	require.NoError(t, schedules.InsertSchedule(ContractSchedule{Id: "tx1:1", Status: ScheduleActive}))
+	due, err = schedules.FindDueSchedules(20, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 2)
+
+	all, err := schedules.FindSchedules(nil, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "tx2:2", all[0].Id)
+
+	contractId := "c1"
+	byContract, err := schedules.FindSchedules(&contractId, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, byContract, 1)
+	assert.Equal(t, ScheduleCancelled, byContract[0].Status)
+	assert.Equal(t, uint64(13), byContract[0].UpdatedHeight)
+
+	status := ScheduleActive
+	byStatus, err := schedules.FindSchedules(nil, &status, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, byStatus, 1)
+	assert.Equal(t, "tx2:1", byStatus[0].Id)
+}
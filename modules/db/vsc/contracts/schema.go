@@ -13,7 +13,9 @@ type Contract struct {
 	CreationHeight uint64               `bson:"creation_height"`
 	CreationTs     *string              `bson:"creation_ts,omitempty"`
 	Runtime        wasm_runtime.Runtime `bson:"runtime"`
-	Latest         bool                 `bson:"latest,omitempty"`
+	//Canonical UpgradePolicy, empty for contracts upgrading immediately
+	UpgradePolicy string `bson:"upgrade_policy,omitempty"`
+	Latest        bool   `bson:"latest,omitempty"`
 }
 
 type Intent struct {
@@ -0,0 +1,187 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc"
+	wasm_runtime "vsc-node/modules/wasm/runtime"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	//Code can never be updated
+	UpgradeImmutable = "immutable"
+	//Code updates activate as soon as they are processed
+	UpgradeImmediate = "immediate"
+	//Code updates activate once a delay has passed
+	UpgradeTimelock = "timelock"
+)
+
+const (
+	UpgradePending   = "pending"
+	UpgradeActivated = "activated"
+	UpgradeCancelled = "cancelled"
+)
+
+// How code updates of a contract take effect, declared at creation
+type UpgradePolicy struct {
+	Mode string
+	//Blocks between the update and its activation under a timelock
+	Delay uint64
+}
+
+// Parses "immutable", "immediate" or "timelock(<blocks>)".
+// Contracts without a policy upgrade immediately.
+func ParseUpgradePolicy(policy string) (UpgradePolicy, error) {
+	switch policy {
+	case "", UpgradeImmediate:
+		return UpgradePolicy{Mode: UpgradeImmediate}, nil
+	case UpgradeImmutable:
+		return UpgradePolicy{Mode: UpgradeImmutable}, nil
+	}
+
+	arg, ok := strings.CutPrefix(policy, UpgradeTimelock+"(")
+	if !ok || !strings.HasSuffix(arg, ")") {
+		return UpgradePolicy{}, fmt.Errorf("unknown upgrade policy %q", policy)
+	}
+	delay, err := strconv.ParseUint(strings.TrimSuffix(arg, ")"), 10, 64)
+	if err != nil || delay == 0 {
+		return UpgradePolicy{}, fmt.Errorf("invalid timelock delay in %q", policy)
+	}
+	if delay > params.CONTRACT_UPGRADE_MAX_DELAY {
+		return UpgradePolicy{}, fmt.Errorf("timelock delay exceeds %d blocks", params.CONTRACT_UPGRADE_MAX_DELAY)
+	}
+	return UpgradePolicy{Mode: UpgradeTimelock, Delay: delay}, nil
+}
+
+func (p UpgradePolicy) String() string {
+	if p.Mode == UpgradeTimelock {
+		return fmt.Sprintf("%s(%d)", UpgradeTimelock, p.Delay)
+	}
+	return p.Mode
+}
+
+// Code update of a contract waiting out its timelock
+type ContractUpgrade struct {
+	//Transaction that proposed the update
+	TxId       string               `json:"tx_id" bson:"tx_id"`
+	ContractId string               `json:"contract_id" bson:"contract_id"`
+	Code       string               `json:"code" bson:"code"`
+	Runtime    wasm_runtime.Runtime `json:"runtime" bson:"runtime"`
+	//Run the migrate entrypoint of the new code when it activates
+	Migrate bool   `json:"migrate" bson:"migrate"`
+	Status  string `json:"status" bson:"status"`
+
+	ProposedHeight   uint64 `json:"proposed_height" bson:"proposed_height"`
+	ActivationHeight uint64 `json:"activation_height" bson:"activation_height"`
+	UpdatedHeight    uint64 `json:"updated_height" bson:"updated_height"`
+}
+
+type contractUpgrades struct {
+	*db.Collection
+}
+
+func NewContractUpgrades(d *vsc.VscDb) ContractUpgrades {
+	if kv := d.Kv(); kv != nil {
+		return newKvContractUpgrades(kv)
+	}
+	return &contractUpgrades{db.NewCollection(d.DbInstance, "contract_upgrades")}
+}
+
+func (cu *contractUpgrades) Init() error {
+	err := cu.Collection.Init()
+	if err != nil {
+		return err
+	}
+
+	_, err = cu.Collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tx_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "contract_id", Value: 1}, {Key: "proposed_height", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "activation_height", Value: 1}},
+		},
+	})
+	return err
+}
+
+func (cu *contractUpgrades) InsertUpgrade(upgrade ContractUpgrade) error {
+	_, err := cu.UpdateOne(context.Background(), bson.M{
+		"tx_id": upgrade.TxId,
+	}, bson.M{
+		"$setOnInsert": upgrade,
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (cu *contractUpgrades) GetPendingUpgrade(contractId string) (ContractUpgrade, error) {
+	record := ContractUpgrade{}
+	err := cu.FindOne(context.Background(), bson.M{
+		"contract_id": contractId,
+		"status":      UpgradePending,
+	}).Decode(&record)
+	return record, err
+}
+
+func (cu *contractUpgrades) FindDueUpgrades(height uint64) ([]ContractUpgrade, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "activation_height", Value: 1}, {Key: "_id", Value: 1}})
+	return cu.find(bson.M{
+		"status":            UpgradePending,
+		"activation_height": bson.M{"$lte": height},
+	}, opts)
+}
+
+func (cu *contractUpgrades) SetUpgradeStatus(txId string, status string, height uint64) error {
+	_, err := cu.UpdateOne(context.Background(), bson.M{"tx_id": txId}, bson.M{
+		"$set": bson.M{
+			"status":         status,
+			"updated_height": height,
+		},
+	})
+	return err
+}
+
+func (cu *contractUpgrades) FindUpgrades(contractId *string, status *string, offset int, limit int) ([]ContractUpgrade, error) {
+	filter := bson.M{}
+	if contractId != nil {
+		filter["contract_id"] = *contractId
+	}
+	if status != nil {
+		filter["status"] = *status
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "proposed_height", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	return cu.find(filter, opts)
+}
+
+func (cu *contractUpgrades) find(filter bson.M, opts *options.FindOptions) ([]ContractUpgrade, error) {
+	ctx := context.Background()
+	cursor, err := cu.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]ContractUpgrade, 0)
+	for cursor.Next(ctx) {
+		var record ContractUpgrade
+		if err := cursor.Decode(&record); err != nil {
+			return nil, err
+		}
+		results = append(results, record)
+	}
+	return results, nil
+}
@@ -0,0 +1,86 @@
+package contracts
+
+import (
+	"testing"
+	"vsc-node/modules/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseUpgradePolicy(t *testing.T) {
+	valid := map[string]string{
+		"":              UpgradeImmediate,
+		"immediate":     UpgradeImmediate,
+		"immutable":     UpgradeImmutable,
+		"timelock(100)": "timelock(100)",
+	}
+	for input, canonical := range valid {
+		policy, err := ParseUpgradePolicy(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, canonical, policy.String())
+	}
+
+	for _, input := range []string{"timelock", "timelock()", "timelock(0)", "timelock(-1)", "timelock(999999999)", "later"} {
+		_, err := ParseUpgradePolicy(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestKvContractUpgrades(t *testing.T) {
+	store := db.NewKvStore()
+	require.NoError(t, store.OpenInMemory())
+	t.Cleanup(func() { store.Close() })
+	upgrades := newKvContractUpgrades(store)
+
+	first := ContractUpgrade{TxId: "tx1", ContractId: "c1", Code: "a", Status: UpgradePending, ProposedHeight: 10, ActivationHeight: 20}
+	second := ContractUpgrade{TxId: "tx2", ContractId: "c2", Code: "b", Status: UpgradePending, ProposedHeight: 12, ActivationHeight: 15}
+	require.NoError(t, upgrades.InsertUpgrade(first))
+	require.NoError(t, upgrades.InsertUpgrade(second))
+
+	pending, err := upgrades.GetPendingUpgrade("c1")
+	require.NoError(t, err)
+	assert.Equal(t, "tx1", pending.TxId)
+
+	due, err := upgrades.FindDueUpgrades(14)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = upgrades.FindDueUpgrades(20)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "tx2", due[0].TxId)
+
+	//Replayed inserts keep the status of the upgrade
+	require.NoError(t, upgrades.SetUpgradeStatus("tx1", UpgradeCancelled, 13))
+	require.NoError(t, upgrades.InsertUpgrade(first))
+	_, err = upgrades.GetPendingUpgrade("c1")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	due, err = upgrades.FindDueUpgrades(20)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "tx2", due[0].TxId)
+
+	all, err := upgrades.FindUpgrades(nil, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "tx2", all[0].TxId)
+
+	contractId := "c1"
+	byContract, err := upgrades.FindUpgrades(&contractId, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, byContract, 1)
+	assert.Equal(t, UpgradeCancelled, byContract[0].Status)
+	assert.Equal(t, uint64(13), byContract[0].UpdatedHeight)
+
+	status := UpgradePending
+	byStatus, err := upgrades.FindUpgrades(nil, &status, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, byStatus, 1)
+	assert.Equal(t, "tx2", byStatus[0].TxId)
+
+	page, err := upgrades.FindUpgrades(nil, nil, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "tx1", page[0].TxId)
+}
@@ -20,6 +20,9 @@ type elections struct {
 }
 
 func New(d *vsc.VscDb) Elections {
+	if kv := d.Kv(); kv != nil {
+		return newKvElections(kv)
+	}
 	return &elections{db.NewCollection(d.DbInstance, "elections")}
 }
 
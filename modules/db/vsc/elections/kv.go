@@ -0,0 +1,109 @@
+package elections
+
+import (
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	"github.com/polydawn/refmt"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Elections on the embedded kv store, keyed by epoch
+type kvElections struct {
+	docs *db.KvCollection[ElectionResultRecord]
+}
+
+func newKvElections(store *db.KvStore) *kvElections {
+	return &kvElections{
+		db.NewKvCollection[ElectionResultRecord](store, "elections").
+			WithIndex("block_height", func(e ElectionResultRecord) []any { return []any{e.BlockHeight} }),
+	}
+}
+
+func (e *kvElections) Init() error {
+	return nil
+}
+
+func (e *kvElections) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (e *kvElections) Stop() error {
+	return nil
+}
+
+func (e *kvElections) StoreElection(a ElectionResult) error {
+	totalWeight := uint64(0)
+	if len(a.Weights) > 0 {
+		for _, weight := range a.Weights {
+			totalWeight = totalWeight + weight
+		}
+	} else {
+		totalWeight = uint64(len(a.Members))
+	}
+	a.TotalWeight = totalWeight
+
+	record := ElectionResultRecord{}
+	err := refmt.CloneAtlased(a, &record, cbornode.CborAtlas)
+	if record.Type == "" {
+		record.Type = "initial"
+	}
+	if err != nil {
+		return err
+	}
+	return e.docs.Put(db.KvKey(a.Epoch), record)
+}
+
+func toResult(record ElectionResultRecord) (ElectionResult, error) {
+	electionResult := ElectionResult{}
+	err := refmt.CloneAtlased(record, &electionResult, cbornode.CborAtlas)
+	return electionResult, err
+}
+
+func (e *kvElections) GetElection(epoch uint64) *ElectionResult {
+	entry, err := e.docs.Get(db.KvKey(epoch))
+	if err != nil {
+		return nil
+	}
+	electionResult, err := toResult(entry.Doc)
+	if err != nil {
+		return nil
+	}
+	return &electionResult
+}
+
+func (e *kvElections) GetPreviousElections(beforeEpoch uint64, limit int) []ElectionResult {
+	var results []ElectionResult
+	if beforeEpoch == 0 {
+		return results
+	}
+	err := e.docs.Scan(db.KvRange{To: []any{beforeEpoch - 1}, Reverse: true}, func(entry db.KvEntry[ElectionResultRecord]) bool {
+		electionResult, err := toResult(entry.Doc)
+		if err == nil {
+			results = append(results, electionResult)
+		}
+		return limit <= 0 || len(results) < limit
+	})
+	if err != nil {
+		return nil
+	}
+	return results
+}
+
+func (e *kvElections) GetElectionByHeight(height uint64) (ElectionResult, error) {
+	//Elections activate going forward, not retroactively to the same block
+	if height == 0 {
+		return ElectionResult{}, mongo.ErrNoDocuments
+	}
+	entry, err := e.docs.First(db.KvRange{
+		Index:   "block_height",
+		To:      []any{height - 1},
+		Reverse: true,
+	}, nil)
+	if err != nil {
+		return ElectionResult{}, err
+	}
+	return toResult(entry.Doc)
+}
@@ -155,6 +155,9 @@ func isFieldKeys(m map[string]interface{}) bool {
 }
 
 func New(d *vsc.VscDb) (HiveBlocks, error) {
+	if kv := d.Kv(); kv != nil {
+		return newKvHiveBlocks(kv), nil
+	}
 	hiveBlocks := &hiveBlocks{db.NewCollection(d.DbInstance, "hive_blocks"), sync.Mutex{}}
 
 	return hiveBlocks, nil
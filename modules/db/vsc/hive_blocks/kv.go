@@ -0,0 +1,350 @@
+package hive_blocks
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Blocks handed to a block listener per round
+const kvListenBatch = 1000
+
+// HiveBlocks on the embedded kv store.
+// Documents are JSON encoded, which keeps the nested operation arrays intact.
+type kvHiveBlocks struct {
+	store *db.KvStore
+	docs  *db.KvCollection[Document]
+}
+
+const kvMetadataKey = "metadata"
+
+func newKvHiveBlocks(store *db.KvStore) *kvHiveBlocks {
+	return &kvHiveBlocks{store, db.NewKvCollection[Document](store, "hive_blocks").WithJson()}
+}
+
+func blockKey(blockNum uint64) string {
+	return db.KvKey(string(DocumentTypeHiveBlock), blockNum)
+}
+
+func (h *kvHiveBlocks) Init() error {
+	return nil
+}
+
+func (h *kvHiveBlocks) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (h *kvHiveBlocks) Stop() error {
+	return nil
+}
+
+func (h *kvHiveBlocks) setMetadata(update func(doc *Document)) error {
+	return h.docs.Update(kvMetadataKey, func(doc *Document, found bool) bool {
+		doc.Type = DocumentTypeMetadata
+		update(doc)
+		return true
+	})
+}
+
+func (h *kvHiveBlocks) StoreBlocks(headBlock uint64, blocks ...HiveBlock) error {
+	if len(blocks) == 0 {
+		return fmt.Errorf("empty blocks")
+	}
+	for _, block := range blocks {
+		err := h.docs.Put(blockKey(block.BlockNumber), Document{
+			Type:  DocumentTypeHiveBlock,
+			Block: &block,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store block %d: %w", block.BlockNumber, err)
+		}
+	}
+	return h.setMetadata(func(doc *Document) {
+		doc.LastStoredBlock = &blocks[len(blocks)-1].BlockNumber
+		doc.HeadHeight = &headBlock
+	})
+}
+
+func (h *kvHiveBlocks) FetchStoredBlocks(startBlock uint64, endBlock uint64) ([]HiveBlock, error) {
+	var blocks []HiveBlock
+	err := h.docs.Scan(db.KvRange{
+		Prefix: []any{string(DocumentTypeHiveBlock)},
+		From:   []any{startBlock},
+		To:     []any{endBlock},
+	}, func(entry db.KvEntry[Document]) bool {
+		blocks = append(blocks, *entry.Doc.Block)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+func (h *kvHiveBlocks) ClearBlocks() error {
+	err := h.store.DropCollections("hive_blocks")
+	if err != nil {
+		return fmt.Errorf("failed to clear blocks: %w", err)
+	}
+	return nil
+}
+
+func (h *kvHiveBlocks) StoreLastProcessedBlock(blockNumber uint64) error {
+	err := h.setMetadata(func(doc *Document) {
+		doc.LastProcessedBlock = &blockNumber
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store last processed block: %w", err)
+	}
+	return nil
+}
+
+func (h *kvHiveBlocks) GetLastProcessedBlock() (uint64, error) {
+	metadata, err := h.GetMetadata()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last processed block: %w", err)
+	}
+	if metadata.LastProcessedBlock == nil {
+		return 0, nil
+	}
+	return *metadata.LastProcessedBlock, nil
+}
+
+func (h *kvHiveBlocks) GetHighestBlock() (uint64, error) {
+	entry, err := h.docs.First(db.KvRange{
+		Prefix:  []any{string(DocumentTypeHiveBlock)},
+		Reverse: true,
+	}, nil)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get highest block: %w", err)
+	}
+	return entry.Doc.Block.BlockNumber, nil
+}
+
+func (h *kvHiveBlocks) ListenToBlockUpdates(ctx context.Context, startBlock uint64, listener func(block HiveBlock, headHeight *uint64) error) (context.CancelFunc, <-chan error) {
+	startBlock--
+	ctx, cancel := context.WithCancel(ctx)
+	errChan := make(chan error)
+	go func() {
+		//Same as the Mongo implementation, a panicking listener must not
+		//take the node down
+		defer func() {
+			if r := recover(); r != nil {
+				errChan <- fmt.Errorf("panic in block listener at block %d: %v", startBlock, r)
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			metadata, err := h.docs.Get(kvMetadataKey)
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			blocks := make([]HiveBlock, 0)
+			err = h.docs.Scan(db.KvRange{
+				Prefix: []any{string(DocumentTypeHiveBlock)},
+				From:   []any{startBlock + 1},
+			}, func(entry db.KvEntry[Document]) bool {
+				blocks = append(blocks, *entry.Doc.Block)
+				return len(blocks) < kvListenBatch
+			})
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			for _, block := range blocks {
+				startBlock = block.BlockNumber
+				err = listener(block, metadata.Doc.HeadHeight)
+				if err != nil {
+					errChan <- err
+					return
+				}
+			}
+			if len(blocks) < kvListenBatch {
+				time.Sleep(1 * time.Second)
+			}
+		}
+	}()
+	return cancel, errChan
+}
+
+func (h *kvHiveBlocks) GetBlock(blockNum uint64) (HiveBlock, error) {
+	entry, err := h.docs.Get(blockKey(blockNum))
+	if err == mongo.ErrNoDocuments {
+		return HiveBlock{}, mongo.ErrNoDocuments
+	}
+	if err != nil {
+		return HiveBlock{}, fmt.Errorf("failed to get block: %w", err)
+	}
+	return *entry.Doc.Block, nil
+}
+
+// Sets the fields of doc that are present, like a $set would
+func (h *kvHiveBlocks) SetMetadata(doc Document) error {
+	err := h.docs.Update(kvMetadataKey, func(metadata *Document, found bool) bool {
+		metadata.Type = doc.Type
+		if doc.Block != nil {
+			metadata.Block = doc.Block
+		}
+		if doc.LastProcessedBlock != nil {
+			metadata.LastProcessedBlock = doc.LastProcessedBlock
+		}
+		if doc.LastStoredBlock != nil {
+			metadata.LastStoredBlock = doc.LastStoredBlock
+		}
+		if doc.HeadHeight != nil {
+			metadata.HeadHeight = doc.HeadHeight
+		}
+		if doc.ReindexId != nil {
+			metadata.ReindexId = doc.ReindexId
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set metadata: %w", err)
+	}
+	return nil
+}
+
+func (h *kvHiveBlocks) GetMetadata() (Document, error) {
+	entry, err := h.docs.Get(kvMetadataKey)
+	if err == mongo.ErrNoDocuments {
+		return Document{}, nil
+	}
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	return entry.Doc, nil
+}
+
+// Block timestamps for the kv implementations of other collections,
+// standing in for the hive_blocks $lookup of the aggregation pipelines
+type KvTimestamps struct {
+	docs *db.KvCollection[Document]
+}
+
+func NewKvTimestamps(store *db.KvStore) KvTimestamps {
+	return KvTimestamps{db.NewKvCollection[Document](store, "hive_blocks").WithJson()}
+}
+
+func (t KvTimestamps) Get(height uint64) (string, bool) {
+	entry, err := t.docs.Get(blockKey(height))
+	if err != nil || entry.Doc.Block == nil {
+		return "", false
+	}
+	return entry.Doc.Block.Timestamp, true
+}
+
+// Kv counterpart of GetAggTimestampPipeline: sorts records newest first,
+// applies offset and limit and joins the block timestamps.
+// Returns nil when nothing matches, like decoding an empty cursor into a nil slice.
+func KvTimestampRange[T any](ts KvTimestamps, entries []db.KvEntry[T], height func(doc T) uint64, setTs func(doc *T, ts string), offset int, limit int) []T {
+	SortKvEntries(entries, height, false)
+	entries = entries[min(max(offset, 0), len(entries)):]
+	entries = entries[:min(max(limit, 0), len(entries))]
+
+	var results []T
+	for _, entry := range entries {
+		timestamp, ok := ts.Get(height(entry.Doc))
+		if !ok {
+			continue
+		}
+		setTs(&entry.Doc, timestamp)
+		results = append(results, entry.Doc)
+	}
+	return results
+}
+
+// Kv counterpart of GetAggTimestampPagePipeline and DecodePage.
+// position returns the cursor of a record, see PageKey.
+func KvTimestampPage[T any](ts KvTimestamps, entries []db.KvEntry[T], position func(doc T) PageCursor, setTs func(doc *T, ts string), args PageArgs) Page[T] {
+	docs := db.KvDocs(entries)
+	slices.SortStableFunc(docs, func(a, b T) int {
+		if args.Reverse {
+			return ComparePageCursors(position(a), position(b))
+		}
+		return ComparePageCursors(position(b), position(a))
+	})
+	if cur := args.Cursor; cur != nil {
+		docs = slices.DeleteFunc(docs, func(doc T) bool {
+			cmp := ComparePageCursors(position(doc), *cur)
+			if args.Reverse {
+				return cmp <= 0
+			}
+			return cmp >= 0
+		})
+	}
+
+	page := Page[T]{
+		Items:   make([]T, 0, args.Limit),
+		Cursors: make([]PageCursor, 0, args.Limit),
+	}
+	for _, doc := range docs {
+		if len(page.Items) == args.Limit {
+			page.HasMore = true
+			break
+		}
+		pos := position(doc)
+		//Records whose block is not indexed are kept without a timestamp
+		if timestamp, ok := ts.Get(pos.Height); ok {
+			setTs(&doc, timestamp)
+		}
+		page.Items = append(page.Items, doc)
+		page.Cursors = append(page.Cursors, pos)
+	}
+	if args.Reverse {
+		slices.Reverse(page.Items)
+		slices.Reverse(page.Cursors)
+	}
+	return page
+}
+
+// Orders records by height, then by insertion like a sort on _id would
+func SortKvEntries[T any](entries []db.KvEntry[T], height func(doc T) uint64, ascending bool) {
+	slices.SortStableFunc(entries, func(a, b db.KvEntry[T]) int {
+		cmp := 0
+		ha, hb := height(a.Doc), height(b.Doc)
+		if ha != hb {
+			if ha < hb {
+				cmp = -1
+			} else {
+				cmp = 1
+			}
+		} else {
+			cmp = bytes.Compare(a.Id[:], b.Id[:])
+		}
+		if ascending {
+			return cmp
+		}
+		return -cmp
+	})
+}
+
+// Orders cursors by height, then by record ID and index
+func ComparePageCursors(a PageCursor, b PageCursor) int {
+	if a.Height != b.Height {
+		return cmp.Compare(a.Height, b.Height)
+	}
+	if c := slices.Compare(a.Id, b.Id); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Index, b.Index)
+}
@@ -0,0 +1,200 @@
+package hive_blocks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Fields ordering the records of a collection: the block height, then the
+// ID fields of the record, then its position within the record for records
+// unwound from an array such as contract events. ID fields are natural keys
+// of the records rather than storage IDs, so cursors are the same on every
+// storage backend.
+type PageKey struct {
+	Height string
+	Id     []string
+	Index  string
+}
+
+// Position of a record within a collection ordered by its PageKey (newest first).
+// The ordering is total, so pages never overlap or skip records. Records not
+// anchored in a block yet, such as unconfirmed transactions, have a height of 0
+// and come last.
+type PageCursor struct {
+	Height uint64
+	//Values of the ID fields of the PageKey
+	Id []string
+	//Position within the record, for records unwound from an array
+	Index uint64
+}
+
+// Keyset pagination arguments.
+// By default records are walked from the newest to the oldest, starting right after Cursor.
+// With Reverse, they are walked from the oldest to the newest, starting right before Cursor.
+// Either way, the returned page is in descending order.
+type PageArgs struct {
+	Cursor  *PageCursor
+	Reverse bool
+	Limit   int
+}
+
+// A page of records along with the cursor of each record
+type Page[T any] struct {
+	Items   []T
+	Cursors []PageCursor
+	//Whether more records exist past the end of the page in the direction of travel
+	HasMore bool
+}
+
+// Keyset variant of GetAggTimestampPipeline.
+// Instead of skipping over records, the match continues right after (or before)
+// the cursor, so every page costs the same regardless of how deep it is.
+// One extra record is fetched to detect whether there are more results.
+// Records whose block is not indexed, like unconfirmed transactions, are kept
+// without a timestamp.
+func GetAggTimestampPagePipeline(filters bson.D, key PageKey, timestampField string, args PageArgs) mongo.Pipeline {
+	match := filters
+	if args.Cursor != nil {
+		match = append(slices.Clone(filters), PageCursorMatch(key, args))
+	}
+	//$and keeps the cursor condition from clashing with any $or already in filters
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "$and", Value: andClauses(match)}}}},
+		{{Key: "$sort", Value: PageSort(key, args)}},
+		{{Key: "$limit", Value: args.Limit + 1}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "hive_blocks"},
+			{Key: "localField", Value: key.Height},
+			{Key: "foreignField", Value: "block.block_number"},
+			{Key: "as", Value: "block_info"},
+		}}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: timestampField, Value: PageTimestamp()},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "block_info", Value: 0},
+		}}},
+	}
+}
+
+// Timestamp of the block joined as block_info, missing if the block is not indexed
+func PageTimestamp() bson.D {
+	return bson.D{{Key: "$arrayElemAt", Value: bson.A{"$block_info.block.timestamp", 0}}}
+}
+
+// Sorts records by their key in the direction of travel, see PageArgs
+func PageSort(key PageKey, args PageArgs) bson.D {
+	dir := -1
+	if args.Reverse {
+		dir = 1
+	}
+	sort := bson.D{{Key: key.Height, Value: dir}}
+	for _, field := range key.Id {
+		sort = append(sort, bson.E{Key: field, Value: dir})
+	}
+	if key.Index != "" {
+		sort = append(sort, bson.E{Key: key.Index, Value: dir})
+	}
+	return sort
+}
+
+// Matches the records past the cursor in the direction of travel, see PageArgs
+func PageCursorMatch(key PageKey, args PageArgs) bson.E {
+	cmp := "$lt"
+	if args.Reverse {
+		cmp = "$gt"
+	}
+	cur := args.Cursor
+	clauses := bson.A{bson.D{{Key: key.Height, Value: bson.D{{Key: cmp, Value: cur.Height}}}}}
+	//Records without a height, like unconfirmed transactions, are ordered as height 0
+	var height any = cur.Height
+	if cur.Height == 0 {
+		height = bson.D{{Key: "$in", Value: bson.A{0, nil}}}
+	} else if !args.Reverse {
+		clauses = append(clauses, bson.D{{Key: key.Height, Value: nil}})
+	}
+	equal := bson.D{{Key: key.Height, Value: height}}
+	for i, field := range key.Id {
+		id := ""
+		if i < len(cur.Id) {
+			id = cur.Id[i]
+		}
+		clauses = append(clauses, append(slices.Clone(equal), bson.E{Key: field, Value: bson.D{{Key: cmp, Value: id}}}))
+		equal = append(equal, bson.E{Key: field, Value: id})
+	}
+	if key.Index != "" {
+		clauses = append(clauses, append(slices.Clone(equal), bson.E{Key: key.Index, Value: bson.D{{Key: cmp, Value: int64(cur.Index)}}}))
+	}
+	return bson.E{Key: "$or", Value: clauses}
+}
+
+// Decodes the results of a pipeline built by GetAggTimestampPagePipeline
+func DecodePage[T any](ctx context.Context, cursor *mongo.Cursor, key PageKey, args PageArgs) (Page[T], error) {
+	defer cursor.Close(ctx)
+	page := Page[T]{
+		Items:   make([]T, 0, args.Limit),
+		Cursors: make([]PageCursor, 0, args.Limit),
+	}
+	for cursor.Next(ctx) {
+		if len(page.Items) == args.Limit {
+			page.HasMore = true
+			break
+		}
+		var elem T
+		if err := cursor.Decode(&elem); err != nil {
+			return Page[T]{}, err
+		}
+		pos, err := decodePageCursor(cursor.Current, key)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		page.Items = append(page.Items, elem)
+		page.Cursors = append(page.Cursors, pos)
+	}
+	if err := cursor.Err(); err != nil {
+		return Page[T]{}, err
+	}
+	if args.Reverse {
+		slices.Reverse(page.Items)
+		slices.Reverse(page.Cursors)
+	}
+	return page, nil
+}
+
+func decodePageCursor(raw bson.Raw, key PageKey) (PageCursor, error) {
+	pos := PageCursor{Id: make([]string, 0, len(key.Id))}
+	if val, err := raw.LookupErr(key.Height); err == nil && val.Type != bson.TypeNull {
+		height, ok := val.AsInt64OK()
+		if !ok || height < 0 {
+			return PageCursor{}, fmt.Errorf("record has an invalid %s", key.Height)
+		}
+		pos.Height = uint64(height)
+	}
+	for _, field := range key.Id {
+		id, ok := raw.Lookup(field).StringValueOK()
+		if !ok {
+			return PageCursor{}, fmt.Errorf("record is missing its %s", field)
+		}
+		pos.Id = append(pos.Id, id)
+	}
+	if key.Index != "" {
+		index, ok := raw.Lookup(key.Index).AsInt64OK()
+		if !ok || index < 0 {
+			return PageCursor{}, fmt.Errorf("record has an invalid %s", key.Index)
+		}
+		pos.Index = uint64(index)
+	}
+	return pos, nil
+}
+
+func andClauses(filters bson.D) bson.A {
+	clauses := bson.A{bson.D{}}
+	for _, f := range filters {
+		clauses = append(clauses, bson.D{f})
+	}
+	return clauses
+}
@@ -0,0 +1,496 @@
+package ledger_db
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc/hive_blocks"
+
+	"github.com/chebyrash/promise"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type kvPlugin struct{}
+
+func (kvPlugin) Init() error {
+	return nil
+}
+
+func (kvPlugin) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (kvPlugin) Stop() error {
+	return nil
+}
+
+// Ledger on the embedded kv store, keyed by op ID
+type kvLedger struct {
+	kvPlugin
+	docs *db.KvCollection[LedgerRecord]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvLedger(store *db.KvStore) *kvLedger {
+	return &kvLedger{
+		docs: db.NewKvCollection[LedgerRecord](store, "ledger").
+			WithIndex("owner", func(r LedgerRecord) []any { return []any{r.Owner, r.BlockHeight} }).
+			WithIndex("block_height", func(r LedgerRecord) []any { return []any{r.BlockHeight} }),
+		ts: hive_blocks.NewKvTimestamps(store),
+	}
+}
+
+func ledgerHeight(r LedgerRecord) uint64 {
+	return r.BlockHeight
+}
+
+func ledgerPosition(r LedgerRecord) hive_blocks.PageCursor {
+	return hive_blocks.PageCursor{Height: r.BlockHeight, Id: []string{r.Id}}
+}
+
+func setLedgerTs(r *LedgerRecord, ts string) {
+	r.Timestamp = &ts
+}
+
+func (ledger *kvLedger) StoreLedger(ledgerRecords ...LedgerRecord) {
+	for _, ledgerRecord := range ledgerRecords {
+		ledger.docs.Put(db.KvKey(ledgerRecord.Id), ledgerRecord)
+	}
+}
+
+// Records of an account within a height range, in order of height then insertion
+func (ledger *kvLedger) ownerRange(account string, from uint64, to *uint64, filter func(r LedgerRecord) bool) ([]db.KvEntry[LedgerRecord], error) {
+	r := db.KvRange{Index: "owner", Prefix: []any{account}, From: []any{from}}
+	if to != nil {
+		r.To = []any{*to}
+	}
+	entries, err := ledger.docs.Find(r, filter)
+	if err != nil {
+		return nil, err
+	}
+	hive_blocks.SortKvEntries(entries, ledgerHeight, true)
+	return entries, nil
+}
+
+// Get ledger ops after height inclusive
+func (ledger *kvLedger) GetLedgerAfterHeight(account string, blockHeight uint64, asset string, limit *int64) (*[]LedgerRecord, error) {
+	entries, err := ledger.ownerRange(account, blockHeight, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if limit != nil && *limit > 0 && int64(len(entries)) > *limit {
+		entries = entries[:*limit]
+	}
+	results := db.KvDocs(entries)
+	return &results, nil
+}
+
+// Get ledger ops after height inclusive
+func (ledger *kvLedger) GetLedgerRange(account string, start uint64, end uint64, asset string, searchOps ...LedgerOptions) (*[]LedgerRecord, error) {
+	var opTypes []string
+	for _, op := range searchOps {
+		if len(op.OpType) > 0 {
+			opTypes = op.OpType
+		}
+	}
+	entries, err := ledger.ownerRange(account, start, &end, func(r LedgerRecord) bool {
+		if asset != "" && r.Asset != asset {
+			return false
+		}
+		return opTypes == nil || slices.Contains(opTypes, r.Type)
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := db.KvDocs(entries)
+	return &results, nil
+}
+
+// Kv counterpart of ledgerFilters
+func kvLedgerFilter(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64) func(r LedgerRecord) bool {
+	return func(r LedgerRecord) bool {
+		if account != nil && r.From != *account && r.Owner != *account {
+			return false
+		}
+		if txId != nil && !strings.HasPrefix(r.Id, *txId) {
+			return false
+		}
+		if fromBlock != nil && r.BlockHeight < *fromBlock {
+			return false
+		}
+		if toBlock != nil && r.BlockHeight > *toBlock {
+			return false
+		}
+		if len(txTypes) > 0 && !slices.Contains(txTypes, r.Type) {
+			return false
+		}
+		return asset == nil || r.Asset == string(*asset)
+	}
+}
+
+func (ledger *kvLedger) GetLedgersTsRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error) {
+	entries, err := ledger.docs.Find(db.KvRange{}, kvLedgerFilter(account, txId, txTypes, asset, fromBlock, toBlock))
+	if err != nil {
+		return []LedgerRecord{}, err
+	}
+	return hive_blocks.KvTimestampRange(ledger.ts, entries, ledgerHeight, setLedgerTs, offset, limit), nil
+}
+
+func (ledger *kvLedger) GetLedgersTsPage(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[LedgerRecord], error) {
+	entries, err := ledger.docs.Find(db.KvRange{}, kvLedgerFilter(account, txId, txTypes, asset, fromBlock, toBlock))
+	if err != nil {
+		return hive_blocks.Page[LedgerRecord]{}, err
+	}
+	return hive_blocks.KvTimestampPage(ledger.ts, entries, ledgerPosition, setLedgerTs, page), nil
+}
+
+func (ledger *kvLedger) GetRawLedgerRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error) {
+	entries, err := ledger.docs.Find(db.KvRange{}, kvLedgerFilter(account, txId, txTypes, asset, fromBlock, toBlock))
+	if err != nil {
+		return []LedgerRecord{}, err
+	}
+	entries = entries[min(max(offset, 0), len(entries)):]
+	entries = entries[:min(max(limit, 0), len(entries))]
+	var results []LedgerRecord
+	for _, entry := range entries {
+		results = append(results, entry.Doc)
+	}
+	return results, nil
+}
+
+func (ledger *kvLedger) GetDistinctAccountsRange(startHeight, endHeight uint64) ([]string, error) {
+	seen := make(map[string]bool)
+	err := ledger.docs.Scan(db.KvRange{
+		Index: "block_height",
+		From:  []any{startHeight},
+		To:    []any{endHeight},
+	}, func(entry db.KvEntry[LedgerRecord]) bool {
+		seen[entry.Doc.Owner] = true
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]string, 0, len(seen))
+	for account := range seen {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// Balances on the embedded kv store, keyed by account and block height
+type kvBalances struct {
+	kvPlugin
+	docs *db.KvCollection[BalanceRecord]
+}
+
+func newKvBalances(store *db.KvStore) *kvBalances {
+	return &kvBalances{docs: db.NewKvCollection[BalanceRecord](store, "ledger_balances")}
+}
+
+// Gets the balance record for a given account and asset
+// Note: this does not return updated ledger records
+func (balances *kvBalances) GetBalanceRecord(account string, blockHeight uint64) (*BalanceRecord, error) {
+	entry, err := balances.docs.First(db.KvRange{
+		Prefix:  []any{account},
+		To:      []any{blockHeight},
+		Reverse: true,
+	}, nil)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Doc, nil
+}
+
+func (balances *kvBalances) UpdateBalanceRecord(record BalanceRecord) error {
+	return balances.docs.Put(db.KvKey(record.Account, record.BlockHeight), record)
+}
+
+func (balances *kvBalances) GetAll(blockHeight uint64) []BalanceRecord {
+	//Records are ordered by account then height, so the last record at or
+	//below the height of each account is its balance
+	records := make([]BalanceRecord, 0)
+	balances.docs.Scan(db.KvRange{}, func(entry db.KvEntry[BalanceRecord]) bool {
+		if entry.Doc.BlockHeight > blockHeight {
+			return true
+		}
+		if len(records) > 0 && records[len(records)-1].Account == entry.Doc.Account {
+			records[len(records)-1] = entry.Doc
+		} else {
+			records = append(records, entry.Doc)
+		}
+		return true
+	})
+	return records
+}
+
+// Bridge actions on the embedded kv store, keyed by action ID
+type kvActions struct {
+	kvPlugin
+	docs *db.KvCollection[ActionRecord]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvActions(store *db.KvStore) *kvActions {
+	return &kvActions{
+		docs: db.NewKvCollection[ActionRecord](store, "ledger_actions").
+			WithIndex("status", func(a ActionRecord) []any { return []any{a.Status} }),
+		ts: hive_blocks.NewKvTimestamps(store),
+	}
+}
+
+func actionHeight(a ActionRecord) uint64 {
+	return a.BlockHeight
+}
+
+func actionPosition(a ActionRecord) hive_blocks.PageCursor {
+	return hive_blocks.PageCursor{Height: a.BlockHeight, Id: []string{a.Id}}
+}
+
+func setActionTs(a *ActionRecord, ts string) {
+	a.Timestamp = &ts
+}
+
+// Number stored under key of the action data, as decoded from BSON
+func actionParam(params map[string]interface{}, key string) (float64, bool) {
+	switch v := params[key].(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// Pending actions matching filter, sorted by block height then ID
+func (actions *kvActions) pending(filter func(a ActionRecord) bool) ([]ActionRecord, error) {
+	entries, err := actions.docs.Find(db.KvRange{Index: "status", Prefix: []any{"pending"}}, filter)
+	if err != nil {
+		return nil, err
+	}
+	results := db.KvDocs(entries)
+	slices.SortStableFunc(results, func(a, b ActionRecord) int {
+		if a.BlockHeight != b.BlockHeight {
+			if a.BlockHeight < b.BlockHeight {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return results, nil
+}
+
+func (actions *kvActions) StoreAction(withdraw ActionRecord) {
+	actions.docs.Put(db.KvKey(withdraw.Id), withdraw)
+}
+
+func (actions *kvActions) ExecuteComplete(actionId *string, ids ...string) {
+	for _, id := range ids {
+		actions.docs.Update(db.KvKey(id), func(doc *ActionRecord, found bool) bool {
+			doc.Status = "complete"
+			if actionId != nil {
+				doc.TxId = *actionId
+			}
+			return found
+		})
+	}
+}
+
+func (actions *kvActions) Get(id string) (*ActionRecord, error) {
+	entry, err := actions.docs.Get(db.KvKey(id))
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Doc, nil
+}
+
+func (actions *kvActions) SetStatus(id string, status string) {
+	actions.docs.Update(db.KvKey(id), func(doc *ActionRecord, found bool) bool {
+		doc.Status = status
+		return found
+	})
+}
+
+func (actions *kvActions) GetPendingActions(bh uint64, t ...string) ([]ActionRecord, error) {
+	return actions.pending(func(a ActionRecord) bool {
+		return a.BlockHeight <= bh && (len(t) == 0 || slices.Contains(t, a.Type))
+	})
+}
+
+// Gets list of actions equal or less than the supplied epoch
+func (actions *kvActions) GetPendingActionsByEpoch(epoch uint64, t ...string) ([]ActionRecord, error) {
+	results, _ := actions.pending(func(a ActionRecord) bool {
+		actionEpoch, ok := actionParam(a.Params, "epoch")
+		return ok && actionEpoch <= float64(epoch) && (len(t) == 0 || slices.Contains(t, a.Type))
+	})
+	//Only the first action is returned, same as the Mongo implementation
+	actionRecords := make([]ActionRecord, 0)
+	if len(results) > 0 {
+		actionRecords = append(actionRecords, results[0])
+	}
+	return actionRecords, nil
+}
+
+// Kv counterpart of actionFilters
+func kvActionFilter(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64) func(a ActionRecord) bool {
+	return func(a ActionRecord) bool {
+		if txId != nil && a.Id != *txId {
+			return false
+		}
+		if actionId != nil && a.TxId != *actionId {
+			return false
+		}
+		if account != nil && a.To != *account {
+			return false
+		}
+		if len(byTypes) > 0 && !slices.Contains(byTypes, a.Type) {
+			return false
+		}
+		if asset != nil && a.Asset != string(*asset) {
+			return false
+		}
+		if status != nil && a.Status != strings.ToLower(*status) {
+			return false
+		}
+		if fromBlock != nil && a.BlockHeight < *fromBlock {
+			return false
+		}
+		return toBlock == nil || a.BlockHeight <= *toBlock
+	}
+}
+
+func (actions *kvActions) GetActionsRange(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ActionRecord, error) {
+	entries, err := actions.docs.Find(db.KvRange{}, kvActionFilter(txId, actionId, account, byTypes, asset, status, fromBlock, toBlock))
+	if err != nil {
+		return []ActionRecord{}, err
+	}
+	return hive_blocks.KvTimestampRange(actions.ts, entries, actionHeight, setActionTs, offset, limit), nil
+}
+
+func (actions *kvActions) GetActionsPage(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ActionRecord], error) {
+	entries, err := actions.docs.Find(db.KvRange{}, kvActionFilter(txId, actionId, account, byTypes, asset, status, fromBlock, toBlock))
+	if err != nil {
+		return hive_blocks.Page[ActionRecord]{}, err
+	}
+	return hive_blocks.KvTimestampPage(actions.ts, entries, actionPosition, setActionTs, page), nil
+}
+
+func (actions *kvActions) GetAccountPendingConsensusUnstake(account string) (int64, error) {
+	results, err := actions.pending(func(a ActionRecord) bool {
+		return a.To == account && a.Type == "consensus_unstake"
+	})
+	if err != nil {
+		return -1, err
+	}
+	total := int64(0)
+	for _, action := range results {
+		total += action.Amount
+	}
+	return total, nil
+}
+
+// Gets pending scheduled transfers with a release height equal or less than the supplied height
+func (actions *kvActions) GetDueScheduledTransfers(bh uint64) ([]ActionRecord, error) {
+	return actions.pending(func(a ActionRecord) bool {
+		releaseHeight, ok := actionParam(a.Params, "release_height")
+		return a.Type == "scheduled_transfer" && ok && releaseHeight <= float64(bh)
+	})
+}
+
+// Gets scheduled transfers sent or received by an account
+func (actions *kvActions) GetScheduledTransfers(account *string, status *string, offset int, limit int) ([]ActionRecord, error) {
+	entries, err := actions.docs.Find(db.KvRange{}, func(a ActionRecord) bool {
+		if a.Type != "scheduled_transfer" {
+			return false
+		}
+		if account != nil {
+			from, _ := a.Params["from"].(string)
+			if a.To != *account && from != *account {
+				return false
+			}
+		}
+		return status == nil || a.Status == strings.ToLower(*status)
+	})
+	if err != nil {
+		return []ActionRecord{}, err
+	}
+	results := hive_blocks.KvTimestampRange(actions.ts, entries, actionHeight, setActionTs, offset, limit)
+	if results == nil {
+		results = make([]ActionRecord, 0)
+	}
+	return results, nil
+}
+
+func (actions *kvActions) GetActionsByTxId(txId string) ([]ActionRecord, error) {
+	prefix := db.KvKey(txId)
+	actionRecords := make([]ActionRecord, 0)
+	err := actions.docs.Scan(db.KvRange{From: []any{txId}}, func(entry db.KvEntry[ActionRecord]) bool {
+		if !strings.HasPrefix(entry.Key, prefix) {
+			return false
+		}
+		actionRecords = append(actionRecords, entry.Doc)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actionRecords, nil
+}
+
+// Interest claims on the embedded kv store, keyed by block height
+type kvInterestClaims struct {
+	kvPlugin
+	docs *db.KvCollection[ClaimRecord]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvInterestClaims(store *db.KvStore) *kvInterestClaims {
+	return &kvInterestClaims{
+		docs: db.NewKvCollection[ClaimRecord](store, "ledger_claims"),
+		ts:   hive_blocks.NewKvTimestamps(store),
+	}
+}
+
+func (ic *kvInterestClaims) GetLastClaim(blockHeight uint64) *ClaimRecord {
+	if blockHeight == 0 {
+		return nil
+	}
+	entry, err := ic.docs.First(db.KvRange{To: []any{blockHeight - 1}, Reverse: true}, nil)
+	if err != nil {
+		return nil
+	}
+	return &entry.Doc
+}
+
+func (ic *kvInterestClaims) SaveClaim(claim ClaimRecord) {
+	ic.docs.Put(db.KvKey(claim.BlockHeight), claim)
+}
+
+func (ic *kvInterestClaims) FindClaims(fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ClaimRecord, error) {
+	r := db.KvRange{}
+	if fromBlock != nil {
+		r.From = []any{*fromBlock}
+	}
+	if toBlock != nil {
+		r.To = []any{*toBlock}
+	}
+	entries, err := ic.docs.Find(r, nil)
+	if err != nil {
+		return []ClaimRecord{}, err
+	}
+	return hive_blocks.KvTimestampRange(ic.ts, entries, func(c ClaimRecord) uint64 {
+		return c.BlockHeight
+	}, func(c *ClaimRecord, ts string) {
+		c.Timestamp = &ts
+	}, offset, limit), nil
+}
@@ -18,6 +18,9 @@ type ledger struct {
 }
 
 func New(d *vsc.VscDb) Ledger {
+	if kv := d.Kv(); kv != nil {
+		return newKvLedger(kv)
+	}
 	return &ledger{db.NewCollection(d.DbInstance, "ledger")}
 }
 
@@ -98,7 +101,7 @@ func (ledger *ledger) GetLedgerRange(account string, start uint64, end uint64, a
 	return &results, nil
 }
 
-func (ledger *ledger) GetLedgersTsRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error) {
+func ledgerFilters(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64) bson.D {
 	filters := bson.D{}
 	if account != nil {
 		filters = append(filters, bson.E{Key: "$or", Value: bson.A{
@@ -121,6 +124,11 @@ func (ledger *ledger) GetLedgersTsRange(account *string, txId *string, txTypes [
 	if asset != nil {
 		filters = append(filters, bson.E{Key: "tk", Value: string(*asset)})
 	}
+	return filters
+}
+
+func (ledger *ledger) GetLedgersTsRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error) {
+	filters := ledgerFilters(account, txId, txTypes, asset, fromBlock, toBlock)
 	pipe := hive_blocks.GetAggTimestampPipeline(filters, "block_height", "timestamp", offset, limit)
 	cursor, err := ledger.Aggregate(context.TODO(), pipe)
 	if err != nil {
@@ -138,6 +146,19 @@ func (ledger *ledger) GetLedgersTsRange(account *string, txId *string, txTypes [
 	return results, nil
 }
 
+// Ledger records and actions are paged by block height, then by ID
+var pageKey = hive_blocks.PageKey{Height: "block_height", Id: []string{"id"}}
+
+func (ledger *ledger) GetLedgersTsPage(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[LedgerRecord], error) {
+	filters := ledgerFilters(account, txId, txTypes, asset, fromBlock, toBlock)
+	pipe := hive_blocks.GetAggTimestampPagePipeline(filters, pageKey, "timestamp", page)
+	cursor, err := ledger.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		return hive_blocks.Page[LedgerRecord]{}, err
+	}
+	return hive_blocks.DecodePage[LedgerRecord](context.TODO(), cursor, pageKey, page)
+}
+
 func (ledger *ledger) GetRawLedgerRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error) {
 	filters := bson.D{}
 	if account != nil {
@@ -210,6 +231,9 @@ type balances struct {
 }
 
 func NewBalances(d *vsc.VscDb) Balances {
+	if kv := d.Kv(); kv != nil {
+		return newKvBalances(kv)
+	}
 	return &balances{db.NewCollection(d.DbInstance, "ledger_balances")}
 }
 
@@ -271,6 +295,9 @@ type actionsDb struct {
 }
 
 func NewActionsDb(d *vsc.VscDb) BridgeActions {
+	if kv := d.Kv(); kv != nil {
+		return newKvActions(kv)
+	}
 	return &actionsDb{db.NewCollection(d.DbInstance, "ledger_actions")}
 }
 
@@ -319,7 +346,13 @@ func (actionsDb *actionsDb) Get(id string) (*ActionRecord, error) {
 }
 
 func (actionsDb *actionsDb) SetStatus(id string, status string) {
-
+	actionsDb.UpdateOne(context.Background(), bson.M{
+		"id": id,
+	}, bson.M{
+		"$set": bson.M{
+			"status": status,
+		},
+	})
 }
 
 func (actionsDb *actionsDb) GetPendingActions(bh uint64, t ...string) ([]ActionRecord, error) {
@@ -398,7 +431,7 @@ func (actions *actionsDb) GetPendingActionsByEpoch(epoch uint64, t ...string) ([
 	return actionRecords, nil
 }
 
-func (actions *actionsDb) GetActionsRange(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ActionRecord, error) {
+func actionFilters(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64) bson.D {
 	filters := bson.D{}
 	if txId != nil {
 		filters = append(filters, bson.E{Key: "id", Value: *txId})
@@ -424,6 +457,11 @@ func (actions *actionsDb) GetActionsRange(txId *string, actionId *string, accoun
 	if toBlock != nil {
 		filters = append(filters, bson.E{Key: "block_height", Value: bson.D{{Key: "$lte", Value: *toBlock}}})
 	}
+	return filters
+}
+
+func (actions *actionsDb) GetActionsRange(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ActionRecord, error) {
+	filters := actionFilters(txId, actionId, account, byTypes, asset, status, fromBlock, toBlock)
 	pipe := hive_blocks.GetAggTimestampPipeline(filters, "block_height", "timestamp", offset, limit)
 	cursor, err := actions.Aggregate(context.TODO(), pipe)
 	if err != nil {
@@ -441,6 +479,16 @@ func (actions *actionsDb) GetActionsRange(txId *string, actionId *string, accoun
 	return results, nil
 }
 
+func (actions *actionsDb) GetActionsPage(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ActionRecord], error) {
+	filters := actionFilters(txId, actionId, account, byTypes, asset, status, fromBlock, toBlock)
+	pipe := hive_blocks.GetAggTimestampPagePipeline(filters, pageKey, "timestamp", page)
+	cursor, err := actions.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		return hive_blocks.Page[ActionRecord]{}, err
+	}
+	return hive_blocks.DecodePage[ActionRecord](context.TODO(), cursor, pageKey, page)
+}
+
 func (actions *actionsDb) GetAccountPendingConsensusUnstake(account string) (int64, error) {
 	cursor, err := actions.Aggregate(context.TODO(), mongo.Pipeline{
 		{{Key: "$match", Value: bson.D{
@@ -470,6 +518,69 @@ func (actions *actionsDb) GetAccountPendingConsensusUnstake(account string) (int
 	return 0, nil
 }
 
+// Gets pending scheduled transfers with a release height equal or less than the supplied height
+func (actions *actionsDb) GetDueScheduledTransfers(bh uint64) ([]ActionRecord, error) {
+	options := options.Find().SetSort(bson.D{
+		{
+			Key:   "block_height",
+			Value: 1,
+		},
+		{
+			Key:   "id",
+			Value: 1,
+		},
+	})
+	cursor, err := actions.Find(context.Background(), bson.M{
+		"type":   "scheduled_transfer",
+		"status": "pending",
+		"data.release_height": bson.M{
+			"$lte": bh,
+		},
+	}, options)
+	if err != nil {
+		return nil, err
+	}
+
+	actionRecords := make([]ActionRecord, 0)
+	for cursor.Next(context.Background()) {
+		record := ActionRecord{}
+		cursor.Decode(&record)
+
+		actionRecords = append(actionRecords, record)
+	}
+
+	return actionRecords, nil
+}
+
+// Gets scheduled transfers sent or received by an account
+func (actions *actionsDb) GetScheduledTransfers(account *string, status *string, offset int, limit int) ([]ActionRecord, error) {
+	filters := bson.D{{Key: "type", Value: "scheduled_transfer"}}
+	if account != nil {
+		filters = append(filters, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"to": *account},
+			bson.M{"data.from": *account},
+		}})
+	}
+	if status != nil {
+		filters = append(filters, bson.E{Key: "status", Value: strings.ToLower(*status)})
+	}
+	pipe := hive_blocks.GetAggTimestampPipeline(filters, "block_height", "timestamp", offset, limit)
+	cursor, err := actions.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		return []ActionRecord{}, err
+	}
+	defer cursor.Close(context.TODO())
+	results := make([]ActionRecord, 0)
+	for cursor.Next(context.TODO()) {
+		var elem ActionRecord
+		if err := cursor.Decode(&elem); err != nil {
+			return []ActionRecord{}, err
+		}
+		results = append(results, elem)
+	}
+	return results, nil
+}
+
 func (actions *actionsDb) GetActionsByTxId(txId string) ([]ActionRecord, error) {
 	cursor, err := actions.Find(context.Background(), bson.M{
 		"id": bson.M{
@@ -547,5 +658,8 @@ func (ic *interestClaims) FindClaims(fromBlock *uint64, toBlock *uint64, offset
 }
 
 func NewInterestClaimDb(d *vsc.VscDb) InterestClaims {
+	if kv := d.Kv(); kv != nil {
+		return newKvInterestClaims(kv)
+	}
 	return &interestClaims{db.NewCollection(d.DbInstance, "ledger_claims")}
 }
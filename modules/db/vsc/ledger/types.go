@@ -2,6 +2,7 @@ package ledger_db
 
 import (
 	"vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
 )
 
 type Ledger interface {
@@ -10,6 +11,7 @@ type Ledger interface {
 	GetLedgerAfterHeight(account string, blockHeight uint64, asset string, limit *int64) (*[]LedgerRecord, error)
 	GetLedgerRange(account string, start uint64, end uint64, asset string, options ...LedgerOptions) (*[]LedgerRecord, error)
 	GetLedgersTsRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error)
+	GetLedgersTsPage(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[LedgerRecord], error)
 	GetRawLedgerRange(account *string, txId *string, txTypes []string, asset *Asset, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]LedgerRecord, error)
 	//Gets distinct accounts on or after a block height
 	//Used to indicate whether balance has been updated or not
@@ -52,6 +54,8 @@ type BalanceRecord struct {
 	HBD_AVG           int64  `json:"hbd_avg" bson:"hbd_avg"`
 	HBD_CLAIM_HEIGHT  uint64 `json:"hbd_claim" bson:"hbd_claim"`
 	HBD_MODIFY_HEIGHT uint64 `json:"hbd_modify" bson:"hbd_modify"`
+	//Native token balances by asset, zero balances are left out
+	Tokens map[string]int64 `json:"tokens,omitempty" bson:"tokens,omitempty"`
 }
 
 // {
@@ -100,8 +104,11 @@ type BridgeActions interface {
 	GetPendingActions(bh uint64, t ...string) ([]ActionRecord, error)
 	GetPendingActionsByEpoch(epoch uint64, t ...string) ([]ActionRecord, error)
 	GetActionsRange(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]ActionRecord, error)
+	GetActionsPage(txId *string, actionId *string, account *string, byTypes []string, asset *Asset, status *string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[ActionRecord], error)
 	GetAccountPendingConsensusUnstake(account string) (int64, error)
 	GetActionsByTxId(txId string) ([]ActionRecord, error)
+	GetDueScheduledTransfers(bh uint64) ([]ActionRecord, error)
+	GetScheduledTransfers(account *string, status *string, offset int, limit int) ([]ActionRecord, error)
 }
 
 // TODO: Flesh out to build more modular op creation
@@ -0,0 +1,41 @@
+package nonces
+
+import (
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+)
+
+// Nonces on the embedded kv store, keyed by account
+type kvNonces struct {
+	docs *db.KvCollection[NonceRecord]
+}
+
+func newKvNonces(store *db.KvStore) *kvNonces {
+	return &kvNonces{db.NewKvCollection[NonceRecord](store, "nonces")}
+}
+
+func (n *kvNonces) Init() error {
+	return nil
+}
+
+func (n *kvNonces) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (n *kvNonces) Stop() error {
+	return nil
+}
+
+func (n *kvNonces) GetNonce(account string) (NonceRecord, error) {
+	entry, err := n.docs.Get(db.KvKey(account))
+	if err != nil {
+		return NonceRecord{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (n *kvNonces) SetNonce(account string, nonce uint64) error {
+	return n.docs.Put(db.KvKey(account), NonceRecord{Account: account, Nonce: nonce})
+}
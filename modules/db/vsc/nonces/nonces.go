@@ -65,5 +65,8 @@ func (n *nonceDb) Init() error {
 }
 
 func New(d *vsc.VscDb) Nonces {
+	if kv := d.Kv(); kv != nil {
+		return newKvNonces(kv)
+	}
 	return &nonceDb{db.NewCollection(d.DbInstance, "nonces")}
 }
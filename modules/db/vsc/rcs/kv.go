@@ -0,0 +1,49 @@
+package rcDb
+
+import (
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+)
+
+// RC records on the embedded kv store, keyed by account and block height
+type kvRcDb struct {
+	docs *db.KvCollection[RcRecord]
+}
+
+func newKvRcDb(store *db.KvStore) *kvRcDb {
+	return &kvRcDb{db.NewKvCollection[RcRecord](store, "rcs")}
+}
+
+func (e *kvRcDb) Init() error {
+	return nil
+}
+
+func (e *kvRcDb) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (e *kvRcDb) Stop() error {
+	return nil
+}
+
+func (e *kvRcDb) GetRecord(account string, blockHeight uint64) (RcRecord, error) {
+	entry, err := e.docs.First(db.KvRange{
+		Prefix:  []any{account},
+		To:      []any{blockHeight},
+		Reverse: true,
+	}, nil)
+	if err != nil {
+		return RcRecord{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (e *kvRcDb) SetRecord(account string, blockHeight uint64, amount int64) {
+	e.docs.Put(db.KvKey(account, blockHeight), RcRecord{
+		Account:     account,
+		BlockHeight: blockHeight,
+		Amount:      amount,
+	})
+}
@@ -14,6 +14,9 @@ type rcDb struct {
 }
 
 func New(d *vsc.VscDb) RcDb {
+	if kv := d.Kv(); kv != nil {
+		return newKvRcDb(kv)
+	}
 	return &rcDb{db.NewCollection(d.DbInstance, "rcs")}
 }
 
@@ -0,0 +1,19 @@
+package snapshots
+
+import a "vsc-node/modules/aggregate"
+
+type Snapshots interface {
+	a.Plugin
+	StoreSnapshot(record SnapshotRecord) error
+	GetLatestSnapshot() (SnapshotRecord, error)
+}
+
+// Snapshot attested by the election quorum
+type SnapshotRecord struct {
+	//CID of the signed snapshot root, as passed to --snapshot
+	Cid         string `json:"cid" bson:"cid"`
+	Manifest    string `json:"manifest" bson:"manifest"`
+	Epoch       uint64 `json:"epoch" bson:"epoch"`
+	BlockHeight uint64 `json:"block_height" bson:"block_height"`
+	NetId       string `json:"net_id" bson:"net_id"`
+}
@@ -0,0 +1,38 @@
+package snapshots
+
+import (
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+)
+
+// Snapshots on the embedded kv store, keyed by epoch
+type kvSnapshots struct {
+	docs *db.KvCollection[SnapshotRecord]
+}
+
+func newKvSnapshots(store *db.KvStore) *kvSnapshots {
+	return &kvSnapshots{db.NewKvCollection[SnapshotRecord](store, "snapshots")}
+}
+
+func (s *kvSnapshots) Init() error {
+	return nil
+}
+
+func (s *kvSnapshots) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (s *kvSnapshots) Stop() error {
+	return nil
+}
+
+func (s *kvSnapshots) StoreSnapshot(record SnapshotRecord) error {
+	return s.docs.Put(db.KvKey(record.Epoch), record)
+}
+
+func (s *kvSnapshots) GetLatestSnapshot() (SnapshotRecord, error) {
+	entry, err := s.docs.First(db.KvRange{Reverse: true}, nil)
+	return entry.Doc, err
+}
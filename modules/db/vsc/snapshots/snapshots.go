@@ -0,0 +1,56 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type snapshots struct {
+	*db.Collection
+}
+
+func (s *snapshots) Init() error {
+	err := s.Collection.Init()
+	if err != nil {
+		return err
+	}
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "epoch", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	}
+	err = s.CreateIndexIfNotExist(indexModel)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+
+	return nil
+}
+
+func (s *snapshots) StoreSnapshot(record SnapshotRecord) error {
+	options := options.Update().SetUpsert(true)
+	_, err := s.UpdateOne(context.Background(), bson.M{"epoch": record.Epoch}, bson.M{
+		"$set": record,
+	}, options)
+	return err
+}
+
+func (s *snapshots) GetLatestSnapshot() (SnapshotRecord, error) {
+	record := SnapshotRecord{}
+	options := options.FindOne().SetSort(bson.D{{Key: "epoch", Value: -1}})
+	err := s.FindOne(context.Background(), bson.M{}, options).Decode(&record)
+	return record, err
+}
+
+func New(d *vsc.VscDb) Snapshots {
+	if kv := d.Kv(); kv != nil {
+		return newKvSnapshots(kv)
+	}
+	return &snapshots{db.NewCollection(d.DbInstance, "snapshots")}
+}
@@ -1,6 +1,9 @@
 package transactions
 
-import a "vsc-node/modules/aggregate"
+import (
+	a "vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
+)
 
 type Transactions interface {
 	a.Plugin
@@ -8,7 +11,11 @@ type Transactions interface {
 	SetOutput(sOut SetResultUpdate)
 	GetTransaction(id string) *TransactionRecord
 	FindTransactions(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TransactionRecord, error)
+	FindTransactionsPage(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[TransactionRecord], error)
 	FindUnconfirmedTransactions(height uint64) ([]TransactionRecord, error)
 	InvalidateCompetingTransactions(requiredAuths []string, nonces []uint64) (int64, error)
 	HasUnconfirmedWithNonce(requiredAuths []string, nonce uint64) (bool, error)
+	FindUnconfirmedWithNonce(requiredAuths []string, nonce uint64) ([]TransactionRecord, error)
+	MarkReplaced(ids []string, replacedBy string) error
+	ExpireUnconfirmed(height uint64) (int64, error)
 }
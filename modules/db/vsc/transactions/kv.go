@@ -0,0 +1,332 @@
+package transactions
+
+import (
+	"errors"
+	"slices"
+	"time"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc/hive_blocks"
+	ledgerSystem "vsc-node/modules/ledger-system"
+
+	"github.com/chebyrash/promise"
+)
+
+// Transaction pool on the embedded kv store, keyed by transaction ID
+type kvTransactions struct {
+	docs *db.KvCollection[TransactionRecord]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvTransactions(store *db.KvStore) *kvTransactions {
+	return &kvTransactions{
+		db.NewKvCollection[TransactionRecord](store, "transaction_pool").
+			WithIndex("status", func(tx TransactionRecord) []any { return []any{string(tx.Status)} }),
+		hive_blocks.NewKvTimestamps(store),
+	}
+}
+
+func (e *kvTransactions) Init() error {
+	return nil
+}
+
+func (e *kvTransactions) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (e *kvTransactions) Stop() error {
+	return nil
+}
+
+func (e *kvTransactions) Ingest(offTx IngestTransactionUpdate) error {
+	return e.docs.Update(db.KvKey(offTx.Id), func(doc *TransactionRecord, found bool) bool {
+		doc.Id = offTx.Id
+		doc.AnchoredHeight = 0
+		if offTx.AnchoredHeight != nil {
+			doc.AnchoredHeight = *offTx.AnchoredHeight
+		}
+		doc.AnchoredBlock = ""
+		if offTx.AnchoredBlock != nil {
+			doc.AnchoredBlock = *offTx.AnchoredBlock
+		}
+		doc.AnchoredIndex = 0
+		if offTx.AnchoredIndex != nil {
+			doc.AnchoredIndex = *offTx.AnchoredIndex
+		}
+		doc.AnchoredId = offTx.AnchoredId
+		doc.Type = offTx.Type
+		doc.Ops = offTx.Ops
+		doc.OpTypes = offTx.OpTypes
+		doc.RequiredAuths = offTx.RequiredAuths
+		doc.RequiredPostingAuths = offTx.RequiredPostingAuths
+		doc.Nonce = int64(offTx.Nonce)
+		doc.RcLimit = offTx.RcLimit
+		doc.Tip = offTx.Tip
+		doc.TipAsset = offTx.TipAsset
+		doc.Payer = offTx.Payer
+		doc.Ledger = nil
+		if offTx.Ledger != nil {
+			ledger := offTx.Ledger
+			doc.Ledger = &ledger
+		}
+		//Resubmitting a transaction does not extend its TTL
+		if offTx.ExpireBlock != nil && !found {
+			expireBlock := *offTx.ExpireBlock
+			doc.ExpireBlock = &expireBlock
+		}
+		if !found {
+			doc.FirstSeen = time.Now()
+			//Prevents case of reprocessing/reindexing
+			if offTx.Status != "" {
+				doc.Status = TransactionStatus(offTx.Status)
+			} else {
+				doc.Status = TransactionStatusUnconfirmed
+			}
+		} else if offTx.Status != "" {
+			//If it already exists do nothing
+			doc.Status = TransactionStatus(offTx.Status)
+		}
+		return true
+	})
+}
+
+func (e *kvTransactions) SetOutput(sOut SetResultUpdate) {
+	e.docs.Update(db.KvKey(sOut.Id), func(doc *TransactionRecord, found bool) bool {
+		if !found {
+			return false
+		}
+		if sOut.Output != nil {
+			doc.Output = append(doc.Output, *sOut.Output)
+		}
+		if sOut.Ledger != nil {
+			doc.Ledger = sOut.Ledger
+		}
+		if sOut.Status != nil {
+			doc.Status = *sOut.Status
+		}
+		return true
+	})
+}
+
+func (e *kvTransactions) GetTransaction(id string) *TransactionRecord {
+	entry, err := e.docs.Get(db.KvKey(id))
+	if err != nil {
+		return nil
+	}
+	return &entry.Doc
+}
+
+func opsHaveData(ops []TransactionOperation, key string, value string) bool {
+	for _, op := range ops {
+		if v, ok := op.Data[key].(string); ok && v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Kv counterpart of transactionFilters
+func kvTransactionFilter(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64) (func(tx TransactionRecord) bool, error) {
+	if id != nil && ids != nil {
+		return nil, errors.New("either input a single id or a list of ids")
+	}
+	return func(tx TransactionRecord) bool {
+		if id != nil && tx.Id != *id {
+			return false
+		}
+		if ids != nil && !slices.Contains(ids, tx.Id) {
+			return false
+		}
+		if account != nil && !slices.Contains(tx.RequiredAuths, *account) &&
+			!slices.Contains(tx.RequiredPostingAuths, *account) &&
+			!opsHaveData(tx.Ops, "to", *account) {
+			return false
+		}
+		if contract != nil && !opsHaveData(tx.Ops, "contract_id", *contract) {
+			return false
+		}
+		if status != nil && tx.Status != *status {
+			return false
+		}
+		if byType != nil && !slices.ContainsFunc(tx.OpTypes, func(t string) bool {
+			return slices.Contains(byType, t)
+		}) {
+			return false
+		}
+		var ledger []ledgerSystem.OpLogEvent
+		if tx.Ledger != nil {
+			ledger = *tx.Ledger
+		}
+		if ledgerToFrom != nil && !slices.ContainsFunc(ledger, func(event ledgerSystem.OpLogEvent) bool {
+			return event.From == *ledgerToFrom || event.To == *ledgerToFrom
+		}) {
+			return false
+		}
+		if len(ledgerTypes) > 0 && !slices.ContainsFunc(ledger, func(event ledgerSystem.OpLogEvent) bool {
+			return slices.Contains(ledgerTypes, event.Type)
+		}) {
+			return false
+		}
+		if fromBlock != nil && tx.AnchoredHeight < *fromBlock {
+			return false
+		}
+		if toBlock != nil && tx.AnchoredHeight > *toBlock {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// Scans the status index when filtering by status
+func (e *kvTransactions) find(status *TransactionStatus, filter func(tx TransactionRecord) bool) ([]db.KvEntry[TransactionRecord], error) {
+	r := db.KvRange{}
+	if status != nil {
+		r = db.KvRange{Index: "status", Prefix: []any{string(*status)}}
+	}
+	return e.docs.Find(r, filter)
+}
+
+func anchoredHeight(tx TransactionRecord) uint64 {
+	return tx.AnchoredHeight
+}
+
+func anchoredPosition(tx TransactionRecord) hive_blocks.PageCursor {
+	return hive_blocks.PageCursor{Height: tx.AnchoredHeight, Id: []string{tx.Id}}
+}
+
+func setAnchoredTs(tx *TransactionRecord, ts string) {
+	tx.AnchoredTs = &ts
+}
+
+func (e *kvTransactions) FindTransactions(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TransactionRecord, error) {
+	filter, err := kvTransactionFilter(ids, id, account, contract, status, byType, ledgerToFrom, ledgerTypes, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := e.find(status, filter)
+	if err != nil {
+		return []TransactionRecord{}, err
+	}
+	return hive_blocks.KvTimestampRange(e.ts, entries, anchoredHeight, setAnchoredTs, offset, limit), nil
+}
+
+func (e *kvTransactions) FindTransactionsPage(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[TransactionRecord], error) {
+	filter, err := kvTransactionFilter(ids, id, account, contract, status, byType, ledgerToFrom, ledgerTypes, fromBlock, toBlock)
+	if err != nil {
+		return hive_blocks.Page[TransactionRecord]{}, err
+	}
+	entries, err := e.find(status, filter)
+	if err != nil {
+		return hive_blocks.Page[TransactionRecord]{}, err
+	}
+	return hive_blocks.KvTimestampPage(e.ts, entries, anchoredPosition, setAnchoredTs, page), nil
+}
+
+// Whether auths holds every required auth and nothing else, like a
+// $all and $size match does
+func sameAuths(auths []string, requiredAuths []string) bool {
+	if len(auths) != len(requiredAuths) {
+		return false
+	}
+	for _, auth := range requiredAuths {
+		if !slices.Contains(auths, auth) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *kvTransactions) findUnconfirmed(filter func(tx TransactionRecord) bool) ([]db.KvEntry[TransactionRecord], error) {
+	status := TransactionStatusUnconfirmed
+	return e.find(&status, filter)
+}
+
+// InvalidateCompetingTransactions deletes UNCONFIRMED transactions
+// that share the same required_auths and nonce as a confirmed transaction.
+func (e *kvTransactions) InvalidateCompetingTransactions(requiredAuths []string, nonces []uint64) (int64, error) {
+	entries, err := e.findUnconfirmed(func(tx TransactionRecord) bool {
+		return sameAuths(tx.RequiredAuths, requiredAuths) && tx.Nonce >= 0 && slices.Contains(nonces, uint64(tx.Nonce))
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if err := e.docs.Delete(entry.Key); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(entries)), nil
+}
+
+// HasUnconfirmedWithNonce checks if an UNCONFIRMED transaction exists
+// with the given required_auths and nonce.
+func (e *kvTransactions) HasUnconfirmedWithNonce(requiredAuths []string, nonce uint64) (bool, error) {
+	txList, err := e.FindUnconfirmedWithNonce(requiredAuths, nonce)
+	if err != nil {
+		return false, err
+	}
+	return len(txList) > 0, nil
+}
+
+// FindUnconfirmedWithNonce returns the UNCONFIRMED transactions
+// with the given required_auths and nonce.
+func (e *kvTransactions) FindUnconfirmedWithNonce(requiredAuths []string, nonce uint64) ([]TransactionRecord, error) {
+	entries, err := e.findUnconfirmed(func(tx TransactionRecord) bool {
+		return sameAuths(tx.RequiredAuths, requiredAuths) && tx.Nonce == int64(nonce)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db.KvDocs(entries), nil
+}
+
+// MarkReplaced moves UNCONFIRMED transactions to REPLACED, pointing them to their replacement.
+func (e *kvTransactions) MarkReplaced(ids []string, replacedBy string) error {
+	for _, id := range ids {
+		err := e.docs.Update(db.KvKey(id), func(doc *TransactionRecord, found bool) bool {
+			if !found || doc.Status != TransactionStatusUnconfirmed {
+				return false
+			}
+			doc.Status = TransactionStatusReplaced
+			doc.ReplacedBy = &replacedBy
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpireUnconfirmed moves UNCONFIRMED transactions whose expire_block
+// is at or below the given height to EXPIRED.
+func (e *kvTransactions) ExpireUnconfirmed(height uint64) (int64, error) {
+	entries, err := e.findUnconfirmed(func(tx TransactionRecord) bool {
+		return tx.ExpireBlock != nil && *tx.ExpireBlock <= height
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		err := e.docs.Update(entry.Key, func(doc *TransactionRecord, found bool) bool {
+			doc.Status = TransactionStatusExpired
+			return found
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(entries)), nil
+}
+
+// Searches for unconfirmed VSC transactions with no verification
+// Provide height for expiration filtering
+func (e *kvTransactions) FindUnconfirmedTransactions(height uint64) ([]TransactionRecord, error) {
+	entries, err := e.findUnconfirmed(func(tx TransactionRecord) bool {
+		return tx.Type == "vsc" && (tx.ExpireBlock == nil || *tx.ExpireBlock > height)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db.KvDocs(entries), nil
+}
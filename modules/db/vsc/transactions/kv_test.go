@@ -0,0 +1,81 @@
+package transactions
+
+import (
+	"testing"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc/hive_blocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKvFindTransactionsPage(t *testing.T) {
+	store := db.NewKvStore()
+	require.NoError(t, store.OpenInMemory())
+	t.Cleanup(func() { store.Close() })
+	txs := newKvTransactions(store)
+
+	blocks := db.NewKvCollection[hive_blocks.Document](store, "hive_blocks").WithJson()
+	block := hive_blocks.HiveBlock{BlockNumber: 10, Timestamp: "2025-01-01T00:00:00"}
+	require.NoError(t, blocks.Put(db.KvKey(string(hive_blocks.DocumentTypeHiveBlock), uint64(10)), hive_blocks.Document{Type: hive_blocks.DocumentTypeHiveBlock, Block: &block}))
+
+	anchored := func(id string, height uint64) {
+		require.NoError(t, txs.Ingest(IngestTransactionUpdate{Id: id, Status: "CONFIRMED", AnchoredHeight: &height}))
+	}
+	anchored("tx-b", 10)
+	anchored("tx-a", 10)
+	anchored("tx-c", 20)
+	require.NoError(t, txs.Ingest(IngestTransactionUpdate{Id: "tx-pending", Status: "UNCONFIRMED"}))
+
+	ids := func(page hive_blocks.Page[TransactionRecord]) []string {
+		ids := make([]string, 0)
+		for _, tx := range page.Items {
+			ids = append(ids, tx.Id)
+		}
+		return ids
+	}
+	find := func(args hive_blocks.PageArgs) hive_blocks.Page[TransactionRecord] {
+		page, err := txs.FindTransactionsPage(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, args)
+		require.NoError(t, err)
+		return page
+	}
+
+	//Newest first, then by ID, unconfirmed transactions last
+	all := find(hive_blocks.PageArgs{Limit: 10})
+	assert.Equal(t, []string{"tx-c", "tx-b", "tx-a", "tx-pending"}, ids(all))
+	assert.Nil(t, all.Items[0].AnchoredTs)
+	require.NotNil(t, all.Items[1].AnchoredTs)
+	assert.Nil(t, all.Items[3].AnchoredTs)
+	assert.Equal(t, hive_blocks.PageCursor{Height: 10, Id: []string{"tx-b"}}, all.Cursors[1])
+
+	walked := make([]string, 0)
+	args := hive_blocks.PageArgs{Limit: 1}
+	for {
+		page := find(args)
+		walked = append(walked, ids(page)...)
+		if !page.HasMore {
+			break
+		}
+		args.Cursor = &page.Cursors[0]
+	}
+	assert.Equal(t, ids(all), walked)
+
+	back := find(hive_blocks.PageArgs{Limit: 2, Cursor: &all.Cursors[3], Reverse: true})
+	assert.Equal(t, []string{"tx-b", "tx-a"}, ids(back))
+}
+
+func TestKvIngestKeepsExpireBlock(t *testing.T) {
+	store := db.NewKvStore()
+	require.NoError(t, store.OpenInMemory())
+	t.Cleanup(func() { store.Close() })
+	txs := newKvTransactions(store)
+
+	expire := func(block uint64) *uint64 { return &block }
+	require.NoError(t, txs.Ingest(IngestTransactionUpdate{Id: "tx", Status: "UNCONFIRMED", ExpireBlock: expire(100)}))
+	require.NoError(t, txs.Ingest(IngestTransactionUpdate{Id: "tx", Status: "UNCONFIRMED", ExpireBlock: expire(200)}))
+
+	record := txs.GetTransaction("tx")
+	require.NotNil(t, record)
+	require.NotNil(t, record.ExpireBlock)
+	assert.Equal(t, uint64(100), *record.ExpireBlock)
+}
@@ -16,11 +16,16 @@ type IngestTransactionUpdate struct {
 	OpTypes              []string
 	Ops                  []TransactionOperation
 	RcLimit              uint64
+	Tip                  uint64
+	TipAsset             string
+	Payer                string
 	AnchoredBlock        *string
 	AnchoredId           *string
 	AnchoredIndex        *int64
 	AnchoredHeight       *uint64
 	Ledger               []ledgerSystem.OpLogEvent
+	//Block height after which the transaction is evicted if still unconfirmed
+	ExpireBlock *uint64
 }
 
 type SetResultUpdate struct {
@@ -38,6 +43,10 @@ const (
 	TransactionStatusFailed      TransactionStatus = "FAILED"
 	TransactionStatusIncluded    TransactionStatus = "INCLUDED"
 	TransactionStatusProcessed   TransactionStatus = "PROCESSED"
+	//Superseded by another transaction with the same nonce
+	TransactionStatusReplaced TransactionStatus = "REPLACED"
+	//Evicted from the pool after never being included
+	TransactionStatusExpired TransactionStatus = "EXPIRED"
 )
 
 type TransactionOperation struct {
@@ -62,6 +71,11 @@ type TransactionRecord struct {
 	Nonce                int64    `json:"nonce" bson:"nonce"`
 
 	RcLimit uint64 `json:"rc_limit" bson:"rc_limit"`
+	//Priority tip paid on top of the RC limit, in RCs or HBD
+	Tip      uint64 `json:"tip,omitempty" bson:"tip,omitempty"`
+	TipAsset string `json:"tip_asset,omitempty" bson:"tip_asset,omitempty"`
+	//Required auth paying RCs and tips, the first required auth if empty
+	Payer string `json:"payer,omitempty" bson:"payer,omitempty"`
 
 	//VSC or Hive
 	// TxId    string                 `json:"tx_id,omitempty" bson:"tx_id,omitempty"`
@@ -80,4 +94,8 @@ type TransactionRecord struct {
 	FirstSeen time.Time                  `json:"first_seen" bson:"first_seen"`
 	Ledger    *[]ledgerSystem.OpLogEvent `json:"ledger,omitempty" bson:"ledger,omitempty"`
 	Output    []TransactionOutput        `json:"output,omitempty" bson:"output,omitempty"`
+
+	ExpireBlock *uint64 `json:"expire_block,omitempty" bson:"expire_block,omitempty"`
+	//ID of the transaction that replaced this one, if status is REPLACED
+	ReplacedBy *string `json:"replaced_by,omitempty" bson:"replaced_by,omitempty"`
 }
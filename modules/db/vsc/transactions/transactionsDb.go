@@ -17,6 +17,9 @@ type transactions struct {
 }
 
 func New(d *vsc.VscDb) Transactions {
+	if kv := d.Kv(); kv != nil {
+		return newKvTransactions(kv)
+	}
 	return &transactions{db.NewCollection(d.DbInstance, "transaction_pool")}
 }
 
@@ -53,8 +56,16 @@ func (e *transactions) Ingest(offTx IngestTransactionUpdate) error {
 		"required_posting_auths": offTx.RequiredPostingAuths,
 		"nonce":                  offTx.Nonce,
 		"rc_limit":               offTx.RcLimit,
+		"tip":                    offTx.Tip,
+		"tip_asset":              offTx.TipAsset,
+		"payer":                  offTx.Payer,
 		"ledger":                 offTx.Ledger,
 	}
+	//Resubmitting a transaction does not extend its TTL
+	setOnInsert := bson.M{}
+	if offTx.ExpireBlock != nil {
+		setOnInsert["expire_block"] = *offTx.ExpireBlock
+	}
 	if findResult.Err() != nil {
 		setOp["first_seen"] = time.Now()
 		//Prevents case of reprocessing/reindexing
@@ -70,9 +81,11 @@ func (e *transactions) Ingest(offTx IngestTransactionUpdate) error {
 		}
 	}
 
-	_, err := e.UpdateOne(ctx, queryy, bson.M{
-		"$set": setOp,
-	}, opts)
+	update := bson.M{"$set": setOp}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+	_, err := e.UpdateOne(ctx, queryy, update, opts)
 
 	return err
 }
@@ -120,7 +133,7 @@ func (e *transactions) GetTransaction(id string) *TransactionRecord {
 	return &record
 }
 
-func (e *transactions) FindTransactions(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TransactionRecord, error) {
+func transactionFilters(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64) (bson.D, error) {
 	if id != nil && ids != nil {
 		return nil, errors.New("either input a single id or a list of ids")
 	}
@@ -168,6 +181,14 @@ func (e *transactions) FindTransactions(ids []string, id *string, account *strin
 	if toBlock != nil {
 		filters = append(filters, bson.E{Key: "anchr_height", Value: bson.D{{Key: "$lte", Value: *toBlock}}})
 	}
+	return filters, nil
+}
+
+func (e *transactions) FindTransactions(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TransactionRecord, error) {
+	filters, err := transactionFilters(ids, id, account, contract, status, byType, ledgerToFrom, ledgerTypes, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
 	pipe := hive_blocks.GetAggTimestampPipeline2(filters, "anchr_height", "anchr_ts", offset, limit)
 	cursor, err := e.Aggregate(context.TODO(), pipe)
 	if err != nil {
@@ -185,6 +206,26 @@ func (e *transactions) FindTransactions(ids []string, id *string, account *strin
 	return results, nil
 }
 
+// Transactions are paged by anchor height, then by ID. Unconfirmed
+// transactions have no anchor height yet and come last.
+var pageKey = hive_blocks.PageKey{Height: "anchr_height", Id: []string{"id"}}
+
+func (e *transactions) FindTransactionsPage(ids []string, id *string, account *string, contract *string, status *TransactionStatus, byType []string, ledgerToFrom *string, ledgerTypes []string, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[TransactionRecord], error) {
+	filters, err := transactionFilters(ids, id, account, contract, status, byType, ledgerToFrom, ledgerTypes, fromBlock, toBlock)
+	if err != nil {
+		return hive_blocks.Page[TransactionRecord]{}, err
+	}
+	pipe := hive_blocks.GetAggTimestampPagePipeline(filters, pageKey, "anchr_ts", page)
+	pipe = append(pipe, bson.D{{Key: "$addFields", Value: bson.D{
+		{Key: "tx_id", Value: "$id"},
+	}}})
+	cursor, err := e.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		return hive_blocks.Page[TransactionRecord]{}, err
+	}
+	return hive_blocks.DecodePage[TransactionRecord](context.TODO(), cursor, pageKey, page)
+}
+
 // InvalidateCompetingTransactions deletes UNCONFIRMED transactions
 // that share the same required_auths and nonce as a confirmed transaction.
 func (e *transactions) InvalidateCompetingTransactions(requiredAuths []string, nonces []uint64) (int64, error) {
@@ -223,6 +264,67 @@ func (e *transactions) HasUnconfirmedWithNonce(requiredAuths []string, nonce uin
 	return count > 0, nil
 }
 
+// FindUnconfirmedWithNonce returns the UNCONFIRMED transactions
+// with the given required_auths and nonce.
+func (e *transactions) FindUnconfirmedWithNonce(requiredAuths []string, nonce uint64) ([]TransactionRecord, error) {
+	filter := bson.M{
+		"status": string(TransactionStatusUnconfirmed),
+		"required_auths": bson.M{
+			"$all":  requiredAuths,
+			"$size": len(requiredAuths),
+		},
+		"nonce": nonce,
+	}
+
+	ctx := context.Background()
+	cursor, err := e.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	txList := make([]TransactionRecord, 0)
+	for cursor.Next(ctx) {
+		tx := TransactionRecord{}
+		if err := cursor.Decode(&tx); err != nil {
+			return nil, err
+		}
+		txList = append(txList, tx)
+	}
+	return txList, nil
+}
+
+// MarkReplaced moves UNCONFIRMED transactions to REPLACED, pointing them to their replacement.
+func (e *transactions) MarkReplaced(ids []string, replacedBy string) error {
+	_, err := e.UpdateMany(context.Background(), bson.M{
+		"id":     bson.M{"$in": ids},
+		"status": string(TransactionStatusUnconfirmed),
+	}, bson.M{
+		"$set": bson.M{
+			"status":      string(TransactionStatusReplaced),
+			"replaced_by": replacedBy,
+		},
+	})
+	return err
+}
+
+// ExpireUnconfirmed moves UNCONFIRMED transactions whose expire_block
+// is at or below the given height to EXPIRED.
+func (e *transactions) ExpireUnconfirmed(height uint64) (int64, error) {
+	result, err := e.UpdateMany(context.Background(), bson.M{
+		"status":       string(TransactionStatusUnconfirmed),
+		"expire_block": bson.M{"$lte": height},
+	}, bson.M{
+		"$set": bson.M{
+			"status": string(TransactionStatusExpired),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
 // Searches for unconfirmed VSC transactions with no verification
 // Provide height for expiration filtering
 func (e *transactions) FindUnconfirmedTransactions(height uint64) ([]TransactionRecord, error) {
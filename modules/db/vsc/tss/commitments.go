@@ -94,7 +94,7 @@ func (tsc *tssCommitments) GetCommitmentByHeight(keyId string, height uint64, qt
 	return commitment, err
 }
 
-func (tsc *tssCommitments) FindCommitments(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TssCommitment, error) {
+func commitmentFilters(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64) bson.D {
 	filters := bson.D{}
 	if keyId != nil {
 		filters = append(filters, bson.E{Key: "key_id", Value: *keyId})
@@ -111,6 +111,11 @@ func (tsc *tssCommitments) FindCommitments(keyId *string, byTypes []string, epoc
 	if toBlock != nil {
 		filters = append(filters, bson.E{Key: "block_height", Value: bson.D{{Key: "$lte", Value: *toBlock}}})
 	}
+	return filters
+}
+
+func (tsc *tssCommitments) FindCommitments(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TssCommitment, error) {
+	filters := commitmentFilters(keyId, byTypes, epoch, fromBlock, toBlock)
 
 	pipe := hive_blocks.GetAggTimestampPipeline(filters, "block_height", "timestamp", offset, limit)
 	cursor, err := tsc.Aggregate(context.Background(), pipe)
@@ -131,6 +136,19 @@ func (tsc *tssCommitments) FindCommitments(keyId *string, byTypes []string, epoc
 	return commitments, nil
 }
 
+// Commitments are paged by block height, then by transaction and key
+var commitmentPageKey = hive_blocks.PageKey{Height: "block_height", Id: []string{"tx_id", "key_id"}}
+
+func (tsc *tssCommitments) FindCommitmentsPage(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[TssCommitment], error) {
+	filters := commitmentFilters(keyId, byTypes, epoch, fromBlock, toBlock)
+	pipe := hive_blocks.GetAggTimestampPagePipeline(filters, commitmentPageKey, "timestamp", page)
+	cursor, err := tsc.Aggregate(context.Background(), pipe)
+	if err != nil {
+		return hive_blocks.Page[TssCommitment]{}, err
+	}
+	return hive_blocks.DecodePage[TssCommitment](context.Background(), cursor, commitmentPageKey, page)
+}
+
 func (tsc *tssCommitments) FindCommitmentsSimple(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, limit int) ([]TssCommitment, error) {
 	query := bson.M{}
 	if keyId != nil {
@@ -202,5 +220,8 @@ func (tsc *tssCommitments) GetBlames(epoch *uint64) ([]TssCommitment, error) {
 }
 
 func NewCommitments(d *vsc.VscDb) TssCommitments {
+	if kv := d.Kv(); kv != nil {
+		return newKvTssCommitments(kv)
+	}
 	return &tssCommitments{db.NewCollection(d.DbInstance, "tss_commitments")}
 }
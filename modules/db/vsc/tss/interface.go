@@ -2,6 +2,7 @@ package tss_db
 
 import (
 	a "vsc-node/modules/aggregate"
+	"vsc-node/modules/db/vsc/hive_blocks"
 )
 
 // MaxKeyEpochs is the maximum number of epochs a key may be created or renewed for at once.
@@ -57,6 +58,14 @@ type TssCommitments interface {
 		offset int,
 		limit int,
 	) ([]TssCommitment, error)
+	FindCommitmentsPage(
+		keyId *string,
+		byTypes []string,
+		epoch *uint64,
+		fromBlock *uint64,
+		toBlock *uint64,
+		page hive_blocks.PageArgs,
+	) (hive_blocks.Page[TssCommitment], error)
 	// FindCommitmentsSimple queries tss_commitments with a direct Find (no aggregation
 	// pipeline, no hive_blocks join). Use for queries where the timestamp field is not
 	// needed and records may not have matching hive_blocks entries (e.g., type="ready"
@@ -96,6 +105,16 @@ type TssRequest struct {
 	KeyId  string        `bson:"key_id"`
 	Msg    string        `bson:"msg"`
 	Sig    string        `bson:"sig"`
+	//Entrypoint called with the signature once the request completes
+	Callback *SignCallback `bson:"callback,omitempty"`
+}
+
+// Contract entrypoint registered with a signing request, run with the RCs
+// the contract reserved when it requested the signature
+type SignCallback struct {
+	ContractId string `bson:"contract_id"`
+	Action     string `bson:"action"`
+	RcLimit    uint   `bson:"rc_limit"`
 }
 
 // CommitmentMetadata optionally stores error/reason for blame commitments (e.g. timeout vs error).
@@ -147,4 +166,7 @@ type TssOp struct {
 	KeyId  string `json:"key_id"`
 	Args   string `json:"args"`
 	Epochs uint64 `json:"epochs,omitempty"`
+	//Callback entrypoint of a sign op and the RCs reserved for it
+	Callback string `json:"callback,omitempty"`
+	RcLimit  uint   `json:"rc_limit,omitempty"`
 }
@@ -178,5 +178,8 @@ func (tssKeys *tssKeys) DeprecateLegacyKeys() error {
 }
 
 func NewKeys(d *vsc.VscDb) TssKeys {
+	if kv := d.Kv(); kv != nil {
+		return newKvTssKeys(kv)
+	}
 	return &tssKeys{db.NewCollection(d.DbInstance, "tss_keys")}
 }
@@ -0,0 +1,373 @@
+package tss_db
+
+import (
+	"bytes"
+	"slices"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+	"vsc-node/modules/db/vsc/hive_blocks"
+
+	"github.com/chebyrash/promise"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type kvPlugin struct{}
+
+func (kvPlugin) Init() error {
+	return nil
+}
+
+func (kvPlugin) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (kvPlugin) Stop() error {
+	return nil
+}
+
+func kvKeysCollection(store *db.KvStore) *db.KvCollection[TssKey] {
+	return db.NewKvCollection[TssKey](store, "tss_keys").
+		WithIndex("status", func(k TssKey) []any { return []any{k.Status} })
+}
+
+// Orders entries by insertion, like the natural order of a Mongo collection
+func sortByInsertion[T any](entries []db.KvEntry[T]) {
+	slices.SortFunc(entries, func(a, b db.KvEntry[T]) int {
+		return bytes.Compare(a.Id[:], b.Id[:])
+	})
+}
+
+// TSS keys on the embedded kv store, keyed by key ID
+type kvTssKeys struct {
+	kvPlugin
+	docs *db.KvCollection[TssKey]
+}
+
+func newKvTssKeys(store *db.KvStore) *kvTssKeys {
+	return &kvTssKeys{docs: kvKeysCollection(store)}
+}
+
+func (tssKeys *kvTssKeys) withStatus(status string, filter func(k TssKey) bool) ([]TssKey, error) {
+	entries, err := tssKeys.docs.Find(db.KvRange{Index: "status", Prefix: []any{status}}, filter)
+	if err != nil {
+		return nil, err
+	}
+	sortByInsertion(entries)
+	return db.KvDocs(entries), nil
+}
+
+func (tssKeys *kvTssKeys) InsertKey(id string, t TssKeyAlgo, epochs uint64) error {
+	tssKeys.docs.Update(db.KvKey(id), func(key *TssKey, found bool) bool {
+		key.Id = id
+		key.Algo = t
+		key.Status = TssKeyCreated
+		key.Epochs = epochs
+		return true
+	})
+
+	log.Verbose("key inserted", "keyId", id, "algo", t, "epochs", epochs)
+	return nil
+}
+
+func (tssKeys *kvTssKeys) FindKey(id string) (TssKey, error) {
+	entry, err := tssKeys.docs.Get(db.KvKey(id))
+	if err != nil {
+		return TssKey{}, err
+	}
+	return entry.Doc, nil
+}
+
+func (tssKeys *kvTssKeys) SetKey(key TssKey) error {
+	exists := false
+	dbErr := tssKeys.docs.Update(db.KvKey(key.Id), func(doc *TssKey, found bool) bool {
+		exists = found
+		doc.Status = key.Status
+		doc.PublicKey = key.PublicKey
+		doc.Epoch = key.Epoch
+		doc.CreatedHeight = key.CreatedHeight
+		doc.ExpiryEpoch = key.ExpiryEpoch
+		doc.DeprecatedHeight = key.DeprecatedHeight
+		return found
+	})
+	if dbErr == nil && !exists {
+		dbErr = mongo.ErrNoDocuments
+	}
+
+	if dbErr != nil {
+		log.Warn("SetKey failed", "keyId", key.Id, "status", key.Status, "epoch", key.Epoch, "err", dbErr)
+	} else {
+		log.Verbose("SetKey OK", "keyId", key.Id, "status", key.Status, "epoch", key.Epoch)
+	}
+	return dbErr
+}
+
+// FindDeprecatingKeys returns active keys whose ExpiryEpoch has been reached (and ExpiryEpoch > 0).
+func (tssKeys *kvTssKeys) FindDeprecatingKeys(epoch uint64) ([]TssKey, error) {
+	return tssKeys.withStatus(TssKeyActive, func(k TssKey) bool {
+		return k.ExpiryEpoch > 0 && k.ExpiryEpoch <= epoch
+	})
+}
+
+// FindNewlyRetired returns deprecated keys whose grace period has elapsed at the given block height.
+func (tssKeys *kvTssKeys) FindNewlyRetired(blockHeight uint64) ([]TssKey, error) {
+	return tssKeys.withStatus(TssKeyDeprecated, func(k TssKey) bool {
+		return k.DeprecatedHeight > 0 && k.DeprecatedHeight <= int64(blockHeight)-int64(KeyDeprecationGracePeriod)
+	})
+}
+
+func (tssKeys *kvTssKeys) FindNewKeys(bh uint64) ([]TssKey, error) {
+	return tssKeys.withStatus(TssKeyCreated, nil)
+}
+
+// FindEpochKeys returns active keys from a lower epoch that have not yet expired.
+func (tssKeys *kvTssKeys) FindEpochKeys(epoch uint64) ([]TssKey, error) {
+	return tssKeys.withStatus(TssKeyActive, func(k TssKey) bool {
+		return k.Epoch < epoch && (k.ExpiryEpoch == 0 || k.ExpiryEpoch > epoch)
+	})
+}
+
+// DeprecateLegacyKeys bulk-deprecates all active keys that have no expiry epoch set.
+func (tssKeys *kvTssKeys) DeprecateLegacyKeys() error {
+	keys, err := tssKeys.withStatus(TssKeyActive, func(k TssKey) bool {
+		return k.ExpiryEpoch == 0
+	})
+	for _, key := range keys {
+		if err != nil {
+			break
+		}
+		err = tssKeys.docs.Update(db.KvKey(key.Id), func(doc *TssKey, found bool) bool {
+			doc.Status = TssKeyDeprecated
+			doc.DeprecatedHeight = 0
+			return found
+		})
+	}
+	if err != nil {
+		log.Warn("DeprecateLegacyKeys failed", "err", err)
+	} else {
+		log.Info("DeprecateLegacyKeys completed")
+	}
+	return err
+}
+
+// TSS signing requests on the embedded kv store, keyed by key ID and message
+type kvTssRequests struct {
+	kvPlugin
+	docs *db.KvCollection[TssRequest]
+	keys *db.KvCollection[TssKey]
+}
+
+func newKvTssRequests(store *db.KvStore) *kvTssRequests {
+	return &kvTssRequests{
+		docs: db.NewKvCollection[TssRequest](store, "tss_requests").
+			WithIndex("status", func(r TssRequest) []any { return []any{string(r.Status)} }),
+		keys: kvKeysCollection(store),
+	}
+}
+
+// FindRequests implements TssRequests.
+// To get all msgHex associated with keyID, pass in nil for msgHex, or empty slice.
+// Returns only the matching hex, if no matching element, nil value is returned.
+func (tssReq *kvTssRequests) FindRequests(keyID string, msgHex []string) ([]TssRequest, error) {
+	entries, err := tssReq.docs.Find(db.KvRange{Prefix: []any{keyID}}, func(r TssRequest) bool {
+		return len(msgHex) == 0 || slices.Contains(msgHex, r.Msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	sortByInsertion(entries)
+	return db.KvDocs(entries), nil
+}
+
+func (tssReqs *kvTssRequests) SetSignedRequest(req TssRequest) error {
+	return tssReqs.docs.Update(db.KvKey(req.KeyId, req.Msg), func(doc *TssRequest, found bool) bool {
+		if found {
+			//Attach the callback to a pending request registered without one
+			if req.Callback == nil || doc.Callback != nil || doc.Status != SignPending {
+				return false
+			}
+			doc.Callback = req.Callback
+			return true
+		}
+		doc.KeyId = req.KeyId
+		doc.Msg = req.Msg
+		doc.Status = SignPending
+		doc.Callback = req.Callback
+		return true
+	})
+}
+
+func (tssReqs *kvTssRequests) FindUnsignedRequests(blockHeight uint64) ([]TssRequest, error) {
+	// Revive "failed" requests, but only for keys that are currently active.
+	// Requests whose key is still deprecated/retired stay "failed".
+	activeKeys, keyErr := tssReqs.keys.Find(db.KvRange{Index: "status", Prefix: []any{TssKeyActive}}, nil)
+	if keyErr == nil && len(activeKeys) > 0 {
+		activeKeyIds := make([]string, 0, len(activeKeys))
+		for _, key := range activeKeys {
+			activeKeyIds = append(activeKeyIds, key.Doc.Id)
+		}
+		failed, err := tssReqs.docs.Find(db.KvRange{Index: "status", Prefix: []any{string(SignFailed)}}, func(r TssRequest) bool {
+			return slices.Contains(activeKeyIds, r.KeyId)
+		})
+		if err == nil {
+			for _, entry := range failed {
+				tssReqs.docs.Update(entry.Key, func(doc *TssRequest, found bool) bool {
+					doc.Status = SignPending
+					return found
+				})
+			}
+		}
+	}
+
+	entries, err := tssReqs.docs.Find(db.KvRange{Index: "status", Prefix: []any{string(SignPending)}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	sortByInsertion(entries)
+	return db.KvDocs(entries), nil
+}
+
+func (tssReqs *kvTssRequests) UpdateRequest(req TssRequest) error {
+	//If there is no signing request then don't update
+	return tssReqs.docs.Update(db.KvKey(req.KeyId, req.Msg), func(doc *TssRequest, found bool) bool {
+		doc.Sig = req.Sig
+		doc.Status = req.Status
+		return found
+	})
+}
+
+// TSS commitments on the embedded kv store, keyed by key ID and transaction ID
+type kvTssCommitments struct {
+	kvPlugin
+	docs *db.KvCollection[TssCommitment]
+	ts   hive_blocks.KvTimestamps
+}
+
+func newKvTssCommitments(store *db.KvStore) *kvTssCommitments {
+	return &kvTssCommitments{
+		docs: db.NewKvCollection[TssCommitment](store, "tss_commitments"),
+		ts:   hive_blocks.NewKvTimestamps(store),
+	}
+}
+
+func commitmentHeight(c TssCommitment) uint64 {
+	return c.BlockHeight
+}
+
+func commitmentPosition(c TssCommitment) hive_blocks.PageCursor {
+	return hive_blocks.PageCursor{Height: c.BlockHeight, Id: []string{c.TxId, c.KeyId}}
+}
+
+func setCommitmentTs(c *TssCommitment, ts string) {
+	c.Timestamp = ts
+}
+
+func (tsc *kvTssCommitments) SetCommitmentData(commitment TssCommitment) error {
+	err := tsc.docs.Update(db.KvKey(commitment.KeyId, commitment.TxId), func(doc *TssCommitment, found bool) bool {
+		doc.Type = commitment.Type
+		doc.BlockHeight = commitment.BlockHeight
+		doc.Epoch = commitment.Epoch
+		doc.KeyId = commitment.KeyId
+		doc.Commitment = commitment.Commitment
+		doc.PublicKey = commitment.PublicKey
+		doc.TxId = commitment.TxId
+		return true
+	})
+	if err != nil {
+		log.Warn("SetCommitmentData failed", "keyId", commitment.KeyId, "type", commitment.Type, "epoch", commitment.Epoch, "txId", commitment.TxId, "err", err)
+		return err
+	}
+	log.Verbose("SetCommitmentData OK", "keyId", commitment.KeyId, "type", commitment.Type, "epoch", commitment.Epoch, "blockHeight", commitment.BlockHeight, "txId", commitment.TxId)
+	return nil
+}
+
+func (tsc *kvTssCommitments) GetCommitment(keyId string, epoch uint64) (TssCommitment, error) {
+	entries, err := tsc.docs.Find(db.KvRange{Prefix: []any{keyId}}, func(c TssCommitment) bool {
+		return c.Epoch == epoch
+	})
+	if err != nil {
+		return TssCommitment{}, err
+	}
+	if len(entries) == 0 {
+		return TssCommitment{}, mongo.ErrNoDocuments
+	}
+	sortByInsertion(entries)
+	return entries[0].Doc, nil
+}
+
+func (tsc *kvTssCommitments) GetCommitmentByHeight(keyId string, height uint64, qtype ...string) (TssCommitment, error) {
+	entries, err := tsc.docs.Find(db.KvRange{Prefix: []any{keyId}}, func(c TssCommitment) bool {
+		return c.BlockHeight < height && (len(qtype) == 0 || slices.Contains(qtype, c.Type))
+	})
+	if err != nil {
+		return TssCommitment{}, err
+	}
+	if len(entries) == 0 {
+		return TssCommitment{}, mongo.ErrNoDocuments
+	}
+	hive_blocks.SortKvEntries(entries, commitmentHeight, false)
+	return entries[0].Doc, nil
+}
+
+// Kv counterpart of commitmentFilters
+func (tsc *kvTssCommitments) find(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64) ([]db.KvEntry[TssCommitment], error) {
+	r := db.KvRange{}
+	if keyId != nil {
+		r.Prefix = []any{*keyId}
+	}
+	return tsc.docs.Find(r, func(c TssCommitment) bool {
+		if len(byTypes) > 0 && !slices.Contains(byTypes, c.Type) {
+			return false
+		}
+		if epoch != nil && c.Epoch != *epoch {
+			return false
+		}
+		if fromBlock != nil && c.BlockHeight <= *fromBlock {
+			return false
+		}
+		return toBlock == nil || c.BlockHeight <= *toBlock
+	})
+}
+
+func (tsc *kvTssCommitments) FindCommitments(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, offset int, limit int) ([]TssCommitment, error) {
+	entries, err := tsc.find(keyId, byTypes, epoch, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	commitments := hive_blocks.KvTimestampRange(tsc.ts, entries, commitmentHeight, setCommitmentTs, offset, limit)
+	if commitments == nil {
+		commitments = make([]TssCommitment, 0)
+	}
+	return commitments, nil
+}
+
+func (tsc *kvTssCommitments) FindCommitmentsPage(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, page hive_blocks.PageArgs) (hive_blocks.Page[TssCommitment], error) {
+	entries, err := tsc.find(keyId, byTypes, epoch, fromBlock, toBlock)
+	if err != nil {
+		return hive_blocks.Page[TssCommitment]{}, err
+	}
+	return hive_blocks.KvTimestampPage(tsc.ts, entries, commitmentPosition, setCommitmentTs, page), nil
+}
+
+func (tsc *kvTssCommitments) FindCommitmentsSimple(keyId *string, byTypes []string, epoch *uint64, fromBlock *uint64, toBlock *uint64, limit int) ([]TssCommitment, error) {
+	entries, err := tsc.find(keyId, byTypes, epoch, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	hive_blocks.SortKvEntries(entries, commitmentHeight, false)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return db.KvDocs(entries), nil
+}
+
+func (tsc *kvTssCommitments) GetBlames(epoch *uint64) ([]TssCommitment, error) {
+	entries, err := tsc.find(nil, []string{"blame"}, epoch, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	sortByInsertion(entries)
+	return db.KvDocs(entries), nil
+}
@@ -60,17 +60,33 @@ func (tssReqs *tssRequests) SetSignedRequest(req TssRequest) error {
 	})
 
 	if singleResult.Err() == nil {
-		return nil
+		if req.Callback == nil {
+			return nil
+		}
+		//Attach the callback to a pending request registered without one
+		_, err := tssReqs.UpdateOne(ctx, bson.M{
+			"key_id":   req.KeyId,
+			"msg":      req.Msg,
+			"status":   "unsigned",
+			"callback": bson.M{"$exists": false},
+		}, bson.M{
+			"$set": bson.M{"callback": req.Callback},
+		})
+		return err
 	}
 
+	set := bson.M{
+		"status": "unsigned",
+	}
+	if req.Callback != nil {
+		set["callback"] = req.Callback
+	}
 	updateOptions := options.FindOneAndUpdate().SetUpsert(true)
 	singeResult := tssReqs.FindOneAndUpdate(context.Background(), bson.M{
 		"key_id": req.KeyId,
 		"msg":    req.Msg,
 	}, bson.M{
-		"$set": bson.M{
-			"status": "unsigned",
-		},
+		"$set": set,
 	}, updateOptions)
 	return singeResult.Err()
 }
@@ -149,5 +165,8 @@ func (tssReqs *tssRequests) UpdateRequest(req TssRequest) error {
 }
 
 func NewRequests(d *vsc.VscDb) TssRequests {
+	if kv := d.Kv(); kv != nil {
+		return newKvTssRequests(kv)
+	}
 	return &tssRequests{db.NewCollection(d.DbInstance, "tss_requests")}
 }
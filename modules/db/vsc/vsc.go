@@ -21,6 +21,10 @@ func New(d db.Db, dbConf db.DbConfig) *VscDb {
 func (db *VscDb) Nuke() error {
 	ctx := context.Background()
 
+	if db.Kv() != nil {
+		return db.Clear()
+	}
+
 	colsNames, err := db.ListCollectionNames(ctx, bson.M{})
 	if err != nil {
 		return err
@@ -0,0 +1,70 @@
+package vscBlocks
+
+import (
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+)
+
+// Block headers on the embedded kv store, keyed by block ID
+type kvVscBlocks struct {
+	docs *db.KvCollection[VscHeaderRecord]
+}
+
+func newKvVscBlocks(store *db.KvStore) *kvVscBlocks {
+	return &kvVscBlocks{
+		db.NewKvCollection[VscHeaderRecord](store, "block_headers").
+			WithIndex("slot_height", func(h VscHeaderRecord) []any { return []any{h.SlotHeight} }).
+			WithIndex("epoch", func(h VscHeaderRecord) []any { return []any{h.Epoch} }),
+	}
+}
+
+func (vblks *kvVscBlocks) Init() error {
+	return nil
+}
+
+func (vblks *kvVscBlocks) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (vblks *kvVscBlocks) Stop() error {
+	return nil
+}
+
+func (vblks *kvVscBlocks) StoreHeader(header VscHeaderRecord) {
+	vblks.docs.Put(db.KvKey(header.Id), header)
+}
+
+// Gets VSC block by height
+func (vblks *kvVscBlocks) GetBlockByHeight(height uint64) (*VscHeaderRecord, error) {
+	entry, err := vblks.docs.First(db.KvRange{
+		Index:   "slot_height",
+		To:      []any{height},
+		Reverse: true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Doc, nil
+}
+
+func (vblks *kvVscBlocks) GetBlockById(id string) (*VscHeaderRecord, error) {
+	entry, err := vblks.docs.Get(db.KvKey(id))
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Doc, nil
+}
+
+func (vblks *kvVscBlocks) GetBlocksByElection(epoch uint64) ([]VscHeaderRecord, error) {
+	var headers []VscHeaderRecord
+	err := vblks.docs.Scan(db.KvRange{Index: "epoch", Prefix: []any{epoch}}, func(entry db.KvEntry[VscHeaderRecord]) bool {
+		headers = append(headers, entry.Doc)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
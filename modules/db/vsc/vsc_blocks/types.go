@@ -1,6 +1,7 @@
 package vscBlocks
 
 import (
+	"vsc-node/lib/dids"
 	"vsc-node/modules/aggregate"
 
 	"github.com/ipfs/go-cid"
@@ -31,6 +32,12 @@ type VscHeaderRecord struct {
 	} `bson:"stats"`
 	Ts        string      `bson:"ts"`
 	DebugData interface{} `bson:"debug_data"`
+
+	//Signed header fields, kept so the header can be re-hashed for proofs
+	Type      string                  `bson:"type,omitempty"`
+	Version   string                  `bson:"version,omitempty"`
+	Prevb     *string                 `bson:"prevb,omitempty"`
+	Signature *dids.SerializedCircuit `bson:"signature,omitempty"`
 }
 
 type VscBlock struct {
@@ -16,6 +16,9 @@ type vscBlocks struct {
 }
 
 func New(d *vsc.VscDb) VscBlocks {
+	if kv := d.Kv(); kv != nil {
+		return newKvVscBlocks(kv)
+	}
 	return &vscBlocks{db.NewCollection(d.DbInstance, "block_headers")}
 }
 
@@ -0,0 +1,220 @@
+package witnesses
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"vsc-node/lib/utils"
+	"vsc-node/modules/db"
+
+	"github.com/chebyrash/promise"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Witnesses on the embedded kv store, keyed by account and height
+type kvWitnesses struct {
+	docs *db.KvCollection[Witness]
+}
+
+func newKvWitnesses(store *db.KvStore) *kvWitnesses {
+	return &kvWitnesses{
+		db.NewKvCollection[Witness](store, "witnesses").
+			WithIndex("height", func(w Witness) []any { return []any{w.Height} }),
+	}
+}
+
+func (w *kvWitnesses) Init() error {
+	return nil
+}
+
+func (w *kvWitnesses) Start() *promise.Promise[any] {
+	return utils.PromiseResolve[any](nil)
+}
+
+func (w *kvWitnesses) Stop() error {
+	return nil
+}
+
+// StoreNodeAnnouncement implements Witnesses.
+func (w *kvWitnesses) StoreNodeAnnouncement(nodeId string) error {
+	return w.docs.Put(db.KvKey("", uint64(0)), Witness{})
+}
+
+func (w *kvWitnesses) SetWitnessUpdate(requestIn SetWitnessUpdateType) error {
+	bbytes, _ := json.Marshal(requestIn)
+	request := SetWitnessUpdateType{}
+	json.Unmarshal(bbytes, &request)
+
+	var consensusKey string
+	for _, key := range request.Metadata.DidKeys {
+		if key.Type == "consensus" {
+			consensusKey = key.Key
+			break
+		}
+	}
+	if consensusKey == "" {
+		return fmt.Errorf("no consensus key found in did keys")
+	}
+
+	vscNode := request.Metadata.VscNode
+	err := w.docs.Update(db.KvKey(request.Account, request.Height), func(doc *Witness, found bool) bool {
+		doc.Account = request.Account
+		doc.Height = request.Height
+		doc.PeerId = vscNode.PeerId
+		doc.PeerAddrs = vscNode.PeerAddrs
+		doc.Ts = vscNode.Ts
+		doc.TxId = request.TxId
+		doc.VersionId = vscNode.VersionId
+		doc.GitCommit = vscNode.GitCommit
+		doc.NetId = vscNode.NetId
+		doc.ProtocolVersion = vscNode.ProtocolVersion
+		doc.Enabled = vscNode.Witness.Enabled
+		doc.DidKeys = request.Metadata.DidKeys
+		doc.GatewayKey = vscNode.GatewayKey
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	//Maximum of 5 old records on a node should be maintained
+	records, err := w.docs.Find(db.KvRange{Prefix: []any{request.Account}, Reverse: true}, nil)
+	if err != nil {
+		return err
+	}
+	for idx, record := range records {
+		if idx < 6 {
+			continue
+		}
+		if err := w.docs.Delete(db.KvKey(request.Account, record.Doc.Height)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *kvWitnesses) GetLastestWitnesses(searchOptions ...SearchOption) ([]Witness, error) {
+	var witnesses []Witness
+	err := w.docs.Scan(db.KvRange{Index: "height", Reverse: true}, func(entry db.KvEntry[Witness]) bool {
+		witnesses = append(witnesses, entry.Doc)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return witnesses, nil
+}
+
+// Whether the witness has the field values the search options set
+func matchesOptions(witness Witness, opts []SearchOption) (bool, error) {
+	if len(opts) == 0 {
+		return true, nil
+	}
+	query := bson.M{}
+	for _, opt := range opts {
+		opt(&query)
+	}
+	raw, err := bson.Marshal(witness)
+	if err != nil {
+		return false, err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return false, err
+	}
+	for key, value := range query {
+		if !reflect.DeepEqual(doc[key], value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Deterministically sorted (alphetical) list of witnesses at a given block height
+func (w *kvWitnesses) GetWitnessesAtBlockHeight(bh uint64, opts ...SearchOption) ([]Witness, error) {
+	outWit := make([]Witness, 0)
+	if bh == 0 {
+		return outWit, nil
+	}
+	var gte uint64
+	if bh > maxSignedDiff {
+		gte = bh - maxSignedDiff
+	}
+
+	records, err := w.docs.Find(db.KvRange{
+		Index:   "height",
+		From:    []any{gte},
+		To:      []any{bh - 1},
+		Reverse: true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessMap := make(map[string]*Witness)
+	for _, record := range records {
+		witness := record.Doc
+		if witnessMap[witness.Account] != nil {
+			continue
+		}
+		matches, err := matchesOptions(witness, opts)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			witnessMap[witness.Account] = &witness
+		}
+	}
+
+	outNames := make([]string, 0, len(witnessMap))
+	for name := range witnessMap {
+		outNames = append(outNames, name)
+	}
+	sort.Strings(outNames)
+
+	for _, name := range outNames {
+		outWit = append(outWit, *witnessMap[name])
+	}
+	return outWit, nil
+}
+
+func (w *kvWitnesses) GetWitnessesByPeerId(peerIds []string, options ...SearchOption) ([]Witness, error) {
+	peers := make(map[string]bool, len(peerIds))
+	for _, peerId := range peerIds {
+		peers[peerId] = true
+	}
+	records, err := w.docs.Find(db.KvRange{}, func(doc Witness) bool {
+		return peers[doc.PeerId]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	witnessSet := make(map[string]Witness)
+	for _, record := range records {
+		witnessSet[record.Doc.Account] = record.Doc
+	}
+
+	witnesses := make([]Witness, 0)
+	for _, witness := range witnessSet {
+		witnesses = append(witnesses, witness)
+	}
+	return witnesses, nil
+}
+
+func (w *kvWitnesses) GetWitnessAtHeight(account string, bh *uint64) (*Witness, error) {
+	r := db.KvRange{Prefix: []any{account}, Reverse: true}
+	if bh != nil {
+		if *bh == 0 {
+			return nil, mongo.ErrNoDocuments
+		}
+		r.To = []any{*bh - 1}
+	}
+	entry, err := w.docs.First(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Doc, nil
+}
@@ -20,6 +20,9 @@ type witnesses struct {
 }
 
 func New(d *vsc.VscDb) Witnesses {
+	if kv := d.Kv(); kv != nil {
+		return newKvWitnesses(kv)
+	}
 	return &witnesses{db.NewCollection(d.DbInstance, "witnesses")}
 }
 
@@ -14,6 +14,8 @@ import (
 	systemconfig "vsc-node/modules/common/system-config"
 	"vsc-node/modules/db"
 	"vsc-node/modules/db/vsc"
+	"vsc-node/modules/db/vsc/authorities"
+	"vsc-node/modules/db/vsc/bridge"
 	"vsc-node/modules/db/vsc/contracts"
 	"vsc-node/modules/db/vsc/elections"
 	ledger_db "vsc-node/modules/db/vsc/ledger"
@@ -124,10 +126,14 @@ func MakeNode(input MakeNodeInput) *Node {
 	actionsDb := ledger_db.NewActionsDb(vscDb)
 	interestClaims := ledger_db.NewInterestClaimDb(vscDb)
 	contractState := contracts.NewContractState(vscDb)
+	contractUpgrades := contracts.NewContractUpgrades(vscDb)
+	contractSchedules := contracts.NewContractSchedules(vscDb)
 	rcDb := rc_db.New(vscDb)
 	nonceDb := nonces.New(vscDb)
+	authorityDb := authorities.New(vscDb)
 
 	tssRequests := tss_db.NewRequests(vscDb)
+	bridgeDb := bridge.New(vscDb)
 	tssCommitments := tss_db.NewCommitments(vscDb)
 	tssKeys := tss_db.NewKeys(vscDb)
 
@@ -164,6 +170,8 @@ func MakeNode(input MakeNodeInput) *Node {
 		electionDb,
 		contractDb,
 		contractState,
+		contractUpgrades,
+		contractSchedules,
 		txDb,
 		ledgerDb,
 		balanceDb,
@@ -173,15 +181,17 @@ func MakeNode(input MakeNodeInput) *Node {
 		actionsDb,
 		rcDb,
 		nonceDb,
+		authorityDb,
 		tssKeys,
 		tssCommitments,
 		tssRequests,
+		bridgeDb,
 		wasm,
 	)
 
 	blockConsumer := blockconsumer.New(se)
 
-	txpool := transactionpool.New(p2p, txDb, nonceDb, electionDb, hiveBlocks, datalayer, identityConfig, se.RcSystem)
+	txpool := transactionpool.New(p2p, se.Events.PoolTransactions(txDb), nonceDb, authorityDb, electionDb, hiveBlocks, datalayer, identityConfig, sysConfig, se.RcSystem)
 
 	dbNuker := NewDbNuker(vscDb)
 
@@ -272,11 +282,15 @@ func MakeNode(input MakeNodeInput) *Node {
 		balanceDb,
 		interestClaims,
 		contractState,
+		contractUpgrades,
+		contractSchedules,
 		rcDb,
 		nonceDb,
+		authorityDb,
 		tssCommitments,
 		tssKeys,
 		tssRequests,
+		bridgeDb,
 		dataAvailability,
 		blockConsumer,
 		wasm,
@@ -291,25 +305,31 @@ func MakeNode(input MakeNodeInput) *Node {
 
 	if input.Primary {
 		gqlManager := gql.New(gqlgen.NewExecutableSchema(gqlgen.Config{Resolvers: &gqlgen.Resolver{
-			Witnesses:      witnessesDb,
-			TxPool:         txpool,
-			Balances:       balanceDb,
-			Ledger:         ledgerDb,
-			Actions:        actionsDb,
-			Elections:      electionDb,
-			Transactions:   txDb,
-			Nonces:         nonceDb,
-			Rc:             rcDb,
-			HiveBlocks:     hiveBlocks,
-			StateEngine:    se,
-			Da:             datalayer,
-			Contracts:      contractDb,
-			ContractsState: contractState,
-			TssKeys:        tssKeys,
-			TssCommitments: tssCommitments,
-			TssRequests:    tssRequests,
-			InterestClaims: interestClaims,
+			Witnesses:         witnessesDb,
+			TxPool:            txpool,
+			Balances:          balanceDb,
+			Ledger:            ledgerDb,
+			Actions:           actionsDb,
+			Elections:         electionDb,
+			Transactions:      txDb,
+			Nonces:            nonceDb,
+			Authorities:       authorityDb,
+			Rc:                rcDb,
+			HiveBlocks:        hiveBlocks,
+			StateEngine:       se,
+			Da:                datalayer,
+			Contracts:         contractDb,
+			ContractsState:    contractState,
+			ContractUpgrades:  contractUpgrades,
+			ContractSchedules: contractSchedules,
+			TssKeys:           tssKeys,
+			TssCommitments:    tssCommitments,
+			TssRequests:       tssRequests,
+			Bridge:            bridgeDb,
+			InterestClaims:    interestClaims,
+			VscBlocks:         vscBlocks,
 		}}), gqlConfig)
+		gqlManager.Handle("GET "+gql.MempoolPath, gql.MempoolHandler(txpool, sysConfig))
 		plugins = append(plugins, gqlManager)
 	}
 
@@ -3,14 +3,10 @@ package election_proposer
 import (
 	"context"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
-	"fmt"
-	"vsc-node/modules/common"
 	libp2p "vsc-node/modules/p2p"
 	"weak"
 
-	"github.com/ipfs/go-cid"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
 	blsu "github.com/protolambda/bls12-381-util"
@@ -147,14 +143,12 @@ func (s p2pSpec) HandleMessage(ctx context.Context, from peer.ID, msg p2pMessage
 				return nil
 			}
 
-			sig, err := signCid(ep.conf, cid)
+			sigBytes, err := ep.conf.Signer().SignCid(cid)
 
 			if err != nil {
 				return nil
 			}
 
-			sigBytes := sig.Serialize()
-
 			sigStr := base64.URLEncoding.EncodeToString(sigBytes[:])
 
 			resp := signResponse{
@@ -291,28 +285,3 @@ func (s p2pSpec) SerializeMessage(msg p2pMessage) []byte {
 func (p2pSpec) Topic() string {
 	return "/election-proposal/v1"
 }
-
-func signCid(conf common.IdentityConfig, cid cid.Cid) (blsu.Signature, error) {
-	blsPrivKey := blsu.SecretKey{}
-	var arr [32]byte
-	blsPrivSeedHex := conf.Get().BlsPrivKeySeed
-	blsPrivSeed, err := hex.DecodeString(blsPrivSeedHex)
-	if err != nil {
-		return blsu.Signature{}, fmt.Errorf("failed to decode bls priv seed: %w", err)
-	}
-	if len(blsPrivSeed) != 32 {
-		return blsu.Signature{}, fmt.Errorf("bls priv seed must be 32 bytes")
-	}
-
-	copy(arr[:], blsPrivSeed)
-	if err = blsPrivKey.Deserialize(&arr); err != nil {
-		return blsu.Signature{}, fmt.Errorf("failed to deserialize bls priv key: %w", err)
-	}
-
-	sig := blsu.Sign(&blsPrivKey, cid.Bytes())
-	if sig == nil {
-		return blsu.Signature{}, fmt.Errorf("signing failed")
-	}
-
-	return *sig, nil
-}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 	"vsc-node/lib/hive"
+	"vsc-node/lib/signer"
 	"vsc-node/lib/utils"
 	"vsc-node/lib/vsclog"
 	a "vsc-node/modules/aggregate"
@@ -22,6 +23,7 @@ import (
 	ledgerDb "vsc-node/modules/db/vsc/ledger"
 	"vsc-node/modules/db/vsc/witnesses"
 	blockconsumer "vsc-node/modules/hive/block-consumer"
+	ledgerSystem "vsc-node/modules/ledger-system"
 	libp2p "vsc-node/modules/p2p"
 	stateEngine "vsc-node/modules/state-processing"
 
@@ -388,6 +390,11 @@ func (ms *MultiSig) executeActions(bh uint64) (signingPackage, error) {
 	for _, action := range actions {
 		// ops = append(ops, ms.createWithdrawOps(action)...)
 
+		//Withdrawals to other chains are settled through the bridge withdrawal queue
+		if action.Type == "withdraw" && ledgerSystem.BridgeChain(action.Asset) != "" && bh >= ms.sconf.ConsensusParams().BridgeHeight {
+			continue
+		}
+
 		executedOps = append(executedOps, action.Id)
 		if action.Type == "withdraw" {
 			splitTo := strings.Split(action.To, ":")
@@ -764,13 +771,9 @@ func (ms *MultiSig) waitCheckBh(INTERVAL uint64, blockHeight uint64) error {
 	return nil
 }
 
-func (ms *MultiSig) getSigningKp() *hivego.KeyPair {
-	kp, err := GatewayKeyFromBlsSeed(ms.identity.Get().BlsPrivKeySeed)
-	if err != nil {
-		fmt.Println("Failed to decode bls priv seed", err)
-		return nil
-	}
-	return kp
+// Signs the transaction with the gateway key of this node
+func (ms *MultiSig) signTx(tx hivego.HiveTransaction) (string, error) {
+	return signer.SignHiveTx(ms.identity.Signer(), signer.GatewayKey, tx, ms.hiveClient.ChainID)
 }
 
 func (ms *MultiSig) toHiveAssetName(asset string) string {
@@ -65,11 +65,7 @@ func (s p2pSpec) HandleMessage(ctx context.Context, from peer.ID, msg p2pMessage
 			}
 
 			if signPkg.TxId == signReq.TxId {
-				kp := s.ms.getSigningKp()
-				if kp == nil {
-					return nil
-				}
-				sig, err := signPkg.Tx.Sign(*kp, s.ms.hiveClient.ChainID)
+				sig, err := s.ms.signTx(signPkg.Tx)
 				if err != nil {
 					return nil
 				}
@@ -111,11 +107,7 @@ func (s p2pSpec) HandleMessage(ctx context.Context, from peer.ID, msg p2pMessage
 			}
 
 			if signPkg.TxId == signReq.TxId {
-				kp := s.ms.getSigningKp()
-				if kp == nil {
-					return nil
-				}
-				sig, err := signPkg.Tx.Sign(*kp, s.ms.hiveClient.ChainID)
+				sig, err := s.ms.signTx(signPkg.Tx)
 				if err != nil {
 					return nil
 				}
@@ -154,11 +146,7 @@ func (s p2pSpec) HandleMessage(ctx context.Context, from peer.ID, msg p2pMessage
 			}
 
 			if signPkg.TxId == signReq.TxId {
-				kp := s.ms.getSigningKp()
-				if kp == nil {
-					return nil
-				}
-				sig, err := signPkg.Tx.Sign(*kp, s.ms.hiveClient.ChainID)
+				sig, err := s.ms.signTx(signPkg.Tx)
 				if err != nil {
 					return nil
 				}
@@ -17,6 +17,14 @@ func limitCost(base int, childComplexity int, limit *int) int {
 	return base + l*childComplexity
 }
 
+// connectionCost is limitCost for Relay-style connections, where the page size is first or last.
+func connectionCost(childComplexity int, first *int, last *int) int {
+	if last != nil {
+		return limitCost(5, childComplexity, last)
+	}
+	return limitCost(5, childComplexity, first)
+}
+
 // NewComplexityRoot returns complexity cost functions for all GraphQL queries and fields.
 // Fields without explicit costs default to 1 (gqlgen default).
 func NewComplexityRoot() gqlgen.ComplexityRoot {
@@ -34,6 +42,9 @@ func NewComplexityRoot() gqlgen.ComplexityRoot {
 	c.Query.GetAccountNonce = func(childComplexity int, account string) int {
 		return 5 + childComplexity
 	}
+	c.Query.GetAccountAuthority = func(childComplexity int, account string, height *model.Uint64) int {
+		return 5 + childComplexity
+	}
 	c.Query.GetWitness = func(childComplexity int, account string, height *model.Uint64) int {
 		return 5 + childComplexity
 	}
@@ -52,17 +63,33 @@ func NewComplexityRoot() gqlgen.ComplexityRoot {
 	c.Query.GetTssKey = func(childComplexity int, keyID string) int {
 		return 5 + childComplexity
 	}
-	c.Query.GetStateByKeys = func(childComplexity int, contractID string, keys []string, encoding *string) int {
+	c.Query.GetBridgeWithdrawal = func(childComplexity int, id string) int {
 		return 5 + childComplexity
 	}
+	c.Query.GetStateByKeys = func(childComplexity int, contractID string, keys []string, encoding *string, height *model.Uint64, outputID *string) int {
+		return 5 + childComplexity
+	}
+	c.Query.GetStateProof = func(childComplexity int, contractID string, key string, height *model.Uint64) int {
+		return 10 + childComplexity
+	}
 	c.Query.GetDagByCid = func(childComplexity int, cidString string) int {
 		return 5 + childComplexity
 	}
+	// Sequences the whole mempool
+	c.Query.MempoolStatus = func(childComplexity int) int {
+		return 10 + childComplexity
+	}
+	c.Query.Mempool = func(childComplexity int, account *string) int {
+		return 10 + childComplexity
+	}
 
 	// Mutation-like: cost 10
-	c.Query.SubmitTransactionV1 = func(childComplexity int, tx string, sig string) int {
+	c.Query.SubmitTransactionV1 = func(childComplexity int, tx string, sig string, dryRun *bool) int {
 		return 10 + childComplexity
 	}
+	c.Mutation.SubmitTransactions = func(childComplexity int, txs []gqlgen.TransactionSubmitInput, dryRun *bool) int {
+		return 10 + len(txs)*(10+childComplexity)
+	}
 	c.Query.SimulateContractCalls = func(childComplexity int, input gqlgen.SimulateContractCallsInput) int {
 		return 10 + len(input.Calls)*childComplexity
 	}
@@ -96,6 +123,20 @@ func NewComplexityRoot() gqlgen.ComplexityRoot {
 		}
 		return limitCost(5, childComplexity, limit)
 	}
+	c.Query.FindScheduledTransfers = func(childComplexity int, filterOptions *gqlgen.ScheduledTransferFilter) int {
+		var limit *int
+		if filterOptions != nil {
+			limit = filterOptions.Limit
+		}
+		return limitCost(5, childComplexity, limit)
+	}
+	c.Query.FindBridgeWithdrawals = func(childComplexity int, filterOptions *gqlgen.BridgeWithdrawalFilter) int {
+		var limit *int
+		if filterOptions != nil {
+			limit = filterOptions.Limit
+		}
+		return limitCost(5, childComplexity, limit)
+	}
 	c.Query.FindContract = func(childComplexity int, filterOptions *gqlgen.FindContractFilter) int {
 		var limit *int
 		if filterOptions != nil {
@@ -103,6 +144,20 @@ func NewComplexityRoot() gqlgen.ComplexityRoot {
 		}
 		return limitCost(5, childComplexity, limit)
 	}
+	c.Query.FindContractUpgrades = func(childComplexity int, filterOptions *gqlgen.ContractUpgradeFilter) int {
+		var limit *int
+		if filterOptions != nil {
+			limit = filterOptions.Limit
+		}
+		return limitCost(5, childComplexity, limit)
+	}
+	c.Query.FindContractSchedules = func(childComplexity int, filterOptions *gqlgen.ContractScheduleFilter) int {
+		var limit *int
+		if filterOptions != nil {
+			limit = filterOptions.Limit
+		}
+		return limitCost(5, childComplexity, limit)
+	}
 	c.Query.FindTssCommitments = func(childComplexity int, filterOptions *gqlgen.TssCommitmentFilter) int {
 		var limit *int
 		if filterOptions != nil {
@@ -110,6 +165,25 @@ func NewComplexityRoot() gqlgen.ComplexityRoot {
 		}
 		return limitCost(5, childComplexity, limit)
 	}
+	// Cursor connections: base cost 5, scaled by first/last * childComplexity
+	c.Query.FindTransactionConnection = func(childComplexity int, filterOptions *gqlgen.TransactionFilter, first *int, after *string, last *int, before *string) int {
+		return connectionCost(childComplexity, first, last)
+	}
+	c.Query.FindContractOutputConnection = func(childComplexity int, filterOptions *gqlgen.ContractOutputFilter, first *int, after *string, last *int, before *string) int {
+		return connectionCost(childComplexity, first, last)
+	}
+	c.Query.FindContractEvents = func(childComplexity int, filterOptions *gqlgen.ContractEventFilter, first *int, after *string, last *int, before *string) int {
+		return connectionCost(childComplexity, first, last)
+	}
+	c.Query.FindLedgerTXsConnection = func(childComplexity int, filterOptions *gqlgen.LedgerTxFilter, first *int, after *string, last *int, before *string) int {
+		return connectionCost(childComplexity, first, last)
+	}
+	c.Query.FindLedgerActionsConnection = func(childComplexity int, filterOptions *gqlgen.LedgerActionsFilter, first *int, after *string, last *int, before *string) int {
+		return connectionCost(childComplexity, first, last)
+	}
+	c.Query.FindTssCommitmentsConnection = func(childComplexity int, filterOptions *gqlgen.TssCommitmentFilter, first *int, after *string, last *int, before *string) int {
+		return connectionCost(childComplexity, first, last)
+	}
 	// Fixed-size list queries (no limit parameter): flat cost 50
 	c.Query.WitnessNodes = func(childComplexity int, height model.Uint64) int {
 		return 50 + childComplexity
@@ -1,10 +1,15 @@
 package gql
 
-import "vsc-node/modules/config"
+import (
+	"strings"
+	"vsc-node/modules/config"
+)
 
 type gqlConfig struct {
 	HostAddr      string
 	MaxComplexity int
+	// Origins allowed to make browser requests and open subscriptions, "*" allows any
+	AllowedOrigins []string
 }
 
 type gqlConfigStruct struct {
@@ -22,8 +27,9 @@ func NewGqlConfig(dataDir ...string) GqlConfig {
 	}
 
 	return &gqlConfigStruct{config.New(gqlConfig{
-		HostAddr:      "0.0.0.0:8080",
-		MaxComplexity: DefaultComplexityLimit,
+		HostAddr:       "0.0.0.0:8080",
+		MaxComplexity:  DefaultComplexityLimit,
+		AllowedOrigins: []string{"*"},
 	}, dataDirPtr)}
 }
 
@@ -50,3 +56,32 @@ func (gc *gqlConfigStruct) GetMaxComplexity() int {
 	}
 	return v
 }
+
+func (gc *gqlConfigStruct) SetAllowedOrigins(origins []string) error {
+	return gc.Update(func(dc *gqlConfig) {
+		dc.AllowedOrigins = origins
+	})
+}
+
+// Config files written before the setting existed allow any origin
+func (gc *gqlConfigStruct) GetAllowedOrigins() []string {
+	origins := gc.Get().AllowedOrigins
+	if len(origins) == 0 {
+		return []string{"*"}
+	}
+	return origins
+}
+
+// Whether a request from origin may use the API. Requests without an
+// Origin header do not come from a browser and are always allowed.
+func (gc *gqlConfigStruct) OriginAllowed(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range gc.GetAllowedOrigins() {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	pg "github.com/99designs/gqlgen/graphql/playground"
 	"github.com/chebyrash/promise"
+	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
 )
 
@@ -24,6 +25,9 @@ import (
 
 const shutdownTimeout = 5 * time.Second
 
+// interval between keep-alive pings sent to subscription clients
+const wsKeepAliveInterval = 10 * time.Second
+
 // ===== types =====
 
 type gqlManager struct {
@@ -32,6 +36,8 @@ type gqlManager struct {
 	startPromise *promise.Promise[any]
 	conf         GqlConfig
 	schema       graphql.ExecutableSchema
+	// extra routes served next to the GraphQL endpoint
+	routes map[string]http.Handler
 }
 
 // ===== interface assertion =====
@@ -44,15 +50,31 @@ func New(schema graphql.ExecutableSchema, conf GqlConfig) *gqlManager {
 	return &gqlManager{
 		conf:   conf,
 		schema: schema,
+		routes: make(map[string]http.Handler),
 	}
 }
 
+// Registers a route on the GraphQL HTTP server. Must be called before Init.
+func (g *gqlManager) Handle(pattern string, handler http.Handler) {
+	g.routes[pattern] = handler
+}
+
 func (g *gqlManager) Init() error {
 	mux := http.NewServeMux()
 
 	// creates GraphQL server with Apollo
 	gqlServer := handler.New(g.schema)
 	gqlServer.AddTransport(transport.POST{})
+	// subscriptions are served over WebSocket (graphql-ws and graphql-transport-ws)
+	gqlServer.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: wsKeepAliveInterval,
+		Upgrader: websocket.Upgrader{
+			// same policy as the CORS config below
+			CheckOrigin: func(r *http.Request) bool {
+				return g.conf.OriginAllowed(r.Header.Get("Origin"))
+			},
+		},
+	})
 	gqlServer.Use(extension.Introspection{})
 	gqlServer.Use(extension.FixedComplexityLimit(g.conf.GetMaxComplexity()))
 
@@ -61,11 +83,15 @@ func (g *gqlManager) Init() error {
 
 	// adds handlers for GraphQL and Apollo sandbox environment
 	mux.Handle("POST /api/v1/graphql", gqlServer)
+	mux.Handle("GET /api/v1/graphql", gqlServer)
 	mux.Handle("GET /sandbox", pg.ApolloSandboxHandler("Apollo Sandbox", "/api/v1/graphql"))
+	for pattern, handler := range g.routes {
+		mux.Handle(pattern, handler)
+	}
 
 	// Configure CORS
 	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
+		AllowedOrigins:   g.conf.GetAllowedOrigins(),
 		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
 		AllowCredentials: false,
@@ -5,7 +5,11 @@ import (
 	"testing"
 
 	"vsc-node/lib/test_utils"
+	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	tss_db "vsc-node/modules/db/vsc/tss"
+	vscBlocks "vsc-node/modules/db/vsc/vsc_blocks"
+	"vsc-node/modules/gql"
 	"vsc-node/modules/gql/gqlgen"
 	"vsc-node/modules/gql/model"
 
@@ -111,6 +115,57 @@ func TestTssCommitmentsResolver(t *testing.T) {
 	assert.Equal(t, "tx-b", filtered[0].TxId)
 }
 
+func TestTssCommitmentsConnectionResolver(t *testing.T) {
+	commitments := &test_utils.MockTssCommitmentsDb{
+		Commitments: map[string]tss_db.TssCommitment{
+			"key-1:5:keygen":  {Type: "keygen", BlockHeight: 100, Epoch: 5, KeyId: "key-1", TxId: "tx-100"},
+			"key-1:6:reshare": {Type: "reshare", BlockHeight: 120, Epoch: 6, KeyId: "key-1", TxId: "tx-120"},
+			"key-1:7:reshare": {Type: "reshare", BlockHeight: 140, Epoch: 7, KeyId: "key-1", TxId: "tx-140"},
+		},
+	}
+	resolver := &gqlgen.Resolver{TssCommitments: commitments}
+
+	ctx := context.Background()
+	first := 2
+	conn, err := resolver.Query().FindTssCommitmentsConnection(ctx, nil, &first, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, conn.Edges, 2)
+	assert.Equal(t, uint64(140), conn.Edges[0].Node.BlockHeight)
+	assert.Equal(t, uint64(120), conn.Edges[1].Node.BlockHeight)
+	assert.True(t, conn.PageInfo.HasNextPage)
+	assert.False(t, conn.PageInfo.HasPreviousPage)
+	require.NotNil(t, conn.PageInfo.EndCursor)
+	assert.Equal(t, conn.Edges[1].Cursor, *conn.PageInfo.EndCursor)
+
+	cursor, err := gqlgen.DecodeCursor(*conn.PageInfo.EndCursor)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(120), cursor.Height)
+
+	_, err = resolver.Query().FindTssCommitmentsConnection(ctx, nil, &first, nil, &first, nil)
+	assert.Error(t, err)
+	bad := "not-a-cursor"
+	_, err = resolver.Query().FindTssCommitmentsConnection(ctx, nil, &first, &bad, nil, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"tx-120", "key-1"}, cursor.Id)
+
+	//Cursors of events carry their position within the output
+	withIndex := hive_blocks.PageCursor{Height: 7, Id: []string{"output-1"}, Index: 1<<32 | 3}
+	decoded, err := gqlgen.DecodeCursor(gqlgen.EncodeCursor(withIndex))
+	require.NoError(t, err)
+	assert.Equal(t, withIndex, decoded)
+
+	//Unconfirmed transactions have no height
+	unconfirmed := hive_blocks.PageCursor{Id: []string{""}}
+	decoded, err = gqlgen.DecodeCursor(gqlgen.EncodeCursor(unconfirmed))
+	require.NoError(t, err)
+	assert.Equal(t, unconfirmed, decoded)
+
+	truncated := gqlgen.EncodeCursor(withIndex)
+	_, err = gqlgen.DecodeCursor(truncated[:len(truncated)-2])
+	assert.Error(t, err)
+}
+
 func TestRecentBlameTimeoutCommitmentsResolver(t *testing.T) {
 	commitments := &test_utils.MockTssCommitmentsDb{
 		Commitments: map[string]tss_db.TssCommitment{
@@ -165,3 +220,84 @@ func TestRecentBlameTimeoutCommitmentsResolver(t *testing.T) {
 	assert.Equal(t, "key-1", recent[1].KeyId)
 	assert.Equal(t, uint64(110), recent[1].BlockHeight)
 }
+
+func TestGetStateByKeysHistoricalArgs(t *testing.T) {
+	contractState := &test_utils.MockContractStateDb{
+		Outputs: map[string]contracts.ContractOutput{
+			"out-1": {Id: "out-1", ContractId: "contract-a", BlockHeight: 10},
+		},
+	}
+	resolver := &gqlgen.Resolver{ContractsState: contractState}
+
+	ctx := context.Background()
+	keys := []string{"key"}
+	height := model.Uint64(10)
+	outputId := "out-1"
+	_, err := resolver.Query().GetStateByKeys(ctx, "contract-a", keys, nil, &height, &outputId)
+	assert.ErrorContains(t, err, "cannot be used together")
+
+	missing := "out-2"
+	_, err = resolver.Query().GetStateByKeys(ctx, "contract-a", keys, nil, nil, &missing)
+	assert.ErrorContains(t, err, "not found")
+
+	//Outputs of other contracts must not be usable as a state root
+	_, err = resolver.Query().GetStateByKeys(ctx, "contract-b", keys, nil, nil, &outputId)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestGetStateProofRequiresSignedBlock(t *testing.T) {
+	contractState := &test_utils.MockContractStateDb{
+		Outputs: map[string]contracts.ContractOutput{
+			"out-1": {Id: "out-1", ContractId: "contract-a", BlockHeight: 10},
+			"out-2": {Id: "out-2", ContractId: "contract-b", BlockHeight: 10, AnchoredId: "block-1"},
+		},
+	}
+	blocks := &test_utils.MockVscBlocksDb{Blocks: []vscBlocks.VscHeaderRecord{{Id: "block-1", SlotHeight: 10}}}
+	resolver := &gqlgen.Resolver{ContractsState: contractState, VscBlocks: blocks}
+
+	ctx := context.Background()
+	_, err := resolver.Query().GetStateProof(ctx, "contract-c", "key", nil)
+	assert.ErrorContains(t, err, "no state found")
+
+	//Outputs indexed before anchr_id was recorded cannot be proven
+	_, err = resolver.Query().GetStateProof(ctx, "contract-a", "key", nil)
+	assert.ErrorContains(t, err, "without its block, resync")
+
+	//Headers indexed before signatures were stored cannot be proven
+	_, err = resolver.Query().GetStateProof(ctx, "contract-b", "key", nil)
+	assert.ErrorContains(t, err, "without its signature")
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	conf := gql.NewGqlConfig()
+	assert.True(t, conf.OriginAllowed("https://anything.example"))
+
+	require.NoError(t, conf.SetAllowedOrigins([]string{"https://app.example"}))
+	assert.True(t, conf.OriginAllowed("https://app.example"))
+	assert.True(t, conf.OriginAllowed("HTTPS://APP.EXAMPLE"))
+	assert.False(t, conf.OriginAllowed("https://evil.example"))
+	//Non-browser clients send no origin
+	assert.True(t, conf.OriginAllowed(""))
+
+	require.NoError(t, conf.SetAllowedOrigins(nil))
+	assert.Equal(t, []string{"*"}, conf.GetAllowedOrigins())
+}
+
+func TestGetStateByKeysNoState(t *testing.T) {
+	contractState := &test_utils.MockContractStateDb{
+		Outputs: map[string]contracts.ContractOutput{
+			"out-1": {Id: "out-1", ContractId: "contract-a", BlockHeight: 10},
+		},
+	}
+	resolver := &gqlgen.Resolver{ContractsState: contractState}
+
+	ctx := context.Background()
+	keys := []string{"key"}
+	_, err := resolver.Query().GetStateByKeys(ctx, "contract-b", keys, nil, nil, nil)
+	assert.ErrorContains(t, err, "no state found")
+
+	//Outputs anchored after height are not visible
+	height := model.Uint64(9)
+	_, err = resolver.Query().GetStateByKeys(ctx, "contract-a", keys, nil, &height, nil)
+	assert.ErrorContains(t, err, "no state found")
+}
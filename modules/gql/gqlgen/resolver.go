@@ -2,6 +2,8 @@ package gqlgen
 
 import (
 	"vsc-node/lib/datalayer"
+	"vsc-node/modules/db/vsc/authorities"
+	"vsc-node/modules/db/vsc/bridge"
 	"vsc-node/modules/db/vsc/contracts"
 	"vsc-node/modules/db/vsc/elections"
 	"vsc-node/modules/db/vsc/hive_blocks"
@@ -10,6 +12,7 @@ import (
 	rcDb "vsc-node/modules/db/vsc/rcs"
 	"vsc-node/modules/db/vsc/transactions"
 	tss_db "vsc-node/modules/db/vsc/tss"
+	vscBlocks "vsc-node/modules/db/vsc/vsc_blocks"
 	"vsc-node/modules/db/vsc/witnesses"
 	"vsc-node/modules/oracle/chain"
 	stateEngine "vsc-node/modules/state-processing"
@@ -21,23 +24,28 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	Witnesses      witnesses.Witnesses
-	TxPool         *transactionpool.TransactionPool
-	Balances       ledgerDb.Balances
-	Ledger         ledgerDb.Ledger
-	Actions        ledgerDb.BridgeActions
-	InterestClaims ledgerDb.InterestClaims
-	Elections      elections.Elections
-	Transactions   transactions.Transactions
-	Nonces         nonces.Nonces
-	Rc             rcDb.RcDb
-	HiveBlocks     hive_blocks.HiveBlocks
-	StateEngine    *stateEngine.StateEngine
-	Da             *datalayer.DataLayer
-	Contracts      contracts.Contracts
-	ContractsState contracts.ContractState
-	TssKeys        tss_db.TssKeys
-	TssCommitments tss_db.TssCommitments
-	TssRequests    tss_db.TssRequests
-	ChainOracle    *chain.ChainOracle
+	Witnesses         witnesses.Witnesses
+	TxPool            *transactionpool.TransactionPool
+	Balances          ledgerDb.Balances
+	Ledger            ledgerDb.Ledger
+	Actions           ledgerDb.BridgeActions
+	InterestClaims    ledgerDb.InterestClaims
+	Elections         elections.Elections
+	Transactions      transactions.Transactions
+	Nonces            nonces.Nonces
+	Authorities       authorities.Authorities
+	Rc                rcDb.RcDb
+	HiveBlocks        hive_blocks.HiveBlocks
+	StateEngine       *stateEngine.StateEngine
+	Da                *datalayer.DataLayer
+	Contracts         contracts.Contracts
+	ContractsState    contracts.ContractState
+	ContractUpgrades  contracts.ContractUpgrades
+	ContractSchedules contracts.ContractSchedules
+	TssKeys           tss_db.TssKeys
+	TssCommitments    tss_db.TssCommitments
+	TssRequests       tss_db.TssRequests
+	Bridge            bridge.Withdrawals
+	ChainOracle       *chain.ChainOracle
+	VscBlocks         vscBlocks.VscBlocks
 }
@@ -12,14 +12,18 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"slices"
 	"strings"
 	"unicode/utf8"
 	"vsc-node/lib/datalayer"
+	"vsc-node/lib/stateproof"
 	"vsc-node/modules/announcements"
 	"vsc-node/modules/common"
 	"vsc-node/modules/common/params"
 	contract_execution_context "vsc-node/modules/contract/execution-context"
 	contract_session "vsc-node/modules/contract/session"
+	"vsc-node/modules/db/vsc/authorities"
+	"vsc-node/modules/db/vsc/bridge"
 	"vsc-node/modules/db/vsc/contracts"
 	"vsc-node/modules/db/vsc/elections"
 	ledgerDb "vsc-node/modules/db/vsc/ledger"
@@ -27,6 +31,7 @@ import (
 	rcDb "vsc-node/modules/db/vsc/rcs"
 	"vsc-node/modules/db/vsc/transactions"
 	tss_db "vsc-node/modules/db/vsc/tss"
+	vscBlocks "vsc-node/modules/db/vsc/vsc_blocks"
 	"vsc-node/modules/db/vsc/witnesses"
 	"vsc-node/modules/gql/model"
 	ledgerSystem "vsc-node/modules/ledger-system"
@@ -40,6 +45,16 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// Threshold is the resolver for the threshold field.
+func (r *accountAuthorityResolver) Threshold(ctx context.Context, obj *authorities.AuthorityRecord) (model.Uint64, error) {
+	return model.Uint64(obj.Threshold), nil
+}
+
+// BlockHeight is the resolver for the block_height field.
+func (r *accountAuthorityResolver) BlockHeight(ctx context.Context, obj *authorities.AuthorityRecord) (model.Uint64, error) {
+	return model.Uint64(obj.BlockHeight), nil
+}
+
 // Amount is the resolver for the amount field.
 func (r *actionRecordResolver) Amount(ctx context.Context, obj *ledgerDb.ActionRecord) (model.Int64, error) {
 	return model.Int64(obj.Amount), nil
@@ -60,6 +75,11 @@ func (r *actionRecordResolver) BlockHeight(ctx context.Context, obj *ledgerDb.Ac
 	return model.Uint64(obj.BlockHeight), nil
 }
 
+// Weight is the resolver for the weight field.
+func (r *authorityKeyResolver) Weight(ctx context.Context, obj *common.AuthorityKey) (model.Uint64, error) {
+	return model.Uint64(obj.Weight), nil
+}
+
 // BlockHeight is the resolver for the block_height field.
 func (r *balanceRecordResolver) BlockHeight(ctx context.Context, obj *ledgerDb.BalanceRecord) (model.Uint64, error) {
 	return model.Uint64(obj.BlockHeight), nil
@@ -126,6 +146,70 @@ func (r *balanceRecordResolver) PendingHbdUnstaking(ctx context.Context, obj *le
 	return &ret, nil
 }
 
+// ScheduledTransfers is the resolver for the scheduled_transfers field.
+func (r *balanceRecordResolver) ScheduledTransfers(ctx context.Context, obj *ledgerDb.BalanceRecord) ([]ledgerDb.ActionRecord, error) {
+	status := "pending"
+	return r.Actions.GetScheduledTransfers(&obj.Account, &status, 0, 100)
+}
+
+// Tokens is the resolver for the tokens field.
+func (r *balanceRecordResolver) Tokens(ctx context.Context, obj *ledgerDb.BalanceRecord) ([]TokenBalance, error) {
+	tokens := make([]TokenBalance, 0, len(obj.Tokens))
+	for asset, amount := range obj.Tokens {
+		contractId, symbol, _ := ledgerSystem.ParseTokenAsset(asset)
+		tokens = append(tokens, TokenBalance{
+			Asset:      asset,
+			ContractID: contractId,
+			Symbol:     symbol,
+			Amount:     model.Int64(amount),
+		})
+	}
+	slices.SortFunc(tokens, func(a, b TokenBalance) int {
+		return strings.Compare(a.Asset, b.Asset)
+	})
+	return tokens, nil
+}
+
+// Block is the resolver for the block field.
+func (r *blockHeaderResolver) Block(ctx context.Context, obj *vscBlocks.VscHeaderRecord) (string, error) {
+	return obj.BlockContent, nil
+}
+
+// StartBlock is the resolver for the start_block field.
+func (r *blockHeaderResolver) StartBlock(ctx context.Context, obj *vscBlocks.VscHeaderRecord) (model.Uint64, error) {
+	return model.Uint64(obj.StartBlock), nil
+}
+
+// EndBlock is the resolver for the end_block field.
+func (r *blockHeaderResolver) EndBlock(ctx context.Context, obj *vscBlocks.VscHeaderRecord) (model.Uint64, error) {
+	return model.Uint64(obj.EndBlock), nil
+}
+
+// SlotHeight is the resolver for the slot_height field.
+func (r *blockHeaderResolver) SlotHeight(ctx context.Context, obj *vscBlocks.VscHeaderRecord) (model.Uint64, error) {
+	return model.Uint64(obj.SlotHeight), nil
+}
+
+// Epoch is the resolver for the epoch field.
+func (r *blockHeaderResolver) Epoch(ctx context.Context, obj *vscBlocks.VscHeaderRecord) (model.Uint64, error) {
+	return model.Uint64(obj.Epoch), nil
+}
+
+// Amount is the resolver for the amount field.
+func (r *bridgeWithdrawalResolver) Amount(ctx context.Context, obj *bridge.Withdrawal) (model.Int64, error) {
+	return model.Int64(obj.Amount), nil
+}
+
+// BlockHeight is the resolver for the block_height field.
+func (r *bridgeWithdrawalResolver) BlockHeight(ctx context.Context, obj *bridge.Withdrawal) (model.Uint64, error) {
+	return model.Uint64(obj.BlockHeight), nil
+}
+
+// UpdatedHeight is the resolver for the updated_height field.
+func (r *bridgeWithdrawalResolver) UpdatedHeight(ctx context.Context, obj *bridge.Withdrawal) (model.Uint64, error) {
+	return model.Uint64(obj.UpdatedHeight), nil
+}
+
 // CreationHeight is the resolver for the creation_height field.
 func (r *contractResolver) CreationHeight(ctx context.Context, obj *contracts.Contract) (model.Uint64, error) {
 	return model.Uint64(obj.CreationHeight), nil
@@ -136,11 +220,88 @@ func (r *contractResolver) Runtime(ctx context.Context, obj *contracts.Contract)
 	return obj.Runtime.String(), nil
 }
 
+// UpgradePolicy is the resolver for the upgrade_policy field.
+func (r *contractResolver) UpgradePolicy(ctx context.Context, obj *contracts.Contract) (string, error) {
+	policy, err := contracts.ParseUpgradePolicy(obj.UpgradePolicy)
+	if err != nil {
+		return "", err
+	}
+	return policy.String(), nil
+}
+
+// BlockHeight is the resolver for the block_height field.
+func (r *contractEventRecordResolver) BlockHeight(ctx context.Context, obj *contracts.ContractEventRecord) (model.Int64, error) {
+	return model.Int64(obj.BlockHeight), nil
+}
+
 // BlockHeight is the resolver for the block_height field.
 func (r *contractOutputResolver) BlockHeight(ctx context.Context, obj *contracts.ContractOutput) (model.Int64, error) {
 	return model.Int64(obj.BlockHeight), nil
 }
 
+// AnchrID is the resolver for the anchr_id field.
+func (r *contractOutputResolver) AnchrID(ctx context.Context, obj *contracts.ContractOutput) (*string, error) {
+	if obj.AnchoredId == "" {
+		return nil, nil
+	}
+	return &obj.AnchoredId, nil
+}
+
+// Interval is the resolver for the interval field.
+func (r *contractScheduleResolver) Interval(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.Interval), nil
+}
+
+// RcLimit is the resolver for the rc_limit field.
+func (r *contractScheduleResolver) RcLimit(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.RcLimit), nil
+}
+
+// NextHeight is the resolver for the next_height field.
+func (r *contractScheduleResolver) NextHeight(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.NextHeight), nil
+}
+
+// CreatedHeight is the resolver for the created_height field.
+func (r *contractScheduleResolver) CreatedHeight(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.CreatedHeight), nil
+}
+
+// LastRunHeight is the resolver for the last_run_height field.
+func (r *contractScheduleResolver) LastRunHeight(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.LastRunHeight), nil
+}
+
+// Runs is the resolver for the runs field.
+func (r *contractScheduleResolver) Runs(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.Runs), nil
+}
+
+// UpdatedHeight is the resolver for the updated_height field.
+func (r *contractScheduleResolver) UpdatedHeight(ctx context.Context, obj *contracts.ContractSchedule) (model.Uint64, error) {
+	return model.Uint64(obj.UpdatedHeight), nil
+}
+
+// Runtime is the resolver for the runtime field.
+func (r *contractUpgradeResolver) Runtime(ctx context.Context, obj *contracts.ContractUpgrade) (string, error) {
+	return obj.Runtime.String(), nil
+}
+
+// ProposedHeight is the resolver for the proposed_height field.
+func (r *contractUpgradeResolver) ProposedHeight(ctx context.Context, obj *contracts.ContractUpgrade) (model.Uint64, error) {
+	return model.Uint64(obj.ProposedHeight), nil
+}
+
+// ActivationHeight is the resolver for the activation_height field.
+func (r *contractUpgradeResolver) ActivationHeight(ctx context.Context, obj *contracts.ContractUpgrade) (model.Uint64, error) {
+	return model.Uint64(obj.ActivationHeight), nil
+}
+
+// UpdatedHeight is the resolver for the updated_height field.
+func (r *contractUpgradeResolver) UpdatedHeight(ctx context.Context, obj *contracts.ContractUpgrade) (model.Uint64, error) {
+	return model.Uint64(obj.UpdatedHeight), nil
+}
+
 // Epoch is the resolver for the epoch field.
 func (r *electionResultResolver) Epoch(ctx context.Context, obj *elections.ElectionResult) (model.Uint64, error) {
 	return model.Uint64(obj.Epoch), nil
@@ -190,6 +351,46 @@ func (r *ledgerRecordResolver) BlockHeight(ctx context.Context, obj *ledgerDb.Le
 	return model.Uint64(obj.BlockHeight), nil
 }
 
+// SubmitTransactions is the resolver for the submitTransactions field.
+func (r *mutationResolver) SubmitTransactions(ctx context.Context, txs []TransactionSubmitInput, dryRun *bool) ([]TransactionBatchResult, error) {
+	batch := make([]transactionpool.SerializedVSCTransaction, 0, len(txs))
+	for i, input := range txs {
+		Tx, err := base64.URLEncoding.DecodeString(input.Tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		Sig, err := base64.URLEncoding.DecodeString(input.Sig)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		batch = append(batch, transactionpool.SerializedVSCTransaction{
+			Tx:  Tx,
+			Sig: Sig,
+		})
+	}
+	results, err := r.TxPool.IngestBatch(batch, transactionpool.IngestOptions{
+		Broadcast: true,
+		DryRun:    dryRun != nil && *dryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TransactionBatchResult, 0, len(results))
+	for _, res := range results {
+		item := TransactionBatchResult{Ok: res.Err == nil}
+		if res.Id != nil {
+			id := res.Id.String()
+			item.ID = &id
+		}
+		if res.Err != nil {
+			msg := res.Err.Error()
+			item.Error = &msg
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
 // Nonce is the resolver for the nonce field.
 func (r *nonceRecordResolver) Nonce(ctx context.Context, obj *nonces.NonceRecord) (model.Uint64, error) {
 	return model.Uint64(obj.Nonce), nil
@@ -215,16 +416,37 @@ func (r *postingJsonKeysResolver) T(ctx context.Context, obj *witnesses.PostingJ
 	return &obj.Type, nil
 }
 
+// Data is the resolver for the data field.
+func (r *proofBlockResolver) Data(ctx context.Context, obj *stateproof.Block) (string, error) {
+	return base64.StdEncoding.EncodeToString(obj.Data), nil
+}
+
 // GetStateByKeys is the resolver for the getStateByKeys field.
-func (r *queryResolver) GetStateByKeys(ctx context.Context, contractID string, keys []string, encoding *string) (model.Map, error) {
+func (r *queryResolver) GetStateByKeys(ctx context.Context, contractID string, keys []string, encoding *string, height *model.Uint64, outputID *string) (model.Map, error) {
 	if len(keys) < 1 || len(keys) > 100 {
 		return nil, fmt.Errorf("number of state keys to query must be between 1 and 100")
 	}
-	output, err := r.ContractsState.GetLastOutput(contractID, math.MaxInt64)
-	if err != nil {
-		return nil, err
+	var stateMerkle string
+	if outputID != nil {
+		if height != nil {
+			return nil, fmt.Errorf("height and outputId cannot be used together")
+		}
+		output := r.ContractsState.GetOutput(*outputID)
+		if output == nil || output.ContractId != contractID {
+			return nil, fmt.Errorf("output %s not found for contract %s", *outputID, contractID)
+		}
+		stateMerkle = output.StateMerkle
+	} else {
+		output, err := r.ContractsState.GetLastOutput(contractID, ParseHeight(height))
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("no state found for contract %s", contractID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		stateMerkle = output.StateMerkle
 	}
-	cidz, err := cid.Parse(output.StateMerkle)
+	cidz, err := cid.Parse(stateMerkle)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +477,69 @@ func (r *queryResolver) GetStateByKeys(ctx context.Context, contractID string, k
 	return model.Map(result), keyErr
 }
 
+// GetStateProof is the resolver for the getStateProof field.
+func (r *queryResolver) GetStateProof(ctx context.Context, contractID string, key string, height *model.Uint64) (*stateproof.Proof, error) {
+	output, err := r.ContractsState.GetLastOutput(contractID, ParseHeight(height))
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("no state found for contract %s", contractID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if output.AnchoredId == "" {
+		return nil, fmt.Errorf("output %s was indexed without its block, resync the node to prove it", output.Id)
+	}
+	header, err := r.VscBlocks.GetBlockById(output.AnchoredId)
+	if err != nil {
+		return nil, err
+	}
+	if header.Signature == nil {
+		return nil, fmt.Errorf("block %s was indexed without its signature, resync the node to prove it", header.Id)
+	}
+
+	blocks := make([]stateproof.Block, 0)
+	for _, id := range []string{header.BlockContent, output.Id} {
+		cidz, err := cid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.Da.GetRaw(cidz)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, stateproof.Block{Cid: id, Data: data})
+	}
+	root, err := cid.Parse(output.StateMerkle)
+	if err != nil {
+		return nil, err
+	}
+	path, err := stateproof.CollectStatePath(ctx, r.Da.DagServ, root, key)
+	if err == os.ErrNotExist {
+		return nil, fmt.Errorf("key %s not found in contract state", key)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &stateproof.Proof{
+		ContractId: contractID,
+		Key:        key,
+		OutputId:   output.Id,
+		StateRoot:  output.StateMerkle,
+		Epoch:      header.Epoch,
+		Header: stateproof.Header{
+			Type:    header.Type,
+			Version: header.Version,
+			Prevb:   header.Prevb,
+			//StartBlock is stored one past the start of the range
+			Br:         [2]int{header.StartBlock - 1, header.EndBlock},
+			MerkleRoot: header.MerkleRoot,
+			Block:      header.BlockContent,
+			Signature:  *header.Signature,
+		},
+		Blocks: append(blocks, path...),
+	}, nil
+}
+
 // FindTransaction is the resolver for the findTransaction field.
 func (r *queryResolver) FindTransaction(ctx context.Context, filterOptions *TransactionFilter) ([]transactions.TransactionRecord, error) {
 	if filterOptions == nil {
@@ -268,6 +553,25 @@ func (r *queryResolver) FindTransaction(ctx context.Context, filterOptions *Tran
 	return r.Transactions.FindTransactions(filterOptions.ByIds, filterOptions.ByID, filterOptions.ByAccount, filterOptions.ByContract, filterOptions.ByStatus, filterOptions.ByType, filterOptions.ByLedgerToFrom, filterOptions.ByLedgerTypes, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), offset, limit)
 }
 
+// FindTransactionConnection is the resolver for the findTransactionConnection field.
+func (r *queryResolver) FindTransactionConnection(ctx context.Context, filterOptions *TransactionFilter, first *int, after *string, last *int, before *string) (*TransactionConnection, error) {
+	if filterOptions == nil {
+		filterOptions = &TransactionFilter{}
+	}
+	page, err := PaginateCursor(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.Transactions.FindTransactionsPage(filterOptions.ByIds, filterOptions.ByID, filterOptions.ByAccount, filterOptions.ByContract, filterOptions.ByStatus, filterOptions.ByType, filterOptions.ByLedgerToFrom, filterOptions.ByLedgerTypes, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), page)
+	if err != nil {
+		return nil, err
+	}
+	edges, info := connection(page, result, func(cursor string, node *transactions.TransactionRecord) TransactionEdge {
+		return TransactionEdge{Cursor: cursor, Node: node}
+	})
+	return &TransactionConnection{Edges: edges, PageInfo: info}, nil
+}
+
 // FindContractOutput is the resolver for the findContractOutput field.
 func (r *queryResolver) FindContractOutput(ctx context.Context, filterOptions *ContractOutputFilter) ([]contracts.ContractOutput, error) {
 	if filterOptions == nil {
@@ -280,6 +584,44 @@ func (r *queryResolver) FindContractOutput(ctx context.Context, filterOptions *C
 	return r.ContractsState.FindOutputs(filterOptions.ByID, filterOptions.ByInput, filterOptions.ByContract, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), offset, limit)
 }
 
+// FindContractOutputConnection is the resolver for the findContractOutputConnection field.
+func (r *queryResolver) FindContractOutputConnection(ctx context.Context, filterOptions *ContractOutputFilter, first *int, after *string, last *int, before *string) (*ContractOutputConnection, error) {
+	if filterOptions == nil {
+		filterOptions = &ContractOutputFilter{}
+	}
+	page, err := PaginateCursor(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.ContractsState.FindOutputsPage(filterOptions.ByID, filterOptions.ByInput, filterOptions.ByContract, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), page)
+	if err != nil {
+		return nil, err
+	}
+	edges, info := connection(page, result, func(cursor string, node *contracts.ContractOutput) ContractOutputEdge {
+		return ContractOutputEdge{Cursor: cursor, Node: node}
+	})
+	return &ContractOutputConnection{Edges: edges, PageInfo: info}, nil
+}
+
+// FindContractEvents is the resolver for the findContractEvents field.
+func (r *queryResolver) FindContractEvents(ctx context.Context, filterOptions *ContractEventFilter, first *int, after *string, last *int, before *string) (*ContractEventConnection, error) {
+	if filterOptions == nil {
+		filterOptions = &ContractEventFilter{}
+	}
+	page, err := PaginateCursor(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.ContractsState.FindEvents(filterOptions.ByContract, filterOptions.ByName, filterOptions.ByTopic, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), page)
+	if err != nil {
+		return nil, err
+	}
+	edges, info := connection(page, result, func(cursor string, node *contracts.ContractEventRecord) ContractEventEdge {
+		return ContractEventEdge{Cursor: cursor, Node: node}
+	})
+	return &ContractEventConnection{Edges: edges, PageInfo: info}, nil
+}
+
 // FindLedgerTXs is the resolver for the findLedgerTXs field.
 func (r *queryResolver) FindLedgerTXs(ctx context.Context, filterOptions *LedgerTxFilter) ([]ledgerDb.LedgerRecord, error) {
 	if filterOptions == nil {
@@ -295,6 +637,28 @@ func (r *queryResolver) FindLedgerTXs(ctx context.Context, filterOptions *Ledger
 	return r.Ledger.GetLedgersTsRange(filterOptions.ByToFrom, filterOptions.ByTxID, filterOptions.ByTypes, filterOptions.ByAsset, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), offset, limit)
 }
 
+// FindLedgerTXsConnection is the resolver for the findLedgerTXsConnection field.
+func (r *queryResolver) FindLedgerTXsConnection(ctx context.Context, filterOptions *LedgerTxFilter, first *int, after *string, last *int, before *string) (*LedgerRecordConnection, error) {
+	if filterOptions == nil {
+		filterOptions = &LedgerTxFilter{}
+	}
+	page, err := PaginateCursor(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	if filterOptions.ByTxID != nil && utf8.RuneCountInString(*filterOptions.ByTxID) < 40 {
+		return nil, fmt.Errorf("invalid tx id")
+	}
+	result, err := r.Ledger.GetLedgersTsPage(filterOptions.ByToFrom, filterOptions.ByTxID, filterOptions.ByTypes, filterOptions.ByAsset, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), page)
+	if err != nil {
+		return nil, err
+	}
+	edges, info := connection(page, result, func(cursor string, node *ledgerDb.LedgerRecord) LedgerRecordEdge {
+		return LedgerRecordEdge{Cursor: cursor, Node: node}
+	})
+	return &LedgerRecordConnection{Edges: edges, PageInfo: info}, nil
+}
+
 // FindLedgerActions is the resolver for the findLedgerActions field.
 func (r *queryResolver) FindLedgerActions(ctx context.Context, filterOptions *LedgerActionsFilter) ([]ledgerDb.ActionRecord, error) {
 	if filterOptions == nil {
@@ -310,6 +674,67 @@ func (r *queryResolver) FindLedgerActions(ctx context.Context, filterOptions *Le
 	return r.Actions.GetActionsRange(filterOptions.ByTxID, filterOptions.ByActionID, filterOptions.ByAccount, filterOptions.ByTypes, filterOptions.ByAsset, filterOptions.ByStatus, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), offset, limit)
 }
 
+// FindLedgerActionsConnection is the resolver for the findLedgerActionsConnection field.
+func (r *queryResolver) FindLedgerActionsConnection(ctx context.Context, filterOptions *LedgerActionsFilter, first *int, after *string, last *int, before *string) (*ActionRecordConnection, error) {
+	if filterOptions == nil {
+		filterOptions = &LedgerActionsFilter{}
+	}
+	page, err := PaginateCursor(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	if filterOptions.ByTxID != nil && utf8.RuneCountInString(*filterOptions.ByTxID) < 40 {
+		return nil, fmt.Errorf("invalid tx id")
+	}
+	result, err := r.Actions.GetActionsPage(filterOptions.ByTxID, filterOptions.ByActionID, filterOptions.ByAccount, filterOptions.ByTypes, filterOptions.ByAsset, filterOptions.ByStatus, (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), page)
+	if err != nil {
+		return nil, err
+	}
+	edges, info := connection(page, result, func(cursor string, node *ledgerDb.ActionRecord) ActionRecordEdge {
+		return ActionRecordEdge{Cursor: cursor, Node: node}
+	})
+	return &ActionRecordConnection{Edges: edges, PageInfo: info}, nil
+}
+
+// FindScheduledTransfers is the resolver for the findScheduledTransfers field.
+func (r *queryResolver) FindScheduledTransfers(ctx context.Context, filterOptions *ScheduledTransferFilter) ([]ledgerDb.ActionRecord, error) {
+	if filterOptions == nil {
+		filterOptions = &ScheduledTransferFilter{}
+	}
+	offset, limit, paginateErr := Paginate(filterOptions.Offset, filterOptions.Limit)
+	if paginateErr != nil {
+		return nil, paginateErr
+	}
+	return r.Actions.GetScheduledTransfers(filterOptions.ByAccount, filterOptions.ByStatus, offset, limit)
+}
+
+// GetBridgeWithdrawal is the resolver for the getBridgeWithdrawal field.
+func (r *queryResolver) GetBridgeWithdrawal(ctx context.Context, id string) (*bridge.Withdrawal, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id parameter cannot be empty")
+	}
+	record, err := r.Bridge.GetWithdrawal(id)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindBridgeWithdrawals is the resolver for the findBridgeWithdrawals field.
+func (r *queryResolver) FindBridgeWithdrawals(ctx context.Context, filterOptions *BridgeWithdrawalFilter) ([]bridge.Withdrawal, error) {
+	if filterOptions == nil {
+		filterOptions = &BridgeWithdrawalFilter{}
+	}
+	offset, limit, paginateErr := Paginate(filterOptions.Offset, filterOptions.Limit)
+	if paginateErr != nil {
+		return nil, paginateErr
+	}
+	return r.Bridge.FindWithdrawals(filterOptions.ByAccount, filterOptions.ByChain, filterOptions.ByStatus, offset, limit)
+}
+
 // GetAccountBalance is the resolver for the getAccountBalance field.
 func (r *queryResolver) GetAccountBalance(ctx context.Context, account string, height *model.Uint64) (*ledgerDb.BalanceRecord, error) {
 	if account == "" {
@@ -412,8 +837,32 @@ func (r *queryResolver) FindContract(ctx context.Context, filterOptions *FindCon
 	return r.Contracts.FindContracts(filterOptions.ByID, filterOptions.ByCode, filterOptions.Historical, offset, limit)
 }
 
+// FindContractUpgrades is the resolver for the findContractUpgrades field.
+func (r *queryResolver) FindContractUpgrades(ctx context.Context, filterOptions *ContractUpgradeFilter) ([]contracts.ContractUpgrade, error) {
+	if filterOptions == nil {
+		filterOptions = &ContractUpgradeFilter{}
+	}
+	offset, limit, paginateErr := Paginate(filterOptions.Offset, filterOptions.Limit)
+	if paginateErr != nil {
+		return nil, paginateErr
+	}
+	return r.ContractUpgrades.FindUpgrades(filterOptions.ByContract, filterOptions.ByStatus, offset, limit)
+}
+
+// FindContractSchedules is the resolver for the findContractSchedules field.
+func (r *queryResolver) FindContractSchedules(ctx context.Context, filterOptions *ContractScheduleFilter) ([]contracts.ContractSchedule, error) {
+	if filterOptions == nil {
+		filterOptions = &ContractScheduleFilter{}
+	}
+	offset, limit, paginateErr := Paginate(filterOptions.Offset, filterOptions.Limit)
+	if paginateErr != nil {
+		return nil, paginateErr
+	}
+	return r.ContractSchedules.FindSchedules(filterOptions.ByContract, filterOptions.ByStatus, offset, limit)
+}
+
 // SubmitTransactionV1 is the resolver for the submitTransactionV1 field.
-func (r *queryResolver) SubmitTransactionV1(ctx context.Context, tx string, sig string) (*TransactionSubmitResult, error) {
+func (r *queryResolver) SubmitTransactionV1(ctx context.Context, tx string, sig string, dryRun *bool) (*TransactionSubmitResult, error) {
 	Tx, err := base64.URLEncoding.DecodeString(tx)
 	if err != nil {
 		return nil, err
@@ -425,6 +874,9 @@ func (r *queryResolver) SubmitTransactionV1(ctx context.Context, tx string, sig
 	cid, err := r.TxPool.IngestTx(transactionpool.SerializedVSCTransaction{
 		Tx:  Tx,
 		Sig: Sig,
+	}, transactionpool.IngestOptions{
+		Broadcast: true,
+		DryRun:    dryRun != nil && *dryRun,
 	})
 	if err != nil {
 		return nil, err
@@ -448,6 +900,21 @@ func (r *queryResolver) GetAccountNonce(ctx context.Context, account string) (*n
 	return &record, err
 }
 
+// GetAccountAuthority is the resolver for the getAccountAuthority field.
+func (r *queryResolver) GetAccountAuthority(ctx context.Context, account string, height *model.Uint64) (*authorities.AuthorityRecord, error) {
+	if account == "" {
+		return nil, fmt.Errorf("account parameter cannot be empty")
+	}
+	record, err := r.Authorities.GetAuthority(account, ParseHeight(height))
+	if err == mongo.ErrNoDocuments || (err == nil && record.Authority() == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
 // LocalNodeInfo is the resolver for the localNodeInfo field.
 func (r *queryResolver) LocalNodeInfo(ctx context.Context) (*LocalNodeInfo, error) {
 	head, headErr := r.HiveBlocks.GetLastProcessedBlock()
@@ -478,6 +945,65 @@ func (r *queryResolver) LocalNodeInfo(ctx context.Context) (*LocalNodeInfo, erro
 	return info, nil
 }
 
+// MempoolStatus is the resolver for the mempoolStatus field.
+func (r *queryResolver) MempoolStatus(ctx context.Context) (*MempoolStatus, error) {
+	status, err := r.TxPool.MempoolStatus(r.StateEngine.SystemConfig().ConsensusParams())
+	if err != nil {
+		return nil, err
+	}
+	return &MempoolStatus{
+		Depth:         status.Depth,
+		PendingRcs:    model.Uint64(status.PendingRcs),
+		NextBlockTxs:  status.NextBlockTxs,
+		NextBlockRcs:  model.Uint64(status.NextBlockRcs),
+		BlockRcBudget: model.Uint64(status.BlockRcBudget),
+		NextBlockTip:  model.Uint64(status.NextBlockTip),
+	}, nil
+}
+
+// Mempool is the resolver for the mempool field.
+func (r *queryResolver) Mempool(ctx context.Context, account *string) (*Mempool, error) {
+	filter := ""
+	if account != nil {
+		filter = *account
+	}
+	mempool, err := r.TxPool.Mempool(r.StateEngine.SystemConfig().ConsensusParams(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Mempool{
+		Summary: &MempoolSummary{
+			Size:     mempool.Summary.Size,
+			Ready:    mempool.Summary.Ready,
+			Rcs:      model.Uint64(mempool.Summary.Rcs),
+			ByOpType: make([]MempoolOpCount, 0, len(mempool.Summary.ByOpType)),
+			Oldest:   mempool.Summary.Oldest,
+		},
+		Accounts: make([]MempoolAccount, 0, len(mempool.Accounts)),
+	}
+	for _, count := range mempool.Summary.ByOpType {
+		res.Summary.ByOpType = append(res.Summary.ByOpType, MempoolOpCount{OpType: count.OpType, Count: count.Count})
+	}
+	for _, acc := range mempool.Accounts {
+		txs := make([]MempoolTransaction, 0, len(acc.Transactions))
+		for _, tx := range acc.Transactions {
+			entry := MempoolTransaction{Transaction: &tx.TransactionRecord}
+			if tx.BlockedReason != "" {
+				reason := MempoolBlockedReason(tx.BlockedReason)
+				entry.BlockedReason = &reason
+			}
+			txs = append(txs, entry)
+		}
+		res.Accounts = append(res.Accounts, MempoolAccount{
+			RequiredAuths: acc.RequiredAuths,
+			Nonce:         model.Int64(acc.Nonce),
+			Transactions:  txs,
+		})
+	}
+	return res, nil
+}
+
 // GetWitness is the resolver for the getWitness field.
 func (r *queryResolver) GetWitness(ctx context.Context, account string, height *model.Uint64) (*witnesses.Witness, error) {
 	blockHeight := ParseHeight(height)
@@ -582,6 +1108,25 @@ func (r *queryResolver) FindTssCommitments(ctx context.Context, filterOptions *T
 	return r.TssCommitments.FindCommitments(filterOptions.ByKeyID, filterOptions.ByTypes, (*uint64)(filterOptions.ByEpoch), (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), off, lim)
 }
 
+// FindTssCommitmentsConnection is the resolver for the findTssCommitmentsConnection field.
+func (r *queryResolver) FindTssCommitmentsConnection(ctx context.Context, filterOptions *TssCommitmentFilter, first *int, after *string, last *int, before *string) (*TssCommitmentConnection, error) {
+	if filterOptions == nil {
+		filterOptions = &TssCommitmentFilter{}
+	}
+	page, err := PaginateCursor(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.TssCommitments.FindCommitmentsPage(filterOptions.ByKeyID, filterOptions.ByTypes, (*uint64)(filterOptions.ByEpoch), (*uint64)(filterOptions.FromBlock), (*uint64)(filterOptions.ToBlock), page)
+	if err != nil {
+		return nil, err
+	}
+	edges, info := connection(page, result, func(cursor string, node *tss_db.TssCommitment) TssCommitmentEdge {
+		return TssCommitmentEdge{Cursor: cursor, Node: node}
+	})
+	return &TssCommitmentConnection{Edges: edges, PageInfo: info}, nil
+}
+
 // SimulateContractCalls is the resolver for the simulateContractCalls field.
 func (r *queryResolver) SimulateContractCalls(ctx context.Context, input SimulateContractCallsInput) ([]SimulateContractCallResult, error) {
 	if len(input.Calls) > 10 {
@@ -672,6 +1217,10 @@ func (r *queryResolver) SimulateContractCalls(ctx context.Context, input Simulat
 		} else if len(input.RequiredPostingAuths) > 0 {
 			caller = input.RequiredPostingAuths[0]
 		}
+		payer := caller
+		if input.Payer != nil {
+			payer = *input.Payer
+		}
 
 		// Convert intents
 		intents := make([]contracts.Intent, 0, len(call.Intents))
@@ -700,9 +1249,10 @@ func (r *queryResolver) SimulateContractCalls(ctx context.Context, input Simulat
 				RequiredPostingAuths: input.RequiredPostingAuths,
 				Caller:               caller,
 				Sender:               caller,
+				Payer:                payer,
 				Intents:              intents,
 			},
-			int64(rcLimit), rc_system.FreeRcRemaining(r.StateEngine.RcSystem.NewSession(ledgerSession), caller, blockHeight), rcLimit*params.CYCLE_GAS_PER_RC, ledgerSession, callSession, 0,
+			int64(rcLimit), rc_system.FreeRcRemaining(r.StateEngine.RcSystem.NewSession(ledgerSession), payer, blockHeight), rcLimit*params.CYCLE_GAS_PER_RC, ledgerSession, callSession, 0,
 		)
 
 		// Unmarshal payload string
@@ -786,6 +1336,110 @@ func (r *rcRecordResolver) MaxRcs(ctx context.Context, obj *rcDb.RcRecord) (mode
 	return model.Int64(obj.MaxRcs), nil
 }
 
+// From is the resolver for the from field.
+func (r *scheduledTransferResolver) From(ctx context.Context, obj *ledgerDb.ActionRecord) (string, error) {
+	return ledgerSystem.ScheduledTransferSender(*obj), nil
+}
+
+// Amount is the resolver for the amount field.
+func (r *scheduledTransferResolver) Amount(ctx context.Context, obj *ledgerDb.ActionRecord) (model.Int64, error) {
+	return model.Int64(obj.Amount), nil
+}
+
+// ReleaseHeight is the resolver for the release_height field.
+func (r *scheduledTransferResolver) ReleaseHeight(ctx context.Context, obj *ledgerDb.ActionRecord) (model.Uint64, error) {
+	return model.Uint64(ledgerSystem.ScheduledTransferReleaseHeight(*obj)), nil
+}
+
+// BlockHeight is the resolver for the block_height field.
+func (r *scheduledTransferResolver) BlockHeight(ctx context.Context, obj *ledgerDb.ActionRecord) (model.Uint64, error) {
+	return model.Uint64(obj.BlockHeight), nil
+}
+
+// Br is the resolver for the br field.
+func (r *signedBlockHeaderResolver) Br(ctx context.Context, obj *stateproof.Header) ([]int, error) {
+	return obj.Br[:], nil
+}
+
+// Epoch is the resolver for the epoch field.
+func (r *stateProofResolver) Epoch(ctx context.Context, obj *stateproof.Proof) (model.Uint64, error) {
+	return model.Uint64(obj.Epoch), nil
+}
+
+// TransactionStatusChanged is the resolver for the transactionStatusChanged field.
+func (r *subscriptionResolver) TransactionStatusChanged(ctx context.Context, id string) (<-chan *TransactionStatusUpdate, error) {
+	return subscribe(ctx, r.StateEngine, func(ev stateEngine.StateEvent) (*TransactionStatusUpdate, bool) {
+		if ev.Type != stateEngine.EventTxStatus || ev.TxStatus.Id != id {
+			return nil, false
+		}
+		return &TransactionStatusUpdate{
+			ID:          ev.TxStatus.Id,
+			Status:      ev.TxStatus.Status,
+			BlockHeight: model.Uint64(ev.BlockHeight),
+		}, true
+	})
+}
+
+// LedgerOps is the resolver for the ledgerOps field.
+func (r *subscriptionResolver) LedgerOps(ctx context.Context, account string, asset *ledgerDb.Asset) (<-chan *ledgerDb.LedgerRecord, error) {
+	if account == "" {
+		return nil, fmt.Errorf("account parameter cannot be empty")
+	}
+	return subscribe(ctx, r.StateEngine, func(ev stateEngine.StateEvent) (*ledgerDb.LedgerRecord, bool) {
+		if ev.Type != stateEngine.EventLedgerOp {
+			return nil, false
+		}
+		record := ev.LedgerRecord
+		if record.Owner != account {
+			return nil, false
+		}
+		if asset != nil && record.Asset != string(*asset) {
+			return nil, false
+		}
+		return record, true
+	})
+}
+
+// ContractOutputs is the resolver for the contractOutputs field.
+func (r *subscriptionResolver) ContractOutputs(ctx context.Context, contractID string) (<-chan *contracts.ContractOutput, error) {
+	return subscribe(ctx, r.StateEngine, func(ev stateEngine.StateEvent) (*contracts.ContractOutput, bool) {
+		if ev.Type != stateEngine.EventContractOutput || ev.ContractOutput.ContractId != contractID {
+			return nil, false
+		}
+		return ev.ContractOutput, true
+	})
+}
+
+// ContractEvents is the resolver for the contractEvents field.
+func (r *subscriptionResolver) ContractEvents(ctx context.Context, contractID string, name *string, topic *string) (<-chan *contracts.ContractEventRecord, error) {
+	return subscribeAll(ctx, r.StateEngine, func(ev stateEngine.StateEvent) []*contracts.ContractEventRecord {
+		if ev.Type != stateEngine.EventContractOutput || ev.ContractOutput.ContractId != contractID {
+			return nil
+		}
+		records := make([]*contracts.ContractEventRecord, 0)
+		for _, record := range ev.ContractOutput.EventRecords() {
+			if name != nil && record.Name != *name {
+				continue
+			}
+			if topic != nil && !slices.Contains(record.Topics, *topic) {
+				continue
+			}
+			records = append(records, &record)
+		}
+		return records
+	})
+}
+
+// NewBlocks is the resolver for the newBlocks field.
+func (r *subscriptionResolver) NewBlocks(ctx context.Context) (<-chan *vscBlocks.VscHeaderRecord, error) {
+	return subscribe(ctx, r.StateEngine, func(ev stateEngine.StateEvent) (*vscBlocks.VscHeaderRecord, bool) {
+		if ev.Type != stateEngine.EventNewBlock {
+			return nil, false
+		}
+		return ev.Block, true
+	})
+}
+
 // Index is the resolver for the index field.
 func (r *transactionOperationResolver) Index(ctx context.Context, obj *transactions.TransactionOperation) (model.Uint64, error) {
 	return model.Uint64(obj.Idx), nil
@@ -826,6 +1480,24 @@ func (r *transactionRecordResolver) RcLimit(ctx context.Context, obj *transactio
 	return model.Uint64(obj.RcLimit), nil
 }
 
+// Tip is the resolver for the tip field.
+func (r *transactionRecordResolver) Tip(ctx context.Context, obj *transactions.TransactionRecord) (*model.Uint64, error) {
+	if obj.Tip == 0 {
+		return nil, nil
+	}
+	tip := model.Uint64(obj.Tip)
+	return &tip, nil
+}
+
+// ExpireBlock is the resolver for the expire_block field.
+func (r *transactionRecordResolver) ExpireBlock(ctx context.Context, obj *transactions.TransactionRecord) (*model.Uint64, error) {
+	if obj.ExpireBlock == nil {
+		return nil, nil
+	}
+	expireBlock := model.Uint64(*obj.ExpireBlock)
+	return &expireBlock, nil
+}
+
 // LedgerActions is the resolver for the ledger_actions field.
 func (r *transactionRecordResolver) LedgerActions(ctx context.Context, obj *transactions.TransactionRecord) ([]*LedgerAction, error) {
 	lrs, err := r.Actions.GetActionsByTxId(obj.Id)
@@ -899,18 +1571,41 @@ func (r *witnessSlotResolver) Bn(ctx context.Context, obj *stateEngine.WitnessSl
 	return model.Uint64(obj.SlotHeight), nil
 }
 
+// AccountAuthority returns AccountAuthorityResolver implementation.
+func (r *Resolver) AccountAuthority() AccountAuthorityResolver { return &accountAuthorityResolver{r} }
+
 // ActionRecord returns ActionRecordResolver implementation.
 func (r *Resolver) ActionRecord() ActionRecordResolver { return &actionRecordResolver{r} }
 
+// AuthorityKey returns AuthorityKeyResolver implementation.
+func (r *Resolver) AuthorityKey() AuthorityKeyResolver { return &authorityKeyResolver{r} }
+
 // BalanceRecord returns BalanceRecordResolver implementation.
 func (r *Resolver) BalanceRecord() BalanceRecordResolver { return &balanceRecordResolver{r} }
 
+// BlockHeader returns BlockHeaderResolver implementation.
+func (r *Resolver) BlockHeader() BlockHeaderResolver { return &blockHeaderResolver{r} }
+
+// BridgeWithdrawal returns BridgeWithdrawalResolver implementation.
+func (r *Resolver) BridgeWithdrawal() BridgeWithdrawalResolver { return &bridgeWithdrawalResolver{r} }
+
 // Contract returns ContractResolver implementation.
 func (r *Resolver) Contract() ContractResolver { return &contractResolver{r} }
 
+// ContractEventRecord returns ContractEventRecordResolver implementation.
+func (r *Resolver) ContractEventRecord() ContractEventRecordResolver {
+	return &contractEventRecordResolver{r}
+}
+
 // ContractOutput returns ContractOutputResolver implementation.
 func (r *Resolver) ContractOutput() ContractOutputResolver { return &contractOutputResolver{r} }
 
+// ContractSchedule returns ContractScheduleResolver implementation.
+func (r *Resolver) ContractSchedule() ContractScheduleResolver { return &contractScheduleResolver{r} }
+
+// ContractUpgrade returns ContractUpgradeResolver implementation.
+func (r *Resolver) ContractUpgrade() ContractUpgradeResolver { return &contractUpgradeResolver{r} }
+
 // ElectionResult returns ElectionResultResolver implementation.
 func (r *Resolver) ElectionResult() ElectionResultResolver { return &electionResultResolver{r} }
 
@@ -922,6 +1617,9 @@ func (r *Resolver) LedgerClaimRecord() LedgerClaimRecordResolver {
 // LedgerRecord returns LedgerRecordResolver implementation.
 func (r *Resolver) LedgerRecord() LedgerRecordResolver { return &ledgerRecordResolver{r} }
 
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
 // NonceRecord returns NonceRecordResolver implementation.
 func (r *Resolver) NonceRecord() NonceRecordResolver { return &nonceRecordResolver{r} }
 
@@ -931,12 +1629,31 @@ func (r *Resolver) OpLogEvent() OpLogEventResolver { return &opLogEventResolver{
 // PostingJsonKeys returns PostingJsonKeysResolver implementation.
 func (r *Resolver) PostingJsonKeys() PostingJsonKeysResolver { return &postingJsonKeysResolver{r} }
 
+// ProofBlock returns ProofBlockResolver implementation.
+func (r *Resolver) ProofBlock() ProofBlockResolver { return &proofBlockResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 // RcRecord returns RcRecordResolver implementation.
 func (r *Resolver) RcRecord() RcRecordResolver { return &rcRecordResolver{r} }
 
+// ScheduledTransfer returns ScheduledTransferResolver implementation.
+func (r *Resolver) ScheduledTransfer() ScheduledTransferResolver {
+	return &scheduledTransferResolver{r}
+}
+
+// SignedBlockHeader returns SignedBlockHeaderResolver implementation.
+func (r *Resolver) SignedBlockHeader() SignedBlockHeaderResolver {
+	return &signedBlockHeaderResolver{r}
+}
+
+// StateProof returns StateProofResolver implementation.
+func (r *Resolver) StateProof() StateProofResolver { return &stateProofResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 // TransactionOperation returns TransactionOperationResolver implementation.
 func (r *Resolver) TransactionOperation() TransactionOperationResolver {
 	return &transactionOperationResolver{r}
@@ -959,18 +1676,31 @@ func (r *Resolver) Witness() WitnessResolver { return &witnessResolver{r} }
 // WitnessSlot returns WitnessSlotResolver implementation.
 func (r *Resolver) WitnessSlot() WitnessSlotResolver { return &witnessSlotResolver{r} }
 
+type accountAuthorityResolver struct{ *Resolver }
 type actionRecordResolver struct{ *Resolver }
+type authorityKeyResolver struct{ *Resolver }
 type balanceRecordResolver struct{ *Resolver }
+type blockHeaderResolver struct{ *Resolver }
+type bridgeWithdrawalResolver struct{ *Resolver }
 type contractResolver struct{ *Resolver }
+type contractEventRecordResolver struct{ *Resolver }
 type contractOutputResolver struct{ *Resolver }
+type contractScheduleResolver struct{ *Resolver }
+type contractUpgradeResolver struct{ *Resolver }
 type electionResultResolver struct{ *Resolver }
 type ledgerClaimRecordResolver struct{ *Resolver }
 type ledgerRecordResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
 type nonceRecordResolver struct{ *Resolver }
 type opLogEventResolver struct{ *Resolver }
 type postingJsonKeysResolver struct{ *Resolver }
+type proofBlockResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type rcRecordResolver struct{ *Resolver }
+type scheduledTransferResolver struct{ *Resolver }
+type signedBlockHeaderResolver struct{ *Resolver }
+type stateProofResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
 type transactionOperationResolver struct{ *Resolver }
 type transactionRecordResolver struct{ *Resolver }
 type tssCommitmentResolver struct{ *Resolver }
@@ -1,9 +1,14 @@
 package gqlgen
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"math"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	"vsc-node/modules/gql/model"
+	stateEngine "vsc-node/modules/state-processing"
 )
 
 // Helper function for handling offset-limit pagination. Returns offset, limit and error.
@@ -27,6 +32,91 @@ func Paginate(offset *int, limit *int) (int, int, error) {
 	return offset_result, limit_result, nil
 }
 
+// Helper function for handling Relay-style cursor pagination.
+// Either first/after (forward) or last/before (backward) may be used, but not both.
+func PaginateCursor(first *int, after *string, last *int, before *string) (hive_blocks.PageArgs, error) {
+	if (first != nil || after != nil) && (last != nil || before != nil) {
+		return hive_blocks.PageArgs{}, fmt.Errorf("first/after cannot be combined with last/before")
+	}
+	count, cursor := first, after
+	reverse := last != nil || before != nil
+	if reverse {
+		count, cursor = last, before
+	}
+	_, limit, err := Paginate(nil, count)
+	if err != nil {
+		return hive_blocks.PageArgs{}, err
+	}
+	args := hive_blocks.PageArgs{Reverse: reverse, Limit: limit}
+	if cursor != nil {
+		c, err := DecodeCursor(*cursor)
+		if err != nil {
+			return hive_blocks.PageArgs{}, err
+		}
+		args.Cursor = &c
+	}
+	return args, nil
+}
+
+// Cursors are the block height and the index within the record, followed by
+// each ID of the record prefixed with its length, base64url encoded.
+// Clients should treat them as opaque.
+func EncodeCursor(c hive_blocks.PageCursor) string {
+	buf := make([]byte, 0, 16)
+	buf = binary.BigEndian.AppendUint64(buf, c.Height)
+	buf = binary.BigEndian.AppendUint64(buf, c.Index)
+	for _, id := range c.Id {
+		buf = binary.AppendUvarint(buf, uint64(len(id)))
+		buf = append(buf, id...)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func DecodeCursor(cursor string) (hive_blocks.PageCursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(buf) < 16 {
+		return hive_blocks.PageCursor{}, fmt.Errorf("invalid cursor")
+	}
+	c := hive_blocks.PageCursor{
+		Height: binary.BigEndian.Uint64(buf[:8]),
+		Index:  binary.BigEndian.Uint64(buf[8:16]),
+		Id:     make([]string, 0),
+	}
+	buf = buf[16:]
+	for len(buf) > 0 {
+		n, read := binary.Uvarint(buf)
+		if read <= 0 || n > uint64(len(buf)-read) {
+			return hive_blocks.PageCursor{}, fmt.Errorf("invalid cursor")
+		}
+		c.Id = append(c.Id, string(buf[read:read+int(n)]))
+		buf = buf[read+int(n):]
+	}
+	return c, nil
+}
+
+// Builds the edges and page info of a connection from a page of records
+func connection[T any, E any](args hive_blocks.PageArgs, page hive_blocks.Page[T], edge func(cursor string, node *T) E) ([]E, *PageInfo) {
+	edges := make([]E, len(page.Items))
+	for i := range page.Items {
+		edges[i] = edge(EncodeCursor(page.Cursors[i]), &page.Items[i])
+	}
+	info := &PageInfo{}
+	if args.Reverse {
+		info.HasPreviousPage = page.HasMore
+		info.HasNextPage = args.Cursor != nil
+	} else {
+		info.HasNextPage = page.HasMore
+		info.HasPreviousPage = args.Cursor != nil
+	}
+	if len(page.Cursors) > 0 {
+		start := EncodeCursor(page.Cursors[0])
+		end := EncodeCursor(page.Cursors[len(page.Cursors)-1])
+		info.StartCursor = &start
+		info.EndCursor = &end
+	}
+	return edges, info
+}
+
 // Parse optional height, falling back to math.MaxInt64 if not specified
 func ParseHeight(height *model.Uint64) uint64 {
 	var blockHeight uint64 = math.MaxInt64
@@ -35,3 +125,48 @@ func ParseHeight(height *model.Uint64) uint64 {
 	}
 	return blockHeight
 }
+
+// Forwards state engine events to a subscription channel until the client disconnects.
+// The filter returns the payload to send and whether the event should be sent at all.
+func subscribe[T any](ctx context.Context, se *stateEngine.StateEngine, filter func(ev stateEngine.StateEvent) (*T, bool)) (<-chan *T, error) {
+	return subscribeAll(ctx, se, func(ev stateEngine.StateEvent) []*T {
+		if payload, match := filter(ev); match {
+			return []*T{payload}
+		}
+		return nil
+	})
+}
+
+// Like subscribe, for events carrying any number of payloads (e.g. the events of a contract output)
+func subscribeAll[T any](ctx context.Context, se *stateEngine.StateEngine, filter func(ev stateEngine.StateEvent) []*T) (<-chan *T, error) {
+	if se == nil || se.Events == nil {
+		return nil, fmt.Errorf("subscriptions are not available on this node")
+	}
+	events, unsubscribe := se.Events.Subscribe(stateEngine.DefaultEventBuffer)
+	out := make(chan *T, 1)
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-events:
+				if !ok {
+					//Dropped by the feed for falling behind
+					return
+				}
+				for _, payload := range filter(ev) {
+					select {
+					case out <- payload:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
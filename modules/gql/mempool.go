@@ -0,0 +1,25 @@
+package gql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	systemconfig "vsc-node/modules/common/system-config"
+	transactionpool "vsc-node/modules/transaction-pool"
+)
+
+const MempoolPath = "/api/v1/mempool"
+
+// Serves the mempool query as plain JSON, optionally limited with ?account=
+func MempoolHandler(txPool *transactionpool.TransactionPool, sconf systemconfig.SystemConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mempool, err := txPool.Mempool(sconf.ConsensusParams(), r.URL.Query().Get("account"))
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(mempool)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"vsc-node/lib/utils"
 	"vsc-node/lib/vsclog"
 	"vsc-node/modules/aggregate"
 	hiveblocks "vsc-node/modules/db/vsc/hive_blocks"
@@ -115,6 +116,10 @@ func NewStreamReader(
 
 // inits the StreamReader, fetching the last processed block
 func (s *StreamReader) Init() error {
+	return s.loadLastProcessed()
+}
+
+func (s *StreamReader) loadLastProcessed() error {
 	// fetch the last processed block number
 	lp, err := s.hiveBlocks.GetLastProcessedBlock()
 	if err != nil {
@@ -139,6 +144,11 @@ func (s *StreamReader) Init() error {
 
 // begins the polling loop for the StreamReader
 func (s *StreamReader) Start() *promise.Promise[any] {
+	// plugins started before this one may have moved the last processed
+	// block since Init, e.g. by importing a snapshot
+	if err := s.loadLastProcessed(); err != nil {
+		return utils.PromiseReject[any](err)
+	}
 	return promise.New(func(resolve func(any), reject func(error)) {
 		defer inteceptError()
 		s.pollDb(reject)
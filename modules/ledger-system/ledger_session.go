@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"vsc-node/modules/common"
 )
 
 type ledgerSession struct {
@@ -28,6 +29,12 @@ func (session *ledgerSession) Done() []string {
 	}
 
 	session.state.Oplog = append(session.state.Oplog, session.oplog...)
+	for _, op := range session.oplog {
+		if op.Type == "cancel_scheduled_transfer" {
+			escrowId, _ := op.Params["escrow_id"].(string)
+			session.state.markCancelled(escrowId)
+		}
+	}
 	for _, op := range session.ledgerOps {
 		// lss.le.Ls.log.Debug("LedgerSession.Done adding LedgerResult", op)
 		session.state.VirtualLedger[op.Owner] = append(session.state.VirtualLedger[op.Owner], op)
@@ -108,7 +115,7 @@ func (ledgerSession *ledgerSession) Withdraw(withdraw WithdrawParams) LedgerResu
 	}
 
 	hiveAsset := slices.Contains([]string{"hive", "hbd"}, withdraw.Asset)
-	ethAsset := slices.Contains([]string{"eth", "usdc"}, withdraw.Asset)
+	ethAsset := BridgeChain(withdraw.Asset) == "ETH"
 	if !hiveAsset && !ethAsset {
 		return LedgerResult{
 			Ok:  false,
@@ -274,6 +281,136 @@ func (ledgerSession *ledgerSession) ConsensusUnstake(params ConsensusParams) Led
 	}
 }
 
+// Locks the amount in escrow until the release height is reached
+// Funds are released to the recipient by the state engine at the slot boundary
+func (ledgerSession *ledgerSession) ScheduleTransfer(params ScheduledTransferParams) LedgerResult {
+	if params.Amount <= 0 {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid amount",
+		}
+	}
+	if params.To == params.From {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "cannot send to self",
+		}
+	}
+	if strings.HasPrefix(params.To, "system:") {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid destination",
+		}
+	}
+	if !isTransferableAsset(params.Asset) {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid asset",
+		}
+	}
+	if params.ReleaseHeight <= params.BlockHeight {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid release height",
+		}
+	}
+
+	fromBal := ledgerSession.GetBalance(params.From, params.BlockHeight, params.Asset)
+
+	if fromBal < params.Amount {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "insufficient balance",
+		}
+	}
+
+	ledgerSession.AppendOplog(OpLogEvent{
+		Id:          params.Id,
+		From:        params.From,
+		To:          params.To,
+		Amount:      params.Amount,
+		Asset:       params.Asset,
+		Memo:        params.Memo,
+		Type:        "scheduled_transfer",
+		BlockHeight: params.BlockHeight,
+
+		BIdx:  params.BIdx,
+		OpIdx: params.OpIdx,
+
+		Params: map[string]interface{}{
+			"release_height": params.ReleaseHeight,
+		},
+	})
+
+	return LedgerResult{
+		Ok:  true,
+		Msg: "success",
+	}
+}
+
+// Returns escrowed funds to the sender
+// Must happen at least one slot before the release height, as releases are processed before the oplog is ingested
+func (ledgerSession *ledgerSession) CancelScheduledTransfer(params CancelScheduledTransferParams) LedgerResult {
+	record, err := ledgerSession.state.ActionDb.Get(params.EscrowId)
+	if err != nil || record == nil || record.Type != "scheduled_transfer" {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "escrow not found",
+		}
+	}
+	if record.Status != "pending" {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "escrow not pending",
+		}
+	}
+	if ScheduledTransferSender(*record) != params.From {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "not escrow owner",
+		}
+	}
+	if params.BlockHeight+common.CONSENSUS_SPECS.SlotLength >= ScheduledTransferReleaseHeight(*record) {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "escrow release too close",
+		}
+	}
+	if ledgerSession.state.CancelledEscrows[params.EscrowId] {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "escrow already cancelled",
+		}
+	}
+	for _, op := range slices.Concat(ledgerSession.state.Oplog, ledgerSession.oplog) {
+		if op.Type == "cancel_scheduled_transfer" && op.Params["escrow_id"] == params.EscrowId {
+			return LedgerResult{
+				Ok:  false,
+				Msg: "escrow already cancelled",
+			}
+		}
+	}
+
+	ledgerSession.AppendOplog(OpLogEvent{
+		Id:          params.Id,
+		From:        params.From,
+		To:          params.From,
+		Amount:      record.Amount,
+		Asset:       record.Asset,
+		Type:        "cancel_scheduled_transfer",
+		BlockHeight: params.BlockHeight,
+
+		Params: map[string]interface{}{
+			"escrow_id": params.EscrowId,
+		},
+	})
+
+	return LedgerResult{
+		Ok:  true,
+		Msg: "success",
+	}
+}
+
 func (ledgerSession *ledgerSession) ExecuteTransfer(opLogEvent OpLogEvent, options ...TransferOptions) LedgerResult {
 	// le := ledgerSession.le
 	//Check if the from account has enough balance
@@ -301,7 +438,7 @@ func (ledgerSession *ledgerSession) ExecuteTransfer(opLogEvent OpLogEvent, optio
 			Msg: "invalid destination",
 		}
 	}
-	if !slices.Contains(transferableAssetTypes, opLogEvent.Asset) {
+	if !isTransferableAsset(opLogEvent.Asset) {
 		return LedgerResult{
 			Ok:  false,
 			Msg: "invalid asset",
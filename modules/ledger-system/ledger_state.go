@@ -21,6 +21,10 @@ type LedgerState struct {
 	//Block height of the operation to be processed at
 	BlockHeight uint64
 
+	//Scheduled transfers cancelled by executed ops, kept across flushes until the
+	//cancellation is ingested so a later slot cannot cancel (and refund) them again
+	CancelledEscrows map[string]bool
+
 	//Potential database state access
 
 	LedgerDb  ledger_db.Ledger
@@ -63,6 +67,21 @@ func (state *LedgerState) Flush() {
 
 	//qq: should this be cleared when flushing?
 	state.GatewayBalances = make(map[string]uint64)
+
+	//Cancellations ingested by an earlier block are covered by the escrow status
+	for escrowId := range state.CancelledEscrows {
+		record, err := state.ActionDb.Get(escrowId)
+		if err == nil && record != nil && record.Status != "pending" {
+			delete(state.CancelledEscrows, escrowId)
+		}
+	}
+}
+
+func (state *LedgerState) markCancelled(escrowId string) {
+	if state.CancelledEscrows == nil {
+		state.CancelledEscrows = make(map[string]bool)
+	}
+	state.CancelledEscrows[escrowId] = true
 }
 
 func (state *LedgerState) Compile(bh uint64) *CompiledResult {
@@ -104,7 +123,7 @@ func (state *LedgerState) SnapshotForAccount(account string, blockHeight uint64,
 // BalanceDb snapshot field for the asset, then add every LedgerDb record past
 // the snapshot height.
 func (ls *LedgerState) GetBalance(account string, blockHeight uint64, asset string) int64 {
-	if !slices.Contains(assetTypes, asset) {
+	if !slices.Contains(assetTypes, asset) && !IsTokenAsset(asset) {
 		return 0
 	}
 
@@ -134,6 +153,6 @@ func (ls *LedgerState) GetBalance(account string, blockHeight uint64, asset stri
 	case "hive_consensus":
 		return balRecord.HIVE_CONSENSUS + balAdjust
 	default:
-		return 0
+		return balRecord.Tokens[asset] + balAdjust
 	}
 }
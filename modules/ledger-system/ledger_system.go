@@ -199,7 +199,21 @@ func (ls *ledgerSystem) IndexActions(actionUpdate map[string]interface{}, extraI
 }
 
 func (ls *ledgerSystem) IngestOplog(oplog []OpLogEvent, options OplogInjestOptions) {
-	executeResults := ExecuteOplog(oplog, options.StartHeight, options.EndHeight)
+	//Cancellations only refund escrows that have not been released in the meantime
+	filtered := make([]OpLogEvent, 0, len(oplog))
+	for _, v := range oplog {
+		if v.Type == "cancel_scheduled_transfer" {
+			escrowId, _ := v.Params["escrow_id"].(string)
+			record, err := ls.ActionsDb.Get(escrowId)
+			if err != nil || record == nil || record.Status != "pending" {
+				continue
+			}
+			ls.ActionsDb.SetStatus(escrowId, "cancelled")
+		}
+		filtered = append(filtered, v)
+	}
+
+	executeResults := ExecuteOplog(filtered, options.StartHeight, options.EndHeight)
 
 	ledgerRecords := executeResults.ledgerRecords
 	actionRecords := executeResults.actionRecords
@@ -0,0 +1,214 @@
+package ledgerSystem_test
+
+import (
+	"testing"
+
+	"vsc-node/lib/test_utils"
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
+	ledgerSystem "vsc-node/modules/ledger-system"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ingestSession commits the session to the ledger state and ingests the
+// resulting oplog, as happens once the VSC block for the slot is indexed.
+func ingestSession(ls ledgerSystem.LedgerSystem, state *ledgerSystem.LedgerState, session ledgerSystem.LedgerSession, endHeight uint64) {
+	session.Done()
+	ls.IngestOplog(state.Oplog, ledgerSystem.OplogInjestOptions{
+		StartHeight: endHeight - 10,
+		EndHeight:   endHeight,
+	})
+	state.Flush()
+}
+
+func ledgerSum(state *ledgerSystem.LedgerState, account, asset string) int64 {
+	sum := int64(0)
+	for _, record := range state.LedgerDb.(*test_utils.MockLedgerDb).LedgerRecords[account] {
+		if record.Asset == asset {
+			sum += record.Amount
+		}
+	}
+	return sum
+}
+
+func TestScheduleTransferLocksFunds(t *testing.T) {
+	state := newTestState()
+	seedBalance(state, "hive:alice", 0, 0, 1000, 0)
+	ls := ledgerSystem.New(state.BalanceDb, state.LedgerDb, &test_utils.MockInterestClaimsDb{}, state.ActionDb)
+
+	session := ledgerSystem.NewSession(state)
+	params := ledgerSystem.ScheduledTransferParams{
+		Id:            "sched-1",
+		From:          "hive:alice",
+		To:            "hive:bob",
+		Amount:        400,
+		Asset:         "hbd",
+		BlockHeight:   101,
+		ReleaseHeight: 101,
+	}
+	assert.Equal(t, "invalid release height", session.ScheduleTransfer(params).Msg)
+
+	params.ReleaseHeight = 200
+	params.Amount = 1001
+	assert.Equal(t, "insufficient balance", session.ScheduleTransfer(params).Msg)
+
+	params.Amount = 400
+	require.True(t, session.ScheduleTransfer(params).Ok)
+	assert.Equal(t, int64(600), session.GetBalance("hive:alice", 101, "hbd"))
+	assert.Equal(t, int64(0), session.GetBalance("hive:bob", 101, "hbd"))
+
+	ingestSession(ls, state, session, 110)
+
+	escrow, err := state.ActionDb.Get("sched-1")
+	require.NoError(t, err)
+	require.NotNil(t, escrow)
+	assert.Equal(t, "pending", escrow.Status)
+	assert.Equal(t, "hive:alice", ledgerSystem.ScheduledTransferSender(*escrow))
+	assert.Equal(t, uint64(200), ledgerSystem.ScheduledTransferReleaseHeight(*escrow))
+	assert.Equal(t, int64(-400), ledgerSum(state, "hive:alice", "hbd"))
+
+	due, _ := state.ActionDb.GetDueScheduledTransfers(199)
+	assert.Empty(t, due)
+	due, _ = state.ActionDb.GetDueScheduledTransfers(200)
+	assert.Len(t, due, 1)
+}
+
+func TestCancelScheduledTransferRefundsSender(t *testing.T) {
+	state := newTestState()
+	seedBalance(state, "hive:alice", 0, 0, 1000, 0)
+	ls := ledgerSystem.New(state.BalanceDb, state.LedgerDb, &test_utils.MockInterestClaimsDb{}, state.ActionDb)
+
+	session := ledgerSystem.NewSession(state)
+	require.True(t, session.ScheduleTransfer(ledgerSystem.ScheduledTransferParams{
+		Id:            "sched-1",
+		From:          "hive:alice",
+		To:            "hive:bob",
+		Amount:        400,
+		Asset:         "hbd",
+		BlockHeight:   101,
+		ReleaseHeight: 200,
+	}).Ok)
+	ingestSession(ls, state, session, 110)
+
+	session = ledgerSystem.NewSession(state)
+	cancel := ledgerSystem.CancelScheduledTransferParams{
+		Id:          "cancel-1",
+		EscrowId:    "sched-2",
+		From:        "hive:alice",
+		BlockHeight: 121,
+	}
+	assert.Equal(t, "escrow not found", session.CancelScheduledTransfer(cancel).Msg)
+
+	cancel.EscrowId = "sched-1"
+	cancel.From = "hive:bob"
+	assert.Equal(t, "not escrow owner", session.CancelScheduledTransfer(cancel).Msg)
+
+	cancel.From = "hive:alice"
+	cancel.BlockHeight = 195
+	assert.Equal(t, "escrow release too close", session.CancelScheduledTransfer(cancel).Msg)
+
+	cancel.BlockHeight = 121
+	require.True(t, session.CancelScheduledTransfer(cancel).Ok)
+	assert.Equal(t, int64(1000), session.GetBalance("hive:alice", 121, "hbd"))
+
+	cancel.Id = "cancel-2"
+	assert.Equal(t, "escrow already cancelled", session.CancelScheduledTransfer(cancel).Msg)
+
+	ingestSession(ls, state, session, 130)
+
+	escrow, _ := state.ActionDb.Get("sched-1")
+	assert.Equal(t, "cancelled", escrow.Status)
+	assert.Equal(t, int64(0), ledgerSum(state, "hive:alice", "hbd"))
+
+	session = ledgerSystem.NewSession(state)
+	cancel.Id = "cancel-3"
+	cancel.BlockHeight = 131
+	assert.Equal(t, "escrow not pending", session.CancelScheduledTransfer(cancel).Msg)
+}
+
+func TestCancelTrackedAcrossFlush(t *testing.T) {
+	state := newTestState()
+	seedBalance(state, "hive:alice", 0, 0, 1000, 0)
+	ls := ledgerSystem.New(state.BalanceDb, state.LedgerDb, &test_utils.MockInterestClaimsDb{}, state.ActionDb)
+
+	session := ledgerSystem.NewSession(state)
+	require.True(t, session.ScheduleTransfer(ledgerSystem.ScheduledTransferParams{
+		Id:            "sched-1",
+		From:          "hive:alice",
+		To:            "hive:bob",
+		Amount:        400,
+		Asset:         "hbd",
+		BlockHeight:   101,
+		ReleaseHeight: 200,
+	}).Ok)
+	ingestSession(ls, state, session, 110)
+
+	cancel := ledgerSystem.CancelScheduledTransferParams{
+		Id:          "cancel-1",
+		EscrowId:    "sched-1",
+		From:        "hive:alice",
+		BlockHeight: 121,
+	}
+	session = ledgerSystem.NewSession(state)
+	require.True(t, session.CancelScheduledTransfer(cancel).Ok)
+	session.Done()
+
+	// The slot oplog is flushed before the block carrying the cancel is ingested
+	state.Flush()
+
+	session = ledgerSystem.NewSession(state)
+	cancel.Id = "cancel-2"
+	cancel.BlockHeight = 131
+	assert.Equal(t, "escrow already cancelled", session.CancelScheduledTransfer(cancel).Msg)
+
+	ls.IngestOplog([]ledgerSystem.OpLogEvent{{
+		Id:     "cancel-1",
+		From:   "hive:alice",
+		To:     "hive:alice",
+		Amount: 400,
+		Asset:  "hbd",
+		Type:   "cancel_scheduled_transfer",
+		Params: map[string]interface{}{
+			"escrow_id": "sched-1",
+		},
+	}}, ledgerSystem.OplogInjestOptions{StartHeight: 130, EndHeight: 140})
+	state.Flush()
+	assert.Empty(t, state.CancelledEscrows)
+	assert.Equal(t, "escrow not pending", session.CancelScheduledTransfer(cancel).Msg)
+}
+
+func TestCancelAfterReleaseIsNotRefunded(t *testing.T) {
+	state := newTestState()
+	ls := ledgerSystem.New(state.BalanceDb, state.LedgerDb, &test_utils.MockInterestClaimsDb{}, state.ActionDb)
+
+	state.ActionDb.StoreAction(ledgerDb.ActionRecord{
+		Id:     "sched-1",
+		Status: "complete",
+		Amount: 400,
+		Asset:  "hbd",
+		To:     "hive:bob",
+		Type:   "scheduled_transfer",
+		Params: map[string]interface{}{
+			"from":           "hive:alice",
+			"release_height": uint64(120),
+		},
+	})
+
+	// A cancellation that raced the release must not credit the sender again
+	ls.IngestOplog([]ledgerSystem.OpLogEvent{{
+		Id:     "cancel-1",
+		From:   "hive:alice",
+		To:     "hive:alice",
+		Amount: 400,
+		Asset:  "hbd",
+		Type:   "cancel_scheduled_transfer",
+		Params: map[string]interface{}{
+			"escrow_id": "sched-1",
+		},
+	}}, ledgerSystem.OplogInjestOptions{StartHeight: 110, EndHeight: 120})
+
+	escrow, _ := state.ActionDb.Get("sched-1")
+	assert.Equal(t, "complete", escrow.Status)
+	assert.Equal(t, int64(0), ledgerSum(state, "hive:alice", "hbd"))
+}
@@ -0,0 +1,139 @@
+package ledgerSystem
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Native tokens are namespaced by the contract that issues them.
+// The asset of a token is "<contract id>:<symbol>", e.g. "vsc1Bem8RnoLgGPP7E2MBN52ekrdVqy2LNpSqF:PEPE".
+// Only the issuing contract can mint or burn its tokens, everyone can transfer them.
+
+// Virtual account holding the circulating supply of every native token
+// Minting credits it and burning debits it, so the supply is a regular ledger balance
+const TOKEN_SUPPLY_ACCOUNT = "system:token_supply"
+
+var tokenSymbolRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,11}$`)
+
+// Asset of the token symbol issued by contractId
+func TokenAsset(contractId string, symbol string) string {
+	return contractId + ":" + symbol
+}
+
+// Splits a native token asset into its issuing contract and symbol
+func ParseTokenAsset(asset string) (contractId string, symbol string, ok bool) {
+	contractId, symbol, ok = strings.Cut(asset, ":")
+	if !ok || !strings.HasPrefix(contractId, "vsc1") || len(contractId) != 38 {
+		return "", "", false
+	}
+	if !tokenSymbolRegex.MatchString(symbol) {
+		return "", "", false
+	}
+	return contractId, symbol, true
+}
+
+func IsTokenAsset(asset string) bool {
+	_, _, ok := ParseTokenAsset(asset)
+	return ok
+}
+
+func isTransferableAsset(asset string) bool {
+	return slices.Contains(transferableAssetTypes, asset) || IsTokenAsset(asset)
+}
+
+// Creates new units of a token issued by the calling contract
+func (ledgerSession *ledgerSession) Mint(params TokenParams) LedgerResult {
+	if params.Amount <= 0 {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid amount",
+		}
+	}
+	if msg := checkTokenIssuer(params); msg != "" {
+		return LedgerResult{
+			Ok:  false,
+			Msg: msg,
+		}
+	}
+	if params.Account == "" || strings.HasPrefix(params.Account, "system:") {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid destination",
+		}
+	}
+
+	supply := ledgerSession.GetBalance(TOKEN_SUPPLY_ACCOUNT, params.BlockHeight, params.Asset)
+	if supply > math.MaxInt64-params.Amount {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "supply overflow",
+		}
+	}
+
+	ledgerSession.AppendOplog(OpLogEvent{
+		Id:          params.Id,
+		From:        "contract:" + params.ContractId,
+		To:          params.Account,
+		Amount:      params.Amount,
+		Asset:       params.Asset,
+		Type:        "mint",
+		BlockHeight: params.BlockHeight,
+	})
+
+	return LedgerResult{
+		Ok:  true,
+		Msg: "success",
+	}
+}
+
+// Destroys units of a token issued by the calling contract
+func (ledgerSession *ledgerSession) Burn(params TokenParams) LedgerResult {
+	if params.Amount <= 0 {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "invalid amount",
+		}
+	}
+	if msg := checkTokenIssuer(params); msg != "" {
+		return LedgerResult{
+			Ok:  false,
+			Msg: msg,
+		}
+	}
+
+	fromBal := ledgerSession.GetBalance(params.Account, params.BlockHeight, params.Asset)
+	if fromBal < params.Amount {
+		return LedgerResult{
+			Ok:  false,
+			Msg: "insufficient balance",
+		}
+	}
+
+	ledgerSession.AppendOplog(OpLogEvent{
+		Id:          params.Id,
+		From:        params.Account,
+		To:          "contract:" + params.ContractId,
+		Amount:      params.Amount,
+		Asset:       params.Asset,
+		Type:        "burn",
+		BlockHeight: params.BlockHeight,
+	})
+
+	return LedgerResult{
+		Ok:  true,
+		Msg: "success",
+	}
+}
+
+func checkTokenIssuer(params TokenParams) string {
+	contractId, _, ok := ParseTokenAsset(params.Asset)
+	if !ok {
+		return "invalid asset"
+	}
+	if contractId != params.ContractId {
+		return "not token issuer"
+	}
+	return ""
+}
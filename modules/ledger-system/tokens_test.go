@@ -0,0 +1,89 @@
+package ledgerSystem_test
+
+import (
+	"testing"
+
+	"vsc-node/lib/test_utils"
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
+	ledgerSystem "vsc-node/modules/ledger-system"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenIssuer = "vsc1Bem8RnoLgGPP7E2MBN52ekrdVqy2LNpSqF"
+
+func TestTokenAsset(t *testing.T) {
+	asset := ledgerSystem.TokenAsset(tokenIssuer, "PEPE")
+	contractId, symbol, ok := ledgerSystem.ParseTokenAsset(asset)
+	assert.True(t, ok)
+	assert.Equal(t, tokenIssuer, contractId)
+	assert.Equal(t, "PEPE", symbol)
+
+	for _, asset := range []string{"hbd", "hive_consensus", tokenIssuer, tokenIssuer + ":pepe", tokenIssuer + ":", "vsc1abc:PEPE"} {
+		assert.False(t, ledgerSystem.IsTokenAsset(asset), asset)
+	}
+}
+
+func TestMintTransferBurnToken(t *testing.T) {
+	state := newTestState()
+	ls := ledgerSystem.New(state.BalanceDb, state.LedgerDb, &test_utils.MockInterestClaimsDb{}, state.ActionDb)
+	asset := ledgerSystem.TokenAsset(tokenIssuer, "PEPE")
+	contract := "contract:" + tokenIssuer
+
+	session := ledgerSystem.NewSession(state)
+	mint := ledgerSystem.TokenParams{
+		Id:          "mint-1",
+		ContractId:  "vsc1BdrQ6EtbQ64rq2PkPd21x4MaLnVRcJj85d",
+		Account:     "hive:alice",
+		Asset:       asset,
+		Amount:      1000,
+		BlockHeight: 101,
+	}
+	assert.Equal(t, "not token issuer", session.Mint(mint).Msg)
+
+	mint.ContractId = tokenIssuer
+	mint.Account = ledgerSystem.TOKEN_SUPPLY_ACCOUNT
+	assert.Equal(t, "invalid destination", session.Mint(mint).Msg)
+
+	mint.Account = "hive:alice"
+	require.True(t, session.Mint(mint).Ok)
+	assert.Equal(t, int64(1000), session.GetBalance("hive:alice", 101, asset))
+	assert.Equal(t, int64(1000), session.GetBalance(ledgerSystem.TOKEN_SUPPLY_ACCOUNT, 101, asset))
+
+	transfer := ledgerSystem.OpLogEvent{
+		Id:          "transfer-1",
+		From:        "hive:alice",
+		To:          contract,
+		Amount:      400,
+		Asset:       asset,
+		BlockHeight: 101,
+	}
+	require.True(t, session.ExecuteTransfer(transfer).Ok)
+
+	burn := ledgerSystem.TokenParams{
+		Id:          "burn-1",
+		ContractId:  tokenIssuer,
+		Account:     contract,
+		Asset:       asset,
+		Amount:      401,
+		BlockHeight: 101,
+	}
+	assert.Equal(t, "insufficient balance", session.Burn(burn).Msg)
+	burn.Amount = 150
+	require.True(t, session.Burn(burn).Ok)
+
+	ingestSession(ls, state, session, 110)
+
+	assert.Equal(t, int64(600), ledgerSum(state, "hive:alice", asset))
+	assert.Equal(t, int64(250), ledgerSum(state, contract, asset))
+	assert.Equal(t, int64(850), ledgerSum(state, ledgerSystem.TOKEN_SUPPLY_ACCOUNT, asset))
+
+	//Snapshotted token balances are picked up by later sessions
+	state.BalanceDb.UpdateBalanceRecord(ledgerDb.BalanceRecord{
+		Account:     "hive:bob",
+		BlockHeight: 110,
+		Tokens:      map[string]int64{asset: 75},
+	})
+	assert.Equal(t, int64(75), sessionBalanceOf(state, "hive:bob", asset, 120))
+}
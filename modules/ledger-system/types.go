@@ -59,6 +59,40 @@ type ConsensusParams struct {
 	ElectionEpoch uint64
 }
 
+type ScheduledTransferParams struct {
+	Id     string
+	From   string
+	To     string
+	Amount int64
+	Asset  string
+	Memo   string
+
+	BIdx          int64
+	OpIdx         int64
+	BlockHeight   uint64
+	ReleaseHeight uint64
+}
+
+type CancelScheduledTransferParams struct {
+	Id string
+	//Id of the escrow action created by the scheduled transfer
+	EscrowId    string
+	From        string
+	BlockHeight uint64
+}
+
+// Mint or burn of a native token
+type TokenParams struct {
+	Id string
+	//Contract calling the mint or burn, must be the issuer of the token
+	ContractId string
+	//Receiver of a mint or holder burning
+	Account     string
+	Asset       string
+	Amount      int64
+	BlockHeight uint64
+}
+
 type LedgerResult struct {
 	Ok  bool
 	Msg string
@@ -115,6 +149,10 @@ type LedgerSession interface {
 	Unstake(StakeOp) LedgerResult
 	ConsensusStake(ConsensusParams) LedgerResult
 	ConsensusUnstake(ConsensusParams) LedgerResult
+	ScheduleTransfer(ScheduledTransferParams) LedgerResult
+	CancelScheduledTransfer(CancelScheduledTransferParams) LedgerResult
+	Mint(TokenParams) LedgerResult
+	Burn(TokenParams) LedgerResult
 	Done() []string
 	Revert()
 }
@@ -8,6 +8,14 @@ import (
 var transferableAssetTypes = []string{"hive", "hbd", "hbd_savings"}
 var assetTypes = slices.Concat(transferableAssetTypes, []string{"hive_consensus"})
 
+// Assets bridged from external chains, by the chain their withdrawals settle on
+var bridgeAssetChains = map[string]string{"eth": "ETH", "usdc": "ETH"}
+
+// Chain withdrawals of asset are sent to, empty for Hive assets
+func BridgeChain(asset string) string {
+	return bridgeAssetChains[asset]
+}
+
 const ETH_REGEX = "^0x[a-fA-F0-9]{40}$"
 const HIVE_REGEX = `^[a-z][0-9a-z\-]*[0-9a-z](\.[a-z][0-9a-z\-]*[0-9a-z])*$`
 
@@ -194,6 +202,88 @@ func ExecuteOplog(oplog []OpLogEvent, startHeight uint64, endBlock uint64) struc
 				BlockHeight: endBlock,
 			})
 		}
+		if v.Type == "scheduled_transfer" {
+			affectedAccounts[v.From] = true
+
+			ledgerRecords = append(ledgerRecords, LedgerUpdate{
+				Id:          v.Id + "#in",
+				BlockHeight: endBlock,
+				Amount:      -v.Amount,
+				Asset:       v.Asset,
+				Owner:       v.From,
+				Type:        "scheduled_transfer",
+			})
+
+			actionRecords = append(actionRecords, ledgerDb.ActionRecord{
+				Id:     v.Id,
+				Amount: v.Amount,
+				Asset:  v.Asset,
+				To:     v.To,
+				Memo:   v.Memo,
+				TxId:   v.Id,
+				Status: "pending",
+				Type:   "scheduled_transfer",
+				Params: map[string]interface{}{
+					"from":           v.From,
+					"release_height": v.Params["release_height"],
+				},
+				BlockHeight: endBlock,
+			})
+		}
+		if v.Type == "cancel_scheduled_transfer" {
+			affectedAccounts[v.To] = true
+
+			ledgerRecords = append(ledgerRecords, LedgerUpdate{
+				Id:          v.Id + "#out",
+				BlockHeight: endBlock,
+				Amount:      v.Amount,
+				Asset:       v.Asset,
+				Owner:       v.To,
+				Type:        "cancel_scheduled_transfer",
+			})
+		}
+		if v.Type == "mint" {
+			affectedAccounts[v.To] = true
+			affectedAccounts[TOKEN_SUPPLY_ACCOUNT] = true
+
+			ledgerRecords = append(ledgerRecords, LedgerUpdate{
+				Id:          v.Id + "#out",
+				BlockHeight: endBlock,
+				Amount:      v.Amount,
+				Asset:       v.Asset,
+				Owner:       v.To,
+				Type:        "mint",
+			})
+			ledgerRecords = append(ledgerRecords, LedgerUpdate{
+				Id:          v.Id + "#supply",
+				BlockHeight: endBlock,
+				Amount:      v.Amount,
+				Asset:       v.Asset,
+				Owner:       TOKEN_SUPPLY_ACCOUNT,
+				Type:        "mint",
+			})
+		}
+		if v.Type == "burn" {
+			affectedAccounts[v.From] = true
+			affectedAccounts[TOKEN_SUPPLY_ACCOUNT] = true
+
+			ledgerRecords = append(ledgerRecords, LedgerUpdate{
+				Id:          v.Id + "#in",
+				BlockHeight: endBlock,
+				Amount:      -v.Amount,
+				Asset:       v.Asset,
+				Owner:       v.From,
+				Type:        "burn",
+			})
+			ledgerRecords = append(ledgerRecords, LedgerUpdate{
+				Id:          v.Id + "#supply",
+				BlockHeight: endBlock,
+				Amount:      -v.Amount,
+				Asset:       v.Asset,
+				Owner:       TOKEN_SUPPLY_ACCOUNT,
+				Type:        "burn",
+			})
+		}
 	}
 	// assets := []string{"hbd", "hive", "hbd_savings"}
 
@@ -232,6 +322,30 @@ func ExecuteOplog(oplog []OpLogEvent, startHeight uint64, endBlock uint64) struc
 	}
 }
 
+// Sender of a scheduled transfer escrow
+func ScheduledTransferSender(record ledgerDb.ActionRecord) string {
+	from, _ := record.Params["from"].(string)
+	return from
+}
+
+// Release height of a scheduled transfer escrow
+// Params may have been round tripped through JSON or BSON, so any numeric type is accepted
+func ScheduledTransferReleaseHeight(record ledgerDb.ActionRecord) uint64 {
+	switch v := record.Params["release_height"].(type) {
+	case uint64:
+		return v
+	case int64:
+		return uint64(v)
+	case int32:
+		return uint64(v)
+	case int:
+		return uint64(v)
+	case float64:
+		return uint64(v)
+	}
+	return 0
+}
+
 func NewSession(ledgerState *LedgerState) LedgerSession {
 	return &ledgerSession{
 		state: ledgerState,
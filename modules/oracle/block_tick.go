@@ -7,13 +7,14 @@ import (
 	"vsc-node/modules/db/vsc/elections"
 	"vsc-node/modules/oracle/chain"
 	"vsc-node/modules/oracle/p2p"
+	"vsc-node/modules/oracle/price"
 	stateEngine "vsc-node/modules/state-processing"
 )
 
 const blockHeightThreshold = 10
 
 var (
-	// _ BlockTickHandler = &price.PriceOracle{}
+	_ BlockTickHandler = &price.PriceOracle{}
 	_ BlockTickHandler = &chain.ChainOracle{}
 )
 
@@ -60,11 +61,11 @@ func (o *Oracle) blockTick(bh uint64, headHeight *uint64) {
 	)
 
 	var (
-		username = o.conf.Get().HiveUsername
-		// isAvgPriceBroadcastTick = *headHeight%priceOracleBroadcastInterval == 0
-		isChainRelayTick = bh%chainRelayInterval == 0
-		isWitness        = slices.Contains(memberAccounts, username)
-		isProducer       = witnessSlot != nil &&
+		username                = o.conf.Get().HiveUsername
+		isAvgPriceBroadcastTick = bh%priceOracleBroadcastInterval == 0
+		isChainRelayTick        = bh%chainRelayInterval == 0
+		isWitness               = slices.Contains(memberAccounts, username)
+		isProducer              = witnessSlot != nil &&
 			witnessSlot.Account == username
 	)
 
@@ -75,9 +76,9 @@ func (o *Oracle) blockTick(bh uint64, headHeight *uint64) {
 		ElectedMembers: members,
 	}
 
-	// if isAvgPriceBroadcastTick {
-	// 	go o.priceOracle.HandleBlockTick(signal, o)
-	// }
+	if isAvgPriceBroadcastTick && o.priceOracle != nil {
+		go o.priceOracle.HandleBlockTick(signal, o)
+	}
 
 	if isChainRelayTick {
 		go o.chainOracle.HandleBlockTick(signal, o)
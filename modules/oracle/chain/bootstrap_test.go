@@ -8,8 +8,10 @@ import (
 	"github.com/chebyrash/promise"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
 	vsclog "vsc-node/lib/vsclog"
 	systemconfig "vsc-node/modules/common/system-config"
 )
@@ -27,9 +29,16 @@ func (m *mockContractState) IngestOutput(contracts.IngestOutputArgs) {}
 func (m *mockContractState) GetLastOutput(string, uint64) (contracts.ContractOutput, error) {
 	return m.output, m.err
 }
+func (m *mockContractState) GetOutput(string) *contracts.ContractOutput { return nil }
 func (m *mockContractState) FindOutputs(*string, *string, *string, *uint64, *uint64, int, int) ([]contracts.ContractOutput, error) {
 	return nil, nil
 }
+func (m *mockContractState) FindOutputsPage(*string, *string, *string, *uint64, *uint64, hive_blocks.PageArgs) (hive_blocks.Page[contracts.ContractOutput], error) {
+	return hive_blocks.Page[contracts.ContractOutput]{}, nil
+}
+func (m *mockContractState) FindEvents(*string, *string, *string, *uint64, *uint64, hive_blocks.PageArgs) (hive_blocks.Page[contracts.ContractEventRecord], error) {
+	return hive_blocks.Page[contracts.ContractEventRecord]{}, nil
+}
 
 // mockChainRelay implements chainRelay for bootstrap tests.
 type mockChainRelay struct {
@@ -70,7 +79,7 @@ func TestFetchChainStatus_BootstrapWhenContractHeightZero(t *testing.T) {
 		logger: logger,
 		contractState: &mockContractState{
 			output: contracts.ContractOutput{},
-			err:    nil,
+			err:    mongo.ErrNoDocuments,
 		},
 	}
 
@@ -97,7 +106,7 @@ func TestFetchChainStatus_BootstrapEmptyChainSkips(t *testing.T) {
 		logger: logger,
 		contractState: &mockContractState{
 			output: contracts.ContractOutput{},
-			err:    nil,
+			err:    mongo.ErrNoDocuments,
 		},
 	}
 
@@ -120,7 +129,7 @@ func TestFetchChainStatus_BootstrapShortChainStartsAtGenesis(t *testing.T) {
 		logger: logger,
 		contractState: &mockContractState{
 			output: contracts.ContractOutput{},
-			err:    nil,
+			err:    mongo.ErrNoDocuments,
 		},
 	}
 
@@ -44,6 +44,7 @@ import (
 
 	"github.com/chebyrash/promise"
 	"github.com/ipfs/go-cid"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 var (
@@ -320,13 +321,16 @@ type chainSession struct {
 // mapping contract's state via the data layer.
 func (c *ChainOracle) getContractBlockHeight(contractId string) (uint64, error) {
 	output, err := c.contractState.GetLastOutput(contractId, math.MaxInt64)
+	// GetLastOutput returns mongo.ErrNoDocuments when the contract has never
+	// produced an output. Surface that as the same "fresh contract" signal as
+	// a missing height key in the databin so the caller can distinguish fresh
+	// state from a transient read error.
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
 	if err != nil {
 		return 0, fmt.Errorf("failed to query contract output for %s: %w", contractId, err)
 	}
-	// GetLastOutput returns a zero-valued ContractOutput (no error) when the
-	// contract has never produced an output. Surface that as the same
-	// "fresh contract" signal as a missing height key in the databin so the
-	// caller can distinguish fresh state from a transient read error.
 	if output.StateMerkle == "" {
 		return 0, nil
 	}
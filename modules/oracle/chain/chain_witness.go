@@ -1,10 +1,14 @@
 package chain
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+	"vsc-node/modules/common"
+
+	"github.com/ipfs/go-cid"
 )
 
 // witnessChainData independently verifies chain data and returns a BLS signature.
@@ -90,12 +94,7 @@ func witnessChainData(c *ChainOracle, msg *chainOracleMessage) (*chainRelayRespo
 	txCid := signableBlock.Cid()
 
 	// Sign the CID with our BLS key
-	blsProvider, err := c.conf.BlsProvider()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get BLS provider: %w", err)
-	}
-
-	sig, err := blsProvider.Sign(txCid)
+	sig, err := signCid(c.conf, txCid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign chain data: %w", err)
 	}
@@ -171,12 +170,7 @@ func witnessReplaceBlock(c *ChainOracle, sessionID string, request *chainRelayRe
 
 	txCid := signableBlock.Cid()
 
-	blsProvider, err := c.conf.BlsProvider()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get BLS provider: %w", err)
-	}
-
-	sig, err := blsProvider.Sign(txCid)
+	sig, err := signCid(c.conf, txCid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign: %w", err)
 	}
@@ -199,3 +193,13 @@ func witnessReplaceBlock(c *ChainOracle, sessionID string, request *chainRelayRe
 		BlsDid:    blsDid.String(),
 	}, nil
 }
+
+// signCid BLS signs the CID with the node's signer, encoded as expected by
+// the BLS circuit.
+func signCid(conf common.IdentityConfig, c cid.Cid) (string, error) {
+	sig, err := conf.Signer().SignCid(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(sig[:]), nil
+}
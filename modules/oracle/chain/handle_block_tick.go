@@ -253,15 +253,7 @@ func (o *ChainOracle) processChainRelay(
 	}
 
 	// Self-sign: producer signs its own data
-	blsProvider, err := o.conf.BlsProvider()
-	if err != nil {
-		o.logger.Error("failed to get BLS provider",
-			"symbol", chainStatus.symbol, "err", err,
-		)
-		return
-	}
-
-	selfSig, err := blsProvider.Sign(txCid)
+	selfSig, err := signCid(o.conf, txCid)
 	if err != nil {
 		o.logger.Error("failed to self-sign",
 			"symbol", chainStatus.symbol, "err", err,
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"vsc-node/modules/config"
+	"vsc-node/modules/oracle/price"
 )
 
 // ChainRpcConfig holds RPC connection details for a single chain node.
@@ -26,13 +27,25 @@ type ChainRpcConfig struct {
 //	    "DASH": { "RpcHost": "dashd:9998",      "RpcUser": "user", "RpcPass": "pass" },
 //	    "LTC":  { "RpcHost": "litecoind:9332",  "RpcUser": "user", "RpcPass": "pass" },
 //	    "ETH":  { "RpcHost": "http://geth:8545" }
-//	  }
+//	  },
+//	  "PriceSources": [
+//	    { "Name": "Example", "Url": "https://prices.example.com/{symbol}-{currency}", "PricePath": "data.price", "VolumePath": "data.volume" }
+//	  ]
 //	}
 type oracleConfig struct {
 	// Chains maps chain symbols (e.g. "BTC", "DASH") to their RPC config.
 	// Each registered chainRelay looks up its RPC details from this map.
 	Chains map[string]ChainRpcConfig `json:"Chains"`
 
+	// PriceSources are JSON HTTP price sources queried by the price oracle
+	// alongside the built-in ones.
+	PriceSources []price.JsonSourceConfig `json:"PriceSources,omitempty"`
+
+	// PriceMaxDeviation is the fraction by which a source may deviate from
+	// the median price before it is dropped, defaults to
+	// price.DefaultMaxSourceDeviation.
+	PriceMaxDeviation float64 `json:"PriceMaxDeviation,omitempty"`
+
 	// Deprecated: use Chains["BTC"] instead. Kept for backwards compatibility
 	// with existing config files that use the flat BitcoindRpc* fields.
 	BitcoindRpcHost string `json:"BitcoindRpcHost,omitempty"`
@@ -2,10 +2,12 @@ package oracle
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"time"
 	DataLayer "vsc-node/lib/datalayer"
+	"vsc-node/lib/hive"
 	"vsc-node/lib/vsclog"
 	"vsc-node/modules/aggregate"
 	"vsc-node/modules/common"
@@ -17,12 +19,14 @@ import (
 	blockconsumer "vsc-node/modules/hive/block-consumer"
 	"vsc-node/modules/oracle/chain"
 	"vsc-node/modules/oracle/p2p"
+	"vsc-node/modules/oracle/price"
 	libp2p "vsc-node/modules/p2p"
 	stateEngine "vsc-node/modules/state-processing"
 	transactionpool "vsc-node/modules/transaction-pool"
 
 	"github.com/chebyrash/promise"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/vsc-eco/hivego"
 )
 
 const (
@@ -43,6 +47,7 @@ const (
 var (
 	watchSymbols          = []string{"BTC", "ETH", "LTC"}
 	errInvalidMessageType = errors.New("invalid message type")
+	errPriceOracleOff     = errors.New("price oracle disabled")
 
 	_ p2p.OracleP2PSpec = &Oracle{}
 )
@@ -54,14 +59,17 @@ type Oracle struct {
 	p2pServer    *libp2p.P2PServer
 	pubSubSrv    libp2p.PubSubService[p2p.Msg]
 	conf         common.IdentityConfig
+	sconf        systemconfig.SystemConfig
 	oracleConf   OracleConfig
 	electionDb   elections.Elections
 	witnessDb    witnesses.Witnesses
 	hiveConsumer *blockconsumer.HiveConsumer
 	stateEngine  *stateEngine.StateEngine
 	txPool       *transactionpool.TransactionPool
+	hiveCreator  hive.HiveTransactionCreator
 
-	// priceOracle *price.PriceOracle
+	// nil when no price source is available
+	priceOracle *price.PriceOracle
 	chainOracle *chain.ChainOracle
 }
 
@@ -78,6 +86,7 @@ func New(
 	txPool *transactionpool.TransactionPool,
 	oracleConf OracleConfig,
 	nonceDb nonces.Nonces,
+	hiveCreator hive.HiveTransactionCreator,
 ) *Oracle {
 	logger := vsclog.Module("oracle").With("id", conf.Get().HiveUsername)
 
@@ -91,6 +100,7 @@ func New(
 		cancelFunc:   cancel,
 		p2pServer:    p2pServer,
 		conf:         conf,
+		sconf:        sconf,
 		oracleConf:   oracleConf,
 		electionDb:   electionDb,
 		witnessDb:    witnessDb,
@@ -99,6 +109,7 @@ func New(
 		logger:       logger,
 		chainOracle:  chainRelayer,
 		txPool:       txPool,
+		hiveCreator:  hiveCreator,
 	}
 }
 
@@ -124,11 +135,39 @@ func (o *Oracle) Init() error {
 		}
 	}
 
-	services := []aggregate.Plugin{
-		o.chainOracle,
-		// o.priceOracle,
+	// JSON price sources must be registered before the price oracle is
+	// created, as it clones the source registry
+	for _, src := range cfg.PriceSources {
+		price.RegisterSource(price.NewJsonSource(src))
 	}
-	return aggregate.New(services).Init()
+
+	priceOracle := price.New(
+		o.ctx,
+		o.logger.Logger,
+		userCurrency,
+		priceOraclePollInterval,
+		watchSymbols,
+		cfg.PriceMaxDeviation,
+		o.conf,
+	)
+
+	// the price oracle is optional, nodes without a price source only relay
+	// chains
+	if err := priceOracle.Init(); err != nil {
+		o.logger.Warn("price oracle disabled", "err", err)
+	} else {
+		o.priceOracle = priceOracle
+	}
+
+	return o.chainOracle.Init()
+}
+
+func (o *Oracle) services() []aggregate.Plugin {
+	services := []aggregate.Plugin{o.chainOracle}
+	if o.priceOracle != nil {
+		services = append(services, o.priceOracle)
+	}
+	return services
 }
 
 // Start implements aggregate.Plugin.
@@ -154,12 +193,7 @@ func (o *Oracle) Start() *promise.Promise[any] {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		services := []aggregate.Plugin{
-			o.chainOracle,
-			// o.priceOracle,
-		}
-
-		s := aggregate.New(services)
+		s := aggregate.New(o.services())
 		if _, err := s.Start().Await(ctx); err != nil {
 			reject(err)
 			return
@@ -174,12 +208,7 @@ func (o *Oracle) Start() *promise.Promise[any] {
 func (o *Oracle) Stop() error {
 	o.cancelFunc()
 
-	services := []aggregate.Plugin{
-		o.chainOracle,
-		// o.priceOracle,
-	}
-
-	s := aggregate.New(services)
+	s := aggregate.New(o.services())
 	if err := s.Stop(); err != nil {
 		return err
 	}
@@ -205,13 +234,54 @@ func (o *Oracle) Broadcast(msgCode p2p.MsgCode, data any) error {
 	return o.pubSubSrv.Send(msg)
 }
 
+// SubmitPriceBlock implements p2p.OracleP2PSpec.
+// Publishes the signed price block to Hive under the producer's account.
+func (o *Oracle) SubmitPriceBlock(block *p2p.OracleBlock) error {
+	payload, err := json.Marshal(map[string]any{
+		"net_id": o.sconf.NetId(),
+		"block":  block,
+	})
+	if err != nil {
+		return err
+	}
+
+	op := o.hiveCreator.CustomJson(
+		[]string{o.conf.Get().HiveUsername},
+		[]string{},
+		"vsc.oracle_price",
+		string(payload),
+	)
+
+	tx := o.hiveCreator.MakeTransaction([]hivego.HiveOperation{op})
+	if err := o.hiveCreator.PopulateSigningProps(&tx, nil); err != nil {
+		return err
+	}
+
+	sig, err := o.hiveCreator.Sign(tx)
+	if err != nil {
+		return err
+	}
+	tx.AddSig(sig)
+
+	id, err := o.hiveCreator.Broadcast(tx)
+	if err != nil {
+		return err
+	}
+
+	o.logger.Info("price block submitted", "block-id", block.ID, "tx-id", id)
+	return nil
+}
+
 // Handle implements p2p.MessageHandler
 func (o *Oracle) Handle(peerID peer.ID, msg p2p.Msg) (p2p.Msg, error) {
 	var handler p2p.MessageHandler
 
 	switch msg.Code {
-	// case p2p.MsgPriceBroadcast, p2p.MsgPriceSignature, p2p.MsgPriceBlock:
-	// 	handler = o.priceOracle
+	case p2p.MsgPriceBroadcast, p2p.MsgPriceSignature, p2p.MsgPriceBlock:
+		if o.priceOracle == nil {
+			return nil, errPriceOracleOff
+		}
+		handler = o.priceOracle
 
 	case p2p.MsgChainRelay:
 		handler = o.chainOracle
@@ -22,6 +22,8 @@ var (
 
 type OracleP2PSpec interface {
 	Broadcast(MsgCode, any) error
+	// publishes a price block signed by the elected witnesses
+	SubmitPriceBlock(*OracleBlock) error
 }
 
 type MessageHandler interface {
@@ -24,7 +24,22 @@ type coinGeckoHandler struct {
 	demoMode bool
 }
 
-var _ priceQuery = &coinGeckoHandler{}
+var _ PriceSource = &coinGeckoHandler{}
+
+func init() {
+	RegisterSource(&coinGeckoHandler{})
+}
+
+// Name implements PriceSource
+func (c *coinGeckoHandler) Name() string {
+	return "CoinGecko"
+}
+
+// Clone implements PriceSource
+func (c *coinGeckoHandler) Clone() PriceSource {
+	clone := *c
+	return &clone
+}
 
 type coinGeckoPriceQueryResponse struct {
 	Symbol       string  `json:"symbol,omitempty"`
@@ -37,7 +52,7 @@ type coinGeckoPriceQueryResponse struct {
 // free) and their conrresponding url + header key. Assume the suppplied key
 // is a pro key by default, otherwise `COINGECKO_API_DEMO` needs to be exported
 // with the value '1', and attributions is required on demo keys.
-func (c *coinGeckoHandler) initialize(currency string) error {
+func (c *coinGeckoHandler) Initialize(currency string) error {
 	apiKey, ok := os.LookupEnv("COINGECKO_API_KEY")
 	if !ok {
 		return errApiKeyNotFound
@@ -67,7 +82,7 @@ func (c *coinGeckoHandler) initialize(currency string) error {
 	return nil
 }
 
-func (c *coinGeckoHandler) queryMarketPrice(
+func (c *coinGeckoHandler) QueryMarketPrice(
 	symbols []string) (map[string]p2p.ObservePricePoint, error) {
 	symLowerCase := make([]string, len(symbols))
 	copy(symLowerCase, symbols)
@@ -17,12 +17,27 @@ type coinMarketCapHandler struct {
 	currency string
 }
 
-var _ priceQuery = &coinMarketCapHandler{}
+var _ PriceSource = &coinMarketCapHandler{}
 
-// initialize implements priceQuery
+func init() {
+	RegisterSource(&coinMarketCapHandler{})
+}
+
+// Name implements PriceSource
+func (c *coinMarketCapHandler) Name() string {
+	return "CoinMarketCap"
+}
+
+// Clone implements PriceSource
+func (c *coinMarketCapHandler) Clone() PriceSource {
+	clone := *c
+	return &clone
+}
+
+// Initialize implements PriceSource
 // returns an error if the environment variable `COINMARKETCAP_API_KEY` is not
 // set
-func (c *coinMarketCapHandler) initialize(currency string) error {
+func (c *coinMarketCapHandler) Initialize(currency string) error {
 	apiKey, ok := os.LookupEnv("COINMARKETCAP_API_KEY")
 	if !ok {
 		return errApiKeyNotFound
@@ -52,8 +67,8 @@ type coinMarketCapQuote struct {
 	Volume float64 `json:"volume_24h,omitempty"`
 }
 
-// queryMarketPrice implements priceQuery
-func (c *coinMarketCapHandler) queryMarketPrice(
+// QueryMarketPrice implements PriceSource
+func (c *coinMarketCapHandler) QueryMarketPrice(
 	watchSymbols []string,
 ) (map[string]p2p.ObservePricePoint, error) {
 	symbols := make([]string, len(watchSymbols))
@@ -0,0 +1,78 @@
+package price
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"vsc-node/modules/oracle/p2p"
+)
+
+type fileSource struct {
+	name string
+	path string
+}
+
+var _ PriceSource = &fileSource{}
+
+// NewFileSource creates a price source reading quotes from a local JSON file,
+// intended for testing. The file is read on every query and maps symbols to
+// price points, e.g. {"HIVE": {"price": 0.25, "volume": 1000}}.
+// Register it with RegisterSource() to make it available to the oracle.
+func NewFileSource(name, path string) PriceSource {
+	return &fileSource{name: name, path: path}
+}
+
+// Name implements PriceSource
+func (f *fileSource) Name() string {
+	return f.name
+}
+
+// Clone implements PriceSource
+func (f *fileSource) Clone() PriceSource {
+	clone := *f
+	return &clone
+}
+
+// Initialize implements PriceSource
+// returns an error if the file does not exist
+func (f *fileSource) Initialize(currency string) error {
+	if _, err := os.Stat(f.path); err != nil {
+		return fmt.Errorf("failed to stat price file: %w", err)
+	}
+	return nil
+}
+
+// QueryMarketPrice implements PriceSource
+func (f *fileSource) QueryMarketPrice(
+	symbols []string,
+) (map[string]p2p.ObservePricePoint, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price file: %w", err)
+	}
+
+	quotes := make(map[string]p2p.ObservePricePoint)
+	if err := json.Unmarshal(data, &quotes); err != nil {
+		return nil, fmt.Errorf("failed to parse price file: %w", err)
+	}
+
+	byUpperSymbol := make(map[string]p2p.ObservePricePoint, len(quotes))
+	for sym, q := range quotes {
+		byUpperSymbol[strings.ToUpper(sym)] = q
+	}
+
+	out := make(map[string]p2p.ObservePricePoint)
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		q, ok := byUpperSymbol[symbol]
+		if !ok {
+			continue
+		}
+
+		q.Symbol = symbol
+		out[symbol] = q
+	}
+
+	return out, nil
+}
@@ -9,7 +9,9 @@ import (
 	"slices"
 	"strings"
 	"time"
+	"vsc-node/lib/signer"
 	"vsc-node/modules/oracle/p2p"
+	"vsc-node/modules/oracle/threadsafe"
 )
 
 const float64Epsilon = 1e-9
@@ -148,9 +150,14 @@ func (p *priceBlockProducer) handleSignal(
 	// make block with median price + broadcast
 	p.logger.Debug("broadcasting new oracle block with median prices")
 
+	activeKey, err := p.conf.Signer().HivePublicKey(signer.ActiveKey)
+	if err != nil {
+		return err
+	}
+
 	block, err := p2p.MakeOracleBlock(
 		p.conf.Get().HiveUsername,
-		p.conf.Get().HiveActiveKey,
+		activeKey,
 		medianPricePoints,
 	)
 	if err != nil {
@@ -185,7 +192,7 @@ func (p *priceBlockProducer) handleSignal(
 		return errors.New("failed to meet signature threshold")
 	}
 
-	return p.SubmitToContract(block)
+	return p.SubmitPriceBlock(block)
 }
 
 // TODO: implement block validation
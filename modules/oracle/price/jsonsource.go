@@ -0,0 +1,177 @@
+package price
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"vsc-node/modules/oracle/httputils"
+	"vsc-node/modules/oracle/p2p"
+)
+
+var errInvalidJsonSource = errors.New("invalid json source config")
+
+// JsonSourceConfig configures a generic JSON HTTP price source.
+//
+// Url, PricePath and VolumePath may contain the placeholders {symbol} and
+// {currency} (upper case) or {symbol_lower} and {currency_lower}. If the url
+// does not depend on the symbol, a single request is sent per query.
+//
+// Paths are dot separated object keys or array indexes, e.g.
+// "data.{symbol}.quote.{currency}.price". Values may be JSON numbers or
+// numeric strings. VolumePath is optional.
+type JsonSourceConfig struct {
+	Name       string
+	Url        string
+	Header     map[string]string
+	PricePath  string
+	VolumePath string
+}
+
+type jsonSource struct {
+	conf     JsonSourceConfig
+	currency string
+}
+
+var _ PriceSource = &jsonSource{}
+
+// NewJsonSource creates a price source that queries any JSON HTTP API.
+// Register it with RegisterSource() to make it available to the oracle.
+func NewJsonSource(conf JsonSourceConfig) PriceSource {
+	return &jsonSource{conf: conf}
+}
+
+// Name implements PriceSource
+func (j *jsonSource) Name() string {
+	return j.conf.Name
+}
+
+// Clone implements PriceSource
+func (j *jsonSource) Clone() PriceSource {
+	clone := *j
+	return &clone
+}
+
+// Initialize implements PriceSource
+func (j *jsonSource) Initialize(currency string) error {
+	if j.conf.Name == "" || j.conf.PricePath == "" {
+		return errInvalidJsonSource
+	}
+	if _, err := url.Parse(j.conf.Url); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidJsonSource, err)
+	}
+
+	j.currency = currency
+	return nil
+}
+
+// QueryMarketPrice implements PriceSource
+func (j *jsonSource) QueryMarketPrice(
+	symbols []string,
+) (map[string]p2p.ObservePricePoint, error) {
+	// responses are cached by url, so symbol independent urls are fetched once
+	responses := make(map[string]any)
+	out := make(map[string]p2p.ObservePricePoint)
+
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		replacer := j.replacer(symbol)
+
+		reqUrl := replacer.Replace(j.conf.Url)
+		doc, ok := responses[reqUrl]
+		if !ok {
+			var err error
+			doc, err = j.fetch(reqUrl)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", symbol, err)
+			}
+			responses[reqUrl] = doc
+		}
+
+		price, err := lookupJsonPath(doc, replacer.Replace(j.conf.PricePath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price of %s: %w", symbol, err)
+		}
+
+		volume := 0.0
+		if j.conf.VolumePath != "" {
+			volume, err = lookupJsonPath(doc, replacer.Replace(j.conf.VolumePath))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse volume of %s: %w", symbol, err)
+			}
+		}
+
+		out[symbol] = p2p.ObservePricePoint{
+			Symbol: symbol,
+			Price:  price,
+			Volume: volume,
+		}
+	}
+
+	return out, nil
+}
+
+func (j *jsonSource) replacer(symbol string) *strings.Replacer {
+	return strings.NewReplacer(
+		"{symbol}", strings.ToUpper(symbol),
+		"{symbol_lower}", strings.ToLower(symbol),
+		"{currency}", strings.ToUpper(j.currency),
+		"{currency_lower}", strings.ToLower(j.currency),
+	)
+}
+
+func (j *jsonSource) fetch(rawUrl string) (any, error) {
+	u, err := httputils.MakeUrl(rawUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httputils.MakeRequest(http.MethodGet, u, j.conf.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := httputils.SendRequest[any](req)
+	if err != nil {
+		return nil, err
+	}
+
+	return *buf, nil
+}
+
+// lookupJsonPath resolves a dot separated path in a decoded JSON document
+// and returns the number found there.
+func lookupJsonPath(doc any, path string) (float64, error) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return 0, fmt.Errorf("key not found: %s", key)
+			}
+			cur = v
+
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return 0, fmt.Errorf("invalid index: %s", key)
+			}
+			cur = node[i]
+
+		default:
+			return 0, fmt.Errorf("cannot resolve key: %s", key)
+		}
+	}
+
+	switch v := cur.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("value at %s is not a number", path)
+	}
+}
@@ -0,0 +1,62 @@
+package price
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupJsonPath(t *testing.T) {
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"data": {
+			"HIVE": {"quote": {"USD": {"price": 0.25, "volume": "1200.5"}}},
+			"tickers": [{"last": 1.5}, {"last": "2.5"}]
+		},
+		"name": "hive"
+	}`), &doc))
+
+	price, err := lookupJsonPath(doc, "data.HIVE.quote.USD.price")
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, price)
+
+	volume, err := lookupJsonPath(doc, "data.HIVE.quote.USD.volume")
+	require.NoError(t, err)
+	assert.Equal(t, 1200.5, volume)
+
+	last, err := lookupJsonPath(doc, "data.tickers.1.last")
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, last)
+
+	_, err = lookupJsonPath(doc, "data.HBD.quote.USD.price")
+	assert.ErrorContains(t, err, "key not found: HBD")
+
+	_, err = lookupJsonPath(doc, "data.tickers.2.last")
+	assert.ErrorContains(t, err, "invalid index: 2")
+
+	_, err = lookupJsonPath(doc, "data.tickers.x.last")
+	assert.ErrorContains(t, err, "invalid index: x")
+
+	_, err = lookupJsonPath(doc, "name.first")
+	assert.ErrorContains(t, err, "cannot resolve key: first")
+
+	_, err = lookupJsonPath(doc, "name")
+	assert.Error(t, err)
+
+	_, err = lookupJsonPath(doc, "data.HIVE")
+	assert.ErrorContains(t, err, "is not a number")
+}
+
+func TestJsonSourcePathPlaceholders(t *testing.T) {
+	src := NewJsonSource(JsonSourceConfig{
+		Name:      "test",
+		PricePath: "data.{symbol}.quote.{currency}.price",
+	}).(*jsonSource)
+	require.NoError(t, src.Initialize("usd"))
+
+	r := src.replacer("hive")
+	assert.Equal(t, "data.HIVE.quote.USD.price", r.Replace(src.conf.PricePath))
+	assert.Equal(t, "hive-usd", r.Replace("{symbol_lower}-{currency_lower}"))
+}
@@ -3,6 +3,7 @@ package price
 import (
 	"sync"
 	"time"
+	"vsc-node/modules/oracle/p2p"
 )
 
 func (p *PriceOracle) marketObserve() {
@@ -20,23 +21,52 @@ func (p *PriceOracle) marketObserve() {
 }
 
 func (p *PriceOracle) queryMarket() {
-	wg := &sync.WaitGroup{}
+	var (
+		wg       = &sync.WaitGroup{}
+		mtx      = &sync.Mutex{}
+		bySource = make(map[string]map[string]p2p.ObservePricePoint)
+	)
+
 	wg.Add(len(p.priceAPIs))
 
 	for src, api := range p.priceAPIs {
-		go func(src string, api priceQuery) {
+		go func(src string, api PriceSource) {
 			defer wg.Done()
 
-			pricePoints, err := api.queryMarketPrice(p.watchSymbols)
+			pricePoints, err := api.QueryMarketPrice(p.watchSymbols)
 			if err != nil {
 				p.logger.Error("failed to query market", "src", src, "err", err)
 				return
 			}
 
-			p.avgPriceMap.Observe(pricePoints)
+			mtx.Lock()
+			bySource[src] = pricePoints
+			mtx.Unlock()
+
 			p.logger.Debug("market price fetched", "src", src)
 		}(src, api)
 	}
 
 	wg.Wait()
+
+	// aggregate across sources before folding into the running average
+	aggregated := make(map[string]p2p.ObservePricePoint)
+	for sym, quotes := range collectSourceQuotes(bySource) {
+		pricePoint, dropped, ok := aggregateQuotes(quotes, p.maxSourceDeviation)
+		if len(dropped) > 0 {
+			p.logger.Warn(
+				"dropped deviating price sources",
+				"symbol", sym,
+				"sources", dropped,
+			)
+		}
+		if !ok {
+			continue
+		}
+
+		pricePoint.Symbol = sym
+		aggregated[sym] = pricePoint
+	}
+
+	p.avgPriceMap.Observe(aggregated)
 }
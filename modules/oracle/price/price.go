@@ -3,7 +3,6 @@ package price
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log/slog"
 	"strings"
 	"time"
@@ -18,16 +17,12 @@ import (
 
 var (
 	errApiKeyNotFound = errors.New("API key not exported")
+	errNoPriceSource  = errors.New("no price source initialized")
 
 	_ p2p.MessageHandler = &PriceOracle{}
 	_ aggregate.Plugin   = &PriceOracle{}
 )
 
-type priceQuery interface {
-	initialize(string) error
-	queryMarketPrice([]string) (map[string]p2p.ObservePricePoint, error)
-}
-
 type PricePoint struct {
 	Price  float64
 	Volume float64
@@ -48,10 +43,12 @@ type PriceOracle struct {
 	pricePollInterval time.Duration
 	watchSymbols      []string
 
+	maxSourceDeviation float64
+
 	ctx         context.Context
 	logger      *slog.Logger
 	avgPriceMap priceMap
-	priceAPIs   map[string]priceQuery
+	priceAPIs   map[string]PriceSource
 	conf        common.IdentityConfig
 }
 
@@ -61,20 +58,27 @@ func New(
 	userCurrency string,
 	pricePollInterval time.Duration,
 	watchSymbols []string,
+	maxSourceDeviation float64,
 	conf common.IdentityConfig,
 ) *PriceOracle {
+	if maxSourceDeviation <= 0 {
+		maxSourceDeviation = DefaultMaxSourceDeviation
+	}
+
 	var (
 		logger         = oracleLogger.With("sub-service", "price-oracle")
 		pricePoints    = threadsafe.NewLockedConsumer[PricePointMap](256)
 		signedBlocks   = threadsafe.NewLockedConsumer[p2p.OracleBlock](256)
 		producerBlocks = threadsafe.NewLockedConsumer[p2p.OracleBlock](8)
 		avgPriceMap    = priceMap{threadsafe.NewMap[string, pricePointData]()}
-		priceQueryMap  = map[string]priceQuery{
-			"CoinMarketCap": &coinMarketCapHandler{},
-			"CoinGecko":     &coinGeckoHandler{},
-		}
 	)
 
+	// Clone registered sources so this instance owns independent state.
+	priceSources := make(map[string]PriceSource, len(sourceRegistry))
+	for name, src := range sourceRegistry {
+		priceSources[name] = src.Clone()
+	}
+
 	return &PriceOracle{
 		ctx:               ctx,
 		pricePoints:       pricePoints,
@@ -84,9 +88,11 @@ func New(
 		pricePollInterval: pricePollInterval,
 		logger:            logger,
 		avgPriceMap:       avgPriceMap,
-		priceAPIs:         priceQueryMap,
+		priceAPIs:         priceSources,
 		conf:              conf,
 		watchSymbols:      watchSymbols,
+
+		maxSourceDeviation: maxSourceDeviation,
 	}
 }
 
@@ -97,16 +103,19 @@ func (p *PriceOracle) Init() error {
 	p.signatures.Lock()
 	p.producerBlocks.Lock()
 
-	// initializes market api's
+	// initializes market api's, sources that are not configured (e.g. missing
+	// API key) are skipped as long as at least one source is available
 	for src, api := range p.priceAPIs {
-		if err := api.initialize(p.userCurrency); err != nil {
-			return fmt.Errorf(
-				"failed to initialize %s handler: %w",
-				src, err,
-			)
+		if err := api.Initialize(p.userCurrency); err != nil {
+			p.logger.Warn("skipping price source", "src", src, "err", err)
+			delete(p.priceAPIs, src)
 		}
 	}
 
+	if len(p.priceAPIs) == 0 {
+		return errNoPriceSource
+	}
+
 	return nil
 }
 
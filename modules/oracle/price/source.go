@@ -0,0 +1,178 @@
+package price
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"vsc-node/modules/oracle/p2p"
+)
+
+// DefaultMaxSourceDeviation is the default fraction by which a quote may
+// deviate from the cross-source median price before it is dropped
+const DefaultMaxSourceDeviation = 0.05
+
+// PriceSource is the interface that market price sources must implement.
+// To add a new source, implement this interface and register it via
+// RegisterSource(), either from init() or before the price oracle is created.
+type PriceSource interface {
+	// Returns the unique name of the source (e.g. "CoinGecko").
+	Name() string
+	// Prepares the source to quote prices in the given currency. Returns an
+	// error if the source is not configured (e.g. missing API key).
+	Initialize(currency string) error
+	// Fetches the latest price and volume of each symbol, keyed by symbol.
+	QueryMarketPrice(symbols []string) (map[string]p2p.ObservePricePoint, error)
+	// Clone returns a fresh, independent copy of this source.
+	// Used by New() to avoid sharing singleton state from the registry.
+	Clone() PriceSource
+}
+
+// sourceRegistry holds all registered price sources.
+// Built-in sources register themselves via RegisterSource() in init().
+var sourceRegistry = make(map[string]PriceSource)
+
+// RegisterSource registers a price source. Sources registered under the
+// same name replace the previous one.
+func RegisterSource(s PriceSource) {
+	sourceRegistry[s.Name()] = s
+}
+
+// RegisteredSources returns the names of all registered price sources.
+func RegisteredSources() map[string]struct{} {
+	result := make(map[string]struct{}, len(sourceRegistry))
+	for name := range sourceRegistry {
+		result[name] = struct{}{}
+	}
+	return result
+}
+
+// a single price point reported by a price source
+type sourceQuote struct {
+	source string
+	price  float64
+	volume float64
+}
+
+// groups the price points of each source by upper cased symbol
+func collectSourceQuotes(
+	bySource map[string]map[string]p2p.ObservePricePoint,
+) map[string][]sourceQuote {
+	out := make(map[string][]sourceQuote)
+
+	for src, pricePoints := range bySource {
+		for sym, pp := range pricePoints {
+			sym = strings.ToUpper(sym)
+			out[sym] = append(out[sym], sourceQuote{
+				source: src,
+				price:  pp.Price,
+				volume: pp.Volume,
+			})
+		}
+	}
+
+	return out
+}
+
+// aggregateQuotes drops the quotes deviating from the median price by more
+// than maxDeviation, then returns the volume weighted median price and the
+// median volume of the remaining quotes, along with the dropped sources.
+// Returns false if no quote is left to aggregate.
+func aggregateQuotes(
+	quotes []sourceQuote,
+	maxDeviation float64,
+) (p2p.ObservePricePoint, []string, bool) {
+	valid := make([]sourceQuote, 0, len(quotes))
+	for _, q := range quotes {
+		if q.price > 0 && q.volume >= 0 {
+			valid = append(valid, q)
+		}
+	}
+
+	if len(valid) == 0 {
+		return p2p.ObservePricePoint{}, nil, false
+	}
+
+	prices := make([]float64, len(valid))
+	for i, q := range valid {
+		prices[i] = q.price
+	}
+	median := getMedian(prices)
+
+	var (
+		kept    = make([]sourceQuote, 0, len(valid))
+		dropped = make([]string, 0)
+	)
+
+	for _, q := range valid {
+		if math.Abs(q.price-median) > median*maxDeviation {
+			dropped = append(dropped, q.source)
+		} else {
+			kept = append(kept, q)
+		}
+	}
+
+	// no quote is close enough to the median, e.g. two disagreeing sources
+	if len(kept) == 0 {
+		return p2p.ObservePricePoint{}, dropped, false
+	}
+
+	// sorting by source first keeps the result deterministic on equal prices
+	slices.SortFunc(kept, func(a, b sourceQuote) int {
+		if a.price != b.price {
+			if a.price < b.price {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.source, b.source)
+	})
+
+	volumes := make([]float64, len(kept))
+	for i, q := range kept {
+		volumes[i] = q.volume
+	}
+
+	out := p2p.ObservePricePoint{
+		Price:  weightedMedian(kept),
+		Volume: getMedian(volumes),
+	}
+
+	return out, dropped, true
+}
+
+// weightedMedian returns the volume weighted median of quotes sorted by
+// price. Quotes are weighted equally if none of them report a volume.
+func weightedMedian(sorted []sourceQuote) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	weights := make([]float64, len(sorted))
+	total := 0.0
+	for i, q := range sorted {
+		weights[i] = q.volume
+		total += q.volume
+	}
+
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = float64(len(weights))
+	}
+
+	cumulative := 0.0
+	for i, q := range sorted {
+		cumulative += weights[i]
+
+		if float64Eq(cumulative, total/2) && i+1 < len(sorted) {
+			// exactly half of the weight is on each side
+			return (q.price + sorted[i+1].price) / 2
+		}
+		if cumulative > total/2 {
+			return q.price
+		}
+	}
+
+	return sorted[len(sorted)-1].price
+}
@@ -0,0 +1,69 @@
+package price
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateQuotesDropsDeviatingSource(t *testing.T) {
+	quotes := []sourceQuote{
+		{source: "a", price: 100, volume: 10},
+		{source: "b", price: 101, volume: 30},
+		{source: "c", price: 150, volume: 1000},
+	}
+
+	pp, dropped, ok := aggregateQuotes(quotes, DefaultMaxSourceDeviation)
+	require.True(t, ok)
+	assert.Equal(t, []string{"c"}, dropped)
+	assert.Equal(t, 101.0, pp.Price)
+	assert.Equal(t, 20.0, pp.Volume)
+}
+
+func TestAggregateQuotesAllRejected(t *testing.T) {
+	quotes := []sourceQuote{
+		{source: "a", price: 0, volume: 10},
+		{source: "b", price: -1, volume: 10},
+		{source: "c", price: 100, volume: -1},
+	}
+
+	_, dropped, ok := aggregateQuotes(quotes, DefaultMaxSourceDeviation)
+	assert.False(t, ok)
+	assert.Empty(t, dropped)
+
+	_, _, ok = aggregateQuotes(nil, DefaultMaxSourceDeviation)
+	assert.False(t, ok)
+}
+
+func TestAggregateQuotesTwoSourcesDisagree(t *testing.T) {
+	// the median falls between both quotes, so neither is close enough to it
+	quotes := []sourceQuote{
+		{source: "a", price: 100, volume: 10},
+		{source: "b", price: 200, volume: 10},
+	}
+
+	_, dropped, ok := aggregateQuotes(quotes, DefaultMaxSourceDeviation)
+	assert.False(t, ok)
+	assert.ElementsMatch(t, []string{"a", "b"}, dropped)
+
+	pp, dropped, ok := aggregateQuotes(quotes, 0.5)
+	require.True(t, ok)
+	assert.Empty(t, dropped)
+	assert.Equal(t, 150.0, pp.Price)
+}
+
+func TestWeightedMedian(t *testing.T) {
+	assert.Equal(t, 0.0, weightedMedian(nil))
+
+	assert.Equal(t, 2.0, weightedMedian([]sourceQuote{
+		{price: 1, volume: 1},
+		{price: 2, volume: 5},
+		{price: 3, volume: 1},
+	}))
+
+	// no volume reported, quotes are weighted equally
+	assert.Equal(t, 2.5, weightedMedian([]sourceQuote{
+		{price: 1}, {price: 2}, {price: 3}, {price: 4},
+	}))
+}
@@ -0,0 +1,92 @@
+package threadsafe
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrLockedChannel = errors.New("channel locked")
+	ErrBufferFull    = errors.New("buffer full")
+)
+
+// CollectFunc is called for every value received during Collect, returning
+// true stops the collection early.
+type CollectFunc[T any] func(T) bool
+
+// LockedConsumer buffers values from concurrent producers, only accepting
+// them while a Collect call is in progress. Values consumed while the
+// consumer is locked are rejected with ErrLockedChannel.
+type LockedConsumer[T any] struct {
+	mtx    sync.Mutex
+	locked bool
+	buf    chan T
+}
+
+func NewLockedConsumer[T any](bufSize int) *LockedConsumer[T] {
+	return &LockedConsumer[T]{
+		locked: false,
+		buf:    make(chan T, bufSize),
+	}
+}
+
+// Lock rejects new values and drops the ones not yet collected.
+func (c *LockedConsumer[T]) Lock() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.locked = true
+	for {
+		select {
+		case <-c.buf:
+		default:
+			return
+		}
+	}
+}
+
+// Unlock accepts new values.
+func (c *LockedConsumer[T]) Unlock() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.locked = false
+}
+
+// Consume buffers v without blocking.
+func (c *LockedConsumer[T]) Consume(v T) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.locked {
+		return ErrLockedChannel
+	}
+
+	select {
+	case c.buf <- v:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+// Collect unlocks the consumer and passes every received value to fn until
+// fn returns true or ctx is done, the consumer is locked again on return.
+// The error of ctx is returned if fn never returned true.
+func (c *LockedConsumer[T]) Collect(ctx context.Context, fn CollectFunc[T]) error {
+	c.Unlock()
+	defer c.Lock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case v := <-c.buf:
+			if fn(v) {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package threadsafe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockedConsumer(t *testing.T) {
+	c := NewLockedConsumer[int](4)
+
+	// new consumers accept values until they are locked
+	assert.NoError(t, c.Consume(1))
+	c.Lock()
+	assert.ErrorIs(t, c.Consume(2), ErrLockedChannel)
+
+	go func() {
+		for i := 0; ; i++ {
+			if c.Consume(i) == nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// values consumed before Lock are dropped
+	var got []int
+	err := c.Collect(ctx, func(v int) bool {
+		got = append(got, v)
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.ErrorIs(t, c.Consume(3), ErrLockedChannel)
+}
+
+func TestLockedConsumerTimeout(t *testing.T) {
+	c := NewLockedConsumer[int](1)
+	c.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := c.Collect(ctx, func(int) bool { return true })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLockedConsumerFull(t *testing.T) {
+	c := NewLockedConsumer[int](1)
+
+	assert.NoError(t, c.Consume(1))
+	assert.ErrorIs(t, c.Consume(2), ErrBufferFull)
+}
+
+func TestMap(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Update(func(v map[string]int) { v["a"] = 1 })
+
+	snapshot := m.Get()
+	snapshot["b"] = 2
+	assert.Equal(t, map[string]int{"a": 1}, m.Get())
+
+	m.Clear()
+	assert.Empty(t, m.Get())
+}
@@ -0,0 +1,39 @@
+package threadsafe
+
+import (
+	"maps"
+	"sync"
+)
+
+// Map is a map guarded by a read/write mutex.
+type Map[K comparable, V any] struct {
+	rwLock sync.RWMutex
+	m      map[K]V
+}
+
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{m: make(map[K]V)}
+}
+
+// Get returns a copy of the map.
+func (m *Map[K, V]) Get() map[K]V {
+	m.rwLock.RLock()
+	defer m.rwLock.RUnlock()
+
+	return maps.Clone(m.m)
+}
+
+// Update calls fn with the underlying map while holding the write lock.
+func (m *Map[K, V]) Update(fn func(map[K]V)) {
+	m.rwLock.Lock()
+	defer m.rwLock.Unlock()
+
+	fn(m.m)
+}
+
+func (m *Map[K, V]) Clear() {
+	m.rwLock.Lock()
+	defer m.rwLock.Unlock()
+
+	clear(m.m)
+}
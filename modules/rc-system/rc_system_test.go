@@ -35,6 +35,18 @@ func (m *mockLedgerSession) ConsensusStake(c ledgerSystem.ConsensusParams) ledge
 func (m *mockLedgerSession) ConsensusUnstake(c ledgerSystem.ConsensusParams) ledgerSystem.LedgerResult {
 	return ledgerSystem.LedgerResult{}
 }
+func (m *mockLedgerSession) ScheduleTransfer(p ledgerSystem.ScheduledTransferParams) ledgerSystem.LedgerResult {
+	return ledgerSystem.LedgerResult{}
+}
+func (m *mockLedgerSession) CancelScheduledTransfer(p ledgerSystem.CancelScheduledTransferParams) ledgerSystem.LedgerResult {
+	return ledgerSystem.LedgerResult{}
+}
+func (m *mockLedgerSession) Mint(p ledgerSystem.TokenParams) ledgerSystem.LedgerResult {
+	return ledgerSystem.LedgerResult{}
+}
+func (m *mockLedgerSession) Burn(p ledgerSystem.TokenParams) ledgerSystem.LedgerResult {
+	return ledgerSystem.LedgerResult{}
+}
 func (m *mockLedgerSession) Done() []string   { return nil }
 func (m *mockLedgerSession) Revert()          {}
 
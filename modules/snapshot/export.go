@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"vsc-node/modules/common/common_types"
+	"vsc-node/modules/db/vsc"
+	"vsc-node/modules/db/vsc/elections"
+
+	"github.com/ipfs/go-cid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A collection included in snapshots
+type collectionSpec struct {
+	name string
+	//When set, only the latest document per key is kept
+	key string
+	//When set along with an empty key, only the latest document is kept
+	height string
+	//Filter given the activation height of the previous election.
+	//Ledger records older than that are folded into balances.
+	filter func(historyStart uint64) bson.M
+}
+
+// Everything needed to resume processing from the snapshot height.
+// Transactions, hive_blocks and node local collections are left out.
+var collections = []collectionSpec{
+	{name: "witnesses", key: "account", height: "height"},
+	{name: "elections"},
+	{name: "ledger_balances", key: "account", height: "block_height"},
+	{name: "ledger", filter: func(historyStart uint64) bson.M {
+		return bson.M{"block_height": bson.M{"$gt": historyStart}}
+	}},
+	{name: "ledger_actions", filter: func(historyStart uint64) bson.M {
+		return bson.M{"$or": bson.A{
+			bson.M{"status": "pending"},
+			bson.M{"block_height": bson.M{"$gt": historyStart}},
+		}}
+	}},
+	{name: "ledger_claims"},
+	{name: "rcs", key: "account", height: "block_height"},
+	{name: "nonces"},
+	{name: "account_authorities", key: "account", height: "block_height"},
+	{name: "contracts"},
+	{name: "contract_upgrades"},
+	{name: "contract_schedules"},
+	{name: "contract_state", key: "contract_id", height: "block_height"},
+	{name: "block_headers", height: "slot_height"},
+	{name: "tss_keys"},
+	{name: "tss_commitments"},
+	{name: "tss_requests"},
+	{name: "bridge_withdrawals"},
+}
+
+func (spec collectionSpec) find(ctx context.Context, d *vsc.VscDb, historyStart uint64) ([]bson.D, error) {
+	col := d.Collection(spec.name)
+	filter := bson.M{}
+	if spec.filter != nil {
+		filter = spec.filter(historyStart)
+	}
+
+	docs := make([]bson.D, 0)
+	switch {
+	case spec.key != "":
+		cursor, err := col.Aggregate(ctx, bson.A{
+			bson.M{"$match": filter},
+			bson.M{"$sort": bson.D{{Key: spec.height, Value: -1}, {Key: "_id", Value: -1}}},
+			bson.M{"$group": bson.M{"_id": "$" + spec.key, "doc": bson.M{"$first": "$$ROOT"}}},
+			bson.M{"$replaceRoot": bson.M{"newRoot": "$doc"}},
+		}, options.Aggregate().SetAllowDiskUse(true))
+		if err != nil {
+			return nil, err
+		}
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	case spec.height != "":
+		opts := options.Find().SetSort(bson.D{{Key: spec.height, Value: -1}}).SetLimit(1)
+		cursor, err := col.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	default:
+		cursor, err := col.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	}
+}
+
+// Exports the database into the datalayer and returns the manifest.
+// Must run while block processing is paused at height.
+// Reads the mongo collections, the badger backend does not export snapshots.
+func export(d *vsc.VscDb, da common_types.DataLayer, netId string, height uint64, election elections.ElectionResult, historyStart uint64) (Manifest, error) {
+	ctx := context.Background()
+
+	manifest := Manifest{
+		Version:     Version,
+		NetId:       netId,
+		Epoch:       election.Epoch,
+		BlockHeight: height,
+		Election:    election,
+		Collections: make([]Collection, 0, len(collections)),
+	}
+
+	for _, spec := range collections {
+		docs, err := spec.find(ctx, d, historyStart)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("%s: %w", spec.name, err)
+		}
+		chunks, err := encodeDocuments(docs)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("%s: %w", spec.name, err)
+		}
+
+		col := Collection{
+			Name:   spec.name,
+			Count:  uint64(len(docs)),
+			Chunks: make([]cid.Cid, 0, len(chunks)),
+		}
+		for _, chunk := range chunks {
+			chunkCid, err := da.PutRaw(chunk, common_types.PutRawOptions{Pin: true})
+			if err != nil {
+				return Manifest{}, fmt.Errorf("%s: %w", spec.name, err)
+			}
+			col.Chunks = append(col.Chunks, *chunkCid)
+		}
+		manifest.Collections = append(manifest.Collections, col)
+	}
+
+	return manifest, nil
+}
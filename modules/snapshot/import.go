@@ -0,0 +1,287 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"vsc-node/lib/dids"
+	"vsc-node/modules/common/common_types"
+	"vsc-node/modules/db/vsc/elections"
+	"vsc-node/modules/db/vsc/snapshots"
+
+	"github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	"github.com/polydawn/refmt"
+	"github.com/vsc-eco/hivego"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Documents inserted per round trip when loading a collection
+const insertBatchSize = 1000
+
+// Source of Hive blocks used to anchor the snapshot's election
+type BlockGetter interface {
+	GetBlock(blockNum int) (hivego.Block, error)
+}
+
+// Fetches a snapshot root and its manifest, and verifies the attestation
+func Fetch(da common_types.DataLayer, rootCid cid.Cid) (Root, Manifest, error) {
+	root := Root{}
+	if err := da.GetObject(rootCid, &root, common_types.GetOptions{}); err != nil {
+		return Root{}, Manifest{}, fmt.Errorf("failed to fetch snapshot root: %w", err)
+	}
+	manifest := Manifest{}
+	if err := da.GetObject(root.Manifest, &manifest, common_types.GetOptions{}); err != nil {
+		return Root{}, Manifest{}, fmt.Errorf("failed to fetch snapshot manifest: %w", err)
+	}
+	if err := Verify(root, manifest); err != nil {
+		return Root{}, Manifest{}, err
+	}
+	return root, manifest, nil
+}
+
+// The election signing a snapshot is part of the snapshot itself, so it is
+// anchored to the election result transaction on Hive. The transaction must
+// commit to the same election data, which in turn commits to the members
+// and weights. Like TxElectionResult, every election after the genesis one
+// must be signed by a quorum of the previous election.
+func verifyElection(client BlockGetter, election elections.ElectionResult, prev *elections.ElectionResult) error {
+	dataCid, err := elections.ElectionData{
+		ElectionCommonInfo: election.ElectionCommonInfo,
+		ElectionDataInfo:   election.ElectionDataInfo,
+	}.Cid()
+	if err != nil {
+		return err
+	}
+	if dataCid.String() != election.Data {
+		return fmt.Errorf("election members do not match election data %s", election.Data)
+	}
+
+	tx, err := findElectionTx(client, election)
+	if err != nil {
+		return err
+	}
+	if election.Epoch == 0 {
+		return nil
+	}
+	if prev == nil || prev.Epoch+1 != election.Epoch {
+		return fmt.Errorf("election %d is missing its previous election", election.Epoch)
+	}
+	if election.BlockHeight < prev.BlockHeight {
+		return fmt.Errorf("election %d is older than election %d", election.Epoch, prev.Epoch)
+	}
+
+	verifyHash, err := elections.ElectionHeader{
+		ElectionCommonInfo: elections.ElectionCommonInfo{
+			Epoch: tx.Epoch,
+			NetId: tx.NetId,
+			Type:  tx.Type,
+		},
+		ElectionHeaderInfo: elections.ElectionHeaderInfo{Data: tx.Data},
+	}.Cid()
+	if err != nil {
+		return err
+	}
+	circuit, err := dids.DeserializeBlsCircuit(tx.Signature, memberKeys(*prev), verifyHash)
+	if err != nil {
+		return fmt.Errorf("election %d: %w", election.Epoch, err)
+	}
+	verified, signers, err := circuit.Verify()
+	if err != nil {
+		return fmt.Errorf("election %d: %w", election.Epoch, err)
+	}
+	if !verified {
+		return fmt.Errorf("invalid signature on election %d", election.Epoch)
+	}
+
+	signed, total := signedWeight(*prev, signers)
+	minimum := elections.MinimalRequiredElectionVotes(election.BlockHeight-prev.BlockHeight, total)
+	if signed < minimum {
+		return fmt.Errorf("election %d signed by %d of %d weight of election %d, %d required", election.Epoch, signed, total, prev.Epoch, minimum)
+	}
+	return nil
+}
+
+// Election result as posted on Hive
+type electionTx struct {
+	Data      string                 `json:"data"`
+	Epoch     uint64                 `json:"epoch"`
+	NetId     string                 `json:"net_id"`
+	Type      string                 `json:"type"`
+	Signature dids.SerializedCircuit `json:"signature"`
+}
+
+// Finds the vsc.election_result operation of the election's transaction
+func findElectionTx(client BlockGetter, election elections.ElectionResult) (electionTx, error) {
+	blk, err := client.GetBlock(int(election.BlockHeight))
+	if err != nil {
+		return electionTx{}, fmt.Errorf("failed to fetch election block %d: %w", election.BlockHeight, err)
+	}
+	for idx, tx := range blk.Transactions {
+		if idx >= len(blk.TransactionIds) || blk.TransactionIds[idx] != election.TxId {
+			continue
+		}
+		for _, op := range tx.Operations {
+			if op.Type != "custom_json" && op.Type != "custom_json_operation" {
+				continue
+			}
+			if op.Value["id"] != "vsc.election_result" {
+				continue
+			}
+			jsonStr, _ := op.Value["json"].(string)
+			result := electionTx{}
+			if json.Unmarshal([]byte(jsonStr), &result) != nil {
+				continue
+			}
+			if result.Data == election.Data && result.Epoch == election.Epoch && result.NetId == election.NetId {
+				return result, nil
+			}
+		}
+	}
+	return electionTx{}, fmt.Errorf("election %d not found in transaction %s of block %d", election.Epoch, election.TxId, election.BlockHeight)
+}
+
+// Walks the snapshot's elections from the genesis election up to the
+// snapshot's election, verifying each one against the previous one. The
+// genesis election is trusted once anchored, as the state engine does.
+func verifyElectionChain(client BlockGetter, election elections.ElectionResult, docs []bson.D) error {
+	byEpoch, err := decodeElections(docs)
+	if err != nil {
+		return err
+	}
+	stored, ok := byEpoch[election.Epoch]
+	if !ok || stored.Data != election.Data || stored.TxId != election.TxId {
+		return fmt.Errorf("snapshot election %d does not match its elections collection", election.Epoch)
+	}
+
+	var prev *elections.ElectionResult
+	for epoch := uint64(0); epoch <= election.Epoch; epoch++ {
+		current, ok := byEpoch[epoch]
+		if !ok {
+			return fmt.Errorf("snapshot is missing election %d", epoch)
+		}
+		if epoch == election.Epoch {
+			current = election
+		}
+		if err := verifyElection(client, current, prev); err != nil {
+			return err
+		}
+		if epoch%100 == 0 {
+			log.Verbose("verified elections", "epoch", epoch, "of", election.Epoch)
+		}
+		prev = &current
+	}
+	return nil
+}
+
+// Decodes election records as stored by elections.StoreElection
+func decodeElections(docs []bson.D) (map[uint64]elections.ElectionResult, error) {
+	out := make(map[uint64]elections.ElectionResult, len(docs))
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		record := elections.ElectionResultRecord{}
+		if err := bson.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("invalid election record: %w", err)
+		}
+		result := elections.ElectionResult{}
+		if err := refmt.CloneAtlased(record, &result, cbornode.CborAtlas); err != nil {
+			return nil, fmt.Errorf("invalid election record: %w", err)
+		}
+		out[result.Epoch] = result
+	}
+	return out, nil
+}
+
+// Imports a snapshot into an empty node and returns its manifest
+func (s *snapshotManager) importSnapshot(rootCid cid.Cid) (Manifest, error) {
+	ctx := context.Background()
+
+	lastProcessed, err := s.hiveBlocks.GetLastProcessedBlock()
+	if err != nil {
+		return Manifest{}, err
+	}
+	if lastProcessed > 0 {
+		return Manifest{}, fmt.Errorf("node has already processed blocks up to %d, snapshots can only be imported into an empty node", lastProcessed)
+	}
+
+	root, manifest, err := Fetch(s.da, rootCid)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if manifest.NetId != s.sconf.NetId() {
+		return Manifest{}, fmt.Errorf("snapshot is for network %s", manifest.NetId)
+	}
+	//Fetch everything before touching the database
+	docs := make(map[string][]bson.D, len(manifest.Collections))
+	for _, col := range manifest.Collections {
+		known := slices.ContainsFunc(collections, func(spec collectionSpec) bool {
+			return spec.name == col.Name
+		})
+		if !known {
+			return Manifest{}, fmt.Errorf("snapshot contains unknown collection %s", col.Name)
+		}
+
+		colDocs := make([]bson.D, 0, col.Count)
+		for _, chunkCid := range col.Chunks {
+			chunk, err := s.da.GetRaw(chunkCid)
+			if err != nil {
+				return Manifest{}, fmt.Errorf("failed to fetch %s chunk %s: %w", col.Name, chunkCid, err)
+			}
+			chunkDocs, err := decodeChunk(chunk)
+			if err != nil {
+				return Manifest{}, fmt.Errorf("%s: %w", col.Name, err)
+			}
+			colDocs = append(colDocs, chunkDocs...)
+		}
+		if uint64(len(colDocs)) != col.Count {
+			return Manifest{}, fmt.Errorf("%s: expected %d documents, got %d", col.Name, col.Count, len(colDocs))
+		}
+		docs[col.Name] = colDocs
+		log.Verbose("fetched collection", "name", col.Name, "count", col.Count)
+	}
+
+	if err := verifyElectionChain(s.hiveClient, manifest.Election, docs["elections"]); err != nil {
+		return Manifest{}, err
+	}
+	log.Info("snapshot verified", "cid", rootCid, "epoch", manifest.Epoch, "height", manifest.BlockHeight)
+
+	for _, col := range manifest.Collections {
+		dbCol := s.vscDb.Collection(col.Name)
+		if _, err := dbCol.DeleteMany(ctx, bson.M{}); err != nil {
+			return Manifest{}, err
+		}
+		colDocs := docs[col.Name]
+		for len(colDocs) > 0 {
+			batch := colDocs[:min(insertBatchSize, len(colDocs))]
+			colDocs = colDocs[len(batch):]
+
+			insert := make([]interface{}, 0, len(batch))
+			for _, doc := range batch {
+				insert = append(insert, doc)
+			}
+			if _, err := dbCol.InsertMany(ctx, insert); err != nil {
+				return Manifest{}, fmt.Errorf("failed to load %s: %w", col.Name, err)
+			}
+		}
+	}
+
+	if err := s.hiveBlocks.StoreLastProcessedBlock(manifest.BlockHeight); err != nil {
+		return Manifest{}, err
+	}
+	err = s.snapshots.StoreSnapshot(snapshots.SnapshotRecord{
+		Cid:         rootCid.String(),
+		Manifest:    root.Manifest.String(),
+		Epoch:       manifest.Epoch,
+		BlockHeight: manifest.BlockHeight,
+		NetId:       manifest.NetId,
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
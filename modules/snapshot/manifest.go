@@ -0,0 +1,212 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"vsc-node/lib/dids"
+	"vsc-node/modules/db/vsc/elections"
+
+	"github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	mh "github.com/multiformats/go-multihash"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Format version of the manifest and chunk encoding
+const Version = 1
+
+// Upper bound of a chunk, kept below the bitswap block size limit
+var MaxChunkSize = 1024 * 1024
+
+// Describes the node state as of the end of a Hive block
+type Manifest struct {
+	Version     int    `refmt:"v"`
+	NetId       string `refmt:"net_id"`
+	Epoch       uint64 `refmt:"epoch"`
+	BlockHeight uint64 `refmt:"block_height"`
+	//Election whose members attest to the snapshot
+	Election    elections.ElectionResult `refmt:"election"`
+	Collections []Collection             `refmt:"collections"`
+}
+
+// Documents of a database collection, as sorted canonical extended JSON
+// lines split into raw blocks
+type Collection struct {
+	Name   string    `refmt:"name"`
+	Count  uint64    `refmt:"count"`
+	Chunks []cid.Cid `refmt:"chunks"`
+}
+
+// Signed snapshot root. Its CID is what nodes import from.
+type Root struct {
+	Manifest cid.Cid `refmt:"manifest"`
+	//BLS circuit of the election over the manifest CID
+	Signature string `refmt:"sig"`
+	BitVector string `refmt:"bv"`
+}
+
+func init() {
+	cbornode.RegisterCborType(Manifest{})
+	cbornode.RegisterCborType(Collection{})
+	cbornode.RegisterCborType(Root{})
+}
+
+func (m Manifest) Cid() (cid.Cid, error) {
+	node, err := cbornode.WrapObject(m, mh.SHA2_256, -1)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return node.Cid(), nil
+}
+
+func (r Root) Circuit() dids.SerializedCircuit {
+	return dids.SerializedCircuit{Signature: r.Signature, BitVector: r.BitVector}
+}
+
+// Member keys of an election, in election order
+func memberKeys(election elections.ElectionResult) []dids.BlsDID {
+	keys := make([]dids.BlsDID, 0, len(election.Members))
+	for _, member := range election.Members {
+		keys = append(keys, dids.BlsDID(member.Key))
+	}
+	return keys
+}
+
+// Weight of the signers and of the whole election.
+// Elections without weights count every member equally.
+func signedWeight(election elections.ElectionResult, signers []dids.BlsDID) (signed uint64, total uint64) {
+	for idx, member := range election.Members {
+		weight := uint64(1)
+		if len(election.Weights) > idx {
+			weight = election.Weights[idx]
+		}
+		total += weight
+		if slices.Contains(signers, dids.BlsDID(member.Key)) {
+			signed += weight
+		}
+	}
+	return signed, total
+}
+
+// Mirrors TxProposeBlock.Validate: more than 2/3 of the election weight must sign
+func hasQuorum(signed uint64, total uint64) bool {
+	return signed > (total*2)/3
+}
+
+// Verifies the root is signed by the quorum of the manifest's election
+func Verify(root Root, manifest Manifest) error {
+	if manifest.Version != Version {
+		return fmt.Errorf("unsupported snapshot version %d", manifest.Version)
+	}
+	manifestCid, err := manifest.Cid()
+	if err != nil {
+		return err
+	}
+	if !manifestCid.Equals(root.Manifest) {
+		return fmt.Errorf("snapshot root signs manifest %s, not %s", root.Manifest, manifestCid)
+	}
+	if manifest.Election.Epoch != manifest.Epoch {
+		return fmt.Errorf("snapshot epoch %d does not match election epoch %d", manifest.Epoch, manifest.Election.Epoch)
+	}
+
+	circuit, err := dids.DeserializeBlsCircuit(root.Circuit(), memberKeys(manifest.Election), root.Manifest)
+	if err != nil {
+		return err
+	}
+	verified, signers, err := circuit.Verify()
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return errors.New("invalid snapshot signature")
+	}
+
+	signed, total := signedWeight(manifest.Election, signers)
+	if !hasQuorum(signed, total) {
+		return fmt.Errorf("snapshot signed by %d of %d election weight", signed, total)
+	}
+	return nil
+}
+
+// Encodes documents as canonical extended JSON lines, sorted so every node
+// produces the same chunks for the same state. Mongo _ids are node local
+// and dropped.
+func encodeDocuments(docs []bson.D) ([][]byte, error) {
+	lines := make([][]byte, 0, len(docs))
+	for _, doc := range docs {
+		doc = slices.DeleteFunc(slices.Clone(doc), func(e bson.E) bool {
+			return e.Key == "_id"
+		})
+		line, err := bson.MarshalExtJSON(canonical(doc), true, false)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return bytes.Compare(lines[i], lines[j]) < 0
+	})
+	return chunkLines(lines), nil
+}
+
+// Groups newline terminated lines into chunks of at most MaxChunkSize.
+// A line larger than that gets a chunk of its own.
+func chunkLines(lines [][]byte) [][]byte {
+	chunks := make([][]byte, 0)
+	var chunk []byte
+	for _, line := range lines {
+		if len(chunk) > 0 && len(chunk)+len(line)+1 > MaxChunkSize {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+		chunk = append(chunk, line...)
+		chunk = append(chunk, '\n')
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func decodeChunk(chunk []byte) ([]bson.D, error) {
+	docs := make([]bson.D, 0)
+	for _, line := range bytes.Split(chunk, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		doc := bson.D{}
+		if err := bson.UnmarshalExtJSON(line, true, &doc); err != nil {
+			return nil, fmt.Errorf("invalid snapshot document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// Sorts document keys recursively. Documents written through bson.M have
+// no stable key order, so the same record may differ between nodes.
+func canonical(v interface{}) interface{} {
+	switch v := v.(type) {
+	case primitive.D:
+		out := make(primitive.D, 0, len(v))
+		for _, e := range v {
+			out = append(out, primitive.E{Key: e.Key, Value: canonical(e.Value)})
+		}
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].Key < out[j].Key
+		})
+		return out
+	case primitive.A:
+		out := make(primitive.A, 0, len(v))
+		for _, item := range v {
+			out = append(out, canonical(item))
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,99 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	libp2p "vsc-node/modules/p2p"
+	"weak"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+type p2pSpec struct {
+	manager weak.Pointer[snapshotManager]
+}
+
+type p2pMessage struct {
+	Type    string `json:"type"`
+	Version string `json:"v"`
+	Data    string `json:"data"`
+}
+
+// BLS signature of an election member over a snapshot manifest
+type signatureMsg struct {
+	Epoch    uint64 `json:"epoch"`
+	Manifest string `json:"manifest"`
+	Account  string `json:"account"`
+	Sig      string `json:"sig"`
+}
+
+var _ libp2p.PubSubServiceParams[p2pMessage] = p2pSpec{}
+
+func (s *snapshotManager) startP2P() error {
+	var err error
+	s.service, err = libp2p.NewPubSubService(s.p2p, p2pSpec{weak.Make(s)})
+	return err
+}
+
+func (s *snapshotManager) stopP2P() error {
+	if s.service == nil {
+		return nil
+	}
+	return s.service.Close()
+}
+
+func (s *snapshotManager) sendSignature(sig signatureMsg) error {
+	data, err := json.Marshal(sig)
+	if err != nil {
+		return err
+	}
+	return s.service.Send(p2pMessage{
+		Type:    "signature",
+		Version: "1",
+		Data:    string(data),
+	})
+}
+
+// ValidateMessage implements libp2p.PubSubServiceParams.
+func (s p2pSpec) ValidateMessage(ctx context.Context, from peer.ID, msg *pubsub.Message, parsedMsg p2pMessage) bool {
+	return parsedMsg.Type == "signature"
+}
+
+// HandleMessage implements libp2p.PubSubServiceParams.
+func (s p2pSpec) HandleMessage(ctx context.Context, from peer.ID, msg p2pMessage, send libp2p.SendFunc[p2pMessage]) error {
+	manager := s.manager.Value()
+	if manager == nil {
+		return nil
+	}
+
+	var sig signatureMsg
+	if err := json.Unmarshal([]byte(msg.Data), &sig); err != nil {
+		return nil
+	}
+	manager.addSignature(sig)
+	return nil
+}
+
+// HandleRawMessage implements libp2p.PubSubServiceParams.
+func (p2pSpec) HandleRawMessage(ctx context.Context, rawMsg *pubsub.Message, send libp2p.SendFunc[p2pMessage]) error {
+	return nil
+}
+
+// ParseMessage implements libp2p.PubSubServiceParams.
+func (s p2pSpec) ParseMessage(data []byte) (p2pMessage, error) {
+	msg := p2pMessage{}
+	err := json.Unmarshal(data, &msg)
+	return msg, err
+}
+
+// SerializeMessage implements libp2p.PubSubServiceParams.
+func (s p2pSpec) SerializeMessage(msg p2pMessage) []byte {
+	jsonBytes, _ := json.Marshal(msg)
+	return jsonBytes
+}
+
+// Topic implements libp2p.PubSubServiceParams.
+func (p2pSpec) Topic() string {
+	return "/snapshot/v1"
+}
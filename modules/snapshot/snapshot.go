@@ -0,0 +1,362 @@
+// State snapshots for fast syncing new nodes.
+//
+// At every SnapshotInterval-th election, live nodes export the state as of
+// the first block after the election where the state engine holds no
+// unflushed state. The export is deterministic, so every honest node ends
+// up with the same manifest CID. Election members sign it and gossip the
+// signatures until more than 2/3 of the election weight has signed, at which
+// point the signed root is stored in the datalayer and its CID logged.
+//
+// A new node started with --snapshot <cid> verifies the signature against
+// the snapshot's election, walks the snapshot's elections back to genesis,
+// anchoring each to its Hive transaction and checking it was signed by a
+// quorum of the previous election, loads the collections and resumes
+// streaming after the snapshot height.
+//
+// Snapshots read and write the Mongo collections directly, so they are
+// neither exported nor imported on the embedded kv backend.
+package snapshot
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"vsc-node/lib/dids"
+	"vsc-node/lib/utils"
+	"vsc-node/lib/vsclog"
+	a "vsc-node/modules/aggregate"
+	"vsc-node/modules/common"
+	"vsc-node/modules/common/common_types"
+	systemconfig "vsc-node/modules/common/system-config"
+	"vsc-node/modules/db/vsc"
+	"vsc-node/modules/db/vsc/elections"
+	"vsc-node/modules/db/vsc/hive_blocks"
+	"vsc-node/modules/db/vsc/snapshots"
+	blockconsumer "vsc-node/modules/hive/block-consumer"
+	libp2p "vsc-node/modules/p2p"
+	start_status "vsc-node/modules/start-status"
+	stateEngine "vsc-node/modules/state-processing"
+
+	"github.com/chebyrash/promise"
+	"github.com/ipfs/go-cid"
+)
+
+var log = vsclog.Module("snapshot")
+
+var (
+	// snapshot every n-th election, 0 disables snapshots
+	SnapshotInterval = uint64(4)
+	// nodes further behind the head than this don't take snapshots
+	MaxSnapshotLag = uint64(100)
+	// signatures buffered for snapshots this node hasn't exported yet
+	maxPendingSignatures = 256
+)
+
+type snapshotManager struct {
+	sconf systemconfig.SystemConfig
+	conf  common.IdentityConfig
+
+	p2p     *libp2p.P2PServer
+	service libp2p.PubSubService[p2pMessage]
+
+	vscDb      *vsc.VscDb
+	da         common_types.DataLayer
+	elections  elections.Elections
+	hiveBlocks hive_blocks.HiveBlocks
+	snapshots  snapshots.Snapshots
+
+	hiveConsumer *blockconsumer.HiveConsumer
+	se           *stateEngine.StateEngine
+	hiveClient   BlockGetter
+
+	importCid   string
+	streamStart *uint64
+	startStatus start_status.StartStatus
+
+	mu        sync.Mutex
+	lastEpoch uint64
+	current   *attestation
+	pending   []signatureMsg
+}
+
+// Signatures collected for a snapshot exported by this node
+type attestation struct {
+	manifest    Manifest
+	manifestCid cid.Cid
+	circuit     dids.PartialBlsCircuit
+	done        bool
+}
+
+type SnapshotManager = *snapshotManager
+
+var _ a.Plugin = &snapshotManager{}
+var _ start_status.Starter = &snapshotManager{}
+
+// When importCid is set, the snapshot is imported on start before later
+// plugins start, and streamStart is moved past the snapshot height.
+func New(
+	sconf systemconfig.SystemConfig,
+	conf common.IdentityConfig,
+	p2p *libp2p.P2PServer,
+	vscDb *vsc.VscDb,
+	da common_types.DataLayer,
+	elections elections.Elections,
+	hiveBlocks hive_blocks.HiveBlocks,
+	snapshots snapshots.Snapshots,
+	hiveConsumer *blockconsumer.HiveConsumer,
+	se *stateEngine.StateEngine,
+	hiveClient BlockGetter,
+	importCid string,
+	streamStart *uint64,
+) SnapshotManager {
+	return &snapshotManager{
+		sconf:        sconf,
+		conf:         conf,
+		p2p:          p2p,
+		vscDb:        vscDb,
+		da:           da,
+		elections:    elections,
+		hiveBlocks:   hiveBlocks,
+		snapshots:    snapshots,
+		hiveConsumer: hiveConsumer,
+		se:           se,
+		hiveClient:   hiveClient,
+		importCid:    importCid,
+		streamStart:  streamStart,
+		startStatus:  start_status.New(),
+	}
+}
+
+// Init implements aggregate.Plugin.
+func (s *snapshotManager) Init() error {
+	if s.importCid != "" {
+		if _, err := cid.Parse(s.importCid); err != nil {
+			return fmt.Errorf("invalid snapshot cid: %w", err)
+		}
+		if s.vscDb.Kv() != nil {
+			return fmt.Errorf("snapshot import is not supported on the badger db backend")
+		}
+	}
+	if s.vscDb.Kv() != nil {
+		log.Info("snapshot export disabled on the badger db backend")
+	}
+	s.hiveConsumer.RegisterBlockTick("snapshot", s.blockTick, false)
+	return nil
+}
+
+// Start implements aggregate.Plugin.
+func (s *snapshotManager) Start() *promise.Promise[any] {
+	if latest, err := s.snapshots.GetLatestSnapshot(); err == nil {
+		s.lastEpoch = latest.Epoch
+	}
+
+	err := s.startP2P()
+	if err != nil {
+		s.startStatus.TriggerStartFailure(err)
+		return utils.PromiseReject[any](err)
+	}
+
+	if s.importCid == "" {
+		s.startStatus.TriggerStart()
+		return utils.PromiseResolve[any](nil)
+	}
+
+	return promise.New(func(resolve func(any), reject func(error)) {
+		rootCid, _ := cid.Parse(s.importCid)
+		log.Info("importing snapshot", "cid", rootCid)
+		manifest, err := s.importSnapshot(rootCid)
+		if err != nil {
+			err = fmt.Errorf("snapshot import failed: %w", err)
+			s.startStatus.TriggerStartFailure(err)
+			reject(err)
+			return
+		}
+		s.lastEpoch = manifest.Epoch
+		if s.streamStart != nil {
+			*s.streamStart = manifest.BlockHeight + 1
+		}
+		log.Info("snapshot imported", "epoch", manifest.Epoch, "height", manifest.BlockHeight)
+		s.startStatus.TriggerStart()
+		resolve(nil)
+	})
+}
+
+// Started implements start_status.Starter.
+func (s *snapshotManager) Started() *promise.Promise[any] {
+	return s.startStatus.Started()
+}
+
+// Stop implements aggregate.Plugin.
+func (s *snapshotManager) Stop() error {
+	return s.stopP2P()
+}
+
+// Runs before bh is processed, so the database holds the state as of bh-1
+func (s *snapshotManager) blockTick(bh uint64, headHeight *uint64) {
+	if SnapshotInterval == 0 || bh == 0 || s.vscDb.Kv() != nil {
+		return
+	}
+	if headHeight == nil || *headHeight > bh+MaxSnapshotLag {
+		return
+	}
+
+	height := bh - 1
+	election, err := s.elections.GetElectionByHeight(height)
+	if err != nil || election.Epoch == 0 || election.Epoch%SnapshotInterval != 0 {
+		return
+	}
+	if election.Epoch <= s.lastEpoch || !s.se.Quiescent() {
+		return
+	}
+	s.lastEpoch = election.Epoch
+
+	var historyStart uint64
+	if prev := s.elections.GetElection(election.Epoch - 1); prev != nil {
+		historyStart = prev.BlockHeight
+	}
+
+	manifest, err := export(s.vscDb, s.da, s.sconf.NetId(), height, election, historyStart)
+	if err != nil {
+		log.Warn("snapshot export failed", "epoch", election.Epoch, "height", height, "err", err)
+		return
+	}
+	manifestCid, err := s.da.PutObject(manifest)
+	if err != nil {
+		log.Warn("failed to store snapshot manifest", "epoch", election.Epoch, "err", err)
+		return
+	}
+	log.Info("snapshot exported", "epoch", election.Epoch, "height", height, "manifest", manifestCid)
+
+	go s.attest(manifest, *manifestCid)
+}
+
+// Signs the manifest when this node is an election member and starts
+// collecting the signatures of the other members
+func (s *snapshotManager) attest(manifest Manifest, manifestCid cid.Cid) {
+	circuit, err := dids.NewBlsCircuitGenerator(memberKeys(manifest.Election)).Generate(manifestCid)
+	if err != nil {
+		log.Warn("failed to create snapshot circuit", "epoch", manifest.Epoch, "err", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.current = &attestation{
+		manifest:    manifest,
+		manifestCid: manifestCid,
+		circuit:     circuit,
+	}
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	for _, msg := range pending {
+		s.addSignature(msg)
+	}
+
+	account := s.conf.Get().HiveUsername
+	isMember := false
+	for _, member := range manifest.Election.Members {
+		if member.Account == account {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return
+	}
+
+	sig, err := s.conf.Signer().SignCid(manifestCid)
+	if err != nil {
+		log.Warn("failed to sign snapshot", "epoch", manifest.Epoch, "err", err)
+		return
+	}
+	msg := signatureMsg{
+		Epoch:    manifest.Epoch,
+		Manifest: manifestCid.String(),
+		Account:  account,
+		Sig:      base64.URLEncoding.EncodeToString(sig[:]),
+	}
+	s.addSignature(msg)
+	if err := s.sendSignature(msg); err != nil {
+		log.Warn("failed to broadcast snapshot signature", "epoch", manifest.Epoch, "err", err)
+	}
+}
+
+// Adds a member signature and stores the signed root once the quorum is reached
+func (s *snapshotManager) addSignature(msg signatureMsg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	att := s.current
+	if att == nil || msg.Epoch > att.manifest.Epoch {
+		//Peers may export before this node does
+		if len(s.pending) < maxPendingSignatures {
+			s.pending = append(s.pending, msg)
+		}
+		return
+	}
+	if att.done || msg.Epoch != att.manifest.Epoch {
+		return
+	}
+	if msg.Manifest != att.manifestCid.String() {
+		log.Debug("signature for a different snapshot manifest", "epoch", msg.Epoch, "account", msg.Account, "manifest", msg.Manifest)
+		return
+	}
+
+	var key dids.BlsDID
+	for _, member := range att.manifest.Election.Members {
+		if member.Account == msg.Account {
+			key = dids.BlsDID(member.Key)
+			break
+		}
+	}
+	if key == "" {
+		return
+	}
+	sig, err := base64.URLEncoding.DecodeString(msg.Sig)
+	if err != nil {
+		return
+	}
+	if _, err := att.circuit.AddAndVerifyRaw(key, sig); err != nil {
+		log.Debug("invalid snapshot signature", "epoch", msg.Epoch, "account", msg.Account, "err", err)
+		return
+	}
+
+	signed, total := signedWeight(att.manifest.Election, att.circuit.Signers())
+	if !hasQuorum(signed, total) {
+		return
+	}
+	att.done = true
+
+	circuit, err := att.circuit.Finalize()
+	if err != nil {
+		log.Warn("failed to finalize snapshot signature", "epoch", msg.Epoch, "err", err)
+		return
+	}
+	serialized, err := circuit.Serialize()
+	if err != nil {
+		log.Warn("failed to serialize snapshot signature", "epoch", msg.Epoch, "err", err)
+		return
+	}
+
+	rootCid, err := s.da.PutObject(Root{
+		Manifest:  att.manifestCid,
+		Signature: serialized.Signature,
+		BitVector: serialized.BitVector,
+	})
+	if err != nil {
+		log.Warn("failed to store snapshot root", "epoch", msg.Epoch, "err", err)
+		return
+	}
+	err = s.snapshots.StoreSnapshot(snapshots.SnapshotRecord{
+		Cid:         rootCid.String(),
+		Manifest:    att.manifestCid.String(),
+		Epoch:       att.manifest.Epoch,
+		BlockHeight: att.manifest.BlockHeight,
+		NetId:       att.manifest.NetId,
+	})
+	if err != nil {
+		log.Warn("failed to record snapshot", "epoch", msg.Epoch, "err", err)
+	}
+	log.Info("snapshot attested", "cid", rootCid, "epoch", att.manifest.Epoch, "height", att.manifest.BlockHeight, "weight", signed, "total_weight", total)
+}
@@ -0,0 +1,333 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"vsc-node/lib/dids"
+	"vsc-node/modules/db/vsc/elections"
+
+	"github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/polydawn/refmt"
+	ethBls "github.com/protolambda/bls12-381-util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vsc-eco/hivego"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type member struct {
+	account  string
+	did      dids.BlsDID
+	provider dids.BlsProvider
+}
+
+func newMember(t *testing.T, account string) member {
+	var seed [32]byte
+	copy(seed[:], account)
+	privKey := dids.BlsPrivKey{}
+	privKey.Deserialize(&seed)
+	pubKey, err := ethBls.SkToPk(&privKey)
+	require.NoError(t, err)
+	did, err := dids.NewBlsDID(pubKey)
+	require.NoError(t, err)
+	provider, err := dids.NewBlsProvider(&privKey)
+	require.NoError(t, err)
+	return member{account, did, provider}
+}
+
+func testElection(t *testing.T, members []member) elections.ElectionResult {
+	election := testElectionAt(t, members, 8, 1000)
+	election.TxId = "c0ffee"
+	return election
+}
+
+func testElectionAt(t *testing.T, members []member, epoch uint64, height uint64) elections.ElectionResult {
+	election := elections.ElectionResult{
+		ElectionCommonInfo: elections.ElectionCommonInfo{Epoch: epoch, NetId: "vsc-mocknet", Type: "staked"},
+		ElectionDataInfo:   elections.ElectionDataInfo{Weights: []uint64{}},
+		BlockHeight:        height,
+		TxId:               fmt.Sprintf("tx%d", epoch),
+	}
+	for _, m := range members {
+		election.Members = append(election.Members, elections.ElectionMember{Key: m.did.String(), Account: m.account})
+		election.Weights = append(election.Weights, 10)
+	}
+	dataCid, err := elections.ElectionData{
+		ElectionCommonInfo: election.ElectionCommonInfo,
+		ElectionDataInfo:   election.ElectionDataInfo,
+	}.Cid()
+	require.NoError(t, err)
+	election.Data = dataCid.String()
+	return election
+}
+
+func testManifest(t *testing.T, election elections.ElectionResult) (Manifest, cid.Cid) {
+	chunk, err := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: mh.SHA2_256, MhLength: -1}.Sum([]byte("{}\n"))
+	require.NoError(t, err)
+	manifest := Manifest{
+		Version:     Version,
+		NetId:       election.NetId,
+		Epoch:       election.Epoch,
+		BlockHeight: 1234,
+		Election:    election,
+		Collections: []Collection{{Name: "nonces", Count: 1, Chunks: []cid.Cid{chunk}}},
+	}
+	node, err := cbornode.WrapObject(manifest, mh.SHA2_256, -1)
+	require.NoError(t, err)
+
+	decoded := Manifest{}
+	require.NoError(t, cbornode.DecodeInto(node.RawData(), &decoded))
+	require.Equal(t, manifest, decoded)
+	return manifest, node.Cid()
+}
+
+func sign(t *testing.T, members []member, signers []member, msg cid.Cid) Root {
+	keys := make([]dids.BlsDID, 0, len(members))
+	for _, m := range members {
+		keys = append(keys, m.did)
+	}
+	circuit, err := dids.NewBlsCircuitGenerator(keys).Generate(msg)
+	require.NoError(t, err)
+	for _, m := range signers {
+		sig, err := m.provider.SignRaw(msg)
+		require.NoError(t, err)
+		_, err = circuit.AddAndVerifyRaw(m.did, sig[:])
+		require.NoError(t, err)
+	}
+	finalized, err := circuit.Finalize()
+	require.NoError(t, err)
+	serialized, err := finalized.Serialize()
+	require.NoError(t, err)
+	return Root{Manifest: msg, Signature: serialized.Signature, BitVector: serialized.BitVector}
+}
+
+func TestEncodeDocumentsIsDeterministic(t *testing.T) {
+	a := []bson.D{
+		{{Key: "_id", Value: 1}, {Key: "account", Value: "hive:bob"}, {Key: "nonce", Value: int64(2)}},
+		{{Key: "_id", Value: 2}, {Key: "nonce", Value: int64(5)}, {Key: "account", Value: "hive:alice"},
+			{Key: "meta", Value: bson.D{{Key: "z", Value: 1}, {Key: "a", Value: bson.A{bson.D{{Key: "y", Value: 1}, {Key: "x", Value: 2}}}}}}},
+	}
+	b := []bson.D{
+		{{Key: "meta", Value: bson.D{{Key: "a", Value: bson.A{bson.D{{Key: "x", Value: 2}, {Key: "y", Value: 1}}}}, {Key: "z", Value: 1}}},
+			{Key: "account", Value: "hive:alice"}, {Key: "_id", Value: 7}, {Key: "nonce", Value: int64(5)}},
+		{{Key: "nonce", Value: int64(2)}, {Key: "account", Value: "hive:bob"}, {Key: "_id", Value: 8}},
+	}
+
+	chunksA, err := encodeDocuments(a)
+	require.NoError(t, err)
+	chunksB, err := encodeDocuments(b)
+	require.NoError(t, err)
+	require.Equal(t, chunksA, chunksB)
+	require.Len(t, chunksA, 1)
+
+	docs, err := decodeChunk(chunksA[0])
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	for _, doc := range docs {
+		for _, e := range doc {
+			assert.NotEqual(t, "_id", e.Key)
+		}
+	}
+	assert.Equal(t, "hive:alice", docs[0].Map()["account"])
+	assert.Equal(t, int64(5), docs[0].Map()["nonce"])
+}
+
+func TestEncodeDocumentsChunks(t *testing.T) {
+	defer func(size int) { MaxChunkSize = size }(MaxChunkSize)
+	MaxChunkSize = 256
+
+	docs := make([]bson.D, 0)
+	for i := 0; i < 100; i++ {
+		docs = append(docs, bson.D{{Key: "account", Value: fmt.Sprintf("hive:user%03d", i)}, {Key: "nonce", Value: int64(i)}})
+	}
+	chunks, err := encodeDocuments(docs)
+	require.NoError(t, err)
+	require.Greater(t, len(chunks), 1)
+
+	count := 0
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), MaxChunkSize)
+		decoded, err := decodeChunk(chunk)
+		require.NoError(t, err)
+		count += len(decoded)
+	}
+	assert.Equal(t, len(docs), count)
+}
+
+func TestVerify(t *testing.T) {
+	members := []member{
+		newMember(t, "alice"),
+		newMember(t, "bob"),
+		newMember(t, "carol"),
+		newMember(t, "dave"),
+	}
+	election := testElection(t, members)
+	manifest, manifestCid := testManifest(t, election)
+
+	require.NoError(t, Verify(sign(t, members, members[:3], manifestCid), manifest))
+
+	err := Verify(sign(t, members, members[:2], manifestCid), manifest)
+	assert.ErrorContains(t, err, "signed by 20 of 40")
+
+	_, otherCid := testManifest(t, testElection(t, members[:3]))
+	assert.Error(t, Verify(sign(t, members, members, otherCid), manifest))
+
+	//Signatures over the manifest don't carry over to another manifest
+	forged := sign(t, members, members, manifestCid)
+	forged.Manifest = otherCid
+	assert.Error(t, Verify(forged, manifest))
+
+	unsupported := manifest
+	unsupported.Version = Version + 1
+	assert.Error(t, Verify(sign(t, members, members, manifestCid), unsupported))
+}
+
+type mockBlocks map[int]hivego.Block
+
+func (m mockBlocks) GetBlock(blockNum int) (hivego.Block, error) {
+	blk, ok := m[blockNum]
+	if !ok {
+		return hivego.Block{}, fmt.Errorf("block %d not found", blockNum)
+	}
+	return blk, nil
+}
+
+// Circuit of the previous election's signers over the election header
+func signElection(t *testing.T, prevMembers []member, signers []member, election elections.ElectionResult) dids.SerializedCircuit {
+	header, err := elections.ElectionHeader{
+		ElectionCommonInfo: election.ElectionCommonInfo,
+		ElectionHeaderInfo: election.ElectionHeaderInfo,
+	}.Cid()
+	require.NoError(t, err)
+	root := sign(t, prevMembers, signers, header)
+	return root.Circuit()
+}
+
+func electionBlock(t *testing.T, election elections.ElectionResult, sig dids.SerializedCircuit) hivego.Block {
+	payload, err := json.Marshal(map[string]interface{}{
+		"data":      election.Data,
+		"epoch":     election.Epoch,
+		"net_id":    election.NetId,
+		"type":      election.Type,
+		"signature": sig,
+	})
+	require.NoError(t, err)
+	return hivego.Block{
+		Transactions: []hivego.Transaction{
+			{Operations: []hivego.Operation{{Type: "transfer_operation", Value: map[string]interface{}{}}}},
+			{Operations: []hivego.Operation{{Type: "custom_json_operation", Value: map[string]interface{}{
+				"id":   "vsc.election_result",
+				"json": string(payload),
+			}}}},
+		},
+		TransactionIds: []string{"deadbeef", election.TxId},
+	}
+}
+
+// Election record as exported into a snapshot
+func electionDoc(t *testing.T, election elections.ElectionResult) bson.D {
+	record := elections.ElectionResultRecord{}
+	require.NoError(t, refmt.CloneAtlased(election, &record, cbornode.CborAtlas))
+	raw, err := bson.Marshal(record)
+	require.NoError(t, err)
+	doc := bson.D{}
+	require.NoError(t, bson.Unmarshal(raw, &doc))
+	chunks, err := encodeDocuments([]bson.D{doc})
+	require.NoError(t, err)
+	docs, err := decodeChunk(chunks[0])
+	require.NoError(t, err)
+	return docs[0]
+}
+
+func TestVerifyElection(t *testing.T) {
+	members := []member{newMember(t, "alice"), newMember(t, "bob")}
+	prev := testElectionAt(t, members, 7, 900)
+	election := testElection(t, members)
+	blocks := mockBlocks{
+		int(prev.BlockHeight):     electionBlock(t, prev, dids.SerializedCircuit{}),
+		int(election.BlockHeight): electionBlock(t, election, signElection(t, members, members, election)),
+	}
+
+	require.NoError(t, verifyElection(blocks, election, &prev))
+
+	//Members not committed to by the election data
+	tampered := election
+	tampered.Members = append([]elections.ElectionMember{}, election.Members...)
+	tampered.Members[1].Key = newMember(t, "mallory").did.String()
+	assert.ErrorContains(t, verifyElection(blocks, tampered, &prev), "do not match")
+
+	//Election data not posted on Hive
+	unanchored := testElection(t, members[:1])
+	unanchored.BlockHeight = election.BlockHeight
+	assert.ErrorContains(t, verifyElection(blocks, unanchored, &prev), "not found")
+
+	wrongTx := election
+	wrongTx.TxId = "deadbeef"
+	assert.Error(t, verifyElection(blocks, wrongTx, &prev))
+
+	//Anchoring alone doesn't prove an election after the genesis one
+	assert.ErrorContains(t, verifyElection(blocks, election, nil), "missing its previous election")
+
+	blocks[int(election.BlockHeight)] = electionBlock(t, election, signElection(t, members, members[:1], election))
+	assert.ErrorContains(t, verifyElection(blocks, election, &prev), "signed by 10 of 20")
+
+	//Signed by keys that weren't elected
+	outsiders := []member{newMember(t, "mallory"), newMember(t, "trent")}
+	blocks[int(election.BlockHeight)] = electionBlock(t, election, signElection(t, outsiders, outsiders, election))
+	assert.Error(t, verifyElection(blocks, election, &prev))
+}
+
+func TestVerifyElectionChain(t *testing.T) {
+	members := []member{newMember(t, "alice"), newMember(t, "bob"), newMember(t, "carol")}
+	next := append(slices.Clone(members), newMember(t, "dave"))
+
+	genesis := testElectionAt(t, members, 0, 100)
+	first := testElectionAt(t, next, 1, 200)
+	second := testElectionAt(t, next, 2, 300)
+	blocks := mockBlocks{
+		100: electionBlock(t, genesis, dids.SerializedCircuit{}),
+		200: electionBlock(t, first, signElection(t, members, members[:2], first)),
+		300: electionBlock(t, second, signElection(t, next, next[1:], second)),
+	}
+	docs := []bson.D{electionDoc(t, genesis), electionDoc(t, first), electionDoc(t, second)}
+
+	require.NoError(t, verifyElectionChain(blocks, second, docs))
+	require.NoError(t, verifyElectionChain(blocks, first, docs))
+
+	assert.ErrorContains(t, verifyElectionChain(blocks, second, []bson.D{docs[0], docs[2]}), "missing election 1")
+
+	other := second
+	other.TxId = "c0ffee"
+	assert.ErrorContains(t, verifyElectionChain(blocks, other, docs), "does not match")
+
+	//A self signed election can be anchored on Hive but doesn't chain up
+	outsiders := []member{newMember(t, "mallory"), newMember(t, "trent")}
+	forged := testElectionAt(t, outsiders, 2, 300)
+	blocks[300] = electionBlock(t, forged, signElection(t, outsiders, outsiders, forged))
+	assert.Error(t, verifyElectionChain(blocks, forged, []bson.D{docs[0], docs[1], electionDoc(t, forged)}))
+
+	//Not enough of the previous election signed
+	blocks[200] = electionBlock(t, first, signElection(t, members, members[:1], first))
+	assert.ErrorContains(t, verifyElectionChain(blocks, first, docs), "election 1 signed by 10 of 30")
+}
+
+func TestSignedWeight(t *testing.T) {
+	members := []member{newMember(t, "alice"), newMember(t, "bob"), newMember(t, "carol")}
+	election := testElection(t, members)
+	election.Weights = []uint64{5, 1, 1}
+
+	signed, total := signedWeight(election, []dids.BlsDID{members[0].did})
+	assert.Equal(t, uint64(5), signed)
+	assert.Equal(t, uint64(7), total)
+	assert.True(t, hasQuorum(signed, total))
+
+	election.Weights = nil
+	signed, total = signedWeight(election, []dids.BlsDID{members[0].did, members[1].did})
+	assert.Equal(t, uint64(2), signed)
+	assert.Equal(t, uint64(3), total)
+	assert.False(t, hasQuorum(signed, total))
+}
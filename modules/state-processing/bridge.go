@@ -0,0 +1,169 @@
+package state_engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/bridge"
+	tss_db "vsc-node/modules/db/vsc/tss"
+	ledgerSystem "vsc-node/modules/ledger-system"
+)
+
+// Fields of a withdrawal covered by the chain key signature
+type bridgeWithdrawalPayload struct {
+	Id      string `json:"id"`
+	Chain   string `json:"chain"`
+	Asset   string `json:"asset"`
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+}
+
+// Hex digest the chain key signs to release a withdrawal
+func bridgeWithdrawalMsg(w bridge.Withdrawal) string {
+	payload, _ := json.Marshal(bridgeWithdrawalPayload{
+		Id:      w.Id,
+		Chain:   w.Chain,
+		Asset:   w.Asset,
+		Address: w.To[strings.LastIndex(w.To, ":")+1:],
+		Amount:  w.Amount,
+	})
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:])
+}
+
+// Queues the withdrawals of bridged assets in an ingested oplog and requests
+// their signature from the chain key. Withdrawals to chains without a key stay
+// pending until keyBridgeWithdrawals picks them up. Below the bridge height they
+// are left to the gateway wallet.
+func (se *StateEngine) queueBridgeWithdrawals(oplog []ledgerSystem.OpLogEvent, blockHeight uint64) {
+	if blockHeight < se.sconf.ConsensusParams().BridgeHeight {
+		return
+	}
+	for _, op := range oplog {
+		chain := ledgerSystem.BridgeChain(op.Asset)
+		if op.Type != "withdraw" || chain == "" {
+			continue
+		}
+
+		w := bridge.Withdrawal{
+			Id:            op.Id,
+			From:          op.From,
+			To:            op.To,
+			Chain:         chain,
+			Asset:         op.Asset,
+			Amount:        op.Amount,
+			Memo:          op.Memo,
+			KeyId:         se.sconf.OracleParams().BridgeKeyId(chain),
+			Status:        bridge.WithdrawalPending,
+			BlockHeight:   blockHeight,
+			UpdatedHeight: blockHeight,
+		}
+		if w.KeyId != "" {
+			w.Msg = bridgeWithdrawalMsg(w)
+		}
+
+		if err := se.bridgeDb.InsertWithdrawal(w); err != nil {
+			log.Warn("failed to queue bridge withdrawal", "id", w.Id, "err", err)
+			continue
+		}
+		if w.KeyId != "" {
+			se.requestBridgeSignature(w)
+		}
+	}
+}
+
+// Requests the signature of withdrawals queued before their chain had a key,
+// once the key is configured. Oldest withdrawals are keyed first.
+func (se *StateEngine) keyBridgeWithdrawals(blockHeight uint64) {
+	bridgeKeys := se.sconf.OracleParams().BridgeKeys
+	for _, chain := range slices.Sorted(maps.Keys(bridgeKeys)) {
+		keyId := bridgeKeys[chain]
+		if keyId == "" {
+			continue
+		}
+		withdrawals, err := se.bridgeDb.FindUnkeyed(chain, params.BRIDGE_KEY_MAX_PER_BLOCK)
+		if err != nil {
+			log.Warn("failed to find unkeyed bridge withdrawals", "chain", chain, "err", err)
+			continue
+		}
+		for _, w := range withdrawals {
+			w.KeyId = keyId
+			w.Msg = bridgeWithdrawalMsg(w)
+			w.UpdatedHeight = blockHeight
+			if err := se.bridgeDb.UpdateWithdrawal(w); err != nil {
+				log.Warn("failed to key bridge withdrawal", "id", w.Id, "err", err)
+				continue
+			}
+			se.requestBridgeSignature(w)
+		}
+	}
+}
+
+func (se *StateEngine) requestBridgeSignature(w bridge.Withdrawal) {
+	se.tssRequests.SetSignedRequest(tss_db.TssRequest{
+		KeyId:  w.KeyId,
+		Msg:    w.Msg,
+		Status: tss_db.SignPending,
+	})
+}
+
+// Marks the pending withdrawals covered by a verified signature as signed
+func (se *StateEngine) markBridgeSigned(keyId string, msg string, sig string, blockHeight uint64) {
+	withdrawals, err := se.bridgeDb.FindBySignRequest(keyId, msg)
+	if err != nil {
+		log.Warn("failed to find bridge withdrawals", "keyId", keyId, "err", err)
+		return
+	}
+	for _, w := range withdrawals {
+		if w.Status != bridge.WithdrawalPending {
+			continue
+		}
+		w.Status = bridge.WithdrawalSigned
+		w.Sig = sig
+		w.UpdatedHeight = blockHeight
+		se.bridgeDb.UpdateWithdrawal(w)
+	}
+}
+
+// Applies broadcast and confirmation reports of the gateway wallet.
+// Confirmed withdrawals complete their ledger action.
+func (se *StateEngine) updateBridgeStatus(payload []byte, blockHeight uint64, txId string) {
+	report := struct {
+		Updates []struct {
+			Id        string `json:"id"`
+			Status    string `json:"status"`
+			ChainTxId string `json:"chain_tx_id"`
+		} `json:"updates"`
+	}{}
+	if err := json.Unmarshal(payload, &report); err != nil {
+		log.Warn("vsc.bridge_status parse error", "txId", txId, "err", err)
+		return
+	}
+
+	for _, update := range report.Updates {
+		w, err := se.bridgeDb.GetWithdrawal(update.Id)
+		if err != nil {
+			log.Warn("unknown bridge withdrawal", "id", update.Id, "txId", txId)
+			continue
+		}
+		if !bridge.CanAdvance(w.Status, update.Status) {
+			log.Warn("invalid bridge withdrawal status", "id", w.Id, "from", w.Status, "to", update.Status)
+			continue
+		}
+
+		w.Status = update.Status
+		if update.ChainTxId != "" {
+			w.ChainTxId = update.ChainTxId
+		}
+		w.UpdatedHeight = blockHeight
+		se.bridgeDb.UpdateWithdrawal(w)
+
+		if w.Status == bridge.WithdrawalConfirmed {
+			se.LedgerState.ActionDb.ExecuteComplete(&txId, w.Id)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package state_engine_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"vsc-node/modules/db/vsc/bridge"
+	tss_db "vsc-node/modules/db/vsc/tss"
+	ledgerSystem "vsc-node/modules/ledger-system"
+	stateEngine "vsc-node/modules/state-processing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bridgeStatus(te *testEnv, auth string, id string, status string, chainTxId string) {
+	payload, _ := json.Marshal(map[string]any{
+		"updates": []map[string]string{
+			{"id": id, "status": status, "chain_tx_id": chainTxId},
+		},
+	})
+	te.Creator.CustomJson(stateEngine.MockJson{
+		RequiredAuths: []string{auth},
+		Id:            "vsc.bridge_status",
+		Json:          string(payload),
+	})
+	te.processAndWait()
+}
+
+func TestBridgeWithdrawalLifecycle(t *testing.T) {
+	te := newTestEnv()
+
+	overrides := filepath.Join(t.TempDir(), "sysconfig.json")
+	require.NoError(t, os.WriteFile(overrides, []byte(`{"oracleParams":{"bridgeKeys":{"ETH":"bridge-eth"}}}`), 0o644))
+	require.NoError(t, te.SE.SystemConfig().LoadOverrides(overrides))
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	te.TssKeys.Keys["bridge-eth"] = tss_db.TssKey{
+		Id:        "bridge-eth",
+		Status:    tss_db.TssKeyActive,
+		Algo:      tss_db.EddsaType,
+		PublicKey: hex.EncodeToString(pub),
+	}
+
+	withdrawal := ledgerSystem.OpLogEvent{
+		Id:     "withdraw-tx-0",
+		From:   "hive:alice",
+		To:     "eth:0x00000000000000000000000000000000000000aa",
+		Amount: 5000,
+		Asset:  "usdc",
+		Type:   "withdraw",
+	}
+	hiveWithdrawal := ledgerSystem.OpLogEvent{
+		Id:     "withdraw-tx-1",
+		From:   "hive:alice",
+		To:     "hive:alice",
+		Amount: 10,
+		Asset:  "hbd",
+		Type:   "withdraw",
+	}
+	oplog := stateEngine.Oplog{
+		Self:      stateEngine.TxSelf{BlockHeight: 100},
+		LedgerOps: []ledgerSystem.OpLogEvent{withdrawal, hiveWithdrawal},
+		EndBlock:  100,
+	}
+	oplog.ExecuteTx(te.SE)
+
+	require.Len(t, te.BridgeDb.Withdrawals, 1)
+	record := te.BridgeDb.Withdrawals["withdraw-tx-0"]
+	assert.Equal(t, "ETH", record.Chain)
+	assert.Equal(t, "bridge-eth", record.KeyId)
+	assert.Equal(t, bridge.WithdrawalPending, record.Status)
+	require.NotEmpty(t, record.Msg)
+
+	requests, _ := te.TssRequests.FindRequests("bridge-eth", []string{record.Msg})
+	require.Len(t, requests, 1)
+
+	//Broadcast reports are only accepted once signed
+	bridgeStatus(te, "vsc.mocknet", record.Id, bridge.WithdrawalBroadcast, "0xabc")
+	assert.Equal(t, bridge.WithdrawalPending, te.BridgeDb.Withdrawals[record.Id].Status)
+
+	msg, _ := hex.DecodeString(record.Msg)
+	signed, _ := json.Marshal(map[string]any{
+		"packet": []map[string]string{{
+			"key_id": "bridge-eth",
+			"msg":    record.Msg,
+			"sig":    hex.EncodeToString(ed25519.Sign(priv, msg)),
+		}},
+	})
+	te.Creator.CustomJson(stateEngine.MockJson{
+		RequiredAuths: []string{"vsc.mocknet"},
+		Id:            "vsc.tss_sign",
+		Json:          string(signed),
+	})
+	te.processAndWait()
+	assert.Equal(t, bridge.WithdrawalSigned, te.BridgeDb.Withdrawals[record.Id].Status)
+
+	//Only the gateway wallet reports progress on the external chain
+	bridgeStatus(te, "alice", record.Id, bridge.WithdrawalBroadcast, "0xabc")
+	assert.Equal(t, bridge.WithdrawalSigned, te.BridgeDb.Withdrawals[record.Id].Status)
+
+	bridgeStatus(te, "vsc.mocknet", record.Id, bridge.WithdrawalBroadcast, "0xabc")
+	assert.Equal(t, bridge.WithdrawalBroadcast, te.BridgeDb.Withdrawals[record.Id].Status)
+	assert.Equal(t, "0xabc", te.BridgeDb.Withdrawals[record.Id].ChainTxId)
+
+	bridgeStatus(te, "vsc.mocknet", record.Id, bridge.WithdrawalConfirmed, "")
+	final := te.BridgeDb.Withdrawals[record.Id]
+	assert.Equal(t, bridge.WithdrawalConfirmed, final.Status)
+	assert.Equal(t, "0xabc", final.ChainTxId)
+	assert.Equal(t, "complete", te.ActionsDb.Actions[record.Id].Status)
+}
+
+func TestBridgeWithdrawalKeyedLater(t *testing.T) {
+	te := newTestEnv()
+
+	oplog := stateEngine.Oplog{
+		Self: stateEngine.TxSelf{BlockHeight: 100},
+		LedgerOps: []ledgerSystem.OpLogEvent{{
+			Id:     "withdraw-tx-0",
+			From:   "hive:alice",
+			To:     "eth:0x00000000000000000000000000000000000000aa",
+			Amount: 5000,
+			Asset:  "usdc",
+			Type:   "withdraw",
+		}},
+		EndBlock: 100,
+	}
+	oplog.ExecuteTx(te.SE)
+
+	record := te.BridgeDb.Withdrawals["withdraw-tx-0"]
+	assert.Equal(t, bridge.WithdrawalPending, record.Status)
+	assert.Empty(t, record.KeyId)
+	assert.Empty(t, record.Msg)
+
+	//Withdrawals queued before the chain had a key are signed once it gets one
+	overrides := filepath.Join(t.TempDir(), "sysconfig.json")
+	require.NoError(t, os.WriteFile(overrides, []byte(`{"oracleParams":{"bridgeKeys":{"ETH":"bridge-eth"}}}`), 0o644))
+	require.NoError(t, te.SE.SystemConfig().LoadOverrides(overrides))
+	te.processAndWait()
+
+	record = te.BridgeDb.Withdrawals["withdraw-tx-0"]
+	assert.Equal(t, "bridge-eth", record.KeyId)
+	require.NotEmpty(t, record.Msg)
+	requests, _ := te.TssRequests.FindRequests("bridge-eth", []string{record.Msg})
+	require.Len(t, requests, 1)
+}
+
+func TestBridgeWithdrawalHeight(t *testing.T) {
+	te := newTestEnv()
+
+	overrides := filepath.Join(t.TempDir(), "sysconfig.json")
+	require.NoError(t, os.WriteFile(overrides, []byte(`{"consensusParams":{"bridgeHeight":200},"oracleParams":{"bridgeKeys":{"ETH":"bridge-eth"}}}`), 0o644))
+	require.NoError(t, te.SE.SystemConfig().LoadOverrides(overrides))
+
+	withdraw := func(id string, height uint64) {
+		oplog := stateEngine.Oplog{
+			Self: stateEngine.TxSelf{BlockHeight: height},
+			LedgerOps: []ledgerSystem.OpLogEvent{{
+				Id:     id,
+				From:   "hive:alice",
+				To:     "eth:0x00000000000000000000000000000000000000aa",
+				Amount: 5000,
+				Asset:  "usdc",
+				Type:   "withdraw",
+			}},
+			EndBlock: height,
+		}
+		oplog.ExecuteTx(te.SE)
+	}
+
+	//Withdrawals below the bridge height are left to the gateway wallet
+	withdraw("withdraw-tx-0", 199)
+	assert.Empty(t, te.BridgeDb.Withdrawals)
+
+	withdraw("withdraw-tx-1", 200)
+	assert.Contains(t, te.BridgeDb.Withdrawals, "withdraw-tx-1")
+}
@@ -0,0 +1,99 @@
+package state_engine
+
+import (
+	"encoding/json"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
+)
+
+// Applies the schedule changes a contract made in one of its calls
+func (se *StateEngine) applyScheduleOps(contractId string, ops []contracts.ScheduleOp, self TxSelf) {
+	for _, op := range ops {
+		switch op.Type {
+		case contracts.ScheduleOpCreate:
+			active, err := se.contractSchedules.CountActiveSchedules(contractId)
+			if err != nil || active >= params.CONTRACT_SCHEDULE_MAX_ACTIVE {
+				log.Debug("dropping contract schedule", "contract", contractId, "id", op.Id, "active", active, "err", err)
+				continue
+			}
+			se.contractSchedules.InsertSchedule(contracts.ContractSchedule{
+				Id:            op.Id,
+				ContractId:    contractId,
+				Action:        op.Action,
+				Payload:       op.Payload,
+				Interval:      op.Interval,
+				RcLimit:       min(op.RcLimit, params.CONTRACT_SCHEDULE_MAX_RC_LIMIT),
+				Status:        contracts.ScheduleActive,
+				NextHeight:    op.Height,
+				CreatedHeight: self.BlockHeight,
+				UpdatedHeight: self.BlockHeight,
+			})
+		case contracts.ScheduleOpCancel:
+			schedule, err := se.contractSchedules.GetSchedule(op.Id)
+			//Contracts may only cancel their own schedules
+			if err != nil || schedule.ContractId != contractId || schedule.Status != contracts.ScheduleActive {
+				continue
+			}
+			schedule.Status = contracts.ScheduleCancelled
+			schedule.UpdatedHeight = self.BlockHeight
+			se.contractSchedules.SetSchedule(schedule)
+		}
+	}
+}
+
+// Queues the calls of schedules due at the block. Calls run under the
+// contract account, which pays their RCs out of its HBD balance. Schedules
+// whose account cannot cover the RC limit of a run are marked failed.
+func (se *StateEngine) runContractSchedules(block hive_blocks.HiveBlock) {
+	due, err := se.contractSchedules.FindDueSchedules(block.BlockNumber, params.CONTRACT_SCHEDULE_MAX_PER_BLOCK)
+	if err != nil {
+		log.Warn("failed to find due contract schedules", "height", block.BlockNumber, "err", err)
+		return
+	}
+
+	//RCs set aside for the runs queued in this block
+	reserved := make(map[string]int64)
+	for _, schedule := range due {
+		account := "contract:" + schedule.ContractId
+		available := se.RcSystem.GetAvailableRCs(account, block.BlockNumber) - se.RcMap[account] - reserved[account]
+		if available < int64(schedule.RcLimit) {
+			schedule.Status = contracts.ScheduleFailed
+			schedule.UpdatedHeight = block.BlockNumber
+			se.contractSchedules.SetSchedule(schedule)
+			continue
+		}
+		reserved[account] += int64(schedule.RcLimit)
+
+		schedule.Runs++
+		schedule.LastRunHeight = block.BlockNumber
+		schedule.UpdatedHeight = block.BlockNumber
+		if schedule.Interval > 0 {
+			schedule.NextHeight = block.BlockNumber + schedule.Interval
+		} else {
+			schedule.Status = contracts.ScheduleCompleted
+		}
+		se.contractSchedules.SetSchedule(schedule)
+
+		payload, _ := json.Marshal(schedule.Payload)
+		txId := MakeTxId(schedule.Id, int(schedule.Runs))
+		se.TxBatch = append(se.TxBatch, TxPacket{
+			TxId:  txId,
+			Payer: account,
+			Ops: []VSCTransaction{TxVscCallContract{
+				Self: TxSelf{
+					TxId:          txId,
+					BlockId:       block.BlockID,
+					BlockHeight:   block.BlockNumber,
+					Timestamp:     block.Timestamp,
+					RequiredAuths: []string{account},
+				},
+				NetId:      se.sconf.NetId(),
+				ContractId: schedule.ContractId,
+				Action:     schedule.Action,
+				Payload:    payload,
+				RcLimit:    schedule.RcLimit,
+			}},
+		})
+	}
+}
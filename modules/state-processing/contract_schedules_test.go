@@ -0,0 +1,153 @@
+package state_engine_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/contracts"
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
+	stateEngine "vsc-node/modules/state-processing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Scheduled calls waiting in the batch by transaction ID
+func scheduledCalls(te *testEnv) map[string]stateEngine.TxVscCallContract {
+	calls := make(map[string]stateEngine.TxVscCallContract)
+	for _, packet := range te.SE.TxBatch {
+		for _, op := range packet.Ops {
+			if call, ok := op.(stateEngine.TxVscCallContract); ok && packet.Payer == "contract:"+call.ContractId {
+				calls[packet.TxId] = call
+			}
+		}
+	}
+	return calls
+}
+
+func ingestScheduleOps(te *testEnv, contractId string, ops ...contracts.ScheduleOp) {
+	output := stateEngine.ContractOutput{
+		Id:         "output-" + contractId,
+		ContractId: contractId,
+		Results:    []contracts.ContractOutputResult{{Ok: true, Schedules: ops}},
+	}
+	output.Ingest(te.SE, stateEngine.TxSelf{TxId: "block-tx", BlockHeight: te.Reader.LastBlock}, 0)
+}
+
+// Gives the contract account HBD to pay its RCs with
+func fundContract(te *testEnv, contractId string, hbd int64) {
+	account := "contract:" + contractId
+	te.BalanceDb.BalanceRecords[account] = []ledgerDb.BalanceRecord{{Account: account, HBD: hbd}}
+}
+
+func TestContractSchedules(t *testing.T) {
+	te := newTestEnv()
+	te.ContractDb.RegisterContract("contract-1", contracts.Contract{Owner: "hive:alice"})
+	fundContract(te, "contract-1", 1_000_000)
+
+	height := te.Reader.LastBlock
+	ingestScheduleOps(te, "contract-1",
+		contracts.ScheduleOp{Type: contracts.ScheduleOpCreate, Id: "tx1:1", Action: "settle", Payload: `{"auction":1}`, Height: height + 2, RcLimit: 500},
+		contracts.ScheduleOp{Type: contracts.ScheduleOpCreate, Id: "tx1:2", Action: "accrue", Height: height + 1, Interval: 2, RcLimit: 1_000_000},
+	)
+	require.Len(t, te.SchedulesDb.Schedules, 2)
+	assert.Equal(t, contracts.ScheduleActive, te.SchedulesDb.Schedules["tx1:1"].Status)
+	assert.Equal(t, "contract-1", te.SchedulesDb.Schedules["tx1:1"].ContractId)
+	assert.Less(t, te.SchedulesDb.Schedules["tx1:2"].RcLimit, uint(1_000_000))
+
+	//Other contracts cannot cancel the schedules
+	ingestScheduleOps(te, "contract-2", contracts.ScheduleOp{Type: contracts.ScheduleOpCancel, Id: "tx1:2"})
+	assert.Equal(t, contracts.ScheduleActive, te.SchedulesDb.Schedules["tx1:2"].Status)
+
+	te.processAndWait()
+	calls := scheduledCalls(te)
+	require.Len(t, calls, 1)
+	accrue := calls["tx1:2-1"]
+	assert.Equal(t, "accrue", accrue.Action)
+	assert.Equal(t, []string{"contract:contract-1"}, accrue.Self.RequiredAuths)
+	assert.Equal(t, height+1, accrue.Self.BlockHeight)
+	assert.Equal(t, height+3, te.SchedulesDb.Schedules["tx1:2"].NextHeight)
+
+	te.processAndWait()
+	calls = scheduledCalls(te)
+	require.Len(t, calls, 2)
+	settle := calls["tx1:1-1"]
+	assert.Equal(t, "settle", settle.Action)
+	assert.Equal(t, uint(500), settle.RcLimit)
+	payload := ""
+	require.NoError(t, json.Unmarshal(settle.Payload, &payload))
+	assert.Equal(t, `{"auction":1}`, payload)
+	assert.Equal(t, contracts.ScheduleCompleted, te.SchedulesDb.Schedules["tx1:1"].Status)
+
+	te.processAndWait()
+	calls = scheduledCalls(te)
+	require.Len(t, calls, 3)
+	assert.Contains(t, calls, "tx1:2-2")
+	assert.Equal(t, uint64(2), te.SchedulesDb.Schedules["tx1:2"].Runs)
+	assert.Equal(t, height+3, te.SchedulesDb.Schedules["tx1:2"].LastRunHeight)
+
+	//Only the owner may cancel through a transaction
+	cancel := func(auth string) {
+		te.Creator.CustomJson(stateEngine.MockJson{
+			RequiredAuths: []string{auth},
+			Id:            "vsc.cancel_contract_schedule",
+			Json:          `{"net_id":"` + te.SE.SystemConfig().NetId() + `","id":"tx1:2"}`,
+		})
+		te.processAndWait()
+	}
+	cancel("bob")
+	assert.Equal(t, contracts.ScheduleActive, te.SchedulesDb.Schedules["tx1:2"].Status)
+	wrongNet := stateEngine.TxCancelContractSchedule{
+		Self:  stateEngine.TxSelf{TxId: "cancel-tx", BlockHeight: te.Reader.LastBlock, RequiredAuths: []string{"hive:alice"}},
+		NetId: "vsc-othernet",
+		Id:    "tx1:2",
+	}
+	assert.Equal(t, "wrong net ID", wrongNet.ExecuteTx(te.SE).Ret)
+	assert.Equal(t, contracts.ScheduleActive, te.SchedulesDb.Schedules["tx1:2"].Status)
+	cancel("alice")
+	assert.Equal(t, contracts.ScheduleCancelled, te.SchedulesDb.Schedules["tx1:2"].Status)
+
+	runs := te.SchedulesDb.Schedules["tx1:2"].Runs
+	te.processAndWait()
+	te.processAndWait()
+	assert.Equal(t, runs, te.SchedulesDb.Schedules["tx1:2"].Runs)
+}
+
+func TestContractScheduleLimits(t *testing.T) {
+	te := newTestEnv()
+	te.ContractDb.RegisterContract("contract-1", contracts.Contract{Owner: "hive:alice"})
+	fundContract(te, "contract-1", 1_500)
+
+	height := te.Reader.LastBlock
+	ops := make([]contracts.ScheduleOp, 0, params.CONTRACT_SCHEDULE_MAX_ACTIVE+1)
+	for i := range params.CONTRACT_SCHEDULE_MAX_ACTIVE + 1 {
+		ops = append(ops, contracts.ScheduleOp{
+			Type:    contracts.ScheduleOpCreate,
+			Id:      "tx1:" + strconv.Itoa(i),
+			Action:  "tick",
+			Height:  height + 1 + uint64(i),
+			RcLimit: 1_000,
+		})
+	}
+	ingestScheduleOps(te, "contract-1", ops...)
+
+	//Schedules beyond the cap are dropped
+	require.Len(t, te.SchedulesDb.Schedules, params.CONTRACT_SCHEDULE_MAX_ACTIVE)
+	assert.NotContains(t, te.SchedulesDb.Schedules, "tx1:"+strconv.Itoa(params.CONTRACT_SCHEDULE_MAX_ACTIVE))
+
+	te.processAndWait()
+	require.Contains(t, scheduledCalls(te), "tx1:0-1")
+	assert.Equal(t, contracts.ScheduleCompleted, te.SchedulesDb.Schedules["tx1:0"].Status)
+
+	//The account cannot cover another run once the first used its RCs
+	te.SE.RcMap["contract:contract-1"] += 1_000
+	te.processAndWait()
+	assert.NotContains(t, scheduledCalls(te), "tx1:1-1")
+	assert.Equal(t, contracts.ScheduleFailed, te.SchedulesDb.Schedules["tx1:1"].Status)
+	assert.Zero(t, te.SchedulesDb.Schedules["tx1:1"].Runs)
+
+	//Failed schedules free up room for new ones
+	ingestScheduleOps(te, "contract-1", contracts.ScheduleOp{Type: contracts.ScheduleOpCreate, Id: "tx2:1", Action: "tick", Height: height + 500, RcLimit: 1_000})
+	assert.Contains(t, te.SchedulesDb.Schedules, "tx2:1")
+}
@@ -0,0 +1,62 @@
+package state_engine
+
+import (
+	"encoding/json"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
+)
+
+// Queues a call to the migrate entrypoint of freshly activated code, run by
+// the owner so it can reach the state left by the old code
+func (se *StateEngine) queueContractMigration(self TxSelf, contractId string, owner string) {
+	self.RequiredAuths = []string{owner}
+	self.RequiredPostingAuths = nil
+	payload, _ := json.Marshal("")
+
+	se.TxBatch = append(se.TxBatch, TxPacket{
+		TxId: self.TxId,
+		Ops: []VSCTransaction{TxVscCallContract{
+			Self:       self,
+			NetId:      se.sconf.NetId(),
+			ContractId: contractId,
+			Action:     "migrate",
+			Payload:    payload,
+			RcLimit:    params.CONTRACT_MIGRATE_RC_LIMIT,
+		}},
+	})
+}
+
+// Swaps in the code of timelocked upgrades whose delay has passed
+func (se *StateEngine) activateContractUpgrades(block hive_blocks.HiveBlock) {
+	due, err := se.contractUpgrades.FindDueUpgrades(block.BlockNumber)
+	if err != nil {
+		log.Warn("failed to find due contract upgrades", "height", block.BlockNumber, "err", err)
+		return
+	}
+
+	for _, upgrade := range due {
+		existing, err := se.contractDb.ContractById(upgrade.ContractId, block.BlockNumber)
+		if err != nil {
+			log.Warn("contract of upgrade not found", "contractId", upgrade.ContractId, "txId", upgrade.TxId)
+			continue
+		}
+
+		activated := existing
+		activated.Code = upgrade.Code
+		activated.Runtime = upgrade.Runtime
+		activated.TxId = upgrade.TxId
+		activated.CreationHeight = block.BlockNumber
+		se.contractDb.RegisterContract(upgrade.ContractId, activated)
+		se.contractUpgrades.SetUpgradeStatus(upgrade.TxId, contracts.UpgradeActivated, block.BlockNumber)
+
+		if upgrade.Migrate {
+			se.queueContractMigration(TxSelf{
+				TxId:        upgrade.TxId,
+				BlockId:     block.BlockID,
+				BlockHeight: block.BlockNumber,
+				Timestamp:   block.Timestamp,
+			}, upgrade.ContractId, activated.Owner)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package state_engine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"vsc-node/modules/db/vsc/contracts"
+	stateEngine "vsc-node/modules/state-processing"
+	wasm_runtime "vsc-node/modules/wasm/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cancelUpgrade(te *testEnv, auth string, contractId string) {
+	te.Creator.CustomJson(stateEngine.MockJson{
+		RequiredAuths: []string{auth},
+		Id:            "vsc.cancel_contract_upgrade",
+		Json:          `{"net_id":"` + te.SE.SystemConfig().NetId() + `","id":"` + contractId + `"}`,
+	})
+	te.processAndWait()
+}
+
+func TestUpdateImmutableContract(t *testing.T) {
+	te := newTestEnv()
+	te.ContractDb.RegisterContract("contract-1", contracts.Contract{
+		Code:          "old-code",
+		Owner:         "hive:alice",
+		UpgradePolicy: contracts.UpgradeImmutable,
+	})
+
+	update := stateEngine.TxUpdateContract{
+		Self:    stateEngine.TxSelf{TxId: "update-tx", BlockHeight: 10, RequiredAuths: []string{"hive:alice"}},
+		Id:      "contract-1",
+		Code:    "new-code",
+		Runtime: &wasm_runtime.Go,
+	}
+	result := update.ExecuteTx(te.SE, true)
+	assert.False(t, result.Success)
+	assert.Equal(t, "contract is immutable", result.Err)
+	assert.Equal(t, "old-code", te.ContractDb.Contracts["contract-1"].Code)
+
+	//Metadata can still change
+	update.Code = ""
+	update.Name = "renamed"
+	result = update.ExecuteTx(te.SE, true)
+	assert.True(t, result.Success)
+	assert.Equal(t, "renamed", te.ContractDb.Contracts["contract-1"].Name)
+	assert.Equal(t, contracts.UpgradeImmutable, te.ContractDb.Contracts["contract-1"].UpgradePolicy)
+}
+
+func TestTimelockedContractUpgrade(t *testing.T) {
+	te := newTestEnv()
+	te.ContractDb.RegisterContract("contract-1", contracts.Contract{
+		Code:          "old-code",
+		Owner:         "hive:alice",
+		Runtime:       wasm_runtime.Go,
+		UpgradePolicy: "timelock(5)",
+	})
+
+	height := te.Reader.LastBlock
+	te.UpgradesDb.InsertUpgrade(contracts.ContractUpgrade{
+		TxId:             "upgrade-1",
+		ContractId:       "contract-1",
+		Code:             "new-code-1",
+		Runtime:          wasm_runtime.Go,
+		Status:           contracts.UpgradePending,
+		ProposedHeight:   height,
+		ActivationHeight: height + 100,
+	})
+
+	//Only one upgrade may be pending at a time
+	update := stateEngine.TxUpdateContract{
+		Self:    stateEngine.TxSelf{TxId: "update-tx", BlockHeight: height, RequiredAuths: []string{"hive:alice"}},
+		Id:      "contract-1",
+		Code:    "new-code-2",
+		Runtime: &wasm_runtime.Go,
+	}
+	result := update.ExecuteTx(te.SE, true)
+	assert.False(t, result.Success)
+	assert.Equal(t, "upgrade already pending", result.Err)
+
+	cancelUpgrade(te, "bob", "contract-1")
+	assert.Equal(t, contracts.UpgradePending, te.UpgradesDb.Upgrades["upgrade-1"].Status)
+
+	//Cancellations for another network are ignored
+	wrongNet := stateEngine.TxCancelContractUpgrade{
+		Self:  stateEngine.TxSelf{TxId: "cancel-tx", BlockHeight: height, RequiredAuths: []string{"hive:alice"}},
+		NetId: "vsc-othernet",
+		Id:    "contract-1",
+	}
+	assert.Equal(t, "wrong net ID", wrongNet.ExecuteTx(te.SE).Ret)
+	assert.Equal(t, contracts.UpgradePending, te.UpgradesDb.Upgrades["upgrade-1"].Status)
+
+	cancelUpgrade(te, "alice", "contract-1")
+	assert.Equal(t, contracts.UpgradeCancelled, te.UpgradesDb.Upgrades["upgrade-1"].Status)
+	assert.Equal(t, "old-code", te.ContractDb.Contracts["contract-1"].Code)
+
+	height = te.Reader.LastBlock
+	te.UpgradesDb.InsertUpgrade(contracts.ContractUpgrade{
+		TxId:             "upgrade-2",
+		ContractId:       "contract-1",
+		Code:             "new-code-2",
+		Runtime:          wasm_runtime.Go,
+		Status:           contracts.UpgradePending,
+		ProposedHeight:   height,
+		ActivationHeight: height + 2,
+		Migrate:          true,
+	})
+
+	te.processAndWait()
+	assert.Equal(t, contracts.UpgradePending, te.UpgradesDb.Upgrades["upgrade-2"].Status)
+	assert.Equal(t, "old-code", te.ContractDb.Contracts["contract-1"].Code)
+
+	te.processAndWait()
+	assert.Equal(t, contracts.UpgradeActivated, te.UpgradesDb.Upgrades["upgrade-2"].Status)
+	assert.Equal(t, height+2, te.UpgradesDb.Upgrades["upgrade-2"].UpdatedHeight)
+
+	activated := te.ContractDb.Contracts["contract-1"]
+	assert.Equal(t, "new-code-2", activated.Code)
+	assert.Equal(t, "upgrade-2", activated.TxId)
+	assert.Equal(t, height+2, activated.CreationHeight)
+	assert.Equal(t, "hive:alice", activated.Owner)
+	assert.Equal(t, "timelock(5)", activated.UpgradePolicy)
+
+	//The migrate entrypoint runs once under the owner
+	migrations := 0
+	for _, packet := range te.SE.TxBatch {
+		for _, op := range packet.Ops {
+			if call, ok := op.(stateEngine.TxVscCallContract); ok && call.Action == "migrate" {
+				migrations++
+				assert.Equal(t, "contract-1", call.ContractId)
+				assert.Equal(t, []string{"hive:alice"}, call.Self.RequiredAuths)
+				assert.Equal(t, height+2, call.Self.BlockHeight)
+			}
+		}
+	}
+	assert.Equal(t, 1, migrations)
+}
+
+func TestRustRuntimeHeight(t *testing.T) {
+	te := newTestEnv()
+	overrides := filepath.Join(t.TempDir(), "sysconfig.json")
+	require.NoError(t, os.WriteFile(overrides, []byte(`{"consensusParams":{"rustRuntimeHeight":100}}`), 0o644))
+	require.NoError(t, te.SE.SystemConfig().LoadOverrides(overrides))
+
+	create := stateEngine.TxCreateContract{
+		Self:    stateEngine.TxSelf{TxId: "create-tx", BlockHeight: 99, RequiredAuths: []string{"hive:alice"}},
+		Code:    "code",
+		Runtime: wasm_runtime.Rust,
+	}
+	result := create.ExecuteTx(te.SE)
+	assert.False(t, result.Success)
+	assert.Equal(t, "rust runtime is not active", result.Ret)
+
+	te.ContractDb.RegisterContract("contract-1", contracts.Contract{
+		Code:    "old-code",
+		Owner:   "hive:alice",
+		Runtime: wasm_runtime.Go,
+	})
+	update := stateEngine.TxUpdateContract{
+		Self:    stateEngine.TxSelf{TxId: "update-tx", BlockHeight: 99, RequiredAuths: []string{"hive:alice"}},
+		Id:      "contract-1",
+		Code:    "new-code",
+		Runtime: &wasm_runtime.Rust,
+	}
+	updated := update.ExecuteTx(te.SE, true)
+	assert.False(t, updated.Success)
+	assert.Equal(t, "rust runtime is not active", updated.Err)
+
+	update.Self.BlockHeight = 100
+	assert.NotEqual(t, "rust runtime is not active", update.ExecuteTx(te.SE, true).Err)
+}
@@ -0,0 +1,248 @@
+package state_engine
+
+import (
+	"sync"
+	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
+	"vsc-node/modules/db/vsc/transactions"
+	vscBlocks "vsc-node/modules/db/vsc/vsc_blocks"
+)
+
+// Buffer size used for subscribers that do not request one explicitly
+const DefaultEventBuffer = 256
+
+type EventType string
+
+const (
+	EventTxStatus       EventType = "tx_status"
+	EventLedgerOp       EventType = "ledger_op"
+	EventContractOutput EventType = "contract_output"
+	EventNewBlock       EventType = "new_block"
+)
+
+// A single piece of committed state.
+// Exactly one of the payload fields is set, matching Type.
+type StateEvent struct {
+	Type EventType
+	//Hive block whose processing committed this change
+	BlockHeight uint64
+	Timestamp   string
+
+	TxStatus       *TxStatusEvent
+	LedgerRecord   *ledgerDb.LedgerRecord
+	ContractOutput *contracts.ContractOutput
+	Block          *vscBlocks.VscHeaderRecord
+}
+
+type TxStatusEvent struct {
+	Id     string
+	Status transactions.TransactionStatus
+}
+
+// EventFeed fans out state changes to in-process subscribers (i.e GraphQL subscriptions).
+//
+// Events are staged while a Hive block is processed and only delivered once
+// ProcessBlock has finished, so subscribers see changes in the exact order the
+// state engine wrote them and never observe a half-processed block.
+// Delivery never blocks block processing: a subscriber whose buffer is full is dropped
+// and its channel closed.
+type EventFeed struct {
+	mu      sync.Mutex
+	subs    map[uint64]chan StateEvent
+	nextId  uint64
+	pending []StateEvent
+}
+
+func NewEventFeed() *EventFeed {
+	return &EventFeed{
+		subs:    make(map[uint64]chan StateEvent),
+		pending: make([]StateEvent, 0),
+	}
+}
+
+// Subscribe registers a new listener.
+// The returned function must be called to release the subscription.
+func (f *EventFeed) Subscribe(bufSize int) (<-chan StateEvent, func()) {
+	if bufSize <= 0 {
+		bufSize = DefaultEventBuffer
+	}
+	ch := make(chan StateEvent, bufSize)
+
+	f.mu.Lock()
+	id := f.nextId
+	f.nextId++
+	f.subs[id] = ch
+	f.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if sub, ok := f.subs[id]; ok {
+				delete(f.subs, id)
+				close(sub)
+			}
+		})
+	}
+}
+
+func (f *EventFeed) stage(ev StateEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	//No point in buffering events nobody will read
+	if len(f.subs) == 0 {
+		return
+	}
+	f.pending = append(f.pending, ev)
+}
+
+// Delivers all staged events, stamping them with the block that committed them
+func (f *EventFeed) commit(block hive_blocks.HiveBlock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, ev := range f.pending {
+		ev.BlockHeight = block.BlockNumber
+		ev.Timestamp = block.Timestamp
+		if ev.ContractOutput != nil && ev.ContractOutput.Timestamp == nil {
+			ts := block.Timestamp
+			ev.ContractOutput.Timestamp = &ts
+		}
+		f.deliver(ev)
+	}
+	f.pending = f.pending[:0]
+}
+
+// Delivers an event that is not tied to block processing right away
+func (f *EventFeed) publish(ev StateEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliver(ev)
+}
+
+// Must be called with f.mu held
+func (f *EventFeed) deliver(ev StateEvent) {
+	for id, sub := range f.subs {
+		select {
+		case sub <- ev:
+		default:
+			log.Warn("dropping slow state event subscriber", "id", id)
+			delete(f.subs, id)
+			close(sub)
+		}
+	}
+}
+
+// The wrappers below sit between the state engine and its stores.
+// Every committed write is forwarded to the store first, then staged on the feed.
+
+type eventTransactions struct {
+	transactions.Transactions
+	feed *EventFeed
+}
+
+func (e *eventTransactions) Ingest(offTx transactions.IngestTransactionUpdate) error {
+	err := e.Transactions.Ingest(offTx)
+	if err == nil && offTx.Status != "" {
+		e.feed.stage(StateEvent{
+			Type: EventTxStatus,
+			TxStatus: &TxStatusEvent{
+				Id:     offTx.Id,
+				Status: transactions.TransactionStatus(offTx.Status),
+			},
+		})
+	}
+	return err
+}
+
+func (e *eventTransactions) SetOutput(sOut transactions.SetResultUpdate) {
+	e.Transactions.SetOutput(sOut)
+	if sOut.Status != nil {
+		e.feed.stage(StateEvent{
+			Type: EventTxStatus,
+			TxStatus: &TxStatusEvent{
+				Id:     sOut.Id,
+				Status: *sOut.Status,
+			},
+		})
+	}
+}
+
+// Wraps the transaction store of the transaction pool. Transactions it
+// accepts are published immediately with a zero block height, as they are
+// not committed by any block yet.
+func (f *EventFeed) PoolTransactions(txDb transactions.Transactions) transactions.Transactions {
+	return &poolTransactions{txDb, f}
+}
+
+type poolTransactions struct {
+	transactions.Transactions
+	feed *EventFeed
+}
+
+func (p *poolTransactions) Ingest(offTx transactions.IngestTransactionUpdate) error {
+	err := p.Transactions.Ingest(offTx)
+	if err == nil && offTx.Status != "" {
+		p.feed.publish(StateEvent{
+			Type: EventTxStatus,
+			TxStatus: &TxStatusEvent{
+				Id:     offTx.Id,
+				Status: transactions.TransactionStatus(offTx.Status),
+			},
+		})
+	}
+	return err
+}
+
+type eventLedger struct {
+	ledgerDb.Ledger
+	feed *EventFeed
+}
+
+func (e *eventLedger) StoreLedger(records ...ledgerDb.LedgerRecord) {
+	e.Ledger.StoreLedger(records...)
+	for _, record := range records {
+		r := record
+		e.feed.stage(StateEvent{
+			Type:         EventLedgerOp,
+			LedgerRecord: &r,
+		})
+	}
+}
+
+type eventContractState struct {
+	contracts.ContractState
+	feed *EventFeed
+}
+
+func (e *eventContractState) IngestOutput(output contracts.IngestOutputArgs) {
+	e.ContractState.IngestOutput(output)
+	e.feed.stage(StateEvent{
+		Type: EventContractOutput,
+		ContractOutput: &contracts.ContractOutput{
+			Id:          output.Id,
+			BlockHeight: output.AnchoredHeight,
+			ContractId:  output.ContractId,
+			Inputs:      output.Inputs,
+			Metadata:    output.Metadata,
+			Results:     output.Results,
+			StateMerkle: output.StateMerkle,
+			AnchoredId:  output.AnchoredId,
+		},
+	})
+}
+
+type eventVscBlocks struct {
+	vscBlocks.VscBlocks
+	feed *EventFeed
+}
+
+func (e *eventVscBlocks) StoreHeader(header vscBlocks.VscHeaderRecord) {
+	e.VscBlocks.StoreHeader(header)
+	e.feed.stage(StateEvent{
+		Type:  EventNewBlock,
+		Block: &header,
+	})
+}
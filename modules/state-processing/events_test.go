@@ -0,0 +1,87 @@
+package state_engine
+
+import (
+	"testing"
+	"vsc-node/modules/db/vsc/contracts"
+	"vsc-node/modules/db/vsc/hive_blocks"
+	"vsc-node/modules/db/vsc/transactions"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFeedDeliversOnCommit(t *testing.T) {
+	feed := NewEventFeed()
+	events, unsubscribe := feed.Subscribe(4)
+	defer unsubscribe()
+
+	feed.stage(StateEvent{Type: EventTxStatus, TxStatus: &TxStatusEvent{Id: "a", Status: transactions.TransactionStatusIncluded}})
+	feed.stage(StateEvent{Type: EventTxStatus, TxStatus: &TxStatusEvent{Id: "b", Status: transactions.TransactionStatusConfirmed}})
+	require.Len(t, events, 0, "events must not be delivered before commit")
+
+	feed.commit(hive_blocks.HiveBlock{BlockNumber: 10, Timestamp: "2025-01-01T00:00:00"})
+
+	first := <-events
+	second := <-events
+	require.Equal(t, "a", first.TxStatus.Id)
+	require.Equal(t, "b", second.TxStatus.Id)
+	require.Equal(t, uint64(10), first.BlockHeight)
+	require.Equal(t, "2025-01-01T00:00:00", second.Timestamp)
+}
+
+func TestEventFeedDropsSlowSubscriber(t *testing.T) {
+	feed := NewEventFeed()
+	events, unsubscribe := feed.Subscribe(1)
+	defer unsubscribe()
+
+	feed.stage(StateEvent{Type: EventNewBlock})
+	feed.stage(StateEvent{Type: EventNewBlock})
+	feed.commit(hive_blocks.HiveBlock{BlockNumber: 1})
+
+	_, ok := <-events
+	require.True(t, ok)
+	_, ok = <-events
+	require.False(t, ok, "slow subscriber should be closed")
+}
+
+func TestEventFeedSkipsStagingWithoutSubscribers(t *testing.T) {
+	feed := NewEventFeed()
+	feed.stage(StateEvent{Type: EventNewBlock})
+	require.Len(t, feed.pending, 0)
+}
+
+type nopTransactions struct{ transactions.Transactions }
+
+func (nopTransactions) Ingest(transactions.IngestTransactionUpdate) error { return nil }
+
+type nopContractState struct{ contracts.ContractState }
+
+func (nopContractState) IngestOutput(contracts.IngestOutputArgs) {}
+
+func TestEventFeedPublishesPoolTransactions(t *testing.T) {
+	feed := NewEventFeed()
+	events, unsubscribe := feed.Subscribe(4)
+	defer unsubscribe()
+
+	txDb := feed.PoolTransactions(nopTransactions{})
+	require.NoError(t, txDb.Ingest(transactions.IngestTransactionUpdate{Id: "pooled", Status: "UNCONFIRMED"}))
+
+	require.Len(t, events, 1, "pool transactions are delivered without a block commit")
+	ev := <-events
+	require.Equal(t, "pooled", ev.TxStatus.Id)
+	require.Equal(t, transactions.TransactionStatus("UNCONFIRMED"), ev.TxStatus.Status)
+	require.Zero(t, ev.BlockHeight)
+}
+
+func TestEventFeedContractOutputAnchor(t *testing.T) {
+	feed := NewEventFeed()
+	events, unsubscribe := feed.Subscribe(4)
+	defer unsubscribe()
+
+	state := &eventContractState{nopContractState{}, feed}
+	state.IngestOutput(contracts.IngestOutputArgs{Id: "out-1", ContractId: "c1", AnchoredId: "block-1", AnchoredHeight: 5})
+	feed.commit(hive_blocks.HiveBlock{BlockNumber: 10})
+
+	ev := <-events
+	require.Equal(t, "block-1", ev.ContractOutput.AnchoredId)
+	require.Equal(t, int64(5), ev.ContractOutput.BlockHeight)
+}
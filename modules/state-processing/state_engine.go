@@ -18,6 +18,8 @@ import (
 	"vsc-node/modules/common/params"
 	systemconfig "vsc-node/modules/common/system-config"
 	contract_session "vsc-node/modules/contract/session"
+	"vsc-node/modules/db/vsc/authorities"
+	"vsc-node/modules/db/vsc/bridge"
 	"vsc-node/modules/db/vsc/contracts"
 	"vsc-node/modules/db/vsc/elections"
 	"vsc-node/modules/db/vsc/hive_blocks"
@@ -30,6 +32,7 @@ import (
 	"vsc-node/modules/db/vsc/witnesses"
 	ledgerSystem "vsc-node/modules/ledger-system"
 	rcSystem "vsc-node/modules/rc-system"
+	transactionpool "vsc-node/modules/transaction-pool"
 	tss_helpers "vsc-node/modules/tss/helpers"
 	wasm_runtime "vsc-node/modules/wasm/runtime_ipc"
 
@@ -53,19 +56,23 @@ type StateEngine struct {
 	da    *DataLayer.DataLayer
 
 	//db access
-	witnessDb      witnesses.Witnesses
-	electionDb     elections.Elections
-	contractDb     contracts.Contracts
-	contractState  contracts.ContractState
-	txDb           transactions.Transactions
-	hiveBlocks     hive_blocks.HiveBlocks
-	vscBlocks      vscBlocks.VscBlocks
-	claimDb        ledgerDb.InterestClaims
-	rcDb           rcDb.RcDb
-	nonceDb        nonces.Nonces
-	tssRequests    tss_db.TssRequests
-	tssKeys        tss_db.TssKeys
-	tssCommitments tss_db.TssCommitments
+	witnessDb         witnesses.Witnesses
+	electionDb        elections.Elections
+	contractDb        contracts.Contracts
+	contractState     contracts.ContractState
+	contractUpgrades  contracts.ContractUpgrades
+	contractSchedules contracts.ContractSchedules
+	txDb              transactions.Transactions
+	hiveBlocks        hive_blocks.HiveBlocks
+	vscBlocks         vscBlocks.VscBlocks
+	claimDb           ledgerDb.InterestClaims
+	rcDb              rcDb.RcDb
+	nonceDb           nonces.Nonces
+	authorityDb       authorities.Authorities
+	tssRequests       tss_db.TssRequests
+	tssKeys           tss_db.TssKeys
+	tssCommitments    tss_db.TssCommitments
+	bridgeDb          bridge.Withdrawals
 
 	wasm *wasm_runtime.Wasm
 
@@ -97,6 +104,9 @@ type StateEngine struct {
 
 	slotStatus *SlotStatus
 
+	//Committed state changes for subscribers
+	Events *EventFeed
+
 	BlockHeight int
 }
 
@@ -258,6 +268,10 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 
 	}
 
+	se.activateContractUpgrades(block)
+	se.runContractSchedules(block)
+	se.keyBridgeWithdrawals(block.BlockNumber)
+
 	for _, virtualOp := range block.VirtualOps {
 		if virtualOp.Op.Type == "interest_operation" {
 			owner, ok := virtualOp.Op.Value["owner"].(string)
@@ -343,6 +357,10 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 					})
 				}
 
+				if Id == "vsc.bridge_status" && RequiredAuths[0] == se.sconf.GatewayWallet() {
+					se.updateBridgeStatus(cj.Json, block.BlockNumber, tx.TransactionID)
+				}
+
 				if Id == "vsc.actions" && RequiredAuths[0] == se.sconf.GatewayWallet() {
 					actionUpdate := map[string]interface{}{}
 					err := json.Unmarshal(cj.Json, &actionUpdate)
@@ -527,7 +545,7 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 						txResult := parsedTx.ExecuteTx(se, hasFee)
 
 						if hasFee {
-							if txResult.Success && txResult.CodeUpdated {
+							if txResult.Success && (txResult.CodeUpdated || txResult.UpgradeScheduled) {
 								se.LedgerSystem.Deposit(ledgerSystem.Deposit{
 									Id:          MakeTxId(tx.TransactionID, 1),
 									Asset:       "hbd",
@@ -555,6 +573,28 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 						}
 					}
 					continue
+				} else if cj.Id == "vsc.cancel_contract_upgrade" {
+					for idx, auth := range txSelf.RequiredAuths {
+						txSelf.RequiredAuths[idx] = "hive:" + auth
+					}
+
+					parsedTx := TxCancelContractUpgrade{
+						Self: txSelf,
+					}
+					json.Unmarshal(cj.Json, &parsedTx)
+					parsedTx.ExecuteTx(se)
+					continue
+				} else if cj.Id == "vsc.cancel_contract_schedule" {
+					for idx, auth := range txSelf.RequiredAuths {
+						txSelf.RequiredAuths[idx] = "hive:" + auth
+					}
+
+					parsedTx := TxCancelContractSchedule{
+						Self: txSelf,
+					}
+					json.Unmarshal(cj.Json, &parsedTx)
+					parsedTx.ExecuteTx(se)
+					continue
 				} else if cj.Id == "vsc.election_result" {
 					parsedTx := &TxElectionResult{
 						Self: txSelf,
@@ -735,6 +775,26 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 
 					json.Unmarshal(cj.Json, &parsedTx)
 
+					vscTx = &parsedTx
+				} else if cj.Id == "vsc.scheduled_transfer" {
+					if txSelf.BlockHeight < se.SystemConfig().ConsensusParams().ScheduledTransferHeight {
+						continue
+					}
+					parsedTx := TxScheduledTransfer{
+						Self: txSelf,
+					}
+					json.Unmarshal(cj.Json, &parsedTx)
+
+					vscTx = &parsedTx
+				} else if cj.Id == "vsc.cancel_scheduled_transfer" {
+					if txSelf.BlockHeight < se.SystemConfig().ConsensusParams().ScheduledTransferHeight {
+						continue
+					}
+					parsedTx := TxCancelScheduledTransfer{
+						Self: txSelf,
+					}
+					json.Unmarshal(cj.Json, &parsedTx)
+
 					vscTx = &parsedTx
 				} else if cj.Id == "vsc.tss_sign" {
 					if se.sconf.OnTestnet() && txSelf.BlockHeight < se.SystemConfig().ConsensusParams().TssIndexHeight {
@@ -754,7 +814,7 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 
 					keyCache := make(map[string]*tss_db.TssKey)
 					if err == nil {
-						for _, sigPack := range signedData.Packet {
+						for sigIdx, sigPack := range signedData.Packet {
 							if keyCache[sigPack.KeyId] == nil {
 								tssKey, err := se.tssKeys.FindKey(sigPack.KeyId)
 								if err != nil {
@@ -791,13 +851,7 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 										if verified {
 
 											fmt.Println("NEED TO SAVE SIGNATURE")
-											// se.tssRequests.SetSignedRequest()
-											se.tssRequests.UpdateRequest(tss_db.TssRequest{
-												KeyId:  sigPack.KeyId,
-												Msg:    sigPack.Msg,
-												Sig:    sigPack.Sig,
-												Status: tss_db.SignComplete,
-											})
+											se.completeSignRequest(sigPack.KeyId, sigPack.Msg, sigPack.Sig, txSelf, sigIdx)
 										}
 									} else if keyCache[sigPack.KeyId].Algo == tss_db.EddsaType {
 										pk := ed25519.PublicKey(publicKey)
@@ -806,12 +860,7 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 
 										fmt.Println("edVerify", edVerify)
 										if edVerify {
-											se.tssRequests.UpdateRequest(tss_db.TssRequest{
-												KeyId:  sigPack.KeyId,
-												Msg:    sigPack.Msg,
-												Sig:    sigPack.Sig,
-												Status: tss_db.SignComplete,
-											})
+											se.completeSignRequest(sigPack.KeyId, sigPack.Msg, sigPack.Sig, txSelf, sigIdx)
 										}
 									}
 								}
@@ -1030,6 +1079,8 @@ func (se *StateEngine) ProcessBlock(block hive_blocks.HiveBlock) {
 		se.ExecuteBatch()
 		//Balances must be updated after the current slot has been fully executed
 	}
+
+	se.Events.commit(block)
 }
 
 func (se *StateEngine) ExecuteBatch() {
@@ -1080,7 +1131,23 @@ func (se *StateEngine) ExecuteBatch() {
 			se.TempOutputs,
 		)
 
+		//Tips are charged before the ops run and kept whether or not they succeed.
+		//Transactions that can't pay their tip are dropped.
+		tipIds := make([]string, 0)
+		if tx.Tip > 0 {
+			if !se.chargeTip(tx, ledgerSession, rcSession) {
+				se.TxOutput[tx.TxId] = TxOutput{
+					Ok:        false,
+					LedgerIds: tipIds,
+				}
+				se.TxOutIds = append(se.TxOutIds, tx.TxId)
+				continue
+			}
+			tipIds = ledgerSession.Done()
+		}
+
 		outputs := make([]ContractIdResult, 0)
+		authorityUpdates := make([]authorities.AuthorityRecord, 0)
 		ok := true
 		for idx, vscTx := range tx.Ops {
 			fmt.Println("Execute tx.bh", vscTx.TxSelf().BlockHeight)
@@ -1099,7 +1166,9 @@ func (se *StateEngine) ExecuteBatch() {
 			}
 
 			var payer string
-			if len(vscTx.TxSelf().RequiredAuths) == 0 {
+			if tx.Payer != "" {
+				payer = tx.Payer
+			} else if len(vscTx.TxSelf().RequiredAuths) == 0 {
 				payer = vscTx.TxSelf().RequiredPostingAuths[0]
 			} else {
 				payer = vscTx.TxSelf().RequiredAuths[0]
@@ -1142,8 +1211,20 @@ func (se *StateEngine) ExecuteBatch() {
 				result.RcUsed,
 			)
 
-			rcUsed := se.RcMap[payer] // don't crash if payer is not in RC map
-			se.RcMap[payer] = rcUsed + result.RcUsed
+			if call, isCall := vscTx.(TxVscCallContract); !isCall || !call.System {
+				rcUsed := se.RcMap[payer] // don't crash if payer is not in RC map
+				se.RcMap[payer] = rcUsed + result.RcUsed
+			}
+
+			if authorityTx, isAuthority := vscTx.(*TxUpdateAuthority); isAuthority && result.Success {
+				authorityUpdates = append(authorityUpdates, authorities.AuthorityRecord{
+					Account:     authorityTx.Account,
+					Keys:        authorityTx.Keys,
+					Threshold:   authorityTx.Threshold,
+					BlockHeight: authorityTx.Self.BlockHeight,
+					TxId:        tx.TxId,
+				})
+			}
 
 			if vscTx.Type() == "call" {
 				txId := MakeTxId(tx.TxId, idx)
@@ -1185,22 +1266,26 @@ func (se *StateEngine) ExecuteBatch() {
 							outputs = append(outputs, ContractIdResult{
 								ContractId: contractId,
 								Output: ContractResult{
-									TxId:    txId,
-									Ret:     result.Ret,
-									Success: result.Success,
-									Logs:    log.Logs,
-									TssOps:  log.TssOps,
+									TxId:      txId,
+									Ret:       result.Ret,
+									Success:   result.Success,
+									Logs:      log.Logs,
+									TssOps:    log.TssOps,
+									Events:    log.Events,
+									Schedules: log.Schedules,
 								},
 							})
 						} else {
 							outputs = append(outputs, ContractIdResult{
 								ContractId: id,
 								Output: ContractResult{
-									TxId:    txId,
-									Ret:     "",
-									Success: result.Success,
-									Logs:    log.Logs,
-									TssOps:  log.TssOps,
+									TxId:      txId,
+									Ret:       "",
+									Success:   result.Success,
+									Logs:      log.Logs,
+									TssOps:    log.TssOps,
+									Events:    log.Events,
+									Schedules: log.Schedules,
 								},
 							})
 						}
@@ -1218,6 +1303,12 @@ func (se *StateEngine) ExecuteBatch() {
 			se.AppendOutput(out.ContractId, out.Output)
 		}
 		if ok {
+			//Authorities apply once the whole transaction succeeded
+			for _, record := range authorityUpdates {
+				if err := se.authorityDb.SetAuthority(record); err != nil {
+					log.Error("failed to set account authority", "account", record.Account, "err", err)
+				}
+			}
 			callSession.Commit()
 			callOutputs := callSession.ToOutputs()
 			for k, v := range callOutputs {
@@ -1225,7 +1316,7 @@ func (se *StateEngine) ExecuteBatch() {
 				se.TempOutputs[k] = &vv
 			}
 		}
-		ledgerIds := ledgerSession.Done()
+		ledgerIds := append(tipIds, ledgerSession.Done()...)
 
 		se.TxOutput[tx.TxId] = TxOutput{
 			Ok:        ok,
@@ -1237,6 +1328,44 @@ func (se *StateEngine) ExecuteBatch() {
 	se.TxBatch = make([]TxPacket, 0)
 }
 
+// Charges the priority tip of an offchain transaction to its payer.
+// RC tips are consumed like RCs used by the transaction,
+// HBD tips are transferred to the block producer.
+// Returns false if the payer can't afford the tip.
+func (se *StateEngine) chargeTip(tx TxPacket, ledgerSession ledgerSystem.LedgerSession, rcSession rcSystem.RcSession) bool {
+	self := tx.Ops[0].TxSelf()
+	if len(self.RequiredAuths) == 0 {
+		return false
+	}
+	payer := transactionpool.RcPayer(self.RequiredAuths, tx.Payer)
+
+	if tx.TipAsset != transactionpool.TipAssetHbd {
+		if didConsume, _ := rcSession.Consume(payer, self.BlockHeight, int64(tx.Tip)); !didConsume {
+			log.Debug("tip not paid", "txId", tx.TxId, "payer", payer, "err", "insufficient RCs")
+			return false
+		}
+		se.RcMap[payer] += int64(tx.Tip)
+		return true
+	}
+	result := ledgerSession.ExecuteTransfer(ledgerSystem.OpLogEvent{
+		Id:          tx.TxId + "-tip",
+		BIdx:        int64(self.Index),
+		OpIdx:       int64(len(tx.Ops)),
+		From:        payer,
+		To:          "hive:" + tx.Producer,
+		Amount:      int64(tx.Tip),
+		Asset:       "hbd",
+		Memo:        "priority tip",
+		BlockHeight: self.BlockHeight,
+	})
+	if !result.Ok {
+		log.Debug("tip not paid", "txId", tx.TxId, "payer", payer, "err", result.Msg)
+		ledgerSession.Revert()
+		return false
+	}
+	return true
+}
+
 func (se *StateEngine) UpdateBalances(startBlock, endBlock uint64) {
 	//Sets a default start block of 0 if near block 0
 	//E2E testing starts at block 0
@@ -1264,6 +1393,22 @@ func (se *StateEngine) UpdateBalances(startBlock, endBlock uint64) {
 			Type:        "consensus_unstake",
 		})
 	}
+
+	//Release scheduled transfers that have reached their release height
+	scheduledRecords, _ := se.LedgerState.ActionDb.GetDueScheduledTransfers(endBlock)
+	for _, record := range scheduledRecords {
+		completeIds = append(completeIds, record.Id)
+
+		ledgerRecords = append(ledgerRecords, ledgerDb.LedgerRecord{
+			Id:          record.Id + "#out",
+			Amount:      record.Amount,
+			Asset:       record.Asset,
+			BlockHeight: endBlock,
+			From:        ledgerSystem.ScheduledTransferSender(record),
+			Owner:       record.To,
+			Type:        "scheduled_transfer",
+		})
+	}
 	se.LedgerState.LedgerDb.StoreLedger(ledgerRecords...)
 	se.LedgerState.ActionDb.ExecuteComplete(nil, completeIds...)
 	// se.LedgerExecutor.Ls.LedgerDb.StoreLedger(ledgerRecords...)
@@ -1314,6 +1459,9 @@ func (se *StateEngine) UpdateBalances(startBlock, endBlock uint64) {
 				ledgerBalances[asset] = 0
 			}
 		}
+		for asset, amount := range balanceR.Tokens {
+			ledgerBalances[asset] = amount
+		}
 		//As of block X or below
 		// se.LedgerExecutor.Ls.log.Debug("GetBalance for account", stBlock, stHeight, endBlock)
 
@@ -1372,6 +1520,14 @@ func (se *StateEngine) UpdateBalances(startBlock, endBlock uint64) {
 			HBD_SAVINGS:    ledgerBalances["hbd_savings"],
 			HBD_AVG:        hbdAvg,
 		}
+		for asset, amount := range ledgerBalances {
+			if amount != 0 && ledgerSystem.IsTokenAsset(asset) {
+				if newRecord.Tokens == nil {
+					newRecord.Tokens = make(map[string]int64)
+				}
+				newRecord.Tokens[asset] = amount
+			}
+		}
 
 		newRecord.HBD_MODIFY_HEIGHT = modifyHeight
 
@@ -1472,6 +1628,16 @@ func (se *StateEngine) SaveBlockHeight(lastBlk uint64, lastSavedBlk uint64) uint
 	// }
 }
 
+// Reports whether all executed state has been flushed to the database, i.e.
+// no transactions, outputs or ledger ops are pending for the next VSC block.
+// The database then fully describes the state as of the last processed block.
+func (se *StateEngine) Quiescent() bool {
+	return len(se.TxBatch) == 0 &&
+		len(se.TxOutput) == 0 &&
+		len(se.ContractResults) == 0 &&
+		len(se.LedgerState.Oplog) == 0
+}
+
 func (se *StateEngine) DataLayer() common_types.DataLayer {
 	return se.da
 }
@@ -1534,6 +1700,8 @@ func New(sconf systemconfig.SystemConfig, da *DataLayer.DataLayer,
 	electionsDb elections.Elections,
 	contractDb contracts.Contracts,
 	contractStateDb contracts.ContractState,
+	contractUpgrades contracts.ContractUpgrades,
+	contractSchedules contracts.ContractSchedules,
 	txDb transactions.Transactions,
 	ledgerDb ledgerDb.Ledger,
 	balanceDb ledgerDb.Balances,
@@ -1543,11 +1711,20 @@ func New(sconf systemconfig.SystemConfig, da *DataLayer.DataLayer,
 	actionDb ledgerDb.BridgeActions,
 	rcDb rcDb.RcDb,
 	nonceDb nonces.Nonces,
+	authorityDb authorities.Authorities,
 	tssKeys tss_db.TssKeys,
 	tssCommitments tss_db.TssCommitments,
 	tssRequests tss_db.TssRequests,
+	bridgeDb bridge.Withdrawals,
 	wasm *wasm_runtime.Wasm,
 ) *StateEngine {
+	events := NewEventFeed()
+
+	//Route committed writes through the event feed
+	ledgerDb = &eventLedger{ledgerDb, events}
+	txDb = &eventTransactions{txDb, events}
+	contractStateDb = &eventContractState{contractStateDb, events}
+	vscBlocks = &eventVscBlocks{vscBlocks, events}
 
 	ls := ledgerSystem.New(balanceDb, ledgerDb, interestClaims, actionDb)
 
@@ -1579,21 +1756,26 @@ func New(sconf systemconfig.SystemConfig, da *DataLayer.DataLayer,
 		da: da,
 		// db: db,
 
-		witnessDb:      witnessesDb,
-		electionDb:     electionsDb,
-		contractDb:     contractDb,
-		contractState:  contractStateDb,
-		hiveBlocks:     hiveBlocks,
-		vscBlocks:      vscBlocks,
-		claimDb:        interestClaims,
-		txDb:           txDb,
-		rcDb:           rcDb,
-		nonceDb:        nonceDb,
-		RcSystem:       rcSystem.New(rcDb, ls),
-		RcMap:          make(map[string]int64),
-		tssRequests:    tssRequests,
-		tssCommitments: tssCommitments,
-		tssKeys:        tssKeys,
+		witnessDb:         witnessesDb,
+		electionDb:        electionsDb,
+		contractDb:        contractDb,
+		contractState:     contractStateDb,
+		contractUpgrades:  contractUpgrades,
+		contractSchedules: contractSchedules,
+		hiveBlocks:        hiveBlocks,
+		vscBlocks:         vscBlocks,
+		claimDb:           interestClaims,
+		txDb:              txDb,
+		rcDb:              rcDb,
+		nonceDb:           nonceDb,
+		authorityDb:       authorityDb,
+		RcSystem:          rcSystem.New(rcDb, ls),
+		RcMap:             make(map[string]int64),
+		tssRequests:       tssRequests,
+		tssCommitments:    tssCommitments,
+		tssKeys:           tssKeys,
+		bridgeDb:          bridgeDb,
+		Events:            events,
 
 		wasm: wasm,
 
@@ -43,6 +43,9 @@ type testEnv struct {
 	TssKeys        *test_utils.MockTssKeysDb
 	TssCommitments *test_utils.MockTssCommitmentsDb
 	TssRequests    *test_utils.MockTssRequestsDb
+	BridgeDb       *test_utils.MockBridgeDb
+	UpgradesDb     *test_utils.MockContractUpgradesDb
+	SchedulesDb    *test_utils.MockContractSchedulesDb
 }
 
 func newTestEnv() *testEnv {
@@ -94,12 +97,16 @@ func newTestEnv() *testEnv {
 		Requests: make(map[string]tss_db.TssRequest),
 	}
 
+	bridgeDb := test_utils.NewMockBridgeDb()
+	upgradesDb := test_utils.NewMockContractUpgradesDb()
+	schedulesDb := test_utils.NewMockContractSchedulesDb()
+
 	se := stateEngine.New(
 		sysConfig, nil,
-		witnessesDb, electionDb, contractDb, contractState,
+		witnessesDb, electionDb, contractDb, contractState, upgradesDb, schedulesDb,
 		txDb, ledgerDbImpl, balanceDb, nil,
 		interestClaims, vscBlocksDb, actionsDb, mockRcDb, nonceDb,
-		tssKeys, tssCommitments, tssRequests, nil,
+		test_utils.NewMockAuthoritiesDb(), tssKeys, tssCommitments, tssRequests, bridgeDb, nil,
 	)
 
 	mockReader := stateEngine.NewMockReader()
@@ -128,6 +135,9 @@ func newTestEnv() *testEnv {
 		TssKeys:        tssKeys,
 		TssCommitments: tssCommitments,
 		TssRequests:    tssRequests,
+		BridgeDb:       bridgeDb,
+		UpgradesDb:     upgradesDb,
+		SchedulesDb:    schedulesDb,
 	}
 }
 
@@ -65,7 +65,7 @@ func (output *ContractOutput) Ingest(se *StateEngine, txSelf TxSelf, slotHeight
 			tssOps = append(tssOps, res.TssOps...)
 		}
 
-		for _, tssOp := range tssOps {
+		for opIdx, tssOp := range tssOps {
 			switch tssOp.Type {
 			case "create":
 				tssLog.Verbose("creating TSS key", "keyId", tssOp.KeyId, "algo", tssOp.Args, "epochs", tssOp.Epochs)
@@ -106,11 +106,7 @@ func (output *ContractOutput) Ingest(se *StateEngine, txSelf TxSelf, slotHeight
 					}
 				}
 			case "sign":
-				se.tssRequests.SetSignedRequest(tss_db.TssRequest{
-					KeyId:  tssOp.KeyId,
-					Status: "unsigned",
-					Msg:    tssOp.Args,
-				})
+				se.requestSignature(output.ContractId, tssOp, txSelf, MakeTxId(output.Id, opIdx+1))
 				// if err == mongo.ErrNoDocuments {
 				// 	se.tssKeys.InsertKey(tssOp.KeyId, tss_db.TssKeyAlgo(tssOp.Args))
 				// }
@@ -118,6 +114,10 @@ func (output *ContractOutput) Ingest(se *StateEngine, txSelf TxSelf, slotHeight
 		}
 	}
 
+	for _, res := range output.Results {
+		se.applyScheduleOps(output.ContractId, res.Schedules, txSelf)
+	}
+
 	go func() {
 		cid, err := cid.Parse(output.StateMerkle)
 		if err == nil {
@@ -161,6 +161,8 @@ type TxCreateContract struct {
 	Owner        string               `json:"owner"`
 	Description  string               `json:"description"`
 	StorageProof StorageProof         `json:"storage_proof"`
+	//immutable, immediate (default) or timelock(<blocks>)
+	UpgradePolicy string `json:"upgrade_policy,omitempty"`
 }
 
 func (tx TxCreateContract) Type() string {
@@ -181,6 +183,12 @@ func (tx *TxCreateContract) ExecuteTx(se *StateEngine) TxResult {
 			Ret:     "runtime name is invalid",
 		}
 	}
+	if tx.Runtime.IsRust() && tx.Self.BlockHeight < se.sconf.ConsensusParams().RustRuntimeHeight {
+		return TxResult{
+			Success: false,
+			Ret:     "rust runtime is not active",
+		}
+	}
 
 	if len(tx.Self.RequiredAuths) == 0 {
 		return TxResult{
@@ -189,6 +197,14 @@ func (tx *TxCreateContract) ExecuteTx(se *StateEngine) TxResult {
 		}
 	}
 
+	policy, err := contracts.ParseUpgradePolicy(tx.UpgradePolicy)
+	if err != nil {
+		return TxResult{
+			Success: false,
+			Ret:     "invalid upgrade policy: " + err.Error(),
+		}
+	}
+
 	election, err := se.electionDb.GetElectionByHeight(tx.Self.BlockHeight)
 
 	if err != nil {
@@ -232,6 +248,7 @@ func (tx *TxCreateContract) ExecuteTx(se *StateEngine) TxResult {
 		TxId:           tx.Self.TxId,
 		CreationHeight: tx.Self.BlockHeight,
 		Runtime:        tx.Runtime,
+		UpgradePolicy:  policy.String(),
 	})
 
 	return TxResult{
@@ -249,6 +266,8 @@ func (tx *TxCreateContract) ToData() map[string]interface{} {
 		"description":   tx.Description,
 		"storage_proof": tx.StorageProof,
 		"runtime":       tx.Runtime,
+
+		"upgrade_policy": tx.UpgradePolicy,
 	}
 }
 
@@ -292,12 +311,16 @@ type TxUpdateContract struct {
 	Runtime      *wasm_runtime.Runtime `json:"runtime,omitempty"`
 	Code         string                `json:"code,omitempty"`
 	StorageProof *StorageProof         `json:"storage_proof,omitempty"`
+	//Run the migrate entrypoint of the new code once it is active
+	Migrate bool `json:"migrate,omitempty"`
 }
 
 type UpdateContractResult struct {
 	Success     bool
 	CodeUpdated bool
-	Err         string
+	//Code update was put behind the timelock of the contract
+	UpgradeScheduled bool
+	Err              string
 }
 
 func (tx TxUpdateContract) Type() string {
@@ -318,6 +341,7 @@ func (tx *TxUpdateContract) ToData() map[string]interface{} {
 		"runtime":       tx.Runtime,
 		"code":          tx.Code,
 		"storage_proof": tx.StorageProof,
+		"migrate":       tx.Migrate,
 	}
 }
 
@@ -341,13 +365,28 @@ func (tx *TxUpdateContract) ExecuteTx(se *StateEngine, hasFee bool) UpdateContra
 			Err:     "not owner",
 		}
 	}
+	policy, err := contracts.ParseUpgradePolicy(existing.UpgradePolicy)
+	if err != nil {
+		return UpdateContractResult{
+			Success: false,
+			Err:     "invalid upgrade policy",
+		}
+	}
+	codeChanged := tx.Code != "" && tx.Code != existing.Code
+	if codeChanged && policy.Mode == contracts.UpgradeImmutable {
+		return UpdateContractResult{
+			Success: false,
+			Err:     "contract is immutable",
+		}
+	}
 	updatedContract := contracts.Contract{
-		Name:        tx.Name,
-		Description: tx.Description,
-		Creator:     existing.Creator,
-		Owner:       existing.Owner,
-		Runtime:     existing.Runtime,
-		Code:        existing.Code,
+		Name:          tx.Name,
+		Description:   tx.Description,
+		Creator:       existing.Creator,
+		Owner:         existing.Owner,
+		Runtime:       existing.Runtime,
+		Code:          existing.Code,
+		UpgradePolicy: existing.UpgradePolicy,
 
 		// contract update history
 		TxId:           tx.Self.TxId,
@@ -361,7 +400,16 @@ func (tx *TxUpdateContract) ExecuteTx(se *StateEngine, hasFee bool) UpdateContra
 			updatedContract.Owner = tx.Owner
 		}
 	}
-	if hasFee && tx.Code != "" && tx.Code != existing.Code {
+	scheduled := false
+	if hasFee && codeChanged {
+		if policy.Mode == contracts.UpgradeTimelock {
+			if _, err := se.contractUpgrades.GetPendingUpgrade(tx.Id); err == nil {
+				return UpdateContractResult{
+					Success: false,
+					Err:     "upgrade already pending",
+				}
+			}
+		}
 		// update contract code
 		if wasm_runtime.NewFromString(tx.Runtime.String()).IsErr() {
 			return UpdateContractResult{
@@ -369,6 +417,12 @@ func (tx *TxUpdateContract) ExecuteTx(se *StateEngine, hasFee bool) UpdateContra
 				Err:     "runtime name is invalid",
 			}
 		}
+		if tx.Runtime.IsRust() && tx.Self.BlockHeight < se.sconf.ConsensusParams().RustRuntimeHeight {
+			return UpdateContractResult{
+				Success: false,
+				Err:     "rust runtime is not active",
+			}
+		}
 		election, err := se.electionDb.GetElectionByHeight(tx.Self.BlockHeight)
 		if err != nil {
 			return UpdateContractResult{
@@ -387,14 +441,168 @@ func (tx *TxUpdateContract) ExecuteTx(se *StateEngine, hasFee bool) UpdateContra
 		go func() {
 			se.da.Get(cidz, &common_types.GetOptions{})
 		}()
-		updatedContract.Code = tx.Code
-		updatedContract.Runtime = *tx.Runtime
+		if policy.Mode == contracts.UpgradeTimelock {
+			// new code waits out the timelock, metadata changes apply right away
+			err := se.contractUpgrades.InsertUpgrade(contracts.ContractUpgrade{
+				TxId:             tx.Self.TxId,
+				ContractId:       tx.Id,
+				Code:             tx.Code,
+				Runtime:          *tx.Runtime,
+				Migrate:          tx.Migrate,
+				Status:           contracts.UpgradePending,
+				ProposedHeight:   tx.Self.BlockHeight,
+				ActivationHeight: tx.Self.BlockHeight + policy.Delay,
+				UpdatedHeight:    tx.Self.BlockHeight,
+			})
+			if err != nil {
+				return UpdateContractResult{
+					Success: false,
+					Err:     "failed to schedule upgrade",
+				}
+			}
+			scheduled = true
+		} else {
+			updatedContract.Code = tx.Code
+			updatedContract.Runtime = *tx.Runtime
+		}
 	}
 	se.contractDb.RegisterContract(tx.Id, updatedContract)
 
+	codeUpdated := updatedContract.Code != existing.Code
+	if codeUpdated && tx.Migrate {
+		se.queueContractMigration(tx.Self, tx.Id, updatedContract.Owner)
+	}
+
 	return UpdateContractResult{
-		Success:     true,
-		CodeUpdated: updatedContract.Code != existing.Code,
+		Success:          true,
+		CodeUpdated:      codeUpdated,
+		UpgradeScheduled: scheduled,
+	}
+}
+
+type TxCancelContractUpgrade struct {
+	Self  TxSelf `json:"-"`
+	NetId string `json:"net_id"`
+	Id    string `json:"id"`
+}
+
+func (tx TxCancelContractUpgrade) Type() string {
+	return "cancel_contract_upgrade"
+}
+
+func (tx TxCancelContractUpgrade) TxSelf() TxSelf {
+	return tx.Self
+}
+
+func (tx *TxCancelContractUpgrade) ToData() map[string]interface{} {
+	return map[string]interface{}{
+		"net_id": tx.NetId,
+		"id":     tx.Id,
+	}
+}
+
+// Cancels the pending upgrade of a timelocked contract. Only the current owner may cancel.
+func (tx *TxCancelContractUpgrade) ExecuteTx(se *StateEngine) TxResult {
+	if tx.NetId != se.sconf.NetId() {
+		return TxResult{
+			Success: false,
+			Ret:     "wrong net ID",
+		}
+	}
+	if len(tx.Self.RequiredAuths) == 0 {
+		return TxResult{
+			Success: false,
+			Ret:     "cannot cancel upgrade with posting auths",
+		}
+	}
+	existing, err := se.contractDb.ContractById(tx.Id, tx.Self.BlockHeight)
+	if err != nil {
+		return TxResult{
+			Success: false,
+			Ret:     "contract not found",
+		}
+	}
+	if tx.Self.RequiredAuths[0] != existing.Owner {
+		return TxResult{
+			Success: false,
+			Ret:     "not owner",
+		}
+	}
+	upgrade, err := se.contractUpgrades.GetPendingUpgrade(tx.Id)
+	if err != nil {
+		return TxResult{
+			Success: false,
+			Ret:     "no pending upgrade",
+		}
+	}
+	se.contractUpgrades.SetUpgradeStatus(upgrade.TxId, contracts.UpgradeCancelled, tx.Self.BlockHeight)
+
+	return TxResult{
+		Success: true,
+	}
+}
+
+// Cancels a scheduled call of a contract on behalf of its owner
+type TxCancelContractSchedule struct {
+	Self  TxSelf `json:"-"`
+	NetId string `json:"net_id"`
+	Id    string `json:"id"`
+}
+
+func (tx TxCancelContractSchedule) Type() string {
+	return "cancel_contract_schedule"
+}
+
+func (tx TxCancelContractSchedule) TxSelf() TxSelf {
+	return tx.Self
+}
+
+func (tx *TxCancelContractSchedule) ToData() map[string]interface{} {
+	return map[string]interface{}{
+		"net_id": tx.NetId,
+		"id":     tx.Id,
+	}
+}
+
+func (tx *TxCancelContractSchedule) ExecuteTx(se *StateEngine) TxResult {
+	if tx.NetId != se.sconf.NetId() {
+		return TxResult{
+			Success: false,
+			Ret:     "wrong net ID",
+		}
+	}
+	if len(tx.Self.RequiredAuths) == 0 {
+		return TxResult{
+			Success: false,
+			Ret:     "cannot cancel schedule with posting auths",
+		}
+	}
+	schedule, err := se.contractSchedules.GetSchedule(tx.Id)
+	if err != nil || schedule.Status != contracts.ScheduleActive {
+		return TxResult{
+			Success: false,
+			Ret:     "no active schedule",
+		}
+	}
+	existing, err := se.contractDb.ContractById(schedule.ContractId, tx.Self.BlockHeight)
+	if err != nil {
+		return TxResult{
+			Success: false,
+			Ret:     "contract not found",
+		}
+	}
+	if tx.Self.RequiredAuths[0] != existing.Owner {
+		return TxResult{
+			Success: false,
+			Ret:     "not owner",
+		}
+	}
+	schedule.Status = contracts.ScheduleCancelled
+	schedule.UpdatedHeight = tx.Self.BlockHeight
+	se.contractSchedules.SetSchedule(schedule)
+
+	return TxResult{
+		Success: true,
 	}
 }
 
@@ -693,6 +901,11 @@ func (t *TxProposeBlock) ExecuteTx(se *StateEngine) {
 		},
 		Ts:        t.Self.Timestamp,
 		DebugData: blockContentC,
+
+		Type:      t.SignedBlock.Type,
+		Version:   t.SignedBlock.Version,
+		Prevb:     t.SignedBlock.Headers.PrevBlock,
+		Signature: &t.SignedBlock.Signature,
 	})
 
 	txsToInjest := make([]TxPacket, 0)
@@ -758,10 +971,26 @@ func (t *TxProposeBlock) ExecuteTx(se *StateEngine) {
 			}
 			confirmedNonces[keyId].Nonces[tx.Headers.Nonce] = true
 
+			//Payers are ignored before they activate
+			payer := ""
+			if uint64(t.SignedBlock.Headers.Br[1]) >= se.SystemConfig().ConsensusParams().PayerHeight {
+				payer = tx.Headers.Payer
+			}
+
+			//Tips are ignored before they activate
+			tip, tipAsset := uint64(0), ""
+			if uint64(t.SignedBlock.Headers.Br[1]) >= se.SystemConfig().ConsensusParams().TipHeight {
+				tip, tipAsset = tx.Headers.Tip, tx.Headers.TipAsset
+			}
+
 			txs := tx.ToTransaction()
 			txsToInjest = append(txsToInjest, TxPacket{
-				TxId: tx.Cid().String(),
-				Ops:  txs,
+				TxId:     tx.Cid().String(),
+				Ops:      txs,
+				Tip:      tip,
+				TipAsset: tipAsset,
+				Producer: t.Self.RequiredAuths[0],
+				Payer:    payer,
 			})
 		} else if txContainer.Type() == "output" {
 			contractOutput := txContainer.AsContractOutput()
@@ -0,0 +1,71 @@
+package state_engine_test
+
+import (
+	"testing"
+	stateEngine "vsc-node/modules/state-processing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tipPacket(te *testEnv, txId string, from string, tip uint64, tipAsset string) stateEngine.TxPacket {
+	return stateEngine.TxPacket{
+		TxId: txId,
+		Ops: []stateEngine.VSCTransaction{&stateEngine.TxVSCTransfer{
+			Self: stateEngine.TxSelf{
+				TxId:          txId,
+				BlockHeight:   te.Reader.LastBlock,
+				RequiredAuths: []string{from},
+			},
+			NetId:  te.SE.SystemConfig().NetId(),
+			From:   from,
+			To:     "hive:carol",
+			Amount: "0.001",
+			Asset:  "hbd",
+		}},
+		Tip:      tip,
+		TipAsset: tipAsset,
+		Producer: "bob",
+	}
+}
+
+func TestTipsChargedBeforeOps(t *testing.T) {
+	te := newTestEnv()
+	te.Creator.Transfer("alice", "vsc.gateway", "10", "HBD", "deposit")
+	te.processAndWait()
+
+	balance := te.SE.LedgerSystem.GetBalance("hive:alice", te.Reader.LastBlock, "hbd")
+	require.Positive(t, balance)
+
+	te.SE.TxBatch = append(te.SE.TxBatch,
+		//The tip takes the whole balance, so the transfer fails but the tip stays paid
+		tipPacket(te, "tx-1", "hive:alice", uint64(balance), "hbd"),
+		//Nothing left to pay the tip with, the transaction is dropped
+		tipPacket(te, "tx-2", "hive:alice", 1, "hbd"),
+		//More RCs than the free amount of a Hive account
+		tipPacket(te, "tx-3", "hive:dave", 1_000_000, ""),
+	)
+	te.SE.ExecuteBatch()
+
+	out := te.SE.TxOutput["tx-1"]
+	assert.False(t, out.Ok)
+	assert.Equal(t, []string{"tx-1-tip"}, out.LedgerIds)
+
+	for _, txId := range []string{"tx-2", "tx-3"} {
+		out, ok := te.SE.TxOutput[txId]
+		require.True(t, ok)
+		assert.False(t, out.Ok)
+		assert.Empty(t, out.LedgerIds)
+	}
+	assert.Zero(t, te.SE.RcMap["hive:dave"])
+
+	tips := 0
+	for _, op := range te.SE.LedgerState.Oplog {
+		if op.Memo == "priority tip" {
+			tips++
+			assert.Equal(t, "hive:bob", op.To)
+			assert.Equal(t, balance, op.Amount)
+		}
+	}
+	assert.Equal(t, 1, tips)
+}
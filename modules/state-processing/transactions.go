@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"slices"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 	"vsc-node/lib/datalayer"
@@ -49,6 +50,10 @@ type TxVscCallContract struct {
 	Payload    json.RawMessage    `json:"payload"`
 	RcLimit    uint               `json:"rc_limit"`
 	Intents    []contracts.Intent `json:"intents"`
+
+	//Originated by state processing and run on RCs reserved beforehand,
+	//such as TSS sign callbacks. Never set by users.
+	System bool `json:"-"`
 }
 
 func errorToTxResult(err error, RCs int64) TxResult {
@@ -88,13 +93,18 @@ func (t TxVscCallContract) ExecuteTx(
 
 	code := node.RawData()
 
-	hasMinRCs, availableGas, _ := rcSession.CanConsume(rcPayer, t.Self.BlockHeight, 100)
+	gas := t.RcLimit
+	freeRcs := int64(0)
+	if !t.System {
+		hasMinRCs, availableGas, _ := rcSession.CanConsume(rcPayer, t.Self.BlockHeight, 100)
 
-	if !hasMinRCs {
-		return errorToTxResult(fmt.Errorf("minimum RC requirement is not met. RCs available: %d", availableGas), 100)
-	}
+		if !hasMinRCs {
+			return errorToTxResult(fmt.Errorf("minimum RC requirement is not met. RCs available: %d", availableGas), 100)
+		}
 
-	gas := min(uint(availableGas), t.RcLimit)
+		gas = min(uint(availableGas), t.RcLimit)
+		freeRcs = rcSystem.FreeRcRemaining(rcSession, rcPayer, t.Self.BlockHeight)
+	}
 
 	// Cap gas to prevent overflow when multiplied by CYCLE_GAS_PER_RC
 	const maxGas = ^uint(0) / params.CYCLE_GAS_PER_RC
@@ -132,8 +142,9 @@ func (t TxVscCallContract) ExecuteTx(
 		RequiredPostingAuths: t.Self.RequiredPostingAuths,
 		Caller:               caller,
 		Sender:               caller,
+		Payer:                rcPayer,
 		Intents:              t.Intents,
-	}, int64(gas), rcSystem.FreeRcRemaining(rcSession, rcPayer, t.Self.BlockHeight), gas*params.CYCLE_GAS_PER_RC, ledgerSession, callSession, 0)
+	}, int64(gas), freeRcs, gas*params.CYCLE_GAS_PER_RC, ledgerSession, callSession, 0)
 
 	validUtf8 := utf8.Valid(t.Payload)
 	if !validUtf8 {
@@ -156,6 +167,10 @@ func (t TxVscCallContract) ExecuteTx(
 	res := w.Execute(wasmCtx, gas*params.CYCLE_GAS_PER_RC, t.Action, payload, info.Runtime)
 
 	rcUsed := int64(math.Max(math.Ceil(float64(res.Gas)/params.CYCLE_GAS_PER_RC), 100))
+	if t.System {
+		// paid for when the RCs were reserved
+		rcUsed = 0
+	}
 
 	if res.Error != nil {
 		return TxResult{
@@ -303,7 +318,14 @@ func (tx TxVSCTransfer) ExecuteTx(
 		}
 	}
 
-	amount, err := common.SafeParseHiveFloat(tx.Amount)
+	var amount int64
+	var err error
+	if ledgerSystem.IsTokenAsset(tx.Asset) {
+		//Native tokens have no fixed precision, amounts are in base units
+		amount, err = strconv.ParseInt(tx.Amount, 10, 64)
+	} else {
+		amount, err = common.SafeParseHiveFloat(tx.Amount)
+	}
 
 	if err != nil {
 		return TxResult{
@@ -810,6 +832,262 @@ func (t *TxConsensusUnstake) Type() string {
 	return "consensus_unstake"
 }
 
+type TxScheduledTransfer struct {
+	Self  TxSelf
+	NetId string `json:"net_id"`
+
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Amount        string `json:"amount"`
+	Asset         string `json:"asset"`
+	Memo          string `json:"memo"`
+	ReleaseHeight uint64 `json:"release_height"`
+}
+
+func (tx *TxScheduledTransfer) ExecuteTx(
+	se common_types.StateEngine,
+	ledgerSession ledgerSystem.LedgerSession,
+	rcSession rcSystem.RcSession,
+	callSession *contract_session.CallSession,
+	rcPayer string,
+) TxResult {
+	if tx.NetId != se.SystemConfig().NetId() {
+		return errorToTxResult(fmt.Errorf("wrong net ID"), 50)
+	}
+	if tx.Self.BlockHeight < se.SystemConfig().ConsensusParams().ScheduledTransferHeight {
+		return errorToTxResult(fmt.Errorf("scheduled transfers not active"), 50)
+	}
+	if tx.To == "" || tx.From == "" {
+		return TxResult{
+			Success: false,
+			Ret:     "Invalid to/from",
+			RcUsed:  50,
+		}
+	}
+
+	if (!strings.HasPrefix(tx.To, "did:") && !strings.HasPrefix(tx.To, "hive:")) ||
+		(!strings.HasPrefix(tx.From, "did:") && !strings.HasPrefix(tx.From, "hive:")) {
+		return TxResult{
+			Success: false,
+			Ret:     "Invalid to/from",
+			RcUsed:  50,
+		}
+	}
+
+	if !slices.Contains(tx.Self.RequiredAuths, tx.From) {
+		return TxResult{
+			Success: false,
+			Ret:     "Invalid RequiredAuths",
+			RcUsed:  50,
+		}
+	}
+
+	amount, err := common.SafeParseHiveFloat(tx.Amount)
+
+	if err != nil {
+		return TxResult{
+			Success: false,
+			Ret:     fmt.Errorf("Invalid amount: %w", err).Error(),
+			RcUsed:  50,
+		}
+	}
+
+	ledgerResult := ledgerSession.ScheduleTransfer(ledgerSystem.ScheduledTransferParams{
+		Id:            MakeTxId(tx.Self.TxId, tx.Self.OpIndex),
+		BIdx:          int64(tx.Self.Index),
+		OpIdx:         int64(tx.Self.OpIndex),
+		From:          tx.From,
+		To:            tx.To,
+		Amount:        amount,
+		Asset:         tx.Asset,
+		Memo:          tx.Memo,
+		BlockHeight:   tx.Self.BlockHeight,
+		ReleaseHeight: tx.ReleaseHeight,
+	})
+
+	return TxResult{
+		Success: ledgerResult.Ok,
+		Ret:     ledgerResult.Msg,
+		RcUsed:  100,
+	}
+}
+
+func (tx *TxScheduledTransfer) ToData() map[string]interface{} {
+	return map[string]interface{}{
+		"from":           tx.From,
+		"to":             tx.To,
+		"amount":         tx.Amount,
+		"asset":          tx.Asset,
+		"memo":           tx.Memo,
+		"release_height": tx.ReleaseHeight,
+	}
+}
+
+func (tx *TxScheduledTransfer) TxSelf() TxSelf {
+	return tx.Self
+}
+
+func (tx *TxScheduledTransfer) Type() string {
+	return "scheduled_transfer"
+}
+
+type TxCancelScheduledTransfer struct {
+	Self  TxSelf
+	NetId string `json:"net_id"`
+
+	From string `json:"from"`
+	//Id of the scheduled transfer to cancel
+	Id string `json:"id"`
+}
+
+func (tx *TxCancelScheduledTransfer) ExecuteTx(
+	se common_types.StateEngine,
+	ledgerSession ledgerSystem.LedgerSession,
+	rcSession rcSystem.RcSession,
+	callSession *contract_session.CallSession,
+	rcPayer string,
+) TxResult {
+	if tx.NetId != se.SystemConfig().NetId() {
+		return errorToTxResult(fmt.Errorf("wrong net ID"), 50)
+	}
+	if tx.Self.BlockHeight < se.SystemConfig().ConsensusParams().ScheduledTransferHeight {
+		return errorToTxResult(fmt.Errorf("scheduled transfers not active"), 50)
+	}
+	if tx.From == "" || tx.Id == "" {
+		return TxResult{
+			Success: false,
+			Ret:     "Invalid from/id",
+			RcUsed:  50,
+		}
+	}
+
+	if !slices.Contains(tx.Self.RequiredAuths, tx.From) {
+		return TxResult{
+			Success: false,
+			Ret:     "Invalid RequiredAuths",
+			RcUsed:  50,
+		}
+	}
+
+	ledgerResult := ledgerSession.CancelScheduledTransfer(ledgerSystem.CancelScheduledTransferParams{
+		Id:          MakeTxId(tx.Self.TxId, tx.Self.OpIndex),
+		EscrowId:    tx.Id,
+		From:        tx.From,
+		BlockHeight: tx.Self.BlockHeight,
+	})
+
+	return TxResult{
+		Success: ledgerResult.Ok,
+		Ret:     ledgerResult.Msg,
+		RcUsed:  50,
+	}
+}
+
+func (tx *TxCancelScheduledTransfer) ToData() map[string]interface{} {
+	return map[string]interface{}{
+		"from": tx.From,
+		"id":   tx.Id,
+	}
+}
+
+func (tx *TxCancelScheduledTransfer) TxSelf() TxSelf {
+	return tx.Self
+}
+
+func (tx *TxCancelScheduledTransfer) Type() string {
+	return "cancel_scheduled_transfer"
+}
+
+// Sets the keys allowed to sign for an L2 account. The transaction must be
+// authorized by the account's current authority. An empty key set returns
+// the account to its own key.
+type TxUpdateAuthority struct {
+	Self  TxSelf
+	NetId string `json:"net_id"`
+
+	Account   string                `json:"account"`
+	Keys      []common.AuthorityKey `json:"keys"`
+	Threshold uint64                `json:"threshold"`
+}
+
+func (tx *TxUpdateAuthority) ExecuteTx(
+	se common_types.StateEngine,
+	ledgerSession ledgerSystem.LedgerSession,
+	rcSession rcSystem.RcSession,
+	callSession *contract_session.CallSession,
+	rcPayer string,
+) TxResult {
+	if tx.NetId != se.SystemConfig().NetId() {
+		return errorToTxResult(fmt.Errorf("wrong net ID"), 50)
+	}
+	if tx.Self.BlockHeight < se.SystemConfig().ConsensusParams().AuthorityHeight {
+		return errorToTxResult(fmt.Errorf("account authorities not active"), 50)
+	}
+	if !slices.Contains(tx.Self.RequiredAuths, tx.Account) {
+		return TxResult{
+			Success: false,
+			Ret:     "Invalid RequiredAuths",
+			RcUsed:  50,
+		}
+	}
+	if err := validateAuthority(tx.Account, tx.Keys, tx.Threshold); err != nil {
+		return errorToTxResult(err, 50)
+	}
+
+	return TxResult{
+		Success: true,
+		RcUsed:  100,
+	}
+}
+
+func validateAuthority(account string, keys []common.AuthorityKey, threshold uint64) error {
+	if !strings.HasPrefix(account, "did:") {
+		return fmt.Errorf("only DID accounts can set an authority")
+	}
+	if len(keys) == 0 && threshold == 0 {
+		return nil
+	}
+	if len(keys) > params.MAX_AUTHORITY_KEYS {
+		return fmt.Errorf("authority has more than %d keys", params.MAX_AUTHORITY_KEYS)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	totalWeight := uint64(0)
+	for _, key := range keys {
+		if _, err := dids.Parse(key.Key); err != nil {
+			return fmt.Errorf("invalid key %s: %w", key.Key, err)
+		}
+		if seen[key.Key] {
+			return fmt.Errorf("duplicate key %s", key.Key)
+		}
+		if key.Weight == 0 || key.Weight > math.MaxUint32 {
+			return fmt.Errorf("invalid weight for key %s", key.Key)
+		}
+		seen[key.Key] = true
+		totalWeight += key.Weight
+	}
+	if threshold == 0 || threshold > totalWeight {
+		return fmt.Errorf("threshold must be between 1 and the total key weight %d", totalWeight)
+	}
+	return nil
+}
+
+func (tx *TxUpdateAuthority) ToData() map[string]interface{} {
+	return map[string]interface{}{
+		"account":   tx.Account,
+		"keys":      tx.Keys,
+		"threshold": tx.Threshold,
+	}
+}
+
+func (tx *TxUpdateAuthority) TxSelf() TxSelf {
+	return tx.Self
+}
+
+func (tx *TxUpdateAuthority) Type() string {
+	return "update_authority"
+}
+
 type TransactionSig struct {
 	Type string       `json:"__t"`
 	Sigs []common.Sig `json:"sigs"`
@@ -948,6 +1226,7 @@ func (oplog *Oplog) ExecuteTx(se *StateEngine) {
 		EndHeight:   oplog.EndBlock,
 		StartHeight: startBlock,
 	})
+	se.queueBridgeWithdrawals(aoplog, oplog.Self.BlockHeight)
 
 	for _, v := range oplog.Outputs {
 		ledgerOps := make([]ledgerSystem.OpLogEvent, 0)
@@ -982,34 +1261,6 @@ type OffchainTransaction struct {
 	// Tx []transactionpool.VSCTransactionOp `json:"tx"`
 }
 
-// Verify signature of vsc transaction
-// Note: VSC uses a segratated witness format (segwit) for transaction signatures
-// This eliminates malleability issues and grants flexibility
-// Thus signatures are separately stored and serialized
-// Segwit only applies to transactions generally speaking
-
-// Note: Signatures are verified on a 1:1 pubKey:sig structure
-// In other words, signatures must be sorted the same as requiredAuths sort.
-// Only applicable for multisig TXs
-func (tx *OffchainTransaction) Verify(txSig TransactionSig, nonce int) (bool, error) {
-	//Do verification logic using Key DID and Ethereum DID
-
-	blk, err := tx.ToBlock()
-	if err != nil {
-		return false, err
-	}
-
-	didBufs := make([]dids.DID, len(tx.Headers.RequiredAuths))
-	for i, auth := range tx.Headers.RequiredAuths {
-		didBufs[i], err = dids.Parse(auth)
-		if err != nil {
-			return false, err
-		}
-	}
-
-	return common.VerifySignatures(didBufs, blk, txSig.Sigs)
-}
-
 func (tx *OffchainTransaction) Encode() ([]byte, error) {
 	return common.EncodeDagCbor(tx)
 }
@@ -1084,6 +1335,9 @@ func (tx *OffchainTransaction) Ingest(se *StateEngine, vscBlockTxId string, txSe
 		AnchoredId:     &vscBlockTxId,
 		Nonce:          tx.Headers.Nonce,
 		RcLimit:        tx.Headers.RcLimit,
+		Tip:            tx.Headers.Tip,
+		TipAsset:       tx.Headers.TipAsset,
+		Payer:          tx.Headers.Payer,
 		RequiredAuths:  tx.Headers.RequiredAuths,
 		OpTypes:        opTypes,
 		Ops:            opList,
@@ -1151,6 +1405,30 @@ func (tx *OffchainTransaction) ToTransaction() []VSCTransaction {
 			transactionpool.DecodeTxCbor(op, &stakeTx)
 
 			vtx = &stakeTx
+		case "scheduled_transfer":
+			scheduledTx := TxScheduledTransfer{
+				Self:  self,
+				NetId: tx.Headers.NetId,
+			}
+			transactionpool.DecodeTxCbor(op, &scheduledTx)
+
+			vtx = &scheduledTx
+		case "cancel_scheduled_transfer":
+			cancelTx := TxCancelScheduledTransfer{
+				Self:  self,
+				NetId: tx.Headers.NetId,
+			}
+			transactionpool.DecodeTxCbor(op, &cancelTx)
+
+			vtx = &cancelTx
+		case "update_authority":
+			authorityTx := TxUpdateAuthority{
+				Self:  self,
+				NetId: tx.Headers.NetId,
+			}
+			transactionpool.DecodeTxCbor(op, &authorityTx)
+
+			vtx = &authorityTx
 		}
 
 		output = append(output, vtx)
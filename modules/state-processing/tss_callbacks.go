@@ -0,0 +1,90 @@
+package state_engine
+
+import (
+	"encoding/json"
+	"vsc-node/modules/common/params"
+	tss_db "vsc-node/modules/db/vsc/tss"
+)
+
+// Required auth of the callback calls made once a signing request completes
+const tssCallbackAuth = "system:tss"
+
+// Payload a sign callback entrypoint is called with
+type signCallbackPayload struct {
+	KeyId string `json:"key_id"`
+	Msg   string `json:"msg"`
+	Sig   string `json:"sig"`
+}
+
+// Records the signing request of a contract sign op. Callbacks on messages
+// that were already signed are called right away with the known signature.
+func (se *StateEngine) requestSignature(contractId string, op tss_db.TssOp, self TxSelf, callbackTxId string) {
+	req := tss_db.TssRequest{
+		KeyId:  op.KeyId,
+		Status: tss_db.SignPending,
+		Msg:    op.Args,
+	}
+	if op.Callback != "" {
+		req.Callback = &tss_db.SignCallback{
+			ContractId: contractId,
+			Action:     op.Callback,
+			RcLimit:    min(op.RcLimit, params.TSS_SIGN_CALLBACK_MAX_RC_LIMIT),
+		}
+		existing, _ := se.tssRequests.FindRequests(op.KeyId, []string{op.Args})
+		for _, r := range existing {
+			if r.Status == tss_db.SignComplete {
+				self.TxId = callbackTxId
+				se.queueSignCallback(*req.Callback, r, self)
+				return
+			}
+		}
+	}
+	se.tssRequests.SetSignedRequest(req)
+}
+
+// Stores a verified signature and hands it to everything waiting on it
+func (se *StateEngine) completeSignRequest(keyId string, msg string, sig string, self TxSelf, sigIdx int) {
+	pending, _ := se.tssRequests.FindRequests(keyId, []string{msg})
+
+	se.tssRequests.UpdateRequest(tss_db.TssRequest{
+		KeyId:  keyId,
+		Msg:    msg,
+		Sig:    sig,
+		Status: tss_db.SignComplete,
+	})
+	se.markBridgeSigned(keyId, msg, sig, self.BlockHeight)
+
+	for _, r := range pending {
+		if r.Status == tss_db.SignComplete || r.Callback == nil {
+			continue
+		}
+		r.Sig = sig
+		callbackSelf := self
+		callbackSelf.TxId = MakeTxId(self.TxId, sigIdx+1)
+		se.queueSignCallback(*r.Callback, r, callbackSelf)
+	}
+}
+
+// Queues the callback of a completed signing request as a system call
+func (se *StateEngine) queueSignCallback(cb tss_db.SignCallback, req tss_db.TssRequest, self TxSelf) {
+	payload, _ := json.Marshal(signCallbackPayload{
+		KeyId: req.KeyId,
+		Msg:   req.Msg,
+		Sig:   req.Sig,
+	})
+	self.RequiredAuths = []string{tssCallbackAuth}
+	self.RequiredPostingAuths = nil
+
+	se.TxBatch = append(se.TxBatch, TxPacket{
+		TxId: self.TxId,
+		Ops: []VSCTransaction{TxVscCallContract{
+			Self:       self,
+			NetId:      se.sconf.NetId(),
+			ContractId: cb.ContractId,
+			Action:     cb.Action,
+			Payload:    payload,
+			RcLimit:    cb.RcLimit,
+			System:     true,
+		}},
+	})
+}
@@ -0,0 +1,105 @@
+package state_engine_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"vsc-node/modules/db/vsc/contracts"
+	tss_db "vsc-node/modules/db/vsc/tss"
+	stateEngine "vsc-node/modules/state-processing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sign callback calls waiting in the batch
+func signCallbacks(te *testEnv) []stateEngine.TxVscCallContract {
+	calls := make([]stateEngine.TxVscCallContract, 0)
+	for _, packet := range te.SE.TxBatch {
+		for _, op := range packet.Ops {
+			if call, ok := op.(stateEngine.TxVscCallContract); ok && call.System {
+				calls = append(calls, call)
+			}
+		}
+	}
+	return calls
+}
+
+func ingestSignOp(te *testEnv, outputId string, op tss_db.TssOp) {
+	output := stateEngine.ContractOutput{
+		Id:         outputId,
+		ContractId: "contract-1",
+		Results:    []contracts.ContractOutputResult{{Ok: true, TssOps: []tss_db.TssOp{op}}},
+	}
+	output.Ingest(te.SE, stateEngine.TxSelf{TxId: "block-tx", BlockHeight: te.Reader.LastBlock}, 0)
+}
+
+func TestTssSignCallback(t *testing.T) {
+	te := newTestEnv()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	te.TssKeys.Keys["contract-1-main"] = tss_db.TssKey{
+		Id:        "contract-1-main",
+		Status:    tss_db.TssKeyActive,
+		Algo:      tss_db.EddsaType,
+		PublicKey: hex.EncodeToString(pub),
+	}
+
+	msg := make([]byte, 32)
+	msg[0] = 1
+	msgHex := hex.EncodeToString(msg)
+	ingestSignOp(te, "output-1", tss_db.TssOp{
+		Type:     "sign",
+		KeyId:    "contract-1-main",
+		Args:     msgHex,
+		Callback: "on_signed",
+		RcLimit:  500,
+	})
+
+	requests, _ := te.TssRequests.FindRequests("contract-1-main", []string{msgHex})
+	require.Len(t, requests, 1)
+	require.NotNil(t, requests[0].Callback)
+	assert.Equal(t, tss_db.SignCallback{ContractId: "contract-1", Action: "on_signed", RcLimit: 500}, *requests[0].Callback)
+	assert.Empty(t, signCallbacks(te))
+
+	sig := hex.EncodeToString(ed25519.Sign(priv, msg))
+	signed, _ := json.Marshal(map[string]any{
+		"packet": []map[string]string{{
+			"key_id": "contract-1-main",
+			"msg":    msgHex,
+			"sig":    sig,
+		}},
+	})
+	te.Creator.CustomJson(stateEngine.MockJson{
+		RequiredAuths: []string{"vsc.mocknet"},
+		Id:            "vsc.tss_sign",
+		Json:          string(signed),
+	})
+	te.processAndWait()
+
+	calls := signCallbacks(te)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "contract-1", calls[0].ContractId)
+	assert.Equal(t, "on_signed", calls[0].Action)
+	assert.Equal(t, uint(500), calls[0].RcLimit)
+	assert.Equal(t, []string{"system:tss"}, calls[0].Self.RequiredAuths)
+	payload := map[string]string{}
+	require.NoError(t, json.Unmarshal(calls[0].Payload, &payload))
+	assert.Equal(t, map[string]string{"key_id": "contract-1-main", "msg": msgHex, "sig": sig}, payload)
+
+	//Callbacks on messages already signed are called right away
+	ingestSignOp(te, "output-2", tss_db.TssOp{
+		Type:     "sign",
+		KeyId:    "contract-1-main",
+		Args:     msgHex,
+		Callback: "on_signed_again",
+		RcLimit:  1_000_000,
+	})
+	calls = signCallbacks(te)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "on_signed_again", calls[1].Action)
+	assert.Equal(t, "output-2-1", calls[1].Self.TxId)
+	assert.Less(t, calls[1].RcLimit, uint(1_000_000))
+}
@@ -8,6 +8,14 @@ import (
 type TxPacket struct {
 	TxId string
 	Ops  []VSCTransaction
+
+	//Priority tip of an offchain transaction, paid to the producer of
+	//the block including it
+	Tip      uint64
+	TipAsset string
+	Producer string
+	//Account sponsoring RCs and tips, empty for the first required auth
+	Payer string
 }
 
 type TxOutput struct {
@@ -29,13 +37,15 @@ type ContractIdResult struct {
 }
 
 type ContractResult struct {
-	Success bool
-	Ret     string
-	Err     *contracts.ContractOutputError
-	ErrMsg  string
-	TxId    string
-	Logs    []string
-	TssOps  []tss_db.TssOp
+	Success   bool
+	Ret       string
+	Err       *contracts.ContractOutputError
+	ErrMsg    string
+	TxId      string
+	Logs      []string
+	TssOps    []tss_db.TssOp
+	Events    []contracts.ContractEvent
+	Schedules []contracts.ScheduleOp
 }
 
 // More information about the TX
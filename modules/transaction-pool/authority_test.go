@@ -0,0 +1,20 @@
+package transactionpool
+
+import (
+	"testing"
+	"vsc-node/modules/common/params"
+)
+
+func TestValidateOpsAuthorityHeight(t *testing.T) {
+	ops := []VSCTransactionOp{{Type: "call"}, {Type: "update_authority"}}
+	consensusParams := params.ConsensusParams{AuthorityHeight: 100}
+	if validateOps(ops, consensusParams, 99) == nil {
+		t.Fatal("expected authority updates to be rejected below the authority height")
+	}
+	if validateOps(ops[:1], consensusParams, 99) != nil {
+		t.Fatal("expected other ops to be accepted below the authority height")
+	}
+	if validateOps(ops, consensusParams, 100) != nil {
+		t.Fatal("expected authority updates to be accepted from the authority height")
+	}
+}
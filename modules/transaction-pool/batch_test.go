@@ -0,0 +1,32 @@
+package transactionpool
+
+import "testing"
+
+func TestBatchStateAccumulates(t *testing.T) {
+	batch := newBatchState()
+	if batch.hasNonce("alice", 1) || batch.reservedRcs("hive:alice") != 0 {
+		t.Fatal("new batch should be empty")
+	}
+
+	batch.accept("alice", 1, "hive:alice", 100)
+	batch.accept("alice", 2, "hive:alice", 250)
+	batch.accept("bob", 1, "hive:bob", 50)
+
+	if !batch.hasNonce("alice", 1) || !batch.hasNonce("alice", 2) || batch.hasNonce("alice", 3) {
+		t.Fatal("expected nonces 1 and 2 of alice only")
+	}
+	if got := batch.reservedRcs("hive:alice"); got != 350 {
+		t.Fatalf("expected 350 reserved RCs, got %d", got)
+	}
+	if got := batch.reservedRcs("hive:bob"); got != 50 {
+		t.Fatalf("expected 50 reserved RCs, got %d", got)
+	}
+}
+
+func TestBatchStateNil(t *testing.T) {
+	var batch *batchState
+	batch.accept("alice", 1, "hive:alice", 100)
+	if batch.hasNonce("alice", 1) || batch.reservedRcs("hive:alice") != 0 {
+		t.Fatal("nil batch should not track anything")
+	}
+}
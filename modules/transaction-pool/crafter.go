@@ -123,8 +123,8 @@ func (ib *InternalBroadcast) Broadcast(tx SerializedVSCTransaction) (string, err
 type VSCTransfer struct {
 	From   string `json:"from"`   //From account (e.g. "did:key:z6MkmzUVuC9rdXtDgrfUDRJqBZKUAwpAy3k1dDscsmvK5ftb")
 	To     string `json:"to"`     //To account (e.g. "hive:vaultec")
-	Amount string `json:"amount"` //Amount in decimal format (e.g. "0.001")
-	Asset  string `json:"asset"`  //Example: "hbd" or "hive" must be lowercase
+	Amount string `json:"amount"` //Amount in decimal format (e.g. "0.001"), in base units for native tokens (e.g. "1000")
+	Asset  string `json:"asset"`  //Example: "hbd" or "hive" must be lowercase, or a native token "<contract id>:<SYMBOL>"
 	//NOTE: NetId should be included in headers
 	NetId string `json:"-"` //NetId is included in custom_json as "net_id" if hive transaction; otherwise it's in headers
 }
@@ -614,6 +614,12 @@ type VSCTransaction struct {
 	Nonce   uint64
 	NetId   string // NetId is included in headers
 	RcLimit uint64 // Optional; 0 means use the protocol default (500)
+	// Optional priority tip, paid in TipAsset (rc by default)
+	Tip      uint64
+	TipAsset string
+	// Optional account paying RCs and tips, it signs the transaction
+	// without becoming a required auth
+	Payer string
 }
 
 func (tx *VSCTransaction) Serialize() (SerializedVSCTransaction, error) {
@@ -653,6 +659,9 @@ func (tx *VSCTransaction) ToShell() VSCTransactionShell {
 			RequiredAuths: requiredAuths,
 			NetId:         tx.NetId,
 			RcLimit:       rcLimit,
+			Tip:           tx.Tip,
+			TipAsset:      tx.TipAsset,
+			Payer:         tx.Payer,
 		},
 		Tx: tx.Ops,
 	}
@@ -693,6 +702,9 @@ func (tx *VSCTransaction) ToSignableBlock() (blocks.Block, error) {
 			RequiredAuths: shell.Headers.RequiredAuths,
 			NetId:         shell.Headers.NetId,
 			RcLimit:       shell.Headers.RcLimit,
+			Tip:           shell.Headers.Tip,
+			TipAsset:      shell.Headers.TipAsset,
+			Payer:         shell.Headers.Payer,
 		},
 		"tx": ops,
 	}
@@ -745,6 +757,9 @@ func (tx *VSCTransaction) HashEip712() (cid.Cid, []byte, error) {
 			RequiredAuths: shell.Headers.RequiredAuths,
 			NetId:         shell.Headers.NetId,
 			RcLimit:       shell.Headers.RcLimit,
+			Tip:           shell.Headers.Tip,
+			TipAsset:      shell.Headers.TipAsset,
+			Payer:         shell.Headers.Payer,
 		},
 		"tx": ops,
 	}
@@ -816,6 +831,9 @@ func (tx *VSCTransaction) Hash() (cid.Cid, []byte, error) {
 			RequiredAuths: shell.Headers.RequiredAuths,
 			NetId:         shell.Headers.NetId,
 			RcLimit:       shell.Headers.RcLimit,
+			Tip:           shell.Headers.Tip,
+			TipAsset:      shell.Headers.TipAsset,
+			Payer:         shell.Headers.Payer,
 		},
 		"tx": ops,
 	}
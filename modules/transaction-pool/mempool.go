@@ -0,0 +1,240 @@
+package transactionpool
+
+import (
+	"slices"
+	"strings"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/transactions"
+)
+
+// Reasons a pending transaction is left out of the next block
+const (
+	//Nonce is below the sender's next nonce
+	BlockedStaleNonce = "stale_nonce"
+	//RC limit is below the consensus minimum
+	BlockedRcLimit = "rc_limit_below_minimum"
+	//A nonce between the sender's next nonce and this transaction is missing
+	BlockedNonceGap = "nonce_gap"
+	//An earlier transaction of the same sender is blocked
+	BlockedPrecedingTx = "preceding_tx_blocked"
+	//The payer does not have the RCs for the RC limit and RC tip
+	BlockedInsufficientRcs = "insufficient_rcs"
+	//The payer does not have the HBD for the tip
+	BlockedTipUnpaid = "tip_unpaid"
+	//The block RC budget ran out before the transaction was reached
+	BlockedBlockFull = "block_full"
+)
+
+// Pending transaction along with why it would not make the next block
+type MempoolTx struct {
+	transactions.TransactionRecord
+	//Empty when the transaction fits in the next block
+	BlockedReason string `json:"blocked_reason,omitempty"`
+}
+
+// Pending transactions of a sender, in nonce order
+type MempoolAccount struct {
+	RequiredAuths []string    `json:"required_auths"`
+	Nonce         int64       `json:"nonce"`
+	Transactions  []MempoolTx `json:"transactions"`
+}
+
+type MempoolOpCount struct {
+	OpType string `json:"op_type"`
+	Count  int    `json:"count"`
+}
+
+type MempoolSummary struct {
+	//Unconfirmed transactions, whether or not they are blocked
+	Size int `json:"size"`
+	//Transactions that fit in the next block
+	Ready int `json:"ready"`
+	//Sum of the RC limits of all unconfirmed transactions
+	Rcs      uint64           `json:"rcs"`
+	ByOpType []MempoolOpCount `json:"by_op_type"`
+	//Unconfirmed transaction seen first, nil if the mempool is empty
+	Oldest *transactions.TransactionRecord `json:"oldest,omitempty"`
+}
+
+type Mempool struct {
+	Summary  MempoolSummary   `json:"summary"`
+	Accounts []MempoolAccount `json:"accounts"`
+}
+
+// Balances checked while inspecting the mempool. A nil func skips the check.
+type MempoolBalances struct {
+	AvailableRcs func(account string) int64
+	Hbd          func(account string) int64
+}
+
+// Sequences unconfirmed transactions the way the block producer does and
+// explains why each transaction left out of the next block was skipped.
+// nonces holds the next nonce of each sender by HashKeyAuths.
+func InspectTransactions(
+	txs []transactions.TransactionRecord,
+	nonces map[string]int64,
+	height uint64,
+	consensusParams params.ConsensusParams,
+	balances MempoolBalances,
+) Mempool {
+	reasons := make(map[string]string)
+	pending := make([]transactions.TransactionRecord, 0, len(txs))
+	for _, tx := range txs {
+		keyId := HashKeyAuths(tx.RequiredAuths)
+		if tx.Nonce < nonces[keyId] {
+			reasons[tx.Id] = BlockedStaleNonce
+		} else if tx.RcLimit < consensusParams.MinRcLimit {
+			reasons[tx.Id] = BlockedRcLimit
+		} else {
+			pending = append(pending, tx)
+		}
+	}
+
+	rcsUsed := make(map[string]int64)
+	hbdTips := make(map[string]int64)
+	seq := SequenceTransactions(pending, nonces, consensusParams.BlockRcBudget(height), height >= consensusParams.TipHeight, func(tx transactions.TransactionRecord) bool {
+		payer := RcPayer(tx.RequiredAuths, tx.Payer)
+		if tx.TipAsset == TipAssetHbd && tx.Tip > 0 && balances.Hbd != nil {
+			if balances.Hbd(payer)-hbdTips[payer] < int64(tx.Tip) {
+				reasons[tx.Id] = BlockedTipUnpaid
+				return false
+			}
+		}
+		rcs := int64(RequiredRcs(tx.RcLimit, tx.Tip, tx.TipAsset))
+		if balances.AvailableRcs != nil && balances.AvailableRcs(payer)-rcsUsed[payer] < rcs {
+			reasons[tx.Id] = BlockedInsufficientRcs
+			return false
+		}
+		rcsUsed[payer] += rcs
+		if tx.TipAsset == TipAssetHbd {
+			hbdTips[payer] += int64(tx.Tip)
+		}
+		delete(reasons, tx.Id)
+		return true
+	})
+	sequenced := make(map[string]bool, len(seq.Txs))
+	for _, tx := range seq.Txs {
+		sequenced[tx.Id] = true
+	}
+
+	accounts := make(map[string]*MempoolAccount)
+	for _, tx := range txs {
+		keyId := HashKeyAuths(tx.RequiredAuths)
+		if accounts[keyId] == nil {
+			accounts[keyId] = &MempoolAccount{
+				RequiredAuths: tx.RequiredAuths,
+				Nonce:         nonces[keyId],
+			}
+		}
+		accounts[keyId].Transactions = append(accounts[keyId].Transactions, MempoolTx{TransactionRecord: tx})
+	}
+
+	res := Mempool{
+		Summary: MempoolSummary{
+			Size:     len(txs),
+			ByOpType: make([]MempoolOpCount, 0),
+		},
+		Accounts: make([]MempoolAccount, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		slices.SortFunc(account.Transactions, func(a, b MempoolTx) int {
+			if a.Nonce != b.Nonce {
+				return int(a.Nonce - b.Nonce)
+			}
+			return strings.Compare(a.Id, b.Id)
+		})
+
+		//Walk the nonce chain, everything after the first blocked
+		//transaction waits on it
+		expected := account.Nonce
+		blocked := false
+		for i := range account.Transactions {
+			tx := &account.Transactions[i]
+			if sequenced[tx.Id] {
+				expected = tx.Nonce + 1
+				res.Summary.Ready++
+				continue
+			}
+			if reason, ok := reasons[tx.Id]; ok && (reason == BlockedStaleNonce || reason == BlockedRcLimit) {
+				tx.BlockedReason = reason
+				blocked = blocked || reason == BlockedRcLimit
+			} else if tx.Nonce < expected {
+				tx.BlockedReason = BlockedStaleNonce
+			} else if blocked {
+				tx.BlockedReason = BlockedPrecedingTx
+			} else if tx.Nonce > expected {
+				tx.BlockedReason = BlockedNonceGap
+				blocked = true
+			} else if ok {
+				tx.BlockedReason = reason
+				blocked = true
+			} else {
+				tx.BlockedReason = BlockedBlockFull
+				blocked = true
+			}
+		}
+		res.Accounts = append(res.Accounts, *account)
+	}
+	slices.SortFunc(res.Accounts, func(a, b MempoolAccount) int {
+		return strings.Compare(strings.Join(a.RequiredAuths, ","), strings.Join(b.RequiredAuths, ","))
+	})
+
+	opCounts := make(map[string]int)
+	for i, tx := range txs {
+		res.Summary.Rcs += tx.RcLimit
+		for _, opType := range tx.OpTypes {
+			opCounts[opType]++
+		}
+		if res.Summary.Oldest == nil || tx.FirstSeen.Before(res.Summary.Oldest.FirstSeen) {
+			res.Summary.Oldest = &txs[i]
+		}
+	}
+	for opType, count := range opCounts {
+		res.Summary.ByOpType = append(res.Summary.ByOpType, MempoolOpCount{OpType: opType, Count: count})
+	}
+	slices.SortFunc(res.Summary.ByOpType, func(a, b MempoolOpCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.OpType, b.OpType)
+	})
+
+	return res
+}
+
+// Inspects the mempool against the latest block, optionally limited to the
+// transactions account is required to sign
+func (tp *TransactionPool) Mempool(consensusParams params.ConsensusParams, account string) (Mempool, error) {
+	height, err := tp.hiveBlocks.GetHighestBlock()
+	if err != nil {
+		return Mempool{}, err
+	}
+	txs, err := tp.TxDb.FindUnconfirmedTransactions(height)
+	if err != nil {
+		return Mempool{}, err
+	}
+
+	nonceMap := make(map[string]int64)
+	for _, tx := range txs {
+		keyId := HashKeyAuths(tx.RequiredAuths)
+		if _, ok := nonceMap[keyId]; !ok {
+			nonceRecord, _ := tp.nonceDb.GetNonce(keyId)
+			nonceMap[keyId] = int64(nonceRecord.Nonce)
+		}
+	}
+
+	res := InspectTransactions(txs, nonceMap, height, consensusParams, MempoolBalances{
+		AvailableRcs: func(account string) int64 {
+			return tp.rcs.GetAvailableRCs(account, height)
+		},
+		Hbd: func(account string) int64 {
+			return tp.rcs.LedgerSystem.GetBalance(account, height, "hbd")
+		},
+	})
+	if account != "" {
+		res.Accounts = slices.DeleteFunc(res.Accounts, func(a MempoolAccount) bool {
+			return !slices.Contains(a.RequiredAuths, account)
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package transactionpool
+
+import (
+	"slices"
+	"testing"
+	"time"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/transactions"
+)
+
+func TestInspectTransactions(t *testing.T) {
+	txs := []transactions.TransactionRecord{
+		priorityTestTx("alice-0", "hive:alice", 0, 100, 0),
+		priorityTestTx("alice-1", "hive:alice", 1, 100, 0),
+		priorityTestTx("bob-2", "hive:bob", 2, 100, 0),
+		priorityTestTx("bob-3", "hive:bob", 3, 100, 0),
+		priorityTestTx("carol-0", "hive:carol", 0, 10, 0),
+		priorityTestTx("dave-0", "hive:dave", 0, 100, 0),
+		priorityTestTx("dave-1", "hive:dave", 1, 100, 0),
+	}
+	txs[0].OpTypes = []string{"transfer"}
+	txs[2].OpTypes = []string{"transfer", "call"}
+	txs[4].FirstSeen = txs[4].FirstSeen.Add(-time.Minute)
+	nonces := map[string]int64{
+		HashKeyAuths([]string{"hive:alice"}): 0,
+		HashKeyAuths([]string{"hive:bob"}):   0,
+		HashKeyAuths([]string{"hive:carol"}): 0,
+		HashKeyAuths([]string{"hive:dave"}):  0,
+	}
+
+	mempool := InspectTransactions(txs, nonces, 0, params.ConsensusParams{MinRcLimit: 50}, MempoolBalances{
+		//Dave can only pay for one transaction
+		AvailableRcs: func(account string) int64 {
+			if account == "hive:dave" {
+				return 150
+			}
+			return 1000
+		},
+	})
+
+	reasons := make(map[string]string)
+	for _, account := range mempool.Accounts {
+		for _, tx := range account.Transactions {
+			reasons[tx.Id] = tx.BlockedReason
+		}
+	}
+	want := map[string]string{
+		"alice-0": "",
+		"alice-1": "",
+		"bob-2":   BlockedNonceGap,
+		"bob-3":   BlockedPrecedingTx,
+		"carol-0": BlockedRcLimit,
+		"dave-0":  "",
+		"dave-1":  BlockedInsufficientRcs,
+	}
+	for id, reason := range want {
+		if reasons[id] != reason {
+			t.Fatalf("expected %s to be blocked by %q, got %q", id, reason, reasons[id])
+		}
+	}
+
+	summary := mempool.Summary
+	if summary.Size != 7 || summary.Ready != 3 || summary.Rcs != 610 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if want := []MempoolOpCount{{"transfer", 2}, {"call", 1}}; !slices.Equal(summary.ByOpType, want) {
+		t.Fatalf("expected op counts %v, got %v", want, summary.ByOpType)
+	}
+	if summary.Oldest == nil || summary.Oldest.Id != "carol-0" {
+		t.Fatalf("expected carol-0 to be the oldest, got %v", summary.Oldest)
+	}
+}
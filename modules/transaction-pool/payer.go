@@ -0,0 +1,37 @@
+package transactionpool
+
+import (
+	"fmt"
+	"slices"
+	"vsc-node/modules/common/params"
+)
+
+// A transaction may name a payer to sponsor it. RCs and tips are charged to
+// the payer instead of the first required auth. The payer signs the
+// transaction but is not a required auth, so it only consents to paying
+// and grants no authority over the ops.
+func validatePayer(headers VSCTransactionHeader, consensusParams params.ConsensusParams, height uint64) error {
+	if headers.Payer != "" && height < consensusParams.PayerHeight {
+		return fmt.Errorf("payers are not active")
+	}
+	return nil
+}
+
+// Accounts that must sign a transaction, its required auths and payer
+func signers(headers VSCTransactionHeader) []string {
+	if headers.Payer == "" || slices.Contains(headers.RequiredAuths, headers.Payer) {
+		return headers.RequiredAuths
+	}
+	return append(slices.Clone(headers.RequiredAuths), headers.Payer)
+}
+
+// Account RCs and tips are charged to
+func RcPayer(requiredAuths []string, payer string) string {
+	if payer != "" {
+		return payer
+	}
+	if len(requiredAuths) == 0 {
+		return ""
+	}
+	return requiredAuths[0]
+}
@@ -0,0 +1,61 @@
+package transactionpool
+
+import (
+	"slices"
+	"testing"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/transactions"
+)
+
+func TestPayer(t *testing.T) {
+	auths := []string{"hive:alice"}
+	if RcPayer(auths, "") != "hive:alice" || RcPayer(auths, "hive:sponsor") != "hive:sponsor" {
+		t.Fatal("expected the payer to default to the first required auth")
+	}
+
+	headers := VSCTransactionHeader{RequiredAuths: auths, Payer: "hive:sponsor"}
+	consensusParams := params.ConsensusParams{PayerHeight: 100}
+	if validatePayer(headers, consensusParams, 99) == nil {
+		t.Fatal("expected payers to be rejected below the payer height")
+	}
+	if validatePayer(VSCTransactionHeader{RequiredAuths: auths}, consensusParams, 99) != nil {
+		t.Fatal("expected unsponsored transactions to be accepted below the payer height")
+	}
+	if validatePayer(headers, consensusParams, 100) != nil {
+		t.Fatal("expected payers to be accepted from the payer height")
+	}
+
+	//The payer signs alongside the required auths without becoming one
+	op := VSCTransactionOp{Type: "call"}
+	op.RequiredAuths.Active = []string{"hive:alice"}
+	tx := VSCTransaction{Ops: []VSCTransactionOp{op}, Payer: "hive:sponsor"}
+	shell := tx.ToShell()
+	if !slices.Equal(shell.Headers.RequiredAuths, auths) || shell.Headers.Payer != "hive:sponsor" {
+		t.Fatalf("expected the payer outside the required auths, got %v", shell.Headers.RequiredAuths)
+	}
+	if want := []string{"hive:alice", "hive:sponsor"}; !slices.Equal(signers(shell.Headers), want) {
+		t.Fatalf("expected signers %v, got %v", want, signers(shell.Headers))
+	}
+	if !slices.Equal(signers(VSCTransactionHeader{RequiredAuths: auths, Payer: "hive:alice"}), auths) {
+		t.Fatal("expected a required auth paying to sign once")
+	}
+}
+
+func TestInspectSponsoredTransactions(t *testing.T) {
+	sponsored := priorityTestTx("alice-sponsored-0", "hive:alice", 0, 100, 0)
+	sponsored.Payer = "hive:sponsor"
+	nonces := map[string]int64{HashKeyAuths(sponsored.RequiredAuths): 0}
+
+	//Alice has no RCs, the sponsor does
+	mempool := InspectTransactions([]transactions.TransactionRecord{sponsored}, nonces, 0, params.ConsensusParams{}, MempoolBalances{
+		AvailableRcs: func(account string) int64 {
+			if account == "hive:sponsor" {
+				return 1000
+			}
+			return 0
+		},
+	})
+	if mempool.Summary.Ready != 1 {
+		t.Fatalf("expected the sponsored transaction to be ready, got %+v", mempool.Accounts)
+	}
+}
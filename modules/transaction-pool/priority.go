@@ -0,0 +1,224 @@
+package transactionpool
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/nonces"
+	"vsc-node/modules/db/vsc/transactions"
+)
+
+// Assets a priority tip can be paid in. Both use the same base units,
+// 1000 RCs being worth 1 HBD, so tips compare across assets.
+const (
+	TipAssetRc  = "rc"
+	TipAssetHbd = "hbd"
+)
+
+func validateTip(headers VSCTransactionHeader, consensusParams params.ConsensusParams, height uint64) error {
+	if headers.Tip == 0 && headers.TipAsset == "" {
+		return nil
+	}
+	if height < consensusParams.TipHeight {
+		return fmt.Errorf("tips are not active")
+	}
+	if headers.TipAsset != "" && headers.TipAsset != TipAssetRc && headers.TipAsset != TipAssetHbd {
+		return fmt.Errorf("invalid tip asset %s", headers.TipAsset)
+	}
+	return nil
+}
+
+// RCs consumed by a transaction, including a tip paid in RCs
+func RequiredRcs(rcLimit uint64, tip uint64, tipAsset string) uint64 {
+	if tipAsset == TipAssetHbd {
+		return rcLimit
+	}
+	return rcLimit + tip
+}
+
+// Unconfirmed transactions eligible for the block at slotHeight, along with
+// the next nonce of each sender
+func PendingTransactions(txDb transactions.Transactions, nonceDb nonces.Nonces, slotHeight uint64, minRcLimit uint64) ([]transactions.TransactionRecord, map[string]int64) {
+	prefilteredTxs, _ := txDb.FindUnconfirmedTransactions(slotHeight)
+
+	txRecords := make([]transactions.TransactionRecord, 0)
+	nonceMap := make(map[string]int64, len(prefilteredTxs))
+	for _, txRecord := range prefilteredTxs {
+		keyId := HashKeyAuths(txRecord.RequiredAuths)
+		if _, ok := nonceMap[keyId]; !ok {
+			nonceRecord, _ := nonceDb.GetNonce(keyId)
+			nonceMap[keyId] = int64(nonceRecord.Nonce)
+		}
+		if txRecord.Nonce >= nonceMap[keyId] && txRecord.RcLimit >= minRcLimit {
+			txRecords = append(txRecords, txRecord)
+		} else {
+			log.Debug("tx filtered out", "id", txRecord.Id, "txNonce", txRecord.Nonce, "dbNonce", nonceMap[keyId], "rcLimit", txRecord.RcLimit, "minRc", minRcLimit)
+		}
+	}
+	return txRecords, nonceMap
+}
+
+// Orders pending transactions for inclusion in a block.
+// The transactions are shuffled with a seed derived from their IDs. When
+// ordering by tip, they are sorted by ID before the shuffle, so the order
+// does not depend on how they were loaded, and stably sorted by tip after
+// it, highest first, so equal tips keep the shuffled order.
+func PriorityOrder(txs []transactions.TransactionRecord, byTip bool) []transactions.TransactionRecord {
+	ordered := make([]transactions.TransactionRecord, len(txs))
+	copy(ordered, txs)
+	if byTip {
+		slices.SortFunc(ordered, func(a, b transactions.TransactionRecord) int {
+			return strings.Compare(a.Id, b.Id)
+		})
+	}
+
+	ids := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		ids = append(ids, tx.Id)
+	}
+	if len(ids) == 0 {
+		return ordered
+	}
+	seed := []byte(HashKeyAuths(ids))
+	rando := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:]))))
+	rando.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	if !byTip {
+		return ordered
+	}
+
+	slices.SortStableFunc(ordered, func(a, b transactions.TransactionRecord) int {
+		if a.Tip > b.Tip {
+			return -1
+		} else if a.Tip < b.Tip {
+			return 1
+		}
+		return 0
+	})
+	return ordered
+}
+
+// Result of sequencing the mempool into a block
+type Sequence struct {
+	Txs []transactions.TransactionRecord
+	//RC limits of the sequenced transactions
+	Rcs uint64
+	//Whether transactions were left out because the block budget ran out
+	Full bool
+}
+
+// Sequences pending transactions into a block.
+//
+// Senders take turns in PriorityOrder, each contributing their
+// transactions in nonce order starting at nonces[sender]. A transaction
+// whose RC limit does not fit in the remaining budget is left out, as are
+// transactions include rejects. A budget of 0 means no limit.
+func SequenceTransactions(
+	txs []transactions.TransactionRecord,
+	nonces map[string]int64,
+	budget uint64,
+	byTip bool,
+	include func(tx transactions.TransactionRecord) bool,
+) Sequence {
+	queues := make(map[string][]transactions.TransactionRecord)
+	for _, tx := range txs {
+		keyId := HashKeyAuths(tx.RequiredAuths)
+		queues[keyId] = append(queues[keyId], tx)
+	}
+	for keyId := range queues {
+		slices.SortFunc(queues[keyId], func(a, b transactions.TransactionRecord) int {
+			return int(a.Nonce - b.Nonce)
+		})
+	}
+
+	nextNonce := make(map[string]int64, len(nonces))
+	for k, v := range nonces {
+		nextNonce[k] = v
+	}
+
+	seq := Sequence{Txs: make([]transactions.TransactionRecord, 0)}
+	for _, turn := range PriorityOrder(txs, byTip) {
+		keyId := HashKeyAuths(turn.RequiredAuths)
+		if len(queues[keyId]) == 0 {
+			continue
+		}
+		//Drop transactions competing with an already sequenced nonce
+		for len(queues[keyId]) > 1 && queues[keyId][0].Nonce < nextNonce[keyId] {
+			queues[keyId] = queues[keyId][1:]
+		}
+		tx := queues[keyId][0]
+		if tx.Nonce != nextNonce[keyId] {
+			log.Debug("tx nonce mismatch", "id", tx.Id, "txNonce", tx.Nonce, "expected", nextNonce[keyId])
+			continue
+		}
+		if budget > 0 && seq.Rcs+tx.RcLimit > budget {
+			seq.Full = true
+			continue
+		}
+		if include != nil && !include(tx) {
+			continue
+		}
+		queues[keyId] = queues[keyId][1:]
+		nextNonce[keyId]++
+		seq.Txs = append(seq.Txs, tx)
+		seq.Rcs += tx.RcLimit
+	}
+	return seq
+}
+
+// Lowest tip of the sequenced transactions, 0 if there were none
+func (seq Sequence) MinTip() uint64 {
+	if len(seq.Txs) == 0 {
+		return 0
+	}
+	minTip := seq.Txs[0].Tip
+	for _, tx := range seq.Txs {
+		minTip = min(minTip, tx.Tip)
+	}
+	return minTip
+}
+
+// Depth of the mempool and the tip needed to make it into the next block
+type MempoolStatus struct {
+	//Unconfirmed transactions eligible for inclusion
+	Depth int
+	//Sum of their RC limits
+	PendingRcs uint64
+	//Transactions and RCs that fit in the next block
+	NextBlockTxs  int
+	NextBlockRcs  uint64
+	BlockRcBudget uint64
+	//Tip needed to get ahead of the transactions that fit in the next
+	//block, 0 while the mempool fits in a block
+	NextBlockTip uint64
+}
+
+// Estimates the next block from the mempool. RC and tip balances are not
+// checked, so the estimate may include transactions a producer would skip.
+func (tp *TransactionPool) MempoolStatus(consensusParams params.ConsensusParams) (MempoolStatus, error) {
+	height, err := tp.hiveBlocks.GetHighestBlock()
+	if err != nil {
+		return MempoolStatus{}, err
+	}
+	pending, nonceMap := PendingTransactions(tp.TxDb, tp.nonceDb, height, consensusParams.MinRcLimit)
+	budget := consensusParams.BlockRcBudget(height)
+	seq := SequenceTransactions(pending, nonceMap, budget, height >= consensusParams.TipHeight, nil)
+
+	status := MempoolStatus{
+		Depth:         len(pending),
+		NextBlockTxs:  len(seq.Txs),
+		NextBlockRcs:  seq.Rcs,
+		BlockRcBudget: budget,
+	}
+	for _, tx := range pending {
+		status.PendingRcs += tx.RcLimit
+	}
+	if seq.Full {
+		status.NextBlockTip = seq.MinTip() + 1
+	}
+	return status, nil
+}
@@ -0,0 +1,148 @@
+package transactionpool
+
+import (
+	"slices"
+	"testing"
+	"vsc-node/modules/common/params"
+	"vsc-node/modules/db/vsc/transactions"
+)
+
+func priorityTestTx(id string, sender string, nonce int64, rcLimit uint64, tip uint64) transactions.TransactionRecord {
+	return transactions.TransactionRecord{
+		Id:            id,
+		RequiredAuths: []string{sender},
+		Nonce:         nonce,
+		RcLimit:       rcLimit,
+		Tip:           tip,
+	}
+}
+
+func sequencedIds(seq Sequence) []string {
+	ids := make([]string, 0, len(seq.Txs))
+	for _, tx := range seq.Txs {
+		ids = append(ids, tx.Id)
+	}
+	return ids
+}
+
+func TestSequenceByTip(t *testing.T) {
+	txs := []transactions.TransactionRecord{
+		priorityTestTx("alice-0", "hive:alice", 0, 100, 0),
+		priorityTestTx("bob-0", "hive:bob", 0, 100, 5),
+		priorityTestTx("carol-3", "hive:carol", 3, 100, 50),
+		//Tips on later nonces pull the sender's earlier transactions ahead
+		priorityTestTx("carol-4", "hive:carol", 4, 100, 0),
+	}
+	nonces := map[string]int64{"hive:alice": 0, "hive:bob": 0, "hive:carol": 3}
+
+	seq := SequenceTransactions(txs, nonces, 0, true, nil)
+	ids := sequencedIds(seq)
+	if len(ids) != 4 || ids[0] != "carol-3" || ids[1] != "bob-0" {
+		t.Fatalf("expected the highest tips first, got %v", ids)
+	}
+	//Equal tips keep the seeded shuffle order
+	rest := slices.Sorted(slices.Values(ids[2:]))
+	if !slices.Equal(rest, []string{"alice-0", "carol-4"}) {
+		t.Fatalf("expected the untipped transactions last, got %v", ids)
+	}
+	if seq.Full || seq.Rcs != 400 {
+		t.Fatalf("unexpected sequence totals: full=%v rcs=%d", seq.Full, seq.Rcs)
+	}
+
+	//The input order does not change the sequence
+	reversed := slices.Clone(txs)
+	slices.Reverse(reversed)
+	if !slices.Equal(sequencedIds(SequenceTransactions(reversed, nonces, 0, true, nil)), sequencedIds(seq)) {
+		t.Fatal("sequence depends on input order")
+	}
+}
+
+func TestSequenceTipHeight(t *testing.T) {
+	txs := []transactions.TransactionRecord{
+		priorityTestTx("alice-0", "hive:alice", 0, 100, 1),
+		priorityTestTx("bob-0", "hive:bob", 0, 100, 2),
+		priorityTestTx("carol-0", "hive:carol", 0, 100, 3),
+		priorityTestTx("dave-0", "hive:dave", 0, 100, 4),
+	}
+	nonces := map[string]int64{}
+	consensusParams := params.ConsensusParams{TipHeight: 100}
+
+	//Below the tip height, transactions keep the seeded shuffle of their load order
+	ids := sequencedIds(SequenceTransactions(txs, nonces, 0, 99 >= consensusParams.TipHeight, nil))
+	shuffled := make([]string, 0, len(txs))
+	for _, tx := range PriorityOrder(txs, false) {
+		shuffled = append(shuffled, tx.Id)
+	}
+	if !slices.Equal(ids, shuffled) {
+		t.Fatalf("expected the shuffled order %v, got %v", shuffled, ids)
+	}
+	if slices.Equal(ids, []string{"dave-0", "carol-0", "bob-0", "alice-0"}) {
+		t.Fatalf("expected tips to be ignored, got %v", ids)
+	}
+	if err := validateTip(VSCTransactionHeader{Tip: 1}, consensusParams, 99); err == nil {
+		t.Fatal("expected tips to be rejected below the tip height")
+	}
+	if err := validateTip(VSCTransactionHeader{}, consensusParams, 99); err != nil {
+		t.Fatalf("expected untipped transactions to be accepted: %v", err)
+	}
+
+	//From the tip height, transactions are ordered by tip
+	ids = sequencedIds(SequenceTransactions(txs, nonces, 0, 100 >= consensusParams.TipHeight, nil))
+	if want := []string{"dave-0", "carol-0", "bob-0", "alice-0"}; !slices.Equal(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	if err := validateTip(VSCTransactionHeader{Tip: 1}, consensusParams, 100); err != nil {
+		t.Fatalf("expected tips to be accepted from the tip height: %v", err)
+	}
+}
+
+func TestSequenceNonceGap(t *testing.T) {
+	txs := []transactions.TransactionRecord{
+		priorityTestTx("alice-1", "hive:alice", 1, 100, 10),
+		priorityTestTx("alice-2", "hive:alice", 2, 100, 0),
+	}
+
+	seq := SequenceTransactions(txs, map[string]int64{"hive:alice": 0}, 0, true, nil)
+	if len(seq.Txs) != 0 {
+		t.Fatalf("expected no transactions past a nonce gap, got %v", sequencedIds(seq))
+	}
+}
+
+func TestSequenceBudget(t *testing.T) {
+	txs := []transactions.TransactionRecord{
+		priorityTestTx("alice-0", "hive:alice", 0, 300, 1),
+		priorityTestTx("bob-0", "hive:bob", 0, 500, 20),
+		priorityTestTx("carol-0", "hive:carol", 0, 200, 0),
+	}
+
+	seq := SequenceTransactions(txs, map[string]int64{}, 800, true, nil)
+	if want := []string{"bob-0", "alice-0"}; !slices.Equal(sequencedIds(seq), want) {
+		t.Fatalf("expected %v, got %v", want, sequencedIds(seq))
+	}
+	if !seq.Full || seq.Rcs != 800 || seq.MinTip() != 1 {
+		t.Fatalf("unexpected sequence totals: full=%v rcs=%d minTip=%d", seq.Full, seq.Rcs, seq.MinTip())
+	}
+
+	//Rejected transactions free their share of the budget
+	seq = SequenceTransactions(txs, map[string]int64{}, 800, true, func(tx transactions.TransactionRecord) bool {
+		return tx.Id != "bob-0"
+	})
+	if want := []string{"alice-0", "carol-0"}; !slices.Equal(sequencedIds(seq), want) {
+		t.Fatalf("expected %v, got %v", want, sequencedIds(seq))
+	}
+	if seq.Full {
+		t.Fatal("expected the remaining transactions to fit")
+	}
+}
+
+func TestRequiredRcs(t *testing.T) {
+	if RequiredRcs(100, 20, "") != 120 || RequiredRcs(100, 20, TipAssetRc) != 120 {
+		t.Fatal("expected RC tips to add to the RC limit")
+	}
+	if RequiredRcs(100, 20, TipAssetHbd) != 100 {
+		t.Fatal("expected HBD tips to leave the RC limit unchanged")
+	}
+	if validateTip(VSCTransactionHeader{Tip: 1, TipAsset: "hive"}, params.ConsensusParams{}, 0) == nil {
+		t.Fatal("expected invalid tip asset to be rejected")
+	}
+}
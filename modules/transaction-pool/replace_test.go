@@ -0,0 +1,62 @@
+package transactionpool
+
+import (
+	"testing"
+	"vsc-node/modules/db/vsc/transactions"
+)
+
+type pendingTxDb struct {
+	transactions.Transactions
+	pending []transactions.TransactionRecord
+}
+
+func (p *pendingTxDb) FindUnconfirmedWithNonce(requiredAuths []string, nonce uint64) ([]transactions.TransactionRecord, error) {
+	results := make([]transactions.TransactionRecord, 0)
+	for _, rec := range p.pending {
+		if uint64(rec.Nonce) == nonce {
+			results = append(results, rec)
+		}
+	}
+	return results, nil
+}
+
+func replaceTestShell(nonce uint64, rcLimit uint64) VSCTransactionShell {
+	return VSCTransactionShell{Headers: VSCTransactionHeader{
+		Nonce:         nonce,
+		RequiredAuths: []string{"did:key:alice"},
+		RcLimit:       rcLimit,
+	}}
+}
+
+func TestReplaceByNonce(t *testing.T) {
+	tp := &TransactionPool{TxDb: &pendingTxDb{pending: []transactions.TransactionRecord{{
+		Id:            "pending",
+		Status:        transactions.TransactionStatusUnconfirmed,
+		RequiredAuths: []string{"did:key:alice"},
+		Nonce:         4,
+		RcLimit:       100,
+	}}}}
+
+	if _, err := tp.findReplaced("new", replaceTestShell(4, 100)); err == nil {
+		t.Fatal("expected replacement with equal rc_limit to be rejected")
+	}
+
+	ids, err := tp.findReplaced("new", replaceTestShell(4, 101))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != "pending" {
+		t.Fatalf("expected pending tx to be replaced, got %v", ids)
+	}
+
+	//Resubmitting the pending transaction itself is not a replacement
+	ids, err = tp.findReplaced("pending", replaceTestShell(4, 100))
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("expected no replacement, got %v %v", ids, err)
+	}
+
+	ids, err = tp.findReplaced("other", replaceTestShell(5, 1))
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("expected no replacement for a different nonce, got %v %v", ids, err)
+	}
+}
@@ -8,10 +8,15 @@ import (
 	"math"
 	"slices"
 	"strings"
+	"time"
 	"vsc-node/lib/datalayer"
 	"vsc-node/lib/dids"
+	"vsc-node/lib/vsclog"
 	"vsc-node/modules/common"
 	"vsc-node/modules/common/common_types"
+	"vsc-node/modules/common/params"
+	systemconfig "vsc-node/modules/common/system-config"
+	"vsc-node/modules/db/vsc/authorities"
 	"vsc-node/modules/db/vsc/elections"
 	"vsc-node/modules/db/vsc/hive_blocks"
 	"vsc-node/modules/db/vsc/nonces"
@@ -30,43 +35,133 @@ import (
 )
 
 type TransactionPool struct {
-	TxDb       transactions.Transactions
-	nonceDb    nonces.Nonces
-	rcs        *rcSystem.RcSystem
-	hiveBlocks hive_blocks.HiveBlocks
-	p2p        *libp2p.P2PServer
-	service    libp2p.PubSubService[p2pMessage]
-	datalayer  *datalayer.DataLayer
+	TxDb        transactions.Transactions
+	nonceDb     nonces.Nonces
+	authorities authorities.Authorities
+	rcs         *rcSystem.RcSystem
+	hiveBlocks  hive_blocks.HiveBlocks
+	p2p         *libp2p.P2PServer
+	service     libp2p.PubSubService[p2pMessage]
+	datalayer   *datalayer.DataLayer
 	// electionDataInfo elections.ElectionDataInfo
 	electionDb elections.Elections
 
-	conf common.IdentityConfig
+	conf  common.IdentityConfig
+	sconf systemconfig.SystemConfig
 }
 
 type IngestOptions struct {
 	Broadcast bool
+	//Run every check without indexing or broadcasting the transaction
+	DryRun bool
+}
+
+// Outcome of a single transaction within a batch
+type BatchResult struct {
+	Id  *cid.Cid
+	Err error
 }
 
 var MAX_TX_SIZE = 16384
+var MAX_BATCH_SIZE = 50
+
+// Number of blocks an unconfirmed transaction may wait for inclusion before it is evicted (~1 hour)
+var UNCONFIRMED_TX_TTL uint64 = 1200
+
+// How often expired transactions are evicted from the pool
+var EVICTION_INTERVAL = time.Minute
+
+var log = vsclog.Module("txpool")
 
 // Ingests and verifies a transaction
 func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...IngestOptions) (*cid.Cid, error) {
-	if sTx.Sig == nil {
-		return nil, errors.New("no signature provided")
+	return tp.ingestTx(sTx, nil, options...)
+}
+
+// Ingests and verifies an ordered list of transactions.
+// A transaction may depend on the nonce of an earlier transaction in the same batch,
+// which allows nonce chains to be submitted at once (or checked with DryRun).
+// Failing transactions do not stop the rest of the batch; anything chained onto them fails as well.
+func (tp *TransactionPool) IngestBatch(txs []SerializedVSCTransaction, options ...IngestOptions) ([]BatchResult, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transactions provided")
+	}
+	if len(txs) > MAX_BATCH_SIZE {
+		return nil, fmt.Errorf("too many transactions in batch %d > %d", len(txs), MAX_BATCH_SIZE)
+	}
+
+	batch := newBatchState()
+	results := make([]BatchResult, 0, len(txs))
+	for _, sTx := range txs {
+		id, err := tp.ingestTx(sTx, batch, options...)
+		if id == nil {
+			//Still report the ID of rejected transactions when possible
+			if c, cErr := txPrefix.Sum(sTx.Tx); cErr == nil {
+				id = &c
+			}
+		}
+		results = append(results, BatchResult{Id: id, Err: err})
+	}
+	return results, nil
+}
+
+// Prefix of transaction IDs
+var txPrefix = cid.Prefix{
+	Version:  1,
+	Codec:    uint64(multicodec.DagCbor),
+	MhType:   uint64(multihash.SHA2_256),
+	MhLength: -1,
+}
+
+// Nonces and RCs claimed by the transactions accepted so far in a batch
+type batchState struct {
+	nonces map[string]bool
+	rcs    map[string]uint64
+}
+
+func newBatchState() *batchState {
+	return &batchState{
+		nonces: make(map[string]bool),
+		rcs:    make(map[string]uint64),
 	}
+}
 
-	prefix := cid.Prefix{
-		Version:  1,
-		Codec:    uint64(multicodec.DagCbor),
-		MhType:   uint64(multihash.SHA2_256),
-		MhLength: -1,
+func batchNonceKey(hashAuths string, nonce uint64) string {
+	return fmt.Sprintf("%s:%d", hashAuths, nonce)
+}
+
+func (b *batchState) hasNonce(hashAuths string, nonce uint64) bool {
+	return b != nil && b.nonces[batchNonceKey(hashAuths, nonce)]
+}
+
+// RCs of the payer that earlier transactions of the batch need
+func (b *batchState) reservedRcs(payer string) uint64 {
+	if b == nil {
+		return 0
+	}
+	return b.rcs[payer]
+}
+
+func (b *batchState) accept(hashAuths string, nonce uint64, payer string, rcs uint64) {
+	if b == nil {
+		return
+	}
+	b.nonces[batchNonceKey(hashAuths, nonce)] = true
+	b.rcs[payer] += rcs
+}
+
+// batch holds what transactions accepted earlier in the same batch claimed, if any.
+// On success, the transaction's own nonce and RCs are added to it.
+func (tp *TransactionPool) ingestTx(sTx SerializedVSCTransaction, batch *batchState, options ...IngestOptions) (*cid.Cid, error) {
+	if sTx.Sig == nil {
+		return nil, errors.New("no signature provided")
 	}
 
 	if len(sTx.Tx) > MAX_TX_SIZE {
 		return nil, fmt.Errorf("transaction size too big %d > %d", len(sTx.Tx), MAX_TX_SIZE)
 	}
 
-	cidz, err := prefix.Sum(sTx.Tx)
+	cidz, err := txPrefix.Sum(sTx.Tx)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +177,9 @@ func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...Ing
 	if err := common.DecodeCbor(sTx.Tx, &txShell); err != nil {
 		return nil, err
 	}
+	if len(txShell.Tx) == 0 {
+		return nil, errors.New("transaction has no operations")
+	}
 
 	ops := make([]VSCTransactionSignOp, 0, len(txShell.Tx))
 
@@ -109,12 +207,7 @@ func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...Ing
 	}
 
 	bytes, err := common.EncodeDagCbor(txSignStruct)
-	cidz1, _ := cid.Prefix{
-		Version:  1,
-		Codec:    uint64(multicodec.DagCbor),
-		MhType:   uint64(multihash.SHA2_256),
-		MhLength: -1,
-	}.Sum(bytes)
+	cidz1, _ := txPrefix.Sum(bytes)
 	blk, _ := blocks.NewBlockWithCid(bytes, cidz1)
 
 	if err != nil {
@@ -125,6 +218,21 @@ func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...Ing
 		return nil, errors.New("transaction must have at least one required auth")
 	}
 
+	latestBlk, err := tp.hiveBlocks.GetHighestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	if err := validateTip(txShell.Headers, tp.sconf.ConsensusParams(), latestBlk); err != nil {
+		return nil, err
+	}
+	if err := validatePayer(txShell.Headers, tp.sconf.ConsensusParams(), latestBlk); err != nil {
+		return nil, err
+	}
+	if err := validateOps(txShell.Tx, tp.sconf.ConsensusParams(), latestBlk); err != nil {
+		return nil, err
+	}
+
 	hashAuths := HashKeyAuths(txShell.Headers.RequiredAuths)
 	nonceRecord, err := tp.nonceDb.GetNonce(hashAuths)
 
@@ -142,10 +250,14 @@ func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...Ing
 		return nil, fmt.Errorf("nonce incrementing too fast: %d > %d", txShell.Headers.Nonce, nonce+100)
 	}
 
+	if batch.hasNonce(hashAuths, txShell.Headers.Nonce) {
+		return nil, fmt.Errorf("nonce %d already used by an earlier transaction in the batch", txShell.Headers.Nonce)
+	}
+
 	// Reject if nonce - 1 has not been confirmed (via nonce DB) and does not
 	// exist as UNCONFIRMED in the transaction pool.  This prevents gaps in the
 	// nonce sequence.
-	if txShell.Headers.Nonce > nonce {
+	if txShell.Headers.Nonce > nonce && !batch.hasNonce(hashAuths, txShell.Headers.Nonce-1) {
 		prevExists, err := tp.TxDb.HasUnconfirmedWithNonce(txShell.Headers.RequiredAuths, txShell.Headers.Nonce-1)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check previous nonce: %w", err)
@@ -155,20 +267,7 @@ func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...Ing
 		}
 	}
 
-	electionData, err := tp.electionDb.GetElectionByHeight(math.MaxInt64 - 1)
-
-	// make DIDs + verify signatures
-	didBuf, hasVscDID, err := makeDIDs(
-		electionData.Members,
-		electionData.Weights,
-		txShell.Headers.RequiredAuths,
-		sigPack.Sigs,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse DIDs: %w", err)
-	}
-
-	verified, err := common.VerifySignatures(didBuf, blk, sigPack.Sigs)
+	verified, hasVscDID, err := tp.verifySignatures(blk, signers(txShell.Headers), sigPack.Sigs)
 	if err != nil {
 		return nil, err
 	}
@@ -177,28 +276,42 @@ func (tp *TransactionPool) IngestTx(sTx SerializedVSCTransaction, options ...Ing
 		return nil, errors.New("missing required auth")
 	}
 
-	latestBlk, err := tp.hiveBlocks.GetHighestBlock()
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get latest block: %w", err)
-	}
-
 	// if transaction is signed by VSC DID, then ignore RCs
+	rcPayer := RcPayer(txShell.Headers.RequiredAuths, txShell.Headers.Payer)
+	requiredRcs := uint64(0)
 	if !hasVscDID {
-		rcsAvailable := tp.rcs.GetAvailableRCs(txShell.Headers.RequiredAuths[0], latestBlk)
+		rcsAvailable := tp.rcs.GetAvailableRCs(rcPayer, latestBlk)
 
 		//Note: RcLimit is user defined input
-		if uint64(rcsAvailable) < txShell.Headers.RcLimit || txShell.Headers.RcLimit == 0 {
-			return nil, fmt.Errorf("not enough RCS available: %d < %d", rcsAvailable, txShell.Headers.RcLimit)
+		requiredRcs = RequiredRcs(txShell.Headers.RcLimit, txShell.Headers.Tip, txShell.Headers.TipAsset)
+		//Earlier transactions of the batch are paid by the same RCs
+		reserved := batch.reservedRcs(rcPayer)
+		if uint64(rcsAvailable) < requiredRcs+reserved || txShell.Headers.RcLimit == 0 {
+			return nil, fmt.Errorf("not enough RCS available: %d < %d", rcsAvailable, requiredRcs+reserved)
 		}
 	}
 
+	replaces, err := tp.findReplaced(cidz.String(), txShell)
+	if err != nil {
+		return nil, err
+	}
+
 	//VALIDATION COMPLETE
 
-	err = tp.indexTx(cidz.String(), txShell)
+	batch.accept(hashAuths, txShell.Headers.Nonce, rcPayer, requiredRcs)
+	if len(options) > 0 && options[0].DryRun {
+		return &cidz, nil
+	}
+
+	err = tp.indexTx(cidz.String(), txShell, latestBlk)
 	if err != nil {
 		return nil, err
 	}
+	if len(replaces) > 0 {
+		if err := tp.TxDb.MarkReplaced(replaces, cidz.String()); err != nil {
+			return nil, fmt.Errorf("failed to replace pending transaction: %w", err)
+		}
+	}
 	_, err = tp.datalayer.PutRaw(sTx.Tx, common_types.PutRawOptions{
 		Codec: multicodec.DagCbor,
 	})
@@ -256,14 +369,7 @@ func (tp *TransactionPool) ReceiveTx(p2pMsg p2pMessage) {
 		return
 	}
 
-	prefix := cid.Prefix{
-		Version:  1,
-		Codec:    uint64(multicodec.DagCbor),
-		MhType:   uint64(multihash.SHA2_256),
-		MhLength: -1,
-	}
-
-	cidz, _ := prefix.Sum(decodedTx)
+	cidz, _ := txPrefix.Sum(decodedTx)
 
 	txShell := VSCTransactionShell{}
 
@@ -301,12 +407,7 @@ func (tp *TransactionPool) ReceiveTx(p2pMsg p2pMessage) {
 	}
 
 	bytes, err := common.EncodeDagCbor(txSignStruct)
-	cidz1, _ := cid.Prefix{
-		Version:  1,
-		Codec:    uint64(multicodec.DagCbor),
-		MhType:   uint64(multihash.SHA2_256),
-		MhLength: -1,
-	}.Sum(bytes)
+	cidz1, _ := txPrefix.Sum(bytes)
 	blk, _ := blocks.NewBlockWithCid(bytes, cidz1)
 
 	sigPack := SignaturePackage{}
@@ -316,20 +417,11 @@ func (tp *TransactionPool) ReceiveTx(p2pMsg p2pMessage) {
 
 	json.Unmarshal(sigJson, &sigPack)
 
-	electionData, err := tp.electionDb.GetElectionByHeight(math.MaxInt64 - 1)
-
-	didBuf, hasVscDID, err := makeDIDs(
-		electionData.Members,
-		electionData.Weights,
-		txShell.Headers.RequiredAuths,
-		sigPack.Sigs,
-	)
+	verified, hasVscDID, err := tp.verifySignatures(blk, signers(txShell.Headers), sigPack.Sigs)
 	if err != nil {
 		return
 	}
 
-	verified, err := common.VerifySignatures(didBuf, blk, sigPack.Sigs)
-
 	latestBlk, err := tp.hiveBlocks.GetHighestBlock()
 
 	if err != nil {
@@ -338,10 +430,11 @@ func (tp *TransactionPool) ReceiveTx(p2pMsg p2pMessage) {
 
 	// if transaction is signed by VSC DID, then ignore RCs
 	if !hasVscDID {
-		rcsAvailable := tp.rcs.GetAvailableRCs(txShell.Headers.RequiredAuths[0], latestBlk)
+		rcsAvailable := tp.rcs.GetAvailableRCs(RcPayer(txShell.Headers.RequiredAuths, txShell.Headers.Payer), latestBlk)
 
 		//Note: RcLimit is user defined input
-		if uint64(rcsAvailable) < txShell.Headers.RcLimit || txShell.Headers.RcLimit == 0 {
+		requiredRcs := RequiredRcs(txShell.Headers.RcLimit, txShell.Headers.Tip, txShell.Headers.TipAsset)
+		if uint64(rcsAvailable) < requiredRcs || txShell.Headers.RcLimit == 0 {
 			return
 		}
 	}
@@ -350,7 +443,10 @@ func (tp *TransactionPool) ReceiveTx(p2pMsg p2pMessage) {
 		return
 	}
 
-	if verified {
+	consensusParams := tp.sconf.ConsensusParams()
+	if verified && validateTip(txShell.Headers, consensusParams, latestBlk) == nil &&
+		validatePayer(txShell.Headers, consensusParams, latestBlk) == nil &&
+		validateOps(txShell.Tx, consensusParams, latestBlk) == nil {
 		hashAuths := HashKeyAuths(txShell.Headers.RequiredAuths)
 		nonceRecord, nonceErr := tp.nonceDb.GetNonce(hashAuths)
 		if nonceErr != nil && nonceErr != mongo.ErrNoDocuments {
@@ -369,11 +465,56 @@ func (tp *TransactionPool) ReceiveTx(p2pMsg p2pMessage) {
 			}
 		}
 
-		tp.indexTx(cidz.String(), txShell)
+		replaces, err := tp.findReplaced(cidz.String(), txShell)
+		if err != nil {
+			return
+		}
+
+		if tp.indexTx(cidz.String(), txShell, latestBlk) == nil && len(replaces) > 0 {
+			tp.TxDb.MarkReplaced(replaces, cidz.String())
+		}
+	}
+}
+
+// Returns the IDs of the pending transactions superseded by this one.
+// A transaction may only replace pending transactions with the same auths and nonce
+// if it offers a strictly higher RC limit or tip.
+func (tp *TransactionPool) findReplaced(txId string, txShell VSCTransactionShell) ([]string, error) {
+	pending, err := tp.TxDb.FindUnconfirmedWithNonce(txShell.Headers.RequiredAuths, txShell.Headers.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending nonce: %w", err)
+	}
+	ids := make([]string, 0, len(pending))
+	for _, p := range pending {
+		//Resubmission of the same transaction
+		if p.Id == txId {
+			continue
+		}
+		if txShell.Headers.RcLimit <= p.RcLimit && txShell.Headers.Tip <= p.Tip {
+			return nil, fmt.Errorf("transaction with nonce %d already pending: replacement must have rc_limit above %d or tip above %d", txShell.Headers.Nonce, p.RcLimit, p.Tip)
+		}
+		ids = append(ids, p.Id)
+	}
+	return ids, nil
+}
+
+// Marks unconfirmed transactions past their expiry block as expired
+func (tp *TransactionPool) evictExpired() {
+	height, err := tp.hiveBlocks.GetHighestBlock()
+	if err != nil {
+		return
+	}
+	count, err := tp.TxDb.ExpireUnconfirmed(height)
+	if err != nil {
+		log.Warn("failed to evict expired transactions", "err", err)
+		return
+	}
+	if count > 0 {
+		log.Debug("evicted expired transactions", "count", count, "height", height)
 	}
 }
 
-func (tp *TransactionPool) indexTx(txId string, txShell VSCTransactionShell) error {
+func (tp *TransactionPool) indexTx(txId string, txShell VSCTransactionShell, latestBlk uint64) error {
 	if len(txShell.Tx) == 0 {
 		return errors.New("transaction has no operations")
 	}
@@ -402,6 +543,7 @@ func (tp *TransactionPool) indexTx(txId string, txShell VSCTransactionShell) err
 		opTypes = append(opTypes, opType)
 	}
 
+	expireBlock := latestBlk + UNCONFIRMED_TX_TTL
 	return tp.TxDb.Ingest(transactions.IngestTransactionUpdate{
 		Id:            txId,
 		Status:        "UNCONFIRMED",
@@ -412,7 +554,11 @@ func (tp *TransactionPool) indexTx(txId string, txShell VSCTransactionShell) err
 		OpTypes:       opTypes,
 		Ops:           ops,
 		RcLimit:       txShell.Headers.RcLimit,
+		Tip:           txShell.Headers.Tip,
+		TipAsset:      txShell.Headers.TipAsset,
+		Payer:         txShell.Headers.Payer,
 		Ledger:        make([]ledgerSystem.OpLogEvent, 0),
+		ExpireBlock:   &expireBlock,
 	})
 }
 
@@ -428,6 +574,19 @@ func (tp *TransactionPool) Start() *promise.Promise[any] {
 			return
 		}
 
+		go func() {
+			ticker := time.NewTicker(EVICTION_INTERVAL)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-tp.service.Context().Done():
+					return
+				case <-ticker.C:
+					tp.evictExpired()
+				}
+			}
+		}()
+
 		<-tp.service.Context().Done()
 		resolve(nil)
 	})
@@ -437,17 +596,87 @@ func (tp *TransactionPool) Stop() error {
 	return tp.stopP2P()
 }
 
-func New(p2p *libp2p.P2PServer, txDb transactions.Transactions, nonceDb nonces.Nonces, electionDb elections.Elections, hiveBlocks hive_blocks.HiveBlocks, da *datalayer.DataLayer, conf common.IdentityConfig, rcSystem *rcSystem.RcSystem) *TransactionPool {
+func New(p2p *libp2p.P2PServer, txDb transactions.Transactions, nonceDb nonces.Nonces, authorityDb authorities.Authorities, electionDb elections.Elections, hiveBlocks hive_blocks.HiveBlocks, da *datalayer.DataLayer, conf common.IdentityConfig, sconf systemconfig.SystemConfig, rcSystem *rcSystem.RcSystem) *TransactionPool {
 	return &TransactionPool{
-		TxDb:       txDb,
-		nonceDb:    nonceDb,
-		p2p:        p2p,
-		datalayer:  da,
-		conf:       conf,
-		hiveBlocks: hiveBlocks,
-		rcs:        rcSystem,
-		electionDb: electionDb,
+		TxDb:        txDb,
+		nonceDb:     nonceDb,
+		authorities: authorityDb,
+		p2p:         p2p,
+		datalayer:   da,
+		conf:        conf,
+		sconf:       sconf,
+		hiveBlocks:  hiveBlocks,
+		rcs:         rcSystem,
+		electionDb:  electionDb,
+	}
+}
+
+// Rejects ops that are not active at height
+func validateOps(ops []VSCTransactionOp, consensusParams params.ConsensusParams, height uint64) error {
+	for _, op := range ops {
+		if op.Type == "update_authority" && height < consensusParams.AuthorityHeight {
+			return errors.New("account authorities are not active")
+		}
+	}
+	return nil
+}
+
+// Verifies the signatures of a transaction by its required auths and payer.
+// When a signer has set an account authority, every signer is checked
+// against its weighted key set and each signature names its key in Kid.
+// Otherwise signatures match the signers one to one. Returns whether a VSC
+// DID signed.
+func (tp *TransactionPool) verifySignatures(blk blocks.Block, requiredAuths []string, sigs []common.Sig) (bool, bool, error) {
+	resolved, err := tp.resolveAuthorities(requiredAuths)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to resolve account authorities: %w", err)
+	}
+	if len(resolved) > 0 {
+		verified, err := common.VerifyAuthorities(requiredAuths, func(account string) (*common.Authority, error) {
+			return resolved[account], nil
+		}, blk, sigs)
+		return verified, false, err
+	}
+
+	electionData, _ := tp.electionDb.GetElectionByHeight(math.MaxInt64 - 1)
+
+	// make DIDs + verify signatures
+	didBuf, hasVscDID, err := makeDIDs(
+		electionData.Members,
+		electionData.Weights,
+		requiredAuths,
+		sigs,
+	)
+	if err != nil {
+		return false, hasVscDID, fmt.Errorf("failed to parse DIDs: %w", err)
+	}
+
+	verified, err := common.VerifySignatures(didBuf, blk, sigs)
+	return verified, hasVscDID, err
+}
+
+// Current authorities of the accounts that have set one
+func (tp *TransactionPool) resolveAuthorities(accounts []string) (map[string]*common.Authority, error) {
+	resolved := make(map[string]*common.Authority)
+	if tp.authorities == nil {
+		return resolved, nil
+	}
+	height, err := tp.hiveBlocks.GetHighestBlock()
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		record, err := tp.authorities.GetAuthority(account, height)
+		if err == mongo.ErrNoDocuments {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if authority := record.Authority(); authority != nil {
+			resolved[account] = authority
+		}
 	}
+	return resolved, nil
 }
 
 // MakeDIDs parses the requiredAuths into DIDs.
@@ -91,3 +91,38 @@ func TestTx(t *testing.T) {
 
 // TestFlow removed: requires too many module dependencies (p2p, datalayer, db, etc.)
 // that have evolved. Rewrite with proper test infrastructure if needed.
+
+func TestIngestBatchLimits(t *testing.T) {
+	tp := &transactionpool.TransactionPool{}
+
+	_, err := tp.IngestBatch(nil)
+	if err == nil {
+		t.Fatal("expected empty batch to be rejected")
+	}
+
+	tooMany := make([]transactionpool.SerializedVSCTransaction, transactionpool.MAX_BATCH_SIZE+1)
+	_, err = tp.IngestBatch(tooMany)
+	if err == nil {
+		t.Fatal("expected oversized batch to be rejected")
+	}
+
+	//Unsigned transactions fail individually without aborting the batch
+	results, err := tp.IngestBatch([]transactionpool.SerializedVSCTransaction{
+		{Tx: []byte{0xa0}},
+		{Tx: []byte{0xa1}},
+	}, transactionpool.IngestOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for i, res := range results {
+		if res.Err == nil {
+			t.Errorf("result %d: expected error", i)
+		}
+		if res.Id == nil {
+			t.Errorf("result %d: expected id to be reported", i)
+		}
+	}
+}
@@ -12,6 +12,9 @@ type VSCTransactionHeader struct {
 	RequiredAuths []string `json:"required_auths"`
 	RcLimit       uint64   `json:"rc_limit"` // Optional, used for offchain transactions
 	NetId         string   `json:"net_id"`
+	Tip           uint64   `json:"tip,omitempty"`       // Optional priority tip, paid on top of rc_limit once tips activate
+	TipAsset      string   `json:"tip_asset,omitempty"` // rc (default) or hbd
+	Payer         string   `json:"payer,omitempty"`     // Optional account paying RCs and tips instead of the first required auth, signs alongside them
 }
 
 type VSCTransactionOp struct {
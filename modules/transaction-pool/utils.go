@@ -10,6 +10,8 @@ import (
 	"github.com/multiformats/go-multihash"
 )
 
+// Nonces stay keyed by the account DIDs, so rotating an account authority
+// does not reset the account's nonce.
 func HashKeyAuths(keyAuths []string) string {
 	if len(keyAuths) == 0 {
 		return ""
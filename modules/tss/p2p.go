@@ -3,7 +3,6 @@ package tss
 import (
 	"context"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -19,7 +18,6 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/multiformats/go-multicodec"
-	blsu "github.com/protolambda/bls12-381-util"
 )
 
 var protocolId = protocol.ID("/vsc.network/tss/1.0.0")
@@ -144,24 +142,10 @@ func (s p2pSpec) HandleMessage(
 			commitCid, _ := common.HashBytes(commitBytes, multicodec.DagCbor)
 
 			log.Trace("committed cid", "commitCid", commitCid)
-			blsPrivKey := blsu.SecretKey{}
-			var arr [32]byte
-			blsPrivSeedHex := s.tssMgr.config.Get().BlsPrivKeySeed
-			blsPrivSeed, err := hex.DecodeString(blsPrivSeedHex)
+			sigBytes, err := s.tssMgr.config.Signer().SignCid(commitCid)
 			if err != nil {
 				return nil
 			}
-			if len(blsPrivSeed) != 32 {
-				return nil
-			}
-
-			copy(arr[:], blsPrivSeed)
-			if err = blsPrivKey.Deserialize(&arr); err != nil {
-				return nil
-			}
-			sig := blsu.Sign(&blsPrivKey, commitCid.Bytes())
-
-			sigBytes := sig.Serialize()
 
 			sigStr := base64.URLEncoding.EncodeToString(sigBytes[:])
 
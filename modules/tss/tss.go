@@ -38,7 +38,7 @@ import (
 	gorpc "github.com/libp2p/go-libp2p-gorpc"
 	blsu "github.com/protolambda/bls12-381-util"
 
-	flatfs "github.com/ipfs/go-ds-flatfs"
+	"github.com/ipfs/go-datastore"
 )
 
 var log = vsclog.Module("tss")
@@ -94,22 +94,10 @@ func (tssMgr *TssManager) signReadyAttestation(targetBlock uint64) (*ReadyAttest
 		return nil, err
 	}
 
-	blsPrivKey := blsu.SecretKey{}
-	var arr [32]byte
-	blsPrivSeed, err := hex.DecodeString(tssMgr.config.Get().BlsPrivKeySeed)
+	sigBytes, err := tssMgr.config.Signer().SignCid(c)
 	if err != nil {
-		return nil, fmt.Errorf("decode bls seed: %w", err)
+		return nil, fmt.Errorf("sign attestation: %w", err)
 	}
-	if len(blsPrivSeed) != 32 {
-		return nil, fmt.Errorf("bls seed must be 32 bytes")
-	}
-	copy(arr[:], blsPrivSeed)
-	if err = blsPrivKey.Deserialize(&arr); err != nil {
-		return nil, fmt.Errorf("deserialize bls key: %w", err)
-	}
-
-	sig := blsu.Sign(&blsPrivKey, c.Bytes())
-	sigBytes := sig.Serialize()
 
 	return &ReadyAttestation{
 		Account:     account,
@@ -243,7 +231,7 @@ type TssManager struct {
 	tssKeys        tss_db.TssKeys
 	tssCommitments tss_db.TssCommitments
 
-	keyStore *flatfs.Datastore
+	keyStore datastore.Batching
 
 	//Generates a fresh set of local key params.
 	//A new set of fresh pre params will be available after depletion
@@ -2016,7 +2004,7 @@ func New(
 	se GetScheduler,
 	config common.IdentityConfig,
 	sconf systemconfig.SystemConfig,
-	keystore *flatfs.Datastore,
+	keystore datastore.Batching,
 	hiveClient hive.HiveTransactionCreator,
 ) *TssManager {
 	preParams := make(chan ecKeyGen.LocalPreParams, 1)
@@ -20,6 +20,7 @@ type ExecContextValue interface {
 	ContractStateGet(contractId string, key string) result.Result[string]
 	DeleteEphemState(key string) result.Result[struct{}]
 	DeleteState(key string) result.Result[struct{}]
+	EmitEvent(name string, topicsJson string, dataJson string) result.Result[struct{}]
 	EnvVar(key string) result.Result[string]
 	GetBalance(account string, asset string) int64
 	GetEnv() result.Result[string]
@@ -27,16 +28,23 @@ type ExecContextValue interface {
 	GetState(key string) result.Result[string]
 	IOGas() int
 	IOSession() IOSession
+	ListStateKeys(prefix string, cursor string, limit int) result.Result[string]
 	Log(msg string)
 	PullBalance(from string, amount int64, asset string) result.Result[struct{}]
+	RangeState(start string, end string, limit int) result.Result[string]
 	Revert()
 	SendBalance(to string, amount int64, asset string) result.Result[struct{}]
 	SetGasUsage(gasUsed uint)
 	SetEphemState(key string, value string) result.Result[struct{}]
 	SetState(key string, value string) result.Result[struct{}]
 	WithdrawBalance(to string, amount int64, asset string) result.Result[struct{}]
+	MintToken(to string, amount int64, symbol string) result.Result[struct{}]
+	BurnToken(amount int64, symbol string) result.Result[struct{}]
 	TssCreateKey(keyId string, keyType string, epochs uint64) result.Result[string]
 	TssRenewKey(keyId string, additionalEpochs uint64) result.Result[string]
 	TssGetKey(keyId string) result.Result[string]
 	TssKeySign(keyId string, msg string) result.Result[string]
+	TssKeySignCallback(keyId string, msg string, callback string, rcLimit uint) result.Result[string]
+	Schedule(action string, payload string, when string, rcLimit uint) result.Result[string]
+	CancelSchedule(id string) result.Result[struct{}]
 }
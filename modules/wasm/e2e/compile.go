@@ -15,24 +15,41 @@ func Compile(wkdir string) (string, error) {
 	// cmd.Env = append(cmd.Environ(), "GOOS=js")
 	// cmd.Env = append(cmd.Env, "GOARCH=wasm")
 
+	return path, run(cmd)
+}
+
+// Compiles the rust_wasm contract, requires the wasm32-unknown-unknown target
+func CompileRust(wkdir string) (string, error) {
+	targetDir := wkdir + "/modules/wasm/e2e/tmp/rust"
+	path := targetDir + "/wasm32-unknown-unknown/release/rust_wasm.wasm"
+	cmd := exec.Command(
+		"cargo", "build", "--release",
+		"--target", "wasm32-unknown-unknown",
+		"--manifest-path", wkdir+"/modules/wasm/e2e/rust_wasm/Cargo.toml",
+		"--target-dir", targetDir,
+	)
+	return path, run(cmd)
+}
+
+func run(cmd *exec.Cmd) error {
 	cmdOut, err := cmd.StdoutPipe()
 	if err != nil {
-		return "", err
+		return err
 	}
 	go io.Copy(os.Stdout, cmdOut)
 	cmdErr, err := cmd.StderrPipe()
 	if err != nil {
-		return "", err
+		return err
 	}
 	go io.Copy(os.Stderr, cmdErr)
 	cmdIn, err := cmd.StdinPipe()
 	if err != nil {
-		return "", err
+		return err
 	}
 	go io.Copy(cmdIn, os.Stdin)
 	err = cmd.Start()
 	if err != nil {
-		return "", err
+		return err
 	}
-	return path, cmd.Wait()
+	return cmd.Wait()
 }
@@ -9,6 +9,11 @@ const (
 	AssetHbdSavings Asset = "hbd_savings"
 )
 
+// Native token issued by a contract
+func TokenAsset(contractId string, symbol string) Asset {
+	return Asset(contractId + ":" + symbol)
+}
+
 func (a Asset) String() string {
 	return string(a)
 }
@@ -21,7 +21,7 @@ type Env struct {
 	Caller Address `json:"msg.caller"`
 
 	//Who pays for the RC fee. Can be used in other contexts.
-	//Proper RC payer support is not implemented yet.
+	//A sponsored transaction names its payer, otherwise it is the first required auth.
 	Payer Address `json:"msg.payer"`
 
 	Intents []Intent `json:"intents"`
@@ -6,6 +6,7 @@ import (
 	_ "vsc-node/modules/wasm/e2e/go_wasm/sdk/runtime"
 
 	"github.com/CosmWasm/tinyjson"
+	"github.com/CosmWasm/tinyjson/jwriter"
 )
 
 //go:wasmimport sdk console.log
@@ -24,6 +25,12 @@ func stateGetObject(key *string) *string
 //go:wasmimport sdk db.rm_object
 func stateDeleteObject(key *string) *string
 
+//go:wasmimport sdk db.list_keys
+func stateListKeys(prefix *string, cursor *string, limit *string) *string
+
+//go:wasmimport sdk db.range
+func stateRange(start *string, end *string, limit *string) *string
+
 //go:wasmimport sdk ephem_db.set_object
 func ephemStateSetObject(key *string, value *string) *string
 
@@ -42,6 +49,15 @@ func getEnv(arg *string) *string
 //go:wasmimport sdk system.get_env_key
 func getEnvKey(arg *string) *string
 
+//go:wasmimport sdk system.emit_event
+func emitEvent(name *string, topics *string, data *string) *string
+
+//go:wasmimport sdk system.schedule
+func schedule(action *string, payload *string, when *string, rcLimit *string) *string
+
+//go:wasmimport sdk system.cancel_schedule
+func cancelSchedule(id *string) *string
+
 //go:wasmimport sdk system.verify_address
 func verifyAddress(arg *string) *string
 
@@ -57,6 +73,12 @@ func hiveTransfer(arg1 *string, arg2 *string, arg3 *string) *string
 //go:wasmimport sdk hive.withdraw
 func hiveWithdraw(arg1 *string, arg2 *string, arg3 *string) *string
 
+//go:wasmimport sdk token.mint
+func tokenMint(to *string, amount *string, symbol *string) *string
+
+//go:wasmimport sdk token.burn
+func tokenBurn(amount *string, symbol *string) *string
+
 //go:wasmimport sdk contracts.read
 func contractRead(contractId *string, key *string) *string
 
@@ -75,6 +97,9 @@ func tssRenewKey(keyId *string, epochs *string) *string
 //go:wasmimport sdk tss.sign_key
 func tssSignKey(keyId *string, msgId *string) *string
 
+//go:wasmimport sdk tss_v2.sign_key
+func tssSignKeyCallback(keyId *string, msgId *string, callback *string, rcLimit *string) *string
+
 //go:wasmimport sdk tss.get_key
 func tssGetKey(keyId *string) *string
 
@@ -111,6 +136,28 @@ func StateDeleteObject(key string) {
 	stateDeleteObject(&key)
 }
 
+// List up to limit keys starting with prefix in the contract state, in lexicographic order.
+// Pass "" as the cursor for the first page, then the returned cursor until it is "".
+func StateListKeys(prefix string, cursor string, limit int) ([]string, string) {
+	lim := strconv.Itoa(limit)
+	page := StateKeysPage{}
+	if err := tinyjson.Unmarshal([]byte(*stateListKeys(&prefix, &cursor, &lim)), &page); err != nil {
+		Abort("could not parse state keys")
+	}
+	return page.Keys, page.Next
+}
+
+// Read up to limit entries with start <= key < end from the contract state, in lexicographic order.
+// An empty end is unbounded. The returned start of the next page is "" once the range is exhausted.
+func StateRange(start string, end string, limit int) ([]StateEntry, string) {
+	lim := strconv.Itoa(limit)
+	page := StateRangePage{}
+	if err := tinyjson.Unmarshal([]byte(*stateRange(&start, &end, &lim)), &page); err != nil {
+		Abort("could not parse state range")
+	}
+	return page.Entries, page.Next
+}
+
 // Set a value by key in the ephemeral contract state
 func EphemStateSetObject(key string, value string) {
 	ephemStateSetObject(&key, &value)
@@ -126,6 +173,45 @@ func EphemStateDeleteObject(key string) {
 	ephemStateDeleteObject(&key)
 }
 
+// Emit a structured event stored along with the contract output.
+// Topics can be used by indexers to look the event up, data must be a JSON encoded value.
+func EmitEvent(name string, topics []string, data string) {
+	w := jwriter.Writer{}
+	w.RawByte('[')
+	for i, topic := range topics {
+		if i > 0 {
+			w.RawByte(',')
+		}
+		w.String(topic)
+	}
+	w.RawByte(']')
+	topicsJson := string(w.Buffer.BuildBytes())
+	emitEvent(&name, &topicsJson, &data)
+}
+
+// Call action of this contract with payload once at the given block height.
+// Each run uses up to rcLimit RCs of the contract account, the schedule fails
+// once the account cannot cover them. Returns the schedule ID.
+func ScheduleAt(action string, payload string, height uint64, rcLimit uint64) string {
+	when := "at:" + strconv.FormatUint(height, 10)
+	rcStr := strconv.FormatUint(rcLimit, 10)
+	return *schedule(&action, &payload, &when, &rcStr)
+}
+
+// Call action of this contract with payload every interval blocks.
+// Each run uses up to rcLimit RCs of the contract account, the schedule fails
+// once the account cannot cover them. Returns the schedule ID.
+func ScheduleEvery(action string, payload string, interval uint64, rcLimit uint64) string {
+	when := "every:" + strconv.FormatUint(interval, 10)
+	rcStr := strconv.FormatUint(rcLimit, 10)
+	return *schedule(&action, &payload, &when, &rcStr)
+}
+
+// Cancel a schedule created by this contract
+func CancelSchedule(id string) {
+	cancelSchedule(&id)
+}
+
 // Get current execution environment variables
 func GetEnv() Env {
 	envStr := *getEnv(nil)
@@ -197,6 +283,20 @@ func HiveWithdraw(to Address, amount int64, asset Asset) {
 	hiveWithdraw(&toaddr, &amt, &as)
 }
 
+// Mint units of this contract's token symbol to an account.
+// The token is transferable by its holders as the asset TokenAsset(contract id, symbol).
+func TokenMint(to Address, amount int64, symbol string) {
+	toaddr := to.String()
+	amt := strconv.FormatInt(amount, 10)
+	tokenMint(&toaddr, &amt, &symbol)
+}
+
+// Burn units of this contract's token symbol held by the contract.
+func TokenBurn(amount int64, symbol string) {
+	amt := strconv.FormatInt(amount, 10)
+	tokenBurn(&amt, &symbol)
+}
+
 // Get a value by key from the contract state of another contract
 func ContractStateGet(contractId string, key string) *string {
 	return contractRead(&contractId, &key)
@@ -250,6 +350,15 @@ func TssSignKey(keyId string, bytes []byte) {
 	tssSignKey(&keyId, &byteStr)
 }
 
+// TssSignKeyCallback requests a signature and has callback called once it is
+// available, with a JSON payload of key_id, msg and sig. rcLimit RCs are
+// charged now and bound the callback. Returns "ok" or "fail".
+func TssSignKeyCallback(keyId string, bytes []byte, callback string, rcLimit uint64) string {
+	byteStr := hex.EncodeToString(bytes)
+	rcStr := strconv.FormatUint(rcLimit, 10)
+	return *tssSignKeyCallback(&keyId, &byteStr, &callback, &rcStr)
+}
+
 // SystemCall invokes an SDK function by name via the system.call host import.
 // This is the raw dispatch mechanism — it resolves "namespace.method" and calls it.
 func SystemCall(name string, args string) *string {
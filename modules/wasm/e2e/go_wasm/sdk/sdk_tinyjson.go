@@ -588,3 +588,244 @@ func (v ContractCallOptions) MarshalTinyJSON(w *jwriter.Writer) {
 func (v *ContractCallOptions) UnmarshalTinyJSON(l *jlexer.Lexer) {
 	tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk4(l, v)
 }
+func tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk5(in *jlexer.Lexer, out *StateRangePage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "entries":
+			if in.IsNull() {
+				in.Skip()
+				out.Entries = nil
+			} else {
+				in.Delim('[')
+				if out.Entries == nil {
+					if !in.IsDelim(']') {
+						out.Entries = make([]StateEntry, 0, 2)
+					} else {
+						out.Entries = []StateEntry{}
+					}
+				} else {
+					out.Entries = (out.Entries)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 StateEntry
+					(v1).UnmarshalTinyJSON(in)
+					out.Entries = append(out.Entries, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func tinyjson223cdf42EncodeVscNodeModulesWasmE2eGoWasmSdk5(out *jwriter.Writer, in StateRangePage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"entries\":"
+		out.RawString(prefix[1:])
+		if in.Entries == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Entries {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalTinyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"next\":"
+		out.RawString(prefix)
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalTinyJSON supports tinyjson.Marshaler interface
+func (v StateRangePage) MarshalTinyJSON(w *jwriter.Writer) {
+	tinyjson223cdf42EncodeVscNodeModulesWasmE2eGoWasmSdk5(w, v)
+}
+
+// UnmarshalTinyJSON supports tinyjson.Unmarshaler interface
+func (v *StateRangePage) UnmarshalTinyJSON(l *jlexer.Lexer) {
+	tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk5(l, v)
+}
+func tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk6(in *jlexer.Lexer, out *StateKeysPage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "keys":
+			if in.IsNull() {
+				in.Skip()
+				out.Keys = nil
+			} else {
+				in.Delim('[')
+				if out.Keys == nil {
+					if !in.IsDelim(']') {
+						out.Keys = make([]string, 0, 4)
+					} else {
+						out.Keys = []string{}
+					}
+				} else {
+					out.Keys = (out.Keys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					v4 = string(in.String())
+					out.Keys = append(out.Keys, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func tinyjson223cdf42EncodeVscNodeModulesWasmE2eGoWasmSdk6(out *jwriter.Writer, in StateKeysPage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"keys\":"
+		out.RawString(prefix[1:])
+		if in.Keys == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Keys {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"next\":"
+		out.RawString(prefix)
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalTinyJSON supports tinyjson.Marshaler interface
+func (v StateKeysPage) MarshalTinyJSON(w *jwriter.Writer) {
+	tinyjson223cdf42EncodeVscNodeModulesWasmE2eGoWasmSdk6(w, v)
+}
+
+// UnmarshalTinyJSON supports tinyjson.Unmarshaler interface
+func (v *StateKeysPage) UnmarshalTinyJSON(l *jlexer.Lexer) {
+	tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk6(l, v)
+}
+func tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk7(in *jlexer.Lexer, out *StateEntry) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key":
+			out.Key = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func tinyjson223cdf42EncodeVscNodeModulesWasmE2eGoWasmSdk7(out *jwriter.Writer, in StateEntry) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.String(string(in.Value))
+	}
+	out.RawByte('}')
+}
+
+// MarshalTinyJSON supports tinyjson.Marshaler interface
+func (v StateEntry) MarshalTinyJSON(w *jwriter.Writer) {
+	tinyjson223cdf42EncodeVscNodeModulesWasmE2eGoWasmSdk7(w, v)
+}
+
+// UnmarshalTinyJSON supports tinyjson.Unmarshaler interface
+func (v *StateEntry) UnmarshalTinyJSON(l *jlexer.Lexer) {
+	tinyjson223cdf42DecodeVscNodeModulesWasmE2eGoWasmSdk7(l, v)
+}
@@ -0,0 +1,23 @@
+package sdk
+
+// Page of contract state keys returned by StateListKeys
+//
+//tinyjson:json
+type StateKeysPage struct {
+	Keys []string `json:"keys"`
+	Next string   `json:"next"`
+}
+
+// Page of contract state entries returned by StateRange
+//
+//tinyjson:json
+type StateRangePage struct {
+	Entries []StateEntry `json:"entries"`
+	Next    string       `json:"next"`
+}
+
+//tinyjson:json
+type StateEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
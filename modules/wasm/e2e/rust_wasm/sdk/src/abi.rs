@@ -0,0 +1,101 @@
+//! String ABI of the rust runtime.
+//!
+//! Every string crossing the host boundary is passed as a pointer to a buffer
+//! holding the byte length as a little endian u32 followed by the UTF-8 bytes.
+//! The host allocates the buffers of arguments and SDK results through the
+//! exported `alloc`. Buffers are never freed, a contract instance only lives
+//! for a single call.
+
+use std::string::String;
+use std::vec::Vec;
+
+pub type Ptr = *const u8;
+
+/// Allocates size bytes of contract memory for the host
+#[no_mangle]
+pub extern "C" fn alloc(size: usize) -> *mut u8 {
+    let mut buf = Vec::<u8>::with_capacity(size);
+    let ptr = buf.as_mut_ptr();
+    core::mem::forget(buf);
+    ptr
+}
+
+/// Length prefixed copy of a string passed to the host
+pub struct Buf(Vec<u8>);
+
+impl Buf {
+    pub fn new(s: &str) -> Self {
+        let mut buf = Vec::with_capacity(4 + s.len());
+        buf.extend_from_slice(&(s.len() as u32).to_le_bytes());
+        buf.extend_from_slice(s.as_bytes());
+        Buf(buf)
+    }
+
+    pub fn ptr(&self) -> Ptr {
+        self.0.as_ptr()
+    }
+
+    /// Hands the buffer over to the host, used for entrypoint results
+    pub fn leak(self) -> Ptr {
+        let ptr = self.0.as_ptr();
+        core::mem::forget(self.0);
+        ptr
+    }
+}
+
+/// Reads a string written by the host
+///
+/// # Safety
+///
+/// ptr must point to a length prefixed buffer.
+pub unsafe fn read(ptr: Ptr) -> String {
+    if ptr.is_null() {
+        return String::new();
+    }
+    let mut len = [0u8; 4];
+    core::ptr::copy_nonoverlapping(ptr, len.as_mut_ptr(), 4);
+    let bytes = core::slice::from_raw_parts(ptr.add(4), u32::from_le_bytes(len) as usize);
+    String::from_utf8_lossy(bytes).into_owned()
+}
+
+/// Runs an entrypoint with the payload of the call and returns its result to the host.
+/// Panics abort the call with the panic message.
+pub fn run(arg: Ptr, handler: fn(String) -> String) -> Ptr {
+    std::panic::set_hook(std::boxed::Box::new(|info| {
+        let msg = match info.payload().downcast_ref::<&str>() {
+            Some(s) => String::from(*s),
+            None => match info.payload().downcast_ref::<String>() {
+                Some(s) => s.clone(),
+                None => String::from("panic"),
+            },
+        };
+        let file = info.location().map(|l| l.file()).unwrap_or("");
+        let line = info.location().map(|l| l.line()).unwrap_or(0);
+        let column = info.location().map(|l| l.column()).unwrap_or(0);
+        let (msg, file) = (Buf::new(&msg), Buf::new(file));
+        unsafe { crate::ffi::abort(msg.ptr(), file.ptr(), line as i32, column as i32) };
+    }));
+    let payload = unsafe { read(arg) };
+    Buf::new(&handler(payload)).leak()
+}
+
+/// Exports handler as the contract action name.
+///
+/// ```ignore
+/// vsc_sdk::entrypoint!("greet", greet);
+///
+/// fn greet(name: String) -> String {
+///     format!("hello {}", name)
+/// }
+/// ```
+#[macro_export]
+macro_rules! entrypoint {
+    ($action:literal, $handler:path) => {
+        const _: () = {
+            #[export_name = $action]
+            extern "C" fn entry(arg: $crate::abi::Ptr) -> $crate::abi::Ptr {
+                $crate::abi::run(arg, $handler)
+            }
+        };
+    };
+}
@@ -0,0 +1,9 @@
+pub const HIVE: &str = "hive";
+pub const HIVE_CONSENSUS: &str = "hive_consensus";
+pub const HBD: &str = "hbd";
+pub const HBD_SAVINGS: &str = "hbd_savings";
+
+/// Native token issued by a contract
+pub fn token_asset(contract_id: &str, symbol: &str) -> std::string::String {
+    format!("{}:{}", contract_id, symbol)
+}
@@ -0,0 +1,139 @@
+//! Raw imports of the host SDK. Every argument and result is a length
+//! prefixed string, see [`crate::abi`].
+
+use crate::abi::Ptr;
+
+#[link(wasm_import_module = "env")]
+extern "C" {
+    pub fn abort(msg: Ptr, file: Ptr, line: i32, column: i32);
+    pub fn revert(msg: Ptr, symbol: Ptr);
+}
+
+#[link(wasm_import_module = "sdk")]
+extern "C" {
+    #[link_name = "console.log"]
+    pub fn console_log(msg: Ptr) -> Ptr;
+
+    #[link_name = "db.set_object"]
+    pub fn db_set_object(key: Ptr, value: Ptr) -> Ptr;
+
+    #[link_name = "db.get_object"]
+    pub fn db_get_object(key: Ptr) -> Ptr;
+
+    #[link_name = "db.rm_object"]
+    pub fn db_rm_object(key: Ptr) -> Ptr;
+
+    #[link_name = "db.list_keys"]
+    pub fn db_list_keys(prefix: Ptr, cursor: Ptr, limit: Ptr) -> Ptr;
+
+    #[link_name = "db.range"]
+    pub fn db_range(start: Ptr, end: Ptr, limit: Ptr) -> Ptr;
+
+    #[link_name = "ephem_db.set_object"]
+    pub fn ephem_db_set_object(key: Ptr, value: Ptr) -> Ptr;
+
+    #[link_name = "ephem_db.get_object"]
+    pub fn ephem_db_get_object(contract_id: Ptr, key: Ptr) -> Ptr;
+
+    #[link_name = "ephem_db.rm_object"]
+    pub fn ephem_db_rm_object(key: Ptr) -> Ptr;
+
+    #[link_name = "system.call"]
+    pub fn system_call(name: Ptr, args: Ptr) -> Ptr;
+
+    #[link_name = "system.get_env"]
+    pub fn system_get_env(arg: Ptr) -> Ptr;
+
+    #[link_name = "system.get_env_key"]
+    pub fn system_get_env_key(key: Ptr) -> Ptr;
+
+    #[link_name = "system.emit_event"]
+    pub fn system_emit_event(name: Ptr, topics: Ptr, data: Ptr) -> Ptr;
+
+    #[link_name = "system.schedule"]
+    pub fn system_schedule(action: Ptr, payload: Ptr, when: Ptr, rc_limit: Ptr) -> Ptr;
+
+    #[link_name = "system.cancel_schedule"]
+    pub fn system_cancel_schedule(id: Ptr) -> Ptr;
+
+    #[link_name = "system.verify_address"]
+    pub fn system_verify_address(address: Ptr) -> Ptr;
+
+    #[link_name = "hive.get_balance"]
+    pub fn hive_get_balance(account: Ptr, asset: Ptr) -> Ptr;
+
+    #[link_name = "hive.draw"]
+    pub fn hive_draw(amount: Ptr, asset: Ptr) -> Ptr;
+
+    #[link_name = "hive.transfer"]
+    pub fn hive_transfer(to: Ptr, amount: Ptr, asset: Ptr) -> Ptr;
+
+    #[link_name = "hive.withdraw"]
+    pub fn hive_withdraw(to: Ptr, amount: Ptr, asset: Ptr) -> Ptr;
+
+    #[link_name = "token.mint"]
+    pub fn token_mint(to: Ptr, amount: Ptr, symbol: Ptr) -> Ptr;
+
+    #[link_name = "token.burn"]
+    pub fn token_burn(amount: Ptr, symbol: Ptr) -> Ptr;
+
+    #[link_name = "contracts.read"]
+    pub fn contracts_read(contract_id: Ptr, key: Ptr) -> Ptr;
+
+    #[link_name = "contracts.call"]
+    pub fn contracts_call(contract_id: Ptr, method: Ptr, payload: Ptr, options: Ptr) -> Ptr;
+
+    #[link_name = "tss.create_key"]
+    pub fn tss_create_key(key_id: Ptr, algo: Ptr) -> Ptr;
+
+    #[link_name = "tss.sign_key"]
+    pub fn tss_sign_key(key_id: Ptr, msg: Ptr) -> Ptr;
+
+    #[link_name = "tss.get_key"]
+    pub fn tss_get_key(key_id: Ptr) -> Ptr;
+
+    #[link_name = "tss_v2.create_key"]
+    pub fn tss_v2_create_key(key_id: Ptr, algo: Ptr, epochs: Ptr) -> Ptr;
+
+    #[link_name = "tss_v2.renew_key"]
+    pub fn tss_v2_renew_key(key_id: Ptr, epochs: Ptr) -> Ptr;
+
+    #[link_name = "tss_v2.sign_key"]
+    pub fn tss_v2_sign_key(key_id: Ptr, msg: Ptr, callback: Ptr, rc_limit: Ptr) -> Ptr;
+
+    #[link_name = "crypto.keccak256"]
+    pub fn crypto_keccak256(data: Ptr) -> Ptr;
+
+    #[link_name = "crypto.ecrecover"]
+    pub fn crypto_ecrecover(hash: Ptr, sig: Ptr) -> Ptr;
+
+    #[link_name = "crypto.rlp_decode"]
+    pub fn crypto_rlp_decode(data: Ptr) -> Ptr;
+
+    #[link_name = "crypto.sha256"]
+    pub fn crypto_sha256(data: Ptr) -> Ptr;
+
+    #[link_name = "crypto.double_sha256"]
+    pub fn crypto_double_sha256(data: Ptr) -> Ptr;
+
+    #[link_name = "crypto.ripemd160"]
+    pub fn crypto_ripemd160(data: Ptr) -> Ptr;
+
+    #[link_name = "crypto.blake2b"]
+    pub fn crypto_blake2b(data: Ptr, size: Ptr) -> Ptr;
+
+    #[link_name = "crypto.ed25519_verify"]
+    pub fn crypto_ed25519_verify(pub_key: Ptr, msg: Ptr, sig: Ptr) -> Ptr;
+
+    #[link_name = "crypto.secp256k1_verify"]
+    pub fn crypto_secp256k1_verify(pub_key: Ptr, digest: Ptr, sig: Ptr) -> Ptr;
+
+    #[link_name = "crypto.schnorr_bip340_verify"]
+    pub fn crypto_schnorr_bip340_verify(pub_key: Ptr, msg: Ptr, sig: Ptr) -> Ptr;
+
+    #[link_name = "crypto.bls12381_verify_aggregate"]
+    pub fn crypto_bls12381_verify_aggregate(pub_keys: Ptr, msg: Ptr, sig: Ptr) -> Ptr;
+
+    #[link_name = "crypto.sp1_verify_groth16"]
+    pub fn crypto_sp1_verify_groth16(proof: Ptr, public_inputs: Ptr, vkey_hash: Ptr, groth16_vk: Ptr, vk_root: Ptr) -> Ptr;
+}
@@ -0,0 +1,285 @@
+//! Bindings to the Magi contract SDK for contracts using the rust runtime.
+//!
+//! Contracts are built as a `cdylib` for `wasm32-unknown-unknown` and export
+//! their actions with [`entrypoint!`]. Amounts are in the smallest unit of the
+//! asset and binary data is passed as hex, the same as in the Go SDK.
+
+pub mod abi;
+pub mod asset;
+pub mod ffi;
+
+use abi::Buf;
+use std::string::String;
+use std::vec::Vec;
+
+macro_rules! host {
+    ($f:ident $(, $arg:expr)*) => {
+        unsafe { abi::read(ffi::$f($(Buf::new(&$arg).ptr()),*)) }
+    };
+}
+
+/// Aborts the contract execution
+pub fn abort(msg: &str) -> ! {
+    let (msg, file) = (Buf::new(msg), Buf::new(""));
+    unsafe { ffi::abort(msg.ptr(), file.ptr(), 0, 0) };
+    std::process::abort()
+}
+
+/// Reverts the transaction and aborts execution in the same way as [`abort`]
+pub fn revert(msg: &str, symbol: &str) -> ! {
+    let (msg, symbol) = (Buf::new(msg), Buf::new(symbol));
+    unsafe { ffi::revert(msg.ptr(), symbol.ptr()) };
+    std::process::abort()
+}
+
+pub fn log(msg: &str) {
+    host!(console_log, msg);
+}
+
+/// Set a value by key in the contract state
+pub fn state_set_object(key: &str, value: &str) {
+    host!(db_set_object, key, value);
+}
+
+/// Get a value by key from the contract state, "" if unset
+pub fn state_get_object(key: &str) -> String {
+    host!(db_get_object, key)
+}
+
+/// Delete or unset a value by key in the contract state
+pub fn state_delete_object(key: &str) {
+    host!(db_rm_object, key);
+}
+
+/// List up to limit keys starting with prefix in the contract state, in lexicographic order.
+/// Returns the JSON page `{"keys":[...],"next":"<cursor>"}`.
+pub fn state_list_keys(prefix: &str, cursor: &str, limit: u32) -> String {
+    host!(db_list_keys, prefix, cursor, limit.to_string())
+}
+
+/// Read up to limit entries with start <= key < end from the contract state.
+/// Returns the JSON page `{"entries":[{"key":..,"value":..}],"next":"<start>"}`.
+pub fn state_range(start: &str, end: &str, limit: u32) -> String {
+    host!(db_range, start, end, limit.to_string())
+}
+
+/// Set a value by key in the ephemeral contract state
+pub fn ephem_state_set_object(key: &str, value: &str) {
+    host!(ephem_db_set_object, key, value);
+}
+
+/// Get a value by key from the ephemeral state of a contract, "" for this contract
+pub fn ephem_state_get_object(contract_id: &str, key: &str) -> String {
+    host!(ephem_db_get_object, contract_id, key)
+}
+
+/// Delete or unset a value by key in the ephemeral contract state
+pub fn ephem_state_delete_object(key: &str) {
+    host!(ephem_db_rm_object, key);
+}
+
+/// Invokes a single argument SDK function by name
+pub fn system_call(name: &str, args_json: &str) -> String {
+    host!(system_call, name, args_json)
+}
+
+/// Get current execution environment variables as JSON
+pub fn get_env() -> String {
+    host!(system_get_env, "")
+}
+
+/// Get current execution environment variable by a key
+pub fn get_env_key(key: &str) -> String {
+    host!(system_get_env_key, key)
+}
+
+/// Emit a structured event stored along with the contract output.
+/// data must be a JSON encoded value.
+pub fn emit_event(name: &str, topics: &[&str], data: &str) {
+    let topics: Vec<String> = topics.iter().map(|t| json_string(t)).collect();
+    host!(system_emit_event, name, format!("[{}]", topics.join(",")), data);
+}
+
+/// Call action of this contract with payload once at the given block height.
+/// Returns the schedule ID.
+pub fn schedule_at(action: &str, payload: &str, height: u64, rc_limit: u64) -> String {
+    host!(system_schedule, action, payload, format!("at:{}", height), rc_limit.to_string())
+}
+
+/// Call action of this contract with payload every interval blocks.
+/// Returns the schedule ID.
+pub fn schedule_every(action: &str, payload: &str, interval: u64, rc_limit: u64) -> String {
+    host!(system_schedule, action, payload, format!("every:{}", interval), rc_limit.to_string())
+}
+
+/// Cancel a schedule created by this contract
+pub fn cancel_schedule(id: &str) {
+    host!(system_cancel_schedule, id);
+}
+
+/// Returns the address type, or "unknown" for invalid addresses
+pub fn verify_address(address: &str) -> String {
+    host!(system_verify_address, address)
+}
+
+/// Get balance of an account
+pub fn get_balance(account: &str, asset: &str) -> i64 {
+    match host!(hive_get_balance, account, asset).parse() {
+        Ok(bal) => bal,
+        Err(_) => abort("invalid balance"),
+    }
+}
+
+/// Transfer assets from the caller to the contract up to the limit specified in the intents
+pub fn hive_draw(amount: i64, asset: &str) {
+    host!(hive_draw, amount.to_string(), asset);
+}
+
+/// Transfer assets from the contract to another account
+pub fn hive_transfer(to: &str, amount: i64, asset: &str) {
+    host!(hive_transfer, to, amount.to_string(), asset);
+}
+
+/// Unmap assets from the contract to a Hive account
+pub fn hive_withdraw(to: &str, amount: i64, asset: &str) {
+    host!(hive_withdraw, to, amount.to_string(), asset);
+}
+
+/// Mint units of this contract's token symbol to an account
+pub fn token_mint(to: &str, amount: i64, symbol: &str) {
+    host!(token_mint, to, amount.to_string(), symbol);
+}
+
+/// Burn units of this contract's token symbol held by the contract
+pub fn token_burn(amount: i64, symbol: &str) {
+    host!(token_burn, amount.to_string(), symbol);
+}
+
+/// Get a value by key from the state of another contract
+pub fn contract_state_get(contract_id: &str, key: &str) -> String {
+    host!(contracts_read, contract_id, key)
+}
+
+/// Call another contract. options is "" or a JSON object such as `{"intents":[...]}`.
+pub fn contract_call(contract_id: &str, method: &str, payload: &str, options: &str) -> String {
+    host!(contracts_call, contract_id, method, payload, options)
+}
+
+/// Creates a key with the maximum epoch lifespan.
+/// Deprecated: use [`tss_create_key_for_epochs`] to specify a lifespan.
+pub fn tss_create_key(key_id: &str, algo: &str) -> String {
+    host!(tss_create_key, key_id, algo)
+}
+
+/// Creates a key that expires after the given number of epochs
+pub fn tss_create_key_for_epochs(key_id: &str, algo: &str, epochs: u64) -> String {
+    host!(tss_v2_create_key, key_id, algo, epochs.to_string())
+}
+
+/// Extends the expiry of a key owned by this contract
+pub fn tss_renew_key(key_id: &str, additional_epochs: u64) -> String {
+    host!(tss_v2_renew_key, key_id, additional_epochs.to_string())
+}
+
+pub fn tss_get_key(key_id: &str) -> String {
+    host!(tss_get_key, key_id)
+}
+
+pub fn tss_sign_key(key_id: &str, msg: &[u8]) -> String {
+    host!(tss_sign_key, key_id, to_hex(msg))
+}
+
+/// Requests a signature and has callback called once it is available
+pub fn tss_sign_key_callback(key_id: &str, msg: &[u8], callback: &str, rc_limit: u64) -> String {
+    host!(tss_v2_sign_key, key_id, to_hex(msg), callback, rc_limit.to_string())
+}
+
+pub fn keccak256(data: &[u8]) -> String {
+    host!(crypto_keccak256, to_hex(data))
+}
+
+/// Recovers the EVM address of the signer of a 32 byte hash
+pub fn ecrecover(hash: &[u8], sig: &[u8]) -> String {
+    host!(crypto_ecrecover, to_hex(hash), to_hex(sig))
+}
+
+/// Decodes RLP data into JSON
+pub fn rlp_decode(data: &[u8]) -> String {
+    host!(crypto_rlp_decode, to_hex(data))
+}
+
+pub fn sha256(data: &[u8]) -> String {
+    host!(crypto_sha256, to_hex(data))
+}
+
+pub fn double_sha256(data: &[u8]) -> String {
+    host!(crypto_double_sha256, to_hex(data))
+}
+
+pub fn ripemd160(data: &[u8]) -> String {
+    host!(crypto_ripemd160, to_hex(data))
+}
+
+/// BLAKE2b with a digest of 1 to 64 bytes
+pub fn blake2b(data: &[u8], size: u8) -> String {
+    host!(crypto_blake2b, to_hex(data), size.to_string())
+}
+
+pub fn ed25519_verify(pub_key: &[u8], msg: &[u8], sig: &[u8]) -> bool {
+    host!(crypto_ed25519_verify, to_hex(pub_key), to_hex(msg), to_hex(sig)) == "true"
+}
+
+/// Verifies a 64 byte r || s signature of a 32 byte digest
+pub fn secp256k1_verify(pub_key: &[u8], digest: &[u8], sig: &[u8]) -> bool {
+    host!(crypto_secp256k1_verify, to_hex(pub_key), to_hex(digest), to_hex(sig)) == "true"
+}
+
+pub fn schnorr_bip340_verify(pub_key: &[u8], msg: &[u8], sig: &[u8]) -> bool {
+    host!(crypto_schnorr_bip340_verify, to_hex(pub_key), to_hex(msg), to_hex(sig)) == "true"
+}
+
+/// Verifies an aggregate BLS signature of msg by all pub_keys, given as DIDs or hex
+pub fn bls12381_verify_aggregate(pub_keys: &[&str], msg: &[u8], sig: &[u8]) -> bool {
+    let keys: Vec<String> = pub_keys.iter().map(|k| json_string(k)).collect();
+    host!(crypto_bls12381_verify_aggregate, format!("[{}]", keys.join(",")), to_hex(msg), to_hex(sig)) == "true"
+}
+
+pub fn sp1_verify_groth16(proof: &[u8], public_inputs: &[u8], vkey_hash: &[u8], groth16_vk: &[u8], vk_root: &[u8]) -> String {
+    host!(
+        crypto_sp1_verify_groth16,
+        to_hex(proof),
+        to_hex(public_inputs),
+        to_hex(vkey_hash),
+        to_hex(groth16_vk),
+        to_hex(vk_root)
+    )
+}
+
+pub fn to_hex(data: &[u8]) -> String {
+    const DIGITS: &[u8; 16] = b"0123456789abcdef";
+    let mut s = String::with_capacity(data.len() * 2);
+    for b in data {
+        s.push(DIGITS[(b >> 4) as usize] as char);
+        s.push(DIGITS[(b & 0xf) as usize] as char);
+    }
+    s
+}
+
+/// Encodes s as a JSON string
+pub fn json_string(s: &str) -> String {
+    let mut out = String::with_capacity(s.len() + 2);
+    out.push('"');
+    for c in s.chars() {
+        match c {
+            '"' => out.push_str("\\\""),
+            '\\' => out.push_str("\\\\"),
+            '\n' => out.push_str("\\n"),
+            '\r' => out.push_str("\\r"),
+            '\t' => out.push_str("\\t"),
+            c if (c as u32) < 0x20 => out.push_str(&format!("\\u{:04x}", c as u32)),
+            c => out.push(c),
+        }
+    }
+    out.push('"');
+    out
+}
@@ -0,0 +1,109 @@
+//! Reference contract for the rust runtime e2e tests
+
+use vsc_sdk::{asset, entrypoint};
+
+entrypoint!("setString", set_string);
+entrypoint!("getString", get_string);
+entrypoint!("clearString", clear_string);
+entrypoint!("ephemRoundTrip", ephem_round_trip);
+entrypoint!("drawHive", draw_hive);
+entrypoint!("sendHive", send_hive);
+entrypoint!("contractGetString", contract_get_string);
+entrypoint!("dumpEnv", dump_env);
+entrypoint!("dumpEnvKey", dump_env_key);
+entrypoint!("emitEvent", emit_event);
+entrypoint!("hash", hash);
+entrypoint!("abortMe", abort_me);
+entrypoint!("revertMe", revert_me);
+entrypoint!("panicMe", panic_me);
+
+fn split_pair(payload: &str) -> (&str, &str) {
+    match payload.split_once(',') {
+        Some(pair) => pair,
+        None => vsc_sdk::abort("expected a comma separated pair"),
+    }
+}
+
+fn parse_amount(amount: &str) -> i64 {
+    match amount.trim().parse() {
+        Ok(amount) => amount,
+        Err(_) => vsc_sdk::abort("invalid amount"),
+    }
+}
+
+// - Set a value by key, payload is "key,value"
+fn set_string(payload: String) -> String {
+    let (key, value) = split_pair(&payload);
+    vsc_sdk::state_set_object(key, value);
+    String::from("ok")
+}
+
+fn get_string(key: String) -> String {
+    vsc_sdk::state_get_object(&key)
+}
+
+fn clear_string(key: String) -> String {
+    vsc_sdk::state_delete_object(&key);
+    if !vsc_sdk::state_get_object(&key).is_empty() {
+        vsc_sdk::abort("state not deleted");
+    }
+    String::from("ok")
+}
+
+// - Ephemeral state is readable within the same call
+fn ephem_round_trip(value: String) -> String {
+    vsc_sdk::ephem_state_set_object("tempkey", &value);
+    vsc_sdk::ephem_state_get_object("", "tempkey")
+}
+
+// - Draw hive from the caller and return the contract balance
+fn draw_hive(amount: String) -> String {
+    vsc_sdk::hive_draw(parse_amount(&amount), asset::HIVE);
+    let contract = format!("contract:{}", vsc_sdk::get_env_key("contract.id"));
+    vsc_sdk::get_balance(&contract, asset::HIVE).to_string()
+}
+
+// - Payload is "to,amount"
+fn send_hive(payload: String) -> String {
+    let (to, amount) = split_pair(&payload);
+    vsc_sdk::hive_transfer(to, parse_amount(amount), asset::HIVE);
+    String::from("ok")
+}
+
+// - Read the state of another contract, payload is "contractId,key"
+fn contract_get_string(payload: String) -> String {
+    let (contract_id, key) = split_pair(&payload);
+    vsc_sdk::contract_state_get(contract_id, key)
+}
+
+fn dump_env(_: String) -> String {
+    let env = vsc_sdk::get_env();
+    vsc_sdk::log(&env);
+    env
+}
+
+fn dump_env_key(key: String) -> String {
+    vsc_sdk::get_env_key(&key)
+}
+
+fn emit_event(name: String) -> String {
+    vsc_sdk::emit_event(&name, &["rust", "e2e"], r#"{"ok":true}"#);
+    String::from("ok")
+}
+
+// - SHA-256 of the UTF-8 payload
+fn hash(payload: String) -> String {
+    vsc_sdk::sha256(payload.as_bytes())
+}
+
+fn abort_me(msg: String) -> String {
+    vsc_sdk::abort(&msg)
+}
+
+fn revert_me(msg: String) -> String {
+    vsc_sdk::revert(&msg, "rust_revert")
+}
+
+fn panic_me(msg: String) -> String {
+    panic!("{}", msg)
+}
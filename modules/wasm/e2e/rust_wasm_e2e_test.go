@@ -0,0 +1,140 @@
+package wasm_e2e
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"vsc-node/lib/test_utils"
+	"vsc-node/modules/db/vsc/contracts"
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
+	stateEngine "vsc-node/modules/state-processing"
+	wasm_runtime "vsc-node/modules/wasm/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRustContract(t *testing.T) {
+	wkdir := projectRoot(t)
+	require.NoError(t, os.Chdir(wkdir))
+	wasmPath, err := CompileRust(wkdir)
+	require.NoError(t, err, "failed to compile, is the wasm32-unknown-unknown target installed?")
+	code, err := os.ReadFile(wasmPath)
+	require.NoError(t, err)
+
+	contractId := "vscrustcontract"
+	contractId2 := "vscrustcontract2"
+	txSelf := stateEngine.TxSelf{
+		TxId:                 "sometxid",
+		BlockId:              "abcdef",
+		Index:                69,
+		OpIndex:              0,
+		Timestamp:            "2025-09-03T00:00:00",
+		RequiredAuths:        []string{"hive:someone"},
+		RequiredPostingAuths: []string{},
+	}
+	ct := test_utils.NewContractTest()
+	call := func(contractId string, action string, payload string, intents ...contracts.Intent) test_utils.ContractTestCallResult {
+		return ct.Call(stateEngine.TxVscCallContract{
+			Self:       txSelf,
+			ContractId: contractId,
+			Action:     action,
+			Payload:    json.RawMessage([]byte(payload)),
+			RcLimit:    1000,
+			Intents:    intents,
+		})
+	}
+
+	ct.Deposit("hive:someone", 10000, ledgerDb.AssetHive)
+	ct.Deposit("hive:someone", 20000, ledgerDb.AssetHbd)
+	ct.RegisterContractWithRuntime(contractId, "hive:someone", code, wasm_runtime.Rust)
+	ct.RegisterContractWithRuntime(contractId2, "hive:someone", code, wasm_runtime.Rust)
+
+	//State
+	setStr := call(contractId, "setString", "myString,hello world")
+	require.True(t, setStr.Success, setStr.ErrMsg)
+	assert.Equal(t, "ok", setStr.Ret)
+	assert.Equal(t, "hello world", ct.StateGet(contractId, "myString"))
+	assert.Equal(t, "hello world", string(setStr.StateDiff[contractId].KeyDiff["myString"].Current))
+
+	ct.StateSet(contractId, "manuallyset", "hi")
+	getStr := call(contractId, "getString", "manuallyset")
+	assert.True(t, getStr.Success)
+	assert.Equal(t, "hi", getStr.Ret)
+
+	//Multi-byte UTF-8 survives the string ABI
+	call(contractId, "setString", "unicode,héllo wörld ✓")
+	assert.Equal(t, "héllo wörld ✓", ct.StateGet(contractId, "unicode"))
+
+	clearStr := call(contractId, "clearString", "myString")
+	assert.True(t, clearStr.Success)
+	assert.Equal(t, "", ct.StateGet(contractId, "myString"))
+	assert.True(t, clearStr.StateDiff[contractId].Deletions["myString"])
+
+	ephem := call(contractId, "ephemRoundTrip", "tempvalue")
+	assert.True(t, ephem.Success)
+	assert.Equal(t, "tempvalue", ephem.Ret)
+
+	icGetStr := call(contractId2, "contractGetString", contractId+",manuallyset")
+	assert.True(t, icGetStr.Success)
+	assert.Equal(t, "hi", icGetStr.Ret)
+
+	//Env, logs, events and crypto
+	dumpEnv := call(contractId, "dumpEnv", "")
+	assert.True(t, dumpEnv.Success)
+	assert.Len(t, dumpEnv.Logs[contractId].Logs, 1)
+	env := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(dumpEnv.Ret), &env))
+	assert.Equal(t, contractId, env["contract.id"])
+
+	envKey := call(contractId, "dumpEnvKey", "contract.id")
+	assert.Equal(t, contractId, envKey.Ret)
+
+	event := call(contractId, "emitEvent", "rust")
+	assert.True(t, event.Success)
+	assert.Equal(t, []contracts.ContractEvent{{Name: "rust", Topics: []string{"rust", "e2e"}, Data: `{"ok":true}`}}, event.Logs[contractId].Events)
+
+	hash := call(contractId, "hash", "abc")
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", hash.Ret)
+
+	//Ledger
+	draw := call(contractId, "drawHive", "1000", contracts.Intent{
+		Type: "transfer.allow",
+		Args: map[string]string{"limit": "1.000", "token": "hive"},
+	})
+	require.True(t, draw.Success, draw.ErrMsg)
+	assert.Equal(t, "1000", draw.Ret)
+	assert.Equal(t, int64(9000), ct.GetBalance("hive:someone", ledgerDb.AssetHive))
+	assert.Equal(t, int64(1000), ct.GetBalance("contract:"+contractId, ledgerDb.AssetHive))
+
+	noIntent := call(contractId, "drawHive", "1000")
+	assert.False(t, noIntent.Success)
+	assert.Equal(t, contracts.LEDGER_INTENT_ERROR, noIntent.Err)
+
+	send := call(contractId, "sendHive", "hive:bob,250")
+	assert.True(t, send.Success, send.ErrMsg)
+	assert.Equal(t, int64(750), ct.GetBalance("contract:"+contractId, ledgerDb.AssetHive))
+	assert.Equal(t, int64(250), ct.GetBalance("hive:bob", ledgerDb.AssetHive))
+
+	//Failures
+	abortResult := call(contractId, "abortMe", "aborted successfully")
+	assert.False(t, abortResult.Success)
+	assert.Equal(t, contracts.RUNTIME_ABORT, abortResult.Err)
+	assert.Contains(t, abortResult.ErrMsg, "aborted successfully")
+	assert.GreaterOrEqual(t, abortResult.RcUsed, int64(100))
+
+	panicResult := call(contractId, "panicMe", "panicked successfully")
+	assert.False(t, panicResult.Success)
+	assert.Equal(t, contracts.RUNTIME_ABORT, panicResult.Err)
+	assert.Contains(t, panicResult.ErrMsg, "panicked successfully")
+
+	revertResult := call(contractId, "revertMe", "reverted successfully")
+	assert.False(t, revertResult.Success)
+	assert.Equal(t, "rust_revert", revertResult.Err)
+	assert.Equal(t, "reverted successfully", revertResult.ErrMsg)
+
+	nonExistent := call(contractId, "doesNotExist", "")
+	assert.False(t, nonExistent.Success)
+	assert.Equal(t, contracts.WASM_FUNC_NOT_FND, nonExistent.Err)
+}
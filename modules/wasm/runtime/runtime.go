@@ -19,11 +19,13 @@ var _ bson.Unmarshaler = &runtime{}
 var (
 	AssemblyScript = runtime{"assembly-script"}
 	Go             = runtime{"go"}
+	Rust           = runtime{"rust"}
 )
 
 type RuntimeAction[Result any] struct {
 	AssemblyScript func() Result
 	Go             func() Result
+	Rust           func() Result
 }
 
 func NewFromString(s string) result.Result[Runtime] {
@@ -32,6 +34,8 @@ func NewFromString(s string) result.Result[Runtime] {
 		return result.Ok(AssemblyScript)
 	case Go.string:
 		return result.Ok(Go)
+	case Rust.string:
+		return result.Ok(Rust)
 	default:
 		return result.Err[Runtime](fmt.Errorf("unsupported runtime: %s", s))
 	}
@@ -49,6 +53,10 @@ func (r runtime) IsGo() bool {
 	return r == Go
 }
 
+func (r runtime) IsRust() bool {
+	return r == Rust
+}
+
 func Execute[Result any](r runtime, action RuntimeAction[Result]) Result {
 	switch r {
 	case AssemblyScript:
@@ -63,6 +71,12 @@ func Execute[Result any](r runtime, action RuntimeAction[Result]) Result {
 			return res
 		}
 		return action.Go()
+	case Rust:
+		if action.Rust == nil {
+			var res Result
+			return res
+		}
+		return action.Rust()
 	default:
 		panic(fmt.Errorf("BUG: unsupported runtime: %s", r))
 	}
@@ -36,3 +36,17 @@ func TestBSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, wasm_runtime.AssemblyScript, r.Runtime)
 }
+
+func TestNewFromString(t *testing.T) {
+	for _, r := range []wasm_runtime.Runtime{wasm_runtime.AssemblyScript, wasm_runtime.Go, wasm_runtime.Rust} {
+		assert.Equal(t, r, wasm_runtime.NewFromString(r.String()).Unwrap())
+	}
+	assert.True(t, wasm_runtime.NewFromString("rust").Unwrap().IsRust())
+	assert.True(t, wasm_runtime.NewFromString("c++").IsErr())
+
+	ran := wasm_runtime.Execute(wasm_runtime.Rust, wasm_runtime.RuntimeAction[string]{
+		Go:   func() string { return "go" },
+		Rust: func() string { return "rust" },
+	})
+	assert.Equal(t, "rust", ran)
+}
@@ -280,6 +280,52 @@ func goReadString(memory *wasmedge.Memory, ptr int32) result.Result[string] {
 	)
 }
 
+// Rust contracts pass strings as a pointer to a buffer holding the byte
+// length as a little endian u32 followed by the UTF-8 bytes. The host
+// allocates buffers for arguments and results with the exported
+// `alloc(size i32) i32`, which the contract owns afterwards.
+func rustAllocString(vm *wasmedge.VM, memory *wasmedge.Memory, t string) result.Result[int32] {
+	b := []byte(t)
+	return result.AndThen(
+		result.AndThen(
+			resultWrap(vm.ExecuteRegistered("contract", "alloc", int32(4+len(b)))),
+			parseAllocResult,
+		),
+		func(ptr int32) result.Result[int32] {
+			if memory == nil {
+				mod := vm.GetRegisteredModule("contract")
+				memory = mod.FindMemory(mod.ListMemory()[0])
+			}
+			return result.Map(
+				resultWrap(memory.GetData(uint(ptr), uint(4+len(b)))),
+				func(data []byte) int32 {
+					binary.LittleEndian.PutUint32(data[:4], uint32(len(b)))
+					copy(data[4:], b)
+					return ptr
+				},
+			)
+		},
+	)
+}
+
+func rustReadString(memory *wasmedge.Memory, ptr int32) result.Result[string] {
+	if ptr == 0 {
+		return result.Err[string](fmt.Errorf("null string pointer"))
+	}
+	return result.Map(
+		result.AndThen(
+			resultWrap(memory.GetData(uint(ptr), 4)),
+			func(sizeBytes []byte) result.Result[[]byte] {
+				size := binary.LittleEndian.Uint32(sizeBytes)
+				return resultWrap(memory.GetData(uint(ptr)+4, uint(size)))
+			},
+		),
+		func(b []byte) string {
+			return string(b)
+		},
+	)
+}
+
 func decodeUtf16(b []byte, order binary.ByteOrder) (string, error) {
 	ints := make([]uint16, len(b)/2)
 	if err := binary.Read(bytes.NewReader(b), order, &ints); err != nil {
@@ -305,6 +351,9 @@ func readString(runtime wasm_runtime.Runtime, memory *wasmedge.Memory, ptr int32
 		Go: func() result.Result[string] {
 			return goReadString(memory, ptr)
 		},
+		Rust: func() result.Result[string] {
+			return rustReadString(memory, ptr)
+		},
 	})
 }
 
@@ -321,6 +370,9 @@ func allocString(
 		Go: func() result.Result[int32] {
 			return goAllocString(vm, memory, t)
 		},
+		Rust: func() result.Result[int32] {
+			return rustAllocString(vm, memory, t)
+		},
 	})
 }
 
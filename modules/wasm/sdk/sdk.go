@@ -3,6 +3,8 @@ package sdk
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -19,9 +21,12 @@ import (
 
 	ethCrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ripemd160"
 	wasm_types "vsc-node/modules/wasm/types"
 
 	"github.com/JustinKnueppel/go-result"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 )
 
 type SdkResultStruct = wasm_types.WasmResultStruct
@@ -38,6 +43,19 @@ var (
 
 type sdkFunc any
 
+// parseScanLimit parses the page size of db.list_keys and db.range.
+func parseScanLimit(a any) (int, bool) {
+	limitString, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(limitString)
+	if err != nil || limit < 1 || limit > params.DB_SCAN_MAX_LIMIT {
+		return 0, false
+	}
+	return limit, true
+}
+
 // sdkNamespacesRef is set by init() so that system.call can look up functions
 // without creating an initialization cycle (SdkNamespaces → Resolve → SdkNamespaces).
 var sdkNamespacesRef *map[string]map[string]sdkFunc
@@ -137,6 +155,50 @@ var SdkNamespaces = map[string]map[string]sdkFunc{
 				func(struct{}) SdkResultStruct { return SdkResultStruct{Gas: session.End()} },
 			)
 		},
+		// list_keys — keys starting with prefix, resuming after cursor ("" for the first page).
+		// Returns {"keys":[...],"next":"..."}; pass next as the cursor of the following call.
+		"list_keys": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			prefix, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			cursor, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			limit, ok := parseScanLimit(arg3)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			session := eCtx.IOSession()
+			return result.Map(
+				eCtx.ListStateKeys(prefix, cursor, limit),
+				func(s string) SdkResultStruct { return SdkResultStruct{Result: s, Gas: session.End()} },
+			)
+		},
+		// range — entries with start <= key < end ("" end is unbounded).
+		// Returns {"entries":[{"key":...,"value":...}],"next":"..."}; pass next as the start of the following call.
+		"range": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			start, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			end, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			limit, ok := parseScanLimit(arg3)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			session := eCtx.IOSession()
+			return result.Map(
+				eCtx.RangeState(start, end, limit),
+				func(s string) SdkResultStruct { return SdkResultStruct{Result: s, Gas: session.End()} },
+			)
+		},
 	},
 
 	// -------------------------------------------------------------------------
@@ -258,6 +320,77 @@ var SdkNamespaces = map[string]map[string]sdkFunc{
 				func(s string) SdkResultStruct { return SdkResultStruct{Result: s, Gas: session.End()} },
 			)
 		},
+		// emit_event — structured event stored with the contract output.
+		// topics is a JSON array of strings indexed for lookups, data is any JSON value.
+		"emit_event": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			name, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			topics, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			data, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			session := eCtx.IOSession()
+			return result.Map(
+				eCtx.EmitEvent(name, topics, data),
+				func(struct{}) SdkResultStruct { return SdkResultStruct{Gas: session.End()} },
+			)
+		},
+		// schedule — calls action of the contract with payload once ("at:<height>")
+		// or repeatedly ("every:<blocks>"), each run using up to rcLimit RCs of the
+		// contract account. Creating a schedule costs the caller
+		// CONTRACT_SCHEDULE_CREATE_RCS. Returns the schedule ID to cancel it with.
+		"schedule": func(ctx context.Context, arg1 any, arg2 any, arg3 any, arg4 any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			action, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			matched, _ := regexp.Match("^[a-zA-Z0-9_]+$", []byte(action))
+			if !matched {
+				return ErrInvalidArgument
+			}
+			payload, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			when, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			rcLimitStr, ok := arg4.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			rcLimit, parseErr := strconv.ParseUint(rcLimitStr, 10, 64)
+			if parseErr != nil || rcLimit < 100 || rcLimit > uint64(params.CONTRACT_SCHEDULE_MAX_RC_LIMIT) {
+				return ErrInvalidArgument
+			}
+			session := eCtx.IOSession()
+			return result.Map(
+				eCtx.Schedule(action, payload, when, uint(rcLimit)),
+				func(id string) SdkResultStruct {
+					return SdkResultStruct{Result: id, Gas: params.CONTRACT_SCHEDULE_CREATE_RCS*params.CYCLE_GAS_PER_RC + session.End()}
+				},
+			)
+		},
+		"cancel_schedule": func(ctx context.Context, a any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			id, ok := a.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			return result.Map(
+				eCtx.CancelSchedule(id),
+				func(struct{}) SdkResultStruct { return SdkResultStruct{Gas: params.CYCLE_GAS_PER_RC / 2} },
+			)
+		},
 		"verify_address": func(ctx context.Context, a any) SdkResult {
 			addr, ok := a.(string)
 			if !ok {
@@ -370,6 +503,66 @@ var SdkNamespaces = map[string]map[string]sdkFunc{
 		},
 	},
 
+	// -------------------------------------------------------------------------
+	// token — native tokens issued by the calling contract
+	// -------------------------------------------------------------------------
+	"token": {
+		// mint — credits to with amount of "<contract id>:<symbol>"
+		"mint": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			to, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			amountString, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			amount, err := strconv.ParseInt(amountString, 10, 64)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), err))
+			}
+			if amount <= 0 {
+				return result.Err[SdkResultStruct](
+					errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("amount must be positive")),
+				)
+			}
+			symbol, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			return result.Map(
+				eCtx.MintToken(to, amount, symbol),
+				func(struct{}) SdkResultStruct { return SdkResultStruct{Gas: params.CYCLE_GAS_PER_RC} },
+			)
+		},
+		// burn — destroys amount of "<contract id>:<symbol>" held by the contract
+		"burn": func(ctx context.Context, arg1 any, arg2 any) SdkResult {
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			amountString, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			amount, err := strconv.ParseInt(amountString, 10, 64)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), err))
+			}
+			if amount <= 0 {
+				return result.Err[SdkResultStruct](
+					errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("amount must be positive")),
+				)
+			}
+			symbol, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			return result.Map(
+				eCtx.BurnToken(amount, symbol),
+				func(struct{}) SdkResultStruct { return SdkResultStruct{Gas: params.CYCLE_GAS_PER_RC} },
+			)
+		},
+	},
+
 	// -------------------------------------------------------------------------
 	// contracts — inter-contract calls
 	// -------------------------------------------------------------------------
@@ -525,6 +718,41 @@ var SdkNamespaces = map[string]map[string]sdkFunc{
 			}
 			return result.Err[SdkResultStruct](res.UnwrapErr())
 		},
+		// sign_key — requests a signature and registers an entrypoint of the
+		// contract to call with it. The RCs of the callback are charged now.
+		"sign_key": func(ctx context.Context, arg1 any, arg2 any, arg3 any, arg4 any) SdkResult {
+			keyId, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			signPayload, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			callback, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			matched, _ := regexp.Match("^[a-zA-Z0-9_]+$", []byte(callback))
+			if !matched {
+				return ErrInvalidArgument
+			}
+			rcLimitStr, ok := arg4.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			rcLimit, parseErr := strconv.ParseUint(rcLimitStr, 10, 64)
+			if parseErr != nil || rcLimit == 0 || rcLimit > uint64(params.TSS_SIGN_CALLBACK_MAX_RC_LIMIT) {
+				return ErrInvalidArgument
+			}
+			eCtx := ctx.Value(wasm_context.WasmExecCtxKey).(wasm_context.ExecContextValue)
+			res := eCtx.TssKeySignCallback(keyId, signPayload, callback, uint(rcLimit))
+			gas := uint(100_000)
+			if res.Unwrap() == "ok" {
+				gas += uint(rcLimit) * params.CYCLE_GAS_PER_RC
+			}
+			return result.Ok(SdkResultStruct{Result: res.Unwrap(), Gas: gas})
+		},
 	},
 
 	// -------------------------------------------------------------------------
@@ -588,6 +816,230 @@ var SdkNamespaces = map[string]map[string]sdkFunc{
 				Gas:    params.CYCLE_GAS_PER_RC/4 + uint(len(data))*rlpDecodeGasPerByte,
 			})
 		},
+		"sha256": func(ctx context.Context, arg1 any) SdkResult {
+			hexData, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			data, err := hexDecode(hexData)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("invalid hex")))
+			}
+			hash := sha256.Sum256(data)
+			return result.Ok(SdkResultStruct{
+				Result: hexEncode(hash[:]),
+				Gas:    params.CYCLE_GAS_PER_RC/4 + uint(len(data))*hashGasPerByte,
+			})
+		},
+		// double_sha256 — sha256(sha256(data)), as used for Bitcoin block and transaction IDs
+		"double_sha256": func(ctx context.Context, arg1 any) SdkResult {
+			hexData, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			data, err := hexDecode(hexData)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("invalid hex")))
+			}
+			first := sha256.Sum256(data)
+			hash := sha256.Sum256(first[:])
+			return result.Ok(SdkResultStruct{
+				Result: hexEncode(hash[:]),
+				Gas:    params.CYCLE_GAS_PER_RC/4 + uint(len(data))*hashGasPerByte,
+			})
+		},
+		"ripemd160": func(ctx context.Context, arg1 any) SdkResult {
+			hexData, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			data, err := hexDecode(hexData)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("invalid hex")))
+			}
+			hasher := ripemd160.New()
+			hasher.Write(data)
+			return result.Ok(SdkResultStruct{
+				Result: hexEncode(hasher.Sum(nil)),
+				Gas:    params.CYCLE_GAS_PER_RC/4 + uint(len(data))*hashGasPerByte,
+			})
+		},
+		// blake2b — unkeyed BLAKE2b with a digest of 1 to 64 bytes, e.g. "32" for BLAKE2b-256
+		"blake2b": func(ctx context.Context, arg1 any, arg2 any) SdkResult {
+			hexData, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			sizeStr, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			size, err := strconv.Atoi(sizeStr)
+			if err != nil || size < 1 || size > blake2b.Size {
+				return ErrInvalidArgument
+			}
+			data, err := hexDecode(hexData)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("invalid hex")))
+			}
+			hasher, err := blake2b.New(size, nil)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), err))
+			}
+			hasher.Write(data)
+			return result.Ok(SdkResultStruct{
+				Result: hexEncode(hasher.Sum(nil)),
+				Gas:    params.CYCLE_GAS_PER_RC/4 + uint(len(data))*hashGasPerByte,
+			})
+		},
+		"ed25519_verify": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			pubKeyHex, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			msgHex, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			sigHex, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			pubKey, err := hexDecode(pubKeyHex)
+			if err != nil || len(pubKey) != ed25519.PublicKeySize {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("pubkey must be 32 bytes hex")))
+			}
+			msg, err := hexDecode(msgHex)
+			if err != nil {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("invalid msg hex")))
+			}
+			sig, err := hexDecode(sigHex)
+			if err != nil || len(sig) != ed25519.SignatureSize {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("sig must be 64 bytes hex")))
+			}
+			valid := ed25519.Verify(ed25519.PublicKey(pubKey), msg, sig)
+			return result.Ok(SdkResultStruct{
+				Result: strconv.FormatBool(valid),
+				Gas:    params.CYCLE_GAS_PER_RC + uint(len(msg))*hashGasPerByte,
+			})
+		},
+		// secp256k1_verify — ECDSA over a 32 byte digest. The pubkey is compressed or
+		// uncompressed, the sig is r||s and must have a low s like Bitcoin and Ethereum require.
+		"secp256k1_verify": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			pubKeyHex, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			digestHex, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			sigHex, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			pubKey, err := hexDecode(pubKeyHex)
+			if err != nil || (len(pubKey) != 33 && len(pubKey) != 65) {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("pubkey must be 33 or 65 bytes hex")))
+			}
+			digest, err := hexDecode(digestHex)
+			if err != nil || len(digest) != 32 {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("digest must be 32 bytes hex")))
+			}
+			sig, err := hexDecode(sigHex)
+			if err != nil || len(sig) != 64 {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("sig must be 64 bytes hex (r+s)")))
+			}
+			valid := ethCrypto.VerifySignature(pubKey, digest, sig)
+			return result.Ok(SdkResultStruct{Result: strconv.FormatBool(valid), Gas: params.CYCLE_GAS_PER_RC})
+		},
+		// schnorr_bip340_verify — BIP-340 signature of a 32 byte message by an x-only pubkey, as used by Taproot
+		"schnorr_bip340_verify": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			pubKeyHex, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			msgHex, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			sigHex, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			pubKeyBytes, err := hexDecode(pubKeyHex)
+			if err != nil || len(pubKeyBytes) != schnorr.PubKeyBytesLen {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("pubkey must be 32 bytes hex")))
+			}
+			msg, err := hexDecode(msgHex)
+			if err != nil || len(msg) != 32 {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("msg must be 32 bytes hex")))
+			}
+			sigBytes, err := hexDecode(sigHex)
+			if err != nil || len(sigBytes) != schnorr.SignatureSize {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), fmt.Errorf("sig must be 64 bytes hex")))
+			}
+			gas := uint(params.CYCLE_GAS_PER_RC)
+			pubKey, err := schnorr.ParsePubKey(pubKeyBytes)
+			if err != nil {
+				return result.Ok(SdkResultStruct{Result: "false", Gas: gas})
+			}
+			sig, err := schnorr.ParseSignature(sigBytes)
+			if err != nil {
+				return result.Ok(SdkResultStruct{Result: "false", Gas: gas})
+			}
+			return result.Ok(SdkResultStruct{Result: strconv.FormatBool(sig.Verify(msg, pubKey)), Gas: gas})
+		},
+		// bls12381_verify_aggregate — aggregate BLS signature (G2, 96 bytes) over the same message by
+		// every pubkey. pubkeys is a JSON array of BLS DIDs or hex G1 pubkeys (48 bytes), which must
+		// be proven by the caller, e.g. taken from an election.
+		"bls12381_verify_aggregate": func(ctx context.Context, arg1 any, arg2 any, arg3 any) SdkResult {
+			pubKeysJson, ok := arg1.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			msgHex, ok := arg2.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			sigHex, ok := arg3.(string)
+			if !ok {
+				return ErrInvalidArgument
+			}
+			keys := make([]string, 0)
+			err := json.Unmarshal([]byte(pubKeysJson), &keys)
+			//Parsed keys are charged even when the call fails
+			gas := params.CYCLE_GAS_PER_RC*10 + uint(len(keys))*blsGasPerKey
+			fail := func(err error) SdkResult {
+				return result.Err[SdkResultStruct](errors.Join(fmt.Errorf(contracts.SDK_ERROR), err, fmt.Errorf("%d", gas)))
+			}
+			if err != nil || len(keys) == 0 || len(keys) > blsMaxAggregateKeys {
+				return fail(fmt.Errorf("pubkeys must be a JSON array of 1 to %d keys", blsMaxAggregateKeys))
+			}
+			pubKeys := make([]*dids.BlsPubKey, 0, len(keys))
+			for _, key := range keys {
+				pubKey, err := parseBlsPubKey(key)
+				if err != nil {
+					return fail(err)
+				}
+				pubKeys = append(pubKeys, pubKey)
+			}
+			msg, err := hexDecode(msgHex)
+			if err != nil {
+				return fail(fmt.Errorf("invalid msg hex"))
+			}
+			gas += uint(len(msg)) * hashGasPerByte
+			sigBytes, err := hexDecode(sigHex)
+			if err != nil || len(sigBytes) != 96 {
+				return fail(fmt.Errorf("sig must be 96 bytes hex"))
+			}
+			sig := new(dids.BlsSig)
+			if sig.Deserialize((*[96]byte)(sigBytes)) != nil {
+				return result.Ok(SdkResultStruct{Result: "false", Gas: gas})
+			}
+			valid := dids.VerifyAggregate(pubKeys, msg, sig)
+			return result.Ok(SdkResultStruct{Result: strconv.FormatBool(valid), Gas: gas})
+		},
 		"sp1_verify_groth16": func(ctx context.Context, arg1 any, arg2 any, arg3 any, arg4 any, arg5 any) SdkResult {
 			proofHex, ok := arg1.(string)
 			if !ok {
@@ -652,8 +1104,40 @@ func hexEncode(b []byte) string {
 const (
 	keccak256GasPerByte = params.CYCLE_GAS_PER_RC / 256
 	rlpDecodeGasPerByte = params.CYCLE_GAS_PER_RC / 128
+	// SHA-256, RIPEMD-160 and BLAKE2b, also charged for messages hashed by signature checks
+	hashGasPerByte = params.CYCLE_GAS_PER_RC / 256
+	// G1 point addition per key of an aggregate BLS signature
+	blsGasPerKey = params.CYCLE_GAS_PER_RC / 10
 )
 
+// blsMaxAggregateKeys bounds the pubkeys of bls12381_verify_aggregate,
+// well above the size of an election.
+const blsMaxAggregateKeys = 1024
+
+// parseBlsPubKey accepts a BLS DID ("did:key:z...") or a hex compressed G1 pubkey.
+func parseBlsPubKey(key string) (*dids.BlsPubKey, error) {
+	if strings.HasPrefix(key, dids.BlsDIDPrefix) {
+		did, err := dids.ParseBlsDID(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS DID %q", key)
+		}
+		pubKey := did.Identifier()
+		if pubKey == nil {
+			return nil, fmt.Errorf("invalid BLS DID %q", key)
+		}
+		return pubKey, nil
+	}
+	keyBytes, err := hexDecode(key)
+	if err != nil || len(keyBytes) != 48 {
+		return nil, fmt.Errorf("pubkey must be a BLS DID or 48 bytes hex")
+	}
+	pubKey := new(dids.BlsPubKey)
+	if err := pubKey.Deserialize((*[48]byte)(keyBytes)); err != nil {
+		return nil, fmt.Errorf("invalid BLS pubkey %q", key)
+	}
+	return pubKey, nil
+}
+
 // rlpMaxDecodeDepth bounds recursion to prevent stack exhaustion. Ethereum
 // transactions reach depth 3 (tx → accessList → [addr, [storageKeys]]); 16
 // is comfortably above any legitimate payload.
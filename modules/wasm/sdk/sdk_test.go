@@ -2,8 +2,18 @@ package sdk
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
 	"testing"
+	"vsc-node/modules/common/params"
+
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVerifyAddress(t *testing.T) {
@@ -19,3 +29,149 @@ func TestVerifyAddress(t *testing.T) {
 	val := res.Unwrap()
 	fmt.Printf("address: %q\nresult:  %q\ngas:     %d\n", addr, val.Result, val.Gas)
 }
+
+// Calls a crypto function with string arguments
+func callCrypto(t *testing.T, name string, args ...string) SdkResult {
+	t.Helper()
+	switch fn := SdkNamespaces["crypto"][name].(type) {
+	case func(context.Context, any) SdkResult:
+		require.Len(t, args, 1)
+		return fn(context.Background(), args[0])
+	case func(context.Context, any, any) SdkResult:
+		require.Len(t, args, 2)
+		return fn(context.Background(), args[0], args[1])
+	case func(context.Context, any, any, any) SdkResult:
+		require.Len(t, args, 3)
+		return fn(context.Background(), args[0], args[1], args[2])
+	}
+	t.Fatalf("no crypto function %s", name)
+	return SdkResult{}
+}
+
+func assertCryptoResult(t *testing.T, expected string, res SdkResult) {
+	t.Helper()
+	require.True(t, res.IsOk(), "unexpected error: %v", res)
+	assert.Equal(t, expected, res.Unwrap().Result)
+	assert.NotZero(t, res.Unwrap().Gas)
+}
+
+func TestCryptoHashes(t *testing.T) {
+	abc := hex.EncodeToString([]byte("abc"))
+
+	assertCryptoResult(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", callCrypto(t, "sha256", ""))
+	assertCryptoResult(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", callCrypto(t, "sha256", abc))
+	assertCryptoResult(t, "4f8b42c22dd3729b519ba6f68d2da7cc5b2d606d05daed5ad5128cc03e6c6358", callCrypto(t, "double_sha256", abc))
+	assertCryptoResult(t, "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc", callCrypto(t, "ripemd160", "0x"+abc))
+	assertCryptoResult(t,
+		"ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		callCrypto(t, "blake2b", abc, "64"),
+	)
+	assertCryptoResult(t, "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319", callCrypto(t, "blake2b", abc, "32"))
+
+	//Gas grows with the input
+	small := callCrypto(t, "sha256", abc).Unwrap().Gas
+	large := callCrypto(t, "sha256", strings.Repeat(abc, 1000)).Unwrap().Gas
+	assert.Greater(t, large, small)
+
+	assert.True(t, callCrypto(t, "sha256", "zz").IsErr())
+	assert.True(t, callCrypto(t, "blake2b", abc, "0").IsErr())
+	assert.True(t, callCrypto(t, "blake2b", abc, "65").IsErr())
+}
+
+func TestCryptoEd25519Verify(t *testing.T) {
+	//RFC 8032 test 2
+	pubKey := "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"
+	msg := "72"
+	sig := "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"
+
+	assertCryptoResult(t, "true", callCrypto(t, "ed25519_verify", pubKey, msg, sig))
+	assertCryptoResult(t, "false", callCrypto(t, "ed25519_verify", pubKey, "73", sig))
+	assert.True(t, callCrypto(t, "ed25519_verify", pubKey[2:], msg, sig).IsErr())
+	assert.True(t, callCrypto(t, "ed25519_verify", pubKey, msg, sig[2:]).IsErr())
+}
+
+func TestCryptoSecp256k1Verify(t *testing.T) {
+	pubKey := "03d579fd38b73d527695d98fb81b7b5c9ba08f1f157ca1a4d8638fd52308ea82d0"
+	digest := "e004c78365fddb5ae256b78f5202526e2b3941621d98a6509a38af53bc6c80ed"
+	sig := "7f6f9cfbd72b86cd2bb0949c7d07c6500ec25b6b0dae0426591986628aab51d73f442a65a37e54b399078f49c3214ef6bea525f327a88d0a9aed4edd0ac8e7b0"
+
+	assertCryptoResult(t, "true", callCrypto(t, "secp256k1_verify", pubKey, digest, sig))
+	assertCryptoResult(t, "false", callCrypto(t, "secp256k1_verify", pubKey, strings.Repeat("00", 32), sig))
+
+	//The uncompressed form of the same key
+	pub, err := ethCrypto.DecompressPubkey(mustHex(t, pubKey))
+	require.NoError(t, err)
+	assertCryptoResult(t, "true", callCrypto(t, "secp256k1_verify", hex.EncodeToString(ethCrypto.FromECDSAPub(pub)), digest, sig))
+
+	//Malleated signatures with a high s are rejected
+	n := ethCrypto.S256().Params().N
+	s := new(big.Int).SetBytes(mustHex(t, sig[64:]))
+	highS := new(big.Int).Sub(n, s).FillBytes(make([]byte, 32))
+	assertCryptoResult(t, "false", callCrypto(t, "secp256k1_verify", pubKey, digest, sig[:64]+hex.EncodeToString(highS)))
+
+	assert.True(t, callCrypto(t, "secp256k1_verify", pubKey, digest[2:], sig).IsErr())
+	assert.True(t, callCrypto(t, "secp256k1_verify", pubKey, digest, sig+"1b").IsErr())
+}
+
+func TestCryptoSchnorrBip340Verify(t *testing.T) {
+	//BIP-340 test vectors 0 and 1
+	assertCryptoResult(t, "true", callCrypto(t, "schnorr_bip340_verify",
+		"f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+		strings.Repeat("00", 32),
+		"e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca821525f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0",
+	))
+	pubKey := "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659"
+	msg := "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89"
+	sig := "6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de33418906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a"
+	assertCryptoResult(t, "true", callCrypto(t, "schnorr_bip340_verify", pubKey, msg, sig))
+	assertCryptoResult(t, "false", callCrypto(t, "schnorr_bip340_verify", pubKey, strings.Repeat("00", 32), sig))
+	//BIP-340 test vector 5, the pubkey is not on the curve
+	assertCryptoResult(t, "false", callCrypto(t, "schnorr_bip340_verify",
+		"eefdea4cdb677750a420fee807eacf21eb9898ae79b9768766e4faa04a2d4a34", msg, sig,
+	))
+	assert.True(t, callCrypto(t, "schnorr_bip340_verify", pubKey, msg, sig[2:]).IsErr())
+}
+
+func TestCryptoBls12381VerifyAggregate(t *testing.T) {
+	//Signatures of "magi block" by the secret keys 1, 2 and 3
+	pubKeys := []string{
+		"97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",
+		"a572cbea904d67468808c8eb50a9450c9721db309128012543902d0ac358a62ae28f75bb8f1c7c42c39a8c5529bf0f4e",
+		"did:key:z3tEExoWEdMs3RSM2AunEufVENRcGn6m7Va8H1Zu4hku2iFQhCcDxhZrWxtq2U2jnaP45q",
+	}
+	msg := hex.EncodeToString([]byte("magi block"))
+	sig := "92bab10de867417cd53a72f92e37b71b77192108e87f05b05a38e1487c91a01c0498fe86bf15b1f88ada36dca856c23b0563b7ef95f7dfc71ae75fda1e0517b1eecf80f62a337c06519cead7f98ada69f1231003eb4fb374cdb53f0b4f21d996"
+	keysJson := func(keys ...string) string {
+		b, _ := json.Marshal(keys)
+		return string(b)
+	}
+
+	assertCryptoResult(t, "true", callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys...), msg, sig))
+	assertCryptoResult(t, "false", callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys[:2]...), msg, sig))
+	assertCryptoResult(t, "false", callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys...), hex.EncodeToString([]byte("other block")), sig))
+	assertCryptoResult(t, "false", callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys...), msg, strings.Repeat("00", 96)))
+
+	//Failed calls still charge for the keys they parsed
+	assertCryptoGas := func(minGas uint, res SdkResult) {
+		t.Helper()
+		require.True(t, res.IsErr())
+		joined := res.UnwrapErr().(interface{ Unwrap() []error }).Unwrap()
+		require.Len(t, joined, 3)
+		gas, err := strconv.ParseUint(joined[2].Error(), 10, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, uint(gas), minGas)
+	}
+	assertCryptoGas(params.CYCLE_GAS_PER_RC*10, callCrypto(t, "bls12381_verify_aggregate", "[]", msg, sig))
+	assertCryptoGas(params.CYCLE_GAS_PER_RC*10, callCrypto(t, "bls12381_verify_aggregate", "{", msg, sig))
+	assertCryptoGas(params.CYCLE_GAS_PER_RC*10+blsGasPerKey, callCrypto(t, "bls12381_verify_aggregate", keysJson("did:key:zinvalid"), msg, sig))
+	assertCryptoGas(params.CYCLE_GAS_PER_RC*10+blsGasPerKey, callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys[0][2:]), msg, sig))
+	assertCryptoGas(params.CYCLE_GAS_PER_RC*10+3*blsGasPerKey, callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys...), "zz", sig))
+	assertCryptoGas(params.CYCLE_GAS_PER_RC*10+3*blsGasPerKey, callCrypto(t, "bls12381_verify_aggregate", keysJson(pubKeys...), msg, sig[2:]))
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,20 @@
+package wasm_types
+
+// Page of contract state keys returned by db.list_keys
+type StateKeysPage struct {
+	Keys []string `json:"keys"`
+	//Cursor to resume from, empty when there are no more keys
+	Next string `json:"next"`
+}
+
+// Page of contract state entries returned by db.range
+type StateRangePage struct {
+	Entries []StateEntry `json:"entries"`
+	//Start of the next page, empty when there are no more entries
+	Next string `json:"next"`
+}
+
+type StateEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}